@@ -38,14 +38,19 @@ const (
 type aliCloudProvider struct {
 	manager         *AliCloudManager
 	asgs            []*Asg
+	pricingModel    *priceModel
 	resourceLimiter *cloudprovider.ResourceLimiter
 }
 
 // BuildAliCloudProvider builds CloudProvider implementation for AliCloud.
 func BuildAliCloudProvider(manager *AliCloudManager, discoveryOpts cloudprovider.NodeGroupDiscoveryOptions, resourceLimiter *cloudprovider.ResourceLimiter) (cloudprovider.CloudProvider, error) {
+	return buildAliCloudProvider(manager, newPriceModel(manager, nil), discoveryOpts, resourceLimiter)
+}
+
+func buildAliCloudProvider(manager *AliCloudManager, pricingModel *priceModel, discoveryOpts cloudprovider.NodeGroupDiscoveryOptions, resourceLimiter *cloudprovider.ResourceLimiter) (cloudprovider.CloudProvider, error) {
 	// TODO add discoveryOpts parameters check.
 	if discoveryOpts.StaticDiscoverySpecified() {
-		return buildStaticallyDiscoveringProvider(manager, discoveryOpts.NodeGroupSpecs, resourceLimiter)
+		return buildStaticallyDiscoveringProvider(manager, pricingModel, discoveryOpts.NodeGroupSpecs, resourceLimiter)
 	}
 	if discoveryOpts.AutoDiscoverySpecified() {
 		return nil, fmt.Errorf("only support static discovery scaling group in alicloud for now")
@@ -53,10 +58,11 @@ func BuildAliCloudProvider(manager *AliCloudManager, discoveryOpts cloudprovider
 	return nil, fmt.Errorf("failed to build alicloud provider: node group specs must be specified")
 }
 
-func buildStaticallyDiscoveringProvider(manager *AliCloudManager, specs []string, resourceLimiter *cloudprovider.ResourceLimiter) (*aliCloudProvider, error) {
+func buildStaticallyDiscoveringProvider(manager *AliCloudManager, pricingModel *priceModel, specs []string, resourceLimiter *cloudprovider.ResourceLimiter) (*aliCloudProvider, error) {
 	acp := &aliCloudProvider{
 		manager:         manager,
 		asgs:            make([]*Asg, 0),
+		pricingModel:    pricingModel,
 		resourceLimiter: resourceLimiter,
 	}
 	for _, spec := range specs {
@@ -110,7 +116,7 @@ func (ali *aliCloudProvider) NodeGroupForNode(node *apiv1.Node) (cloudprovider.N
 
 // Pricing returns pricing model for this cloud provider or error if not available.
 func (ali *aliCloudProvider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError) {
-	return nil, cloudprovider.ErrNotImplemented
+	return ali.pricingModel, nil
 }
 
 // GetAvailableMachineTypes get all machine types that can be requested from the cloud provider.
@@ -206,7 +212,15 @@ func BuildAlicloud(opts config.AutoscalingOptions, do cloudprovider.NodeGroupDis
 	if aliError != nil {
 		klog.Fatalf("Failed to create Alicloud Manager: %v", aliError)
 	}
-	cloudProvider, err := BuildAliCloudProvider(aliManager, do, rl)
+	var priceOverrides cloudprovider.InstancePrices
+	if opts.InstancePricesFile != "" {
+		var err error
+		priceOverrides, err = cloudprovider.ReadInstancePrices(opts.InstancePricesFile)
+		if err != nil {
+			klog.Fatalf("Couldn't read instance prices %s: %v", opts.InstancePricesFile, err)
+		}
+	}
+	cloudProvider, err := buildAliCloudProvider(aliManager, newPriceModel(aliManager, priceOverrides), do, rl)
 	if err != nil {
 		klog.Fatalf("Failed to create Alicloud cloud provider: %v", err)
 	}
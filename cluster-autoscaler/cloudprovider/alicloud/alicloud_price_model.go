@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alicloud
+
+import (
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+)
+
+// priceModel implements PricingModel interface for alicloud.
+type priceModel struct {
+	manager        *AliCloudManager
+	priceOverrides cloudprovider.InstancePrices
+}
+
+const (
+	// Prices of a vCPU, of a GiB of memory and of a GPU. They are used to price instance types
+	// without a price in the price file, nodes of unknown instance types and pods. They are
+	// derived from pay-as-you-go prices of g5 and gn5 instances in cn-hangzhou.
+	cpuPricePerHour         = 0.035
+	memoryPricePerHourPerGb = 0.0045
+	gpuPricePerHour         = 0.6
+)
+
+var resourcePrices = cloudprovider.ResourcePrices{
+	CpuPerHour:         cpuPricePerHour,
+	MemoryPerGbPerHour: memoryPricePerHourPerGb,
+	GpuPerHour:         gpuPricePerHour,
+	GpuResource:        ResourceGPU,
+}
+
+// newPriceModel builds a price model pricing instance types with priceOverrides, keyed by region
+// and instance type id, and with their resources described by ECS otherwise.
+func newPriceModel(manager *AliCloudManager, priceOverrides cloudprovider.InstancePrices) *priceModel {
+	return &priceModel{
+		manager:        manager,
+		priceOverrides: priceOverrides,
+	}
+}
+
+// NodePrice returns a price of running the given node for a given period of time.
+// All prices are in USD.
+func (model *priceModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	hours := cloudprovider.PriceHours(startTime, endTime)
+	typeId := node.Labels[kubeletapis.LabelInstanceType]
+	if typeId != "" {
+		if price, found := model.priceOverrides.Price(node.Labels[kubeletapis.LabelZoneRegion], typeId); found {
+			return price * hours, nil
+		}
+		// Only cached instance types are used, types of node group templates are always cached.
+		if model.manager != nil && model.manager.iService != nil {
+			if instanceTypeModel := model.manager.iService.FindInstanceType(typeId); instanceTypeModel != nil {
+				price := resourcePrices.InstancePrice(instanceTypeModel.vcpu, float64(instanceTypeModel.memoryInBytes)/float64(units.GiB), instanceTypeModel.gpu)
+				return price * hours, nil
+			}
+		}
+	}
+	return resourcePrices.Price(node.Status.Capacity, startTime, endTime), nil
+}
+
+// PodPrice returns a theoretical minimum price of running a pod for a given
+// period of time on a perfectly matching machine.
+func (model *priceModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	price := 0.0
+	for _, container := range pod.Spec.Containers {
+		price += resourcePrices.Price(container.Resources.Requests, startTime, endTime)
+	}
+	return price, nil
+}
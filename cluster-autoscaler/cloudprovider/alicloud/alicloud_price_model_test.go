@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alicloud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+)
+
+func TestPriceModelNodePrice(t *testing.T) {
+	manager := &AliCloudManager{
+		iService: &instanceWrapper{
+			InstanceTypeCache: map[string]*instanceTypeModel{
+				"ecs.gn5-c4g1.xlarge": {
+					instanceType{
+						instanceTypeID: "ecs.gn5-c4g1.xlarge",
+						vcpu:           4,
+						memoryInBytes:  30 * 1024 * 1024 * 1024,
+						gpu:            1,
+					},
+				},
+			},
+		},
+	}
+	overrides := cloudprovider.InstancePrices{"cn-hangzhou": {"ecs.g5.large": 0.1}}
+	model := newPriceModel(manager, overrides)
+	now := time.Now()
+	later := now.Add(2 * time.Hour)
+
+	// Instance types are priced with the override for the region of the node.
+	node := BuildTestNode("n1", 2000, 8*1024*1024*1024)
+	node.Labels = map[string]string{
+		kubeletapis.LabelInstanceType: "ecs.g5.large",
+		kubeletapis.LabelZoneRegion:   "cn-hangzhou",
+	}
+	price, err := model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.2, price, 1e-9)
+
+	// Instance types without a price are priced by their resources described by ECS.
+	node.Labels[kubeletapis.LabelInstanceType] = "ecs.gn5-c4g1.xlarge"
+	price, err = model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*(4*cpuPricePerHour+30*memoryPricePerHourPerGb+gpuPricePerHour), price, 1e-9)
+
+	// Nodes of unknown instance types are priced by their capacity.
+	node.Labels[kubeletapis.LabelZoneRegion] = "cn-beijing"
+	node.Labels[kubeletapis.LabelInstanceType] = "ecs.g5.large"
+	price, err = model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*(2*cpuPricePerHour+8*memoryPricePerHourPerGb), price, 1e-9)
+}
+
+func TestPriceModelPodPrice(t *testing.T) {
+	model := newPriceModel(nil, nil)
+	now := time.Now()
+	pod := BuildTestPod("p1", 500, 2*1024*1024*1024)
+	price, err := model.PodPrice(pod, now, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5*cpuPricePerHour+2*memoryPricePerHourPerGb, price, 1e-9)
+}
@@ -38,7 +38,7 @@ type autoScalingWrapper struct {
 	autoScaling
 }
 
-func (m autoScalingWrapper) getInstanceTypeByLCName(name string) (string, error) {
+func (m autoScalingWrapper) getLaunchSpecByLCName(name string) (*launchSpec, error) {
 	params := &autoscaling.DescribeLaunchConfigurationsInput{
 		LaunchConfigurationNames: []*string{aws.String(name)},
 		MaxRecords:               aws.Int64(1),
@@ -46,13 +46,18 @@ func (m autoScalingWrapper) getInstanceTypeByLCName(name string) (string, error)
 	launchConfigurations, err := m.DescribeLaunchConfigurations(params)
 	if err != nil {
 		klog.V(4).Infof("Failed LaunchConfiguration info request for %s: %v", name, err)
-		return "", err
+		return nil, err
 	}
 	if len(launchConfigurations.LaunchConfigurations) < 1 {
-		return "", fmt.Errorf("Unable to get first LaunchConfiguration for %s", name)
+		return nil, fmt.Errorf("Unable to get first LaunchConfiguration for %s", name)
 	}
 
-	return *launchConfigurations.LaunchConfigurations[0].InstanceType, nil
+	lc := launchConfigurations.LaunchConfigurations[0]
+	return &launchSpec{
+		InstanceType: aws.StringValue(lc.InstanceType),
+		// Launch configurations with a spot price request spot instances.
+		Spot: aws.StringValue(lc.SpotPrice) != "",
+	}, nil
 }
 
 func (m *autoScalingWrapper) getAutoscalingGroupsByNames(names []string) ([]*autoscaling.Group, error) {
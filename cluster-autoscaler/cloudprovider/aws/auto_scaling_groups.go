@@ -24,6 +24,7 @@ import (
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/config/dynamic"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
@@ -56,6 +57,9 @@ type asg struct {
 	LaunchTemplateVersion   string
 	LaunchConfigurationName string
 	Tags                    []*autoscaling.TagDescription
+
+	// scaleDownAllowedWindows caches the parsed scale down windows tag.
+	scaleDownAllowedWindows schedule.Cache
 }
 
 func newASGCache(service autoScalingWrapper, explicitSpecs []string, autoDiscoverySpecs []cloudprovider.ASGAutoDiscoveryConfig) (*asgCache, error) {
@@ -95,6 +99,8 @@ func (m *asgCache) parseExplicitAsgs(specs []string) error {
 func (m *asgCache) register(asg *asg) *asg {
 	for i := range m.registeredAsgs {
 		if existing := m.registeredAsgs[i]; existing.AwsRef == asg.AwsRef {
+			// The cache is keyed by the tag value, so it stays valid for the updated ASG.
+			asg.scaleDownAllowedWindows = existing.scaleDownAllowedWindows
 			if reflect.DeepEqual(existing, asg) {
 				return existing
 			}
@@ -41,13 +41,19 @@ const (
 type awsCloudProvider struct {
 	awsManager      *AwsManager
 	resourceLimiter *cloudprovider.ResourceLimiter
+	pricingModel    *AwsPriceModel
 }
 
-// BuildAwsCloudProvider builds CloudProvider implementation for AWS.
+// BuildAwsCloudProvider builds CloudProvider implementation for AWS, using bundled instance prices.
 func BuildAwsCloudProvider(awsManager *AwsManager, resourceLimiter *cloudprovider.ResourceLimiter) (cloudprovider.CloudProvider, error) {
+	return buildAwsCloudProvider(awsManager, NewAwsPriceModel(awsManager, nil, NewEc2SpotPriceSource(awsManager)), resourceLimiter)
+}
+
+func buildAwsCloudProvider(awsManager *AwsManager, pricingModel *AwsPriceModel, resourceLimiter *cloudprovider.ResourceLimiter) (cloudprovider.CloudProvider, error) {
 	aws := &awsCloudProvider{
 		awsManager:      awsManager,
 		resourceLimiter: resourceLimiter,
+		pricingModel:    pricingModel,
 	}
 	return aws, nil
 }
@@ -101,7 +107,7 @@ func (aws *awsCloudProvider) NodeGroupForNode(node *apiv1.Node) (cloudprovider.N
 
 // Pricing returns pricing model for this cloud provider or error if not available.
 func (aws *awsCloudProvider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError) {
-	return nil, cloudprovider.ErrNotImplemented
+	return aws.pricingModel, nil
 }
 
 // GetAvailableMachineTypes get all machine types that can be requested from the cloud provider.
@@ -196,6 +202,17 @@ func (ng *AwsNodeGroup) Autoprovisioned() bool {
 	return false
 }
 
+// GetOptions returns NodeGroupAutoscalingOptions that should be used for this particular
+// NodeGroup. Options can be overridden with ASG tags prefixed with
+// k8s.io/cluster-autoscaler/node-template/autoscaling-options/.
+func (ng *AwsNodeGroup) GetOptions(defaults config.NodeGroupAutoscalingOptions) (*config.NodeGroupAutoscalingOptions, error) {
+	options := ng.awsManager.getAsgOptions(ng.asg, defaults)
+	if options == nil {
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	return options, nil
+}
+
 // Delete deletes the node group on the cloud provider side.
 // This will be executed only for autoprovisioned node groups, once their size drops to 0.
 func (ng *AwsNodeGroup) Delete() error {
@@ -335,7 +352,16 @@ func BuildAWS(opts config.AutoscalingOptions, do cloudprovider.NodeGroupDiscover
 		klog.Fatalf("Failed to create AWS Manager: %v", err)
 	}
 
-	provider, err := BuildAwsCloudProvider(manager, rl)
+	var priceOverrides cloudprovider.InstancePrices
+	if opts.InstancePricesFile != "" {
+		priceOverrides, err = cloudprovider.ReadInstancePrices(opts.InstancePricesFile)
+		if err != nil {
+			klog.Fatalf("Couldn't read instance prices %s: %v", opts.InstancePricesFile, err)
+		}
+	}
+
+	pricingModel := NewAwsPriceModel(manager, priceOverrides, NewEc2SpotPriceSource(manager))
+	provider, err := buildAwsCloudProvider(manager, pricingModel, rl)
 	if err != nil {
 		klog.Fatalf("Failed to create AWS cloud provider: %v", err)
 	}
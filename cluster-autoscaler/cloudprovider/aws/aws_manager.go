@@ -25,7 +25,9 @@ import (
 	"math/rand"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -38,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
 	"k8s.io/klog"
 	provider_aws "k8s.io/kubernetes/pkg/cloudprovider/providers/aws"
@@ -50,6 +53,20 @@ const (
 	maxRecordsReturnedByAPI = 100
 	maxAsgNamesPerDescribe  = 50
 	refreshInterval         = 10 * time.Second
+
+	// autoscalingOptionsTagPrefix is the prefix of ASG tags overriding autoscaling options for the ASG.
+	autoscalingOptionsTagPrefix                    = "k8s.io/cluster-autoscaler/node-template/autoscaling-options/"
+	autoscalingOptionScaleDownUtilizationThreshold = "scaledownutilizationthreshold"
+	autoscalingOptionScaleDownUnneededTime         = "scaledownunneededtime"
+	autoscalingOptionScaleDownUnreadyTime          = "scaledownunreadytime"
+	autoscalingOptionMaxNodeProvisionTime          = "maxnodeprovisiontime"
+	autoscalingOptionScaleDownAllowedWindows       = "scaledownallowedwindows"
+	autoscalingOptionHeadroomCPU                   = "headroomcpu"
+	autoscalingOptionHeadroomMemory                = "headroommemory"
+	autoscalingOptionHeadroomNodes                 = "headroomnodes"
+
+	// asgNameAnnotation is set on template nodes to the name of their ASG.
+	asgNameAnnotation = "cluster-autoscaler.kubernetes.io/aws-asg-name"
 )
 
 // AwsManager is handles aws communication and data caching.
@@ -58,6 +75,10 @@ type AwsManager struct {
 	ec2Service         ec2Wrapper
 	asgCache           *asgCache
 	lastRefresh        time.Time
+
+	launchSpecsMutex sync.Mutex
+	// launchSpecs caches launch configurations and templates by launchSpecKey until the next refresh.
+	launchSpecs map[string]*launchSpec
 }
 
 type asgTemplate struct {
@@ -65,6 +86,8 @@ type asgTemplate struct {
 	Region       string
 	Zone         string
 	Tags         []*autoscaling.TagDescription
+	// Spot is true if the ASG launches spot instances.
+	Spot bool
 }
 
 // getRegion deduces the current AWS Region.
@@ -120,6 +143,7 @@ func createAWSManagerInternal(
 		autoScalingService: *autoScalingService,
 		ec2Service:         *ec2Service,
 		asgCache:           cache,
+		launchSpecs:        make(map[string]*launchSpec),
 	}
 
 	if err := manager.forceRefresh(); err != nil {
@@ -148,6 +172,9 @@ func (m *AwsManager) forceRefresh() error {
 		klog.Errorf("Failed to regenerate ASG cache: %v", err)
 		return err
 	}
+	m.launchSpecsMutex.Lock()
+	m.launchSpecs = make(map[string]*launchSpec)
+	m.launchSpecsMutex.Unlock()
 	m.lastRefresh = time.Now()
 	klog.V(2).Infof("Refreshed ASG list, next refresh after %v", m.lastRefresh.Add(refreshInterval))
 	return nil
@@ -167,6 +194,16 @@ func (m *AwsManager) getAsgs() []*asg {
 	return m.asgCache.Get()
 }
 
+// getAsgByName returns the registered ASG with the given name, or nil if there is none.
+func (m *AwsManager) getAsgByName(name string) *asg {
+	for _, asg := range m.asgCache.Get() {
+		if asg.Name == name {
+			return asg
+		}
+	}
+	return nil
+}
+
 // SetAsgSize sets ASG size.
 func (m *AwsManager) SetAsgSize(asg *asg, size int) error {
 	return m.asgCache.SetAsgSize(asg, size)
@@ -194,30 +231,64 @@ func (m *AwsManager) getAsgTemplate(asg *asg) (*asgTemplate, error) {
 		klog.Warningf("Found multiple availability zones for ASG %q; using %s\n", asg.Name, az)
 	}
 
-	instanceTypeName, err := m.buildInstanceType(asg)
+	spec, err := m.buildLaunchSpec(asg)
 	if err != nil {
 		return nil, err
 	}
 
-	if t, ok := InstanceTypes[instanceTypeName]; ok {
+	if t, ok := InstanceTypes[spec.InstanceType]; ok {
 		return &asgTemplate{
 			InstanceType: t,
 			Region:       region,
 			Zone:         az,
 			Tags:         asg.Tags,
+			Spot:         spec.Spot,
 		}, nil
 	}
-	return nil, fmt.Errorf("ASG %q uses the unknown EC2 instance type %q", asg.Name, instanceTypeName)
+	return nil, fmt.Errorf("ASG %q uses the unknown EC2 instance type %q", asg.Name, spec.InstanceType)
 }
 
 func (m *AwsManager) buildInstanceType(asg *asg) (string, error) {
+	spec, err := m.buildLaunchSpec(asg)
+	if err != nil {
+		return "", err
+	}
+	return spec.InstanceType, nil
+}
+
+// buildLaunchSpec returns the launch configuration or template of the ASG. Results are cached
+// until the next refresh.
+func (m *AwsManager) buildLaunchSpec(asg *asg) (*launchSpec, error) {
+	var key string
+	var fetch func() (*launchSpec, error)
 	if asg.LaunchConfigurationName != "" {
-		return m.autoScalingService.getInstanceTypeByLCName(asg.LaunchConfigurationName)
+		key = "lc/" + asg.LaunchConfigurationName
+		fetch = func() (*launchSpec, error) {
+			return m.autoScalingService.getLaunchSpecByLCName(asg.LaunchConfigurationName)
+		}
 	} else if asg.LaunchTemplateName != "" && asg.LaunchTemplateVersion != "" {
-		return m.ec2Service.getInstanceTypeByLT(asg.LaunchTemplateName, asg.LaunchTemplateVersion)
+		key = "lt/" + asg.LaunchTemplateName + "/" + asg.LaunchTemplateVersion
+		fetch = func() (*launchSpec, error) {
+			return m.ec2Service.getLaunchSpecByLT(asg.LaunchTemplateName, asg.LaunchTemplateVersion)
+		}
+	} else {
+		return nil, errors.New("Unable to get instance type from launch config or launch template")
 	}
 
-	return "", errors.New("Unable to get instance type from launch config or launch template")
+	m.launchSpecsMutex.Lock()
+	defer m.launchSpecsMutex.Unlock()
+	if spec, found := m.launchSpecs[key]; found {
+		return spec, nil
+	}
+	spec, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if m.launchSpecs == nil {
+		m.launchSpecs = make(map[string]*launchSpec)
+	}
+	m.launchSpecs[key] = spec
+	return spec, nil
 }
 
 func (m *AwsManager) buildNodeFromTemplate(asg *asg, template *asgTemplate) (*apiv1.Node, error) {
@@ -228,6 +299,8 @@ func (m *AwsManager) buildNodeFromTemplate(asg *asg, template *asgTemplate) (*ap
 		Name:     nodeName,
 		SelfLink: fmt.Sprintf("/api/v1/nodes/%s", nodeName),
 		Labels:   map[string]string{},
+		// Template nodes have no provider id, the annotation lets the price model find their ASG.
+		Annotations: map[string]string{asgNameAnnotation: asg.Name},
 	}
 
 	node.Status = apiv1.NodeStatus{
@@ -268,6 +341,82 @@ func buildGenericLabels(template *asgTemplate, nodeName string) map[string]strin
 	return result
 }
 
+// getAsgOptions returns autoscaling options overridden with ASG tags, or nil if the ASG
+// doesn't override any of them.
+func (m *AwsManager) getAsgOptions(asg *asg, defaults config.NodeGroupAutoscalingOptions) *config.NodeGroupAutoscalingOptions {
+	options := extractAutoscalingOptionsFromAsg(asg.Tags)
+	if len(options) == 0 {
+		return nil
+	}
+
+	if stringOpt, found := options[autoscalingOptionScaleDownUtilizationThreshold]; found {
+		if opt, err := strconv.ParseFloat(stringOpt, 64); err != nil {
+			klog.Warningf("failed to convert asg %s %s tag to float: %v", asg.Name, autoscalingOptionScaleDownUtilizationThreshold, err)
+		} else {
+			defaults.ScaleDownUtilizationThreshold = opt
+		}
+	}
+	for name, target := range map[string]*time.Duration{
+		autoscalingOptionScaleDownUnneededTime: &defaults.ScaleDownUnneededTime,
+		autoscalingOptionScaleDownUnreadyTime:  &defaults.ScaleDownUnreadyTime,
+		autoscalingOptionMaxNodeProvisionTime:  &defaults.MaxNodeProvisionTime,
+	} {
+		if stringOpt, found := options[name]; found {
+			if opt, err := time.ParseDuration(stringOpt); err != nil {
+				klog.Warningf("failed to convert asg %s %s tag to duration: %v", asg.Name, name, err)
+			} else {
+				*target = opt
+			}
+		}
+	}
+	if stringOpt, found := options[autoscalingOptionScaleDownAllowedWindows]; found {
+		if opt, err := asg.scaleDownAllowedWindows.Parse(stringOpt); err != nil {
+			klog.Warningf("failed to parse asg %s %s tag: %v", asg.Name, autoscalingOptionScaleDownAllowedWindows, err)
+		} else {
+			defaults.ScaleDownAllowedWindows = stringOpt
+			defaults.ScaleDownAllowedSchedule = opt
+		}
+	}
+	if stringOpt, found := options[autoscalingOptionHeadroomCPU]; found {
+		if opt, err := resource.ParseQuantity(stringOpt); err != nil {
+			klog.Warningf("failed to convert asg %s %s tag to quantity: %v", asg.Name, autoscalingOptionHeadroomCPU, err)
+		} else {
+			defaults.Headroom.MilliCPU = opt.MilliValue()
+		}
+	}
+	if stringOpt, found := options[autoscalingOptionHeadroomMemory]; found {
+		if opt, err := resource.ParseQuantity(stringOpt); err != nil {
+			klog.Warningf("failed to convert asg %s %s tag to quantity: %v", asg.Name, autoscalingOptionHeadroomMemory, err)
+		} else {
+			defaults.Headroom.Memory = opt.Value()
+		}
+	}
+	if stringOpt, found := options[autoscalingOptionHeadroomNodes]; found {
+		if opt, err := strconv.Atoi(stringOpt); err != nil {
+			klog.Warningf("failed to convert asg %s %s tag to int: %v", asg.Name, autoscalingOptionHeadroomNodes, err)
+		} else {
+			defaults.Headroom.Nodes = opt
+		}
+	}
+
+	return &defaults
+}
+
+func extractAutoscalingOptionsFromAsg(tags []*autoscaling.TagDescription) map[string]string {
+	options := make(map[string]string)
+	for _, tag := range tags {
+		if !strings.HasPrefix(aws.StringValue(tag.Key), autoscalingOptionsTagPrefix) {
+			continue
+		}
+		splits := strings.Split(aws.StringValue(tag.Key), autoscalingOptionsTagPrefix)
+		if len(splits) != 2 || splits[1] == "" {
+			continue
+		}
+		options[splits[1]] = aws.StringValue(tag.Value)
+	}
+	return options
+}
+
 func extractLabelsFromAsg(tags []*autoscaling.TagDescription) map[string]string {
 	result := make(map[string]string)
 
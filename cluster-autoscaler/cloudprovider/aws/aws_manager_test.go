@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
@@ -34,6 +35,8 @@ import (
 	"github.com/stretchr/testify/mock"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
 )
 
@@ -97,6 +100,55 @@ func TestExtractLabelsFromAsg(t *testing.T) {
 	assert.Equal(t, "bar", labels["foo"])
 }
 
+func TestGetAsgOptions(t *testing.T) {
+	defaults := config.NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.5,
+		ScaleDownUnneededTime:         10 * time.Minute,
+		ScaleDownUnreadyTime:          20 * time.Minute,
+		MaxNodeProvisionTime:          15 * time.Minute,
+	}
+	m := &AwsManager{}
+
+	options := m.getAsgOptions(&asg{Tags: []*autoscaling.TagDescription{
+		{Key: aws.String("k8s.io/cluster-autoscaler/node-template/label/foo"), Value: aws.String("bar")},
+	}}, defaults)
+	assert.Nil(t, options)
+
+	options = m.getAsgOptions(&asg{Tags: []*autoscaling.TagDescription{
+		{Key: aws.String("k8s.io/cluster-autoscaler/node-template/autoscaling-options/scaledownutilizationthreshold"), Value: aws.String("0.7")},
+		{Key: aws.String("k8s.io/cluster-autoscaler/node-template/autoscaling-options/scaledownunneededtime"), Value: aws.String("1h")},
+		{Key: aws.String("k8s.io/cluster-autoscaler/node-template/autoscaling-options/maxnodeprovisiontime"), Value: aws.String("not-a-duration")},
+		{Key: aws.String("k8s.io/cluster-autoscaler/node-template/autoscaling-options/headroomcpu"), Value: aws.String("1500m")},
+		{Key: aws.String("k8s.io/cluster-autoscaler/node-template/autoscaling-options/headroommemory"), Value: aws.String("1Gi")},
+		{Key: aws.String("k8s.io/cluster-autoscaler/node-template/autoscaling-options/headroomnodes"), Value: aws.String("one")},
+	}}, defaults)
+	assert.Equal(t, &config.NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.7,
+		ScaleDownUnneededTime:         time.Hour,
+		ScaleDownUnreadyTime:          20 * time.Minute,
+		MaxNodeProvisionTime:          15 * time.Minute,
+		Headroom: config.Headroom{
+			MilliCPU: 1500,
+			Memory:   1024 * 1024 * 1024,
+		},
+	}, options)
+
+	// Scale down windows are parsed once per ASG, invalid windows are ignored.
+	windowsAsg := &asg{Tags: []*autoscaling.TagDescription{
+		{Key: aws.String("k8s.io/cluster-autoscaler/node-template/autoscaling-options/scaledownallowedwindows"), Value: aws.String("0 22 * * 1-5 10h")},
+	}}
+	options = m.getAsgOptions(windowsAsg, defaults)
+	windows, err := schedule.Parse("0 22 * * 1-5 10h")
+	assert.NoError(t, err)
+	assert.Equal(t, "0 22 * * 1-5 10h", options.ScaleDownAllowedWindows)
+	assert.Equal(t, windows, options.ScaleDownAllowedSchedule)
+	assert.True(t, options.ScaleDownAllowedSchedule == m.getAsgOptions(windowsAsg, defaults).ScaleDownAllowedSchedule)
+	options = m.getAsgOptions(&asg{Tags: []*autoscaling.TagDescription{
+		{Key: aws.String("k8s.io/cluster-autoscaler/node-template/autoscaling-options/scaledownallowedwindows"), Value: aws.String("0 22 * * *")},
+	}}, defaults)
+	assert.Equal(t, &defaults, options)
+}
+
 func TestExtractTaintsFromAsg(t *testing.T) {
 	tags := []*autoscaling.TagDescription{
 		{
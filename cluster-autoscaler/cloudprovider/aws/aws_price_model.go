@@ -0,0 +1,357 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
+	"k8s.io/klog"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+)
+
+// SpotPriceSource provides current prices of spot instances.
+type SpotPriceSource interface {
+	// SpotPrice returns the hourly price of a spot instance of the given type in the given
+	// availability zone, in USD.
+	SpotPrice(instanceType string, availabilityZone string) (float64, error)
+}
+
+// AwsPriceModel implements PriceModel interface for AWS.
+type AwsPriceModel struct {
+	awsManager      *AwsManager
+	priceOverrides  cloudprovider.InstancePrices
+	spotPriceSource SpotPriceSource
+}
+
+const (
+	// Prices of a vCPU and of a GiB of memory, used for pods and for instance types without a
+	// known price. They are derived from on-demand prices of m5, c5 and r5 instances in us-east-1.
+	cpuPricePerHour         = 0.037
+	memoryPricePerHourPerGb = 0.00275
+	gpuPricePerHour         = 0.6
+
+	spotPriceCacheTTL = time.Hour
+)
+
+var resourcePrices = cloudprovider.ResourcePrices{
+	CpuPerHour:         cpuPricePerHour,
+	MemoryPerGbPerHour: memoryPricePerHourPerGb,
+	GpuPerHour:         gpuPricePerHour,
+	GpuResource:        gpu.ResourceNvidiaGPU,
+}
+
+// onDemandPrices are hourly prices of Linux on-demand instances in us-east-1, in USD. They are used
+// for all regions unless overridden with the --instance-prices-file flag. Keys are the same as in
+// InstanceTypes, which also lists Dedicated Host families.
+var onDemandPrices = map[string]float64{
+	"c1.medium":    0.130,
+	"c1.xlarge":    0.520,
+	"c3.large":     0.105,
+	"c3.xlarge":    0.210,
+	"c3.2xlarge":   0.420,
+	"c3.4xlarge":   0.840,
+	"c3.8xlarge":   1.680,
+	"c4.large":     0.100,
+	"c4.xlarge":    0.199,
+	"c4.2xlarge":   0.398,
+	"c4.4xlarge":   0.796,
+	"c4.8xlarge":   1.591,
+	"c5.large":     0.085,
+	"c5.xlarge":    0.170,
+	"c5.2xlarge":   0.340,
+	"c5.4xlarge":   0.680,
+	"c5.9xlarge":   1.530,
+	"c5.18xlarge":  3.060,
+	"c5d.large":    0.096,
+	"c5d.xlarge":   0.192,
+	"c5d.2xlarge":  0.384,
+	"c5d.4xlarge":  0.768,
+	"c5d.9xlarge":  1.728,
+	"c5d.18xlarge": 3.456,
+	"cc2.8xlarge":  2.000,
+	"cr1.8xlarge":  3.500,
+	"d2.xlarge":    0.690,
+	"d2.2xlarge":   1.380,
+	"d2.4xlarge":   2.760,
+	"d2.8xlarge":   5.520,
+	"f1.2xlarge":   1.650,
+	"f1.4xlarge":   3.300,
+	"f1.16xlarge":  13.200,
+	"g2.2xlarge":   0.650,
+	"g2.8xlarge":   2.600,
+	"g3.4xlarge":   1.140,
+	"g3.8xlarge":   2.280,
+	"g3.16xlarge":  4.560,
+	"g3s.xlarge":   0.750,
+	"h1.2xlarge":   0.468,
+	"h1.4xlarge":   0.936,
+	"h1.8xlarge":   1.872,
+	"h1.16xlarge":  3.744,
+	"hs1.8xlarge":  4.600,
+	"i2.xlarge":    0.853,
+	"i2.2xlarge":   1.705,
+	"i2.4xlarge":   3.410,
+	"i2.8xlarge":   6.820,
+	"i3.large":     0.156,
+	"i3.xlarge":    0.312,
+	"i3.2xlarge":   0.624,
+	"i3.4xlarge":   1.248,
+	"i3.8xlarge":   2.496,
+	"i3.16xlarge":  4.992,
+	"i3.metal":     4.992,
+	"m1.small":     0.044,
+	"m1.medium":    0.087,
+	"m1.large":     0.175,
+	"m1.xlarge":    0.350,
+	"m2.xlarge":    0.245,
+	"m2.2xlarge":   0.490,
+	"m2.4xlarge":   0.980,
+	"m3.medium":    0.067,
+	"m3.large":     0.133,
+	"m3.xlarge":    0.266,
+	"m3.2xlarge":   0.532,
+	"m4.large":     0.100,
+	"m4.xlarge":    0.200,
+	"m4.2xlarge":   0.400,
+	"m4.4xlarge":   0.800,
+	"m4.10xlarge":  2.000,
+	"m4.16xlarge":  3.200,
+	"m5.large":     0.096,
+	"m5.xlarge":    0.192,
+	"m5.2xlarge":   0.384,
+	"m5.4xlarge":   0.768,
+	"m5.12xlarge":  2.304,
+	"m5.24xlarge":  4.608,
+	"m5a.large":    0.086,
+	"m5a.xlarge":   0.172,
+	"m5a.2xlarge":  0.344,
+	"m5a.4xlarge":  0.688,
+	"m5a.12xlarge": 2.064,
+	"m5a.24xlarge": 4.128,
+	"m5d.large":    0.113,
+	"m5d.xlarge":   0.226,
+	"m5d.2xlarge":  0.452,
+	"m5d.4xlarge":  0.904,
+	"m5d.12xlarge": 2.712,
+	"m5d.24xlarge": 5.424,
+	"p2.xlarge":    0.900,
+	"p2.8xlarge":   7.200,
+	"p2.16xlarge":  14.400,
+	"p3.2xlarge":   3.060,
+	"p3.8xlarge":   12.240,
+	"p3.16xlarge":  24.480,
+	"r3.large":     0.166,
+	"r3.xlarge":    0.333,
+	"r3.2xlarge":   0.665,
+	"r3.4xlarge":   1.330,
+	"r3.8xlarge":   2.660,
+	"r4.large":     0.133,
+	"r4.xlarge":    0.266,
+	"r4.2xlarge":   0.532,
+	"r4.4xlarge":   1.064,
+	"r4.8xlarge":   2.128,
+	"r4.16xlarge":  4.256,
+	"r5.large":     0.126,
+	"r5.xlarge":    0.252,
+	"r5.2xlarge":   0.504,
+	"r5.4xlarge":   1.008,
+	"r5.12xlarge":  3.024,
+	"r5.24xlarge":  6.048,
+	"r5a.large":    0.113,
+	"r5a.xlarge":   0.226,
+	"r5a.2xlarge":  0.452,
+	"r5a.4xlarge":  0.904,
+	"r5a.12xlarge": 2.712,
+	"r5a.24xlarge": 5.424,
+	"r5d.large":    0.144,
+	"r5d.xlarge":   0.288,
+	"r5d.2xlarge":  0.576,
+	"r5d.4xlarge":  1.152,
+	"r5d.12xlarge": 3.456,
+	"r5d.24xlarge": 6.912,
+	"t1.micro":     0.020,
+	"t2.nano":      0.0058,
+	"t2.micro":     0.0116,
+	"t2.small":     0.023,
+	"t2.medium":    0.0464,
+	"t2.large":     0.0928,
+	"t2.xlarge":    0.1856,
+	"t2.2xlarge":   0.3712,
+	"t3.nano":      0.0052,
+	"t3.micro":     0.0104,
+	"t3.small":     0.0208,
+	"t3.medium":    0.0416,
+	"t3.large":     0.0832,
+	"t3.xlarge":    0.1664,
+	"t3.2xlarge":   0.3328,
+	"x1.16xlarge":  6.669,
+	"x1.32xlarge":  13.338,
+	"x1e.xlarge":   0.834,
+	"x1e.2xlarge":  1.668,
+	"x1e.4xlarge":  3.336,
+	"x1e.8xlarge":  6.672,
+	"x1e.16xlarge": 13.344,
+	"x1e.32xlarge": 26.688,
+	"z1d.large":    0.186,
+	"z1d.xlarge":   0.372,
+	"z1d.2xlarge":  0.744,
+	"z1d.3xlarge":  1.116,
+	"z1d.6xlarge":  2.232,
+	"z1d.12xlarge": 4.464,
+	// Dedicated Hosts, priced per host.
+	"c3":      1.848,
+	"c4":      1.750,
+	"c5":      3.366,
+	"c5d":     3.802,
+	"d2":      6.072,
+	"f1":      14.520,
+	"g2":      2.860,
+	"g3":      5.016,
+	"h1":      4.118,
+	"i2":      7.502,
+	"i3":      5.491,
+	"m3":      2.341,
+	"m4":      2.420,
+	"m5":      5.069,
+	"m5d":     5.966,
+	"p2":      15.840,
+	"p3":      26.928,
+	"r3":      2.926,
+	"r4":      4.682,
+	"r5":      6.653,
+	"r5d":     7.603,
+	"u-12tb1": 92.800,
+	"u-6tb1":  46.400,
+	"u-9tb1":  69.600,
+	"x1":      14.672,
+	"x1e":     29.357,
+	"z1d":     4.910,
+}
+
+// NewAwsPriceModel builds a price model using prices bundled with Cluster Autoscaler, overridden
+// with priceOverrides. Spot instances are priced with spotPriceSource if it is not nil and with
+// on-demand prices otherwise.
+func NewAwsPriceModel(awsManager *AwsManager, priceOverrides cloudprovider.InstancePrices, spotPriceSource SpotPriceSource) *AwsPriceModel {
+	return &AwsPriceModel{
+		awsManager:      awsManager,
+		priceOverrides:  priceOverrides,
+		spotPriceSource: spotPriceSource,
+	}
+}
+
+// NodePrice returns a price of running the given node for a given period of time.
+// All prices are in USD.
+func (model *AwsPriceModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	hours := cloudprovider.PriceHours(startTime, endTime)
+	instanceType, region, zone, spot := model.nodeInstance(node)
+	if instanceType != "" {
+		if spot && model.spotPriceSource != nil {
+			spotPrice, err := model.spotPriceSource.SpotPrice(instanceType, zone)
+			if err == nil {
+				return spotPrice * hours, nil
+			}
+			klog.Warningf("Failed to get spot price of %s in %s, using on-demand price: %v", instanceType, zone, err)
+		}
+		if price, found := model.onDemandPrice(region, instanceType); found {
+			return price * hours, nil
+		}
+	}
+	return resourcePrices.Price(node.Status.Capacity, startTime, endTime), nil
+}
+
+// nodeInstance returns the instance type, region and availability zone of the node, and whether
+// it's a spot instance. They are read from the template of the node's ASG, found by the provider
+// id of the node or the annotation of template nodes, and from node labels if there is no ASG.
+func (model *AwsPriceModel) nodeInstance(node *apiv1.Node) (instanceType, region, zone string, spot bool) {
+	if model.awsManager != nil {
+		var asg *asg
+		if node.Spec.ProviderID != "" {
+			if ref, err := AwsRefFromProviderId(node.Spec.ProviderID); err == nil {
+				asg = model.awsManager.GetAsgForInstance(*ref)
+			}
+		} else if asgName, found := node.Annotations[asgNameAnnotation]; found {
+			asg = model.awsManager.getAsgByName(asgName)
+		}
+		if asg != nil {
+			template, err := model.awsManager.getAsgTemplate(asg)
+			if err == nil {
+				return template.InstanceType.InstanceType, template.Region, template.Zone, template.Spot
+			}
+			klog.Warningf("Failed to get template of ASG %s for pricing: %v", asg.Name, err)
+		}
+	}
+	return node.Labels[kubeletapis.LabelInstanceType], node.Labels[kubeletapis.LabelZoneRegion], node.Labels[kubeletapis.LabelZoneFailureDomain], false
+}
+
+func (model *AwsPriceModel) onDemandPrice(region, instanceType string) (float64, bool) {
+	if price, found := model.priceOverrides.Price(region, instanceType); found {
+		return price, true
+	}
+	price, found := onDemandPrices[instanceType]
+	return price, found
+}
+
+// PodPrice returns a theoretical minimum price of running a pod for a given
+// period of time on a perfectly matching machine.
+func (model *AwsPriceModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	price := 0.0
+	for _, container := range pod.Spec.Containers {
+		price += resourcePrices.Price(container.Resources.Requests, startTime, endTime)
+	}
+	return price, nil
+}
+
+type cachedSpotPrice struct {
+	price     float64
+	fetchTime time.Time
+}
+
+// ec2SpotPriceSource gets spot prices from the EC2 spot price history and caches them for an hour.
+type ec2SpotPriceSource struct {
+	ec2Service ec2Wrapper
+	mutex      sync.Mutex
+	cache      map[string]cachedSpotPrice
+}
+
+// NewEc2SpotPriceSource builds a SpotPriceSource using the EC2 DescribeSpotPriceHistory API.
+func NewEc2SpotPriceSource(awsManager *AwsManager) SpotPriceSource {
+	return &ec2SpotPriceSource{
+		ec2Service: awsManager.ec2Service,
+		cache:      make(map[string]cachedSpotPrice),
+	}
+}
+
+// SpotPrice returns the hourly price of a spot instance of the given type in the given
+// availability zone, in USD.
+func (s *ec2SpotPriceSource) SpotPrice(instanceType string, availabilityZone string) (float64, error) {
+	key := instanceType + "/" + availabilityZone
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if cached, found := s.cache[key]; found && time.Since(cached.fetchTime) < spotPriceCacheTTL {
+		return cached.price, nil
+	}
+	price, err := s.ec2Service.getSpotPrice(instanceType, availabilityZone)
+	if err != nil {
+		return 0, err
+	}
+	s.cache[key] = cachedSpotPrice{price: price, fetchTime: time.Now()}
+	return price, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+)
+
+func TestOnDemandPricesHaveKnownInstanceTypes(t *testing.T) {
+	for instanceType := range onDemandPrices {
+		_, found := InstanceTypes[instanceType]
+		assert.True(t, found, "unknown instance type %s", instanceType)
+	}
+}
+
+func TestInstanceTypesHaveOnDemandPrices(t *testing.T) {
+	for instanceType := range InstanceTypes {
+		_, found := onDemandPrices[instanceType]
+		assert.True(t, found, "no on-demand price of instance type %s", instanceType)
+	}
+}
+
+func mockLaunchTemplate(s *EC2Mock, name, instanceType string, spot bool) {
+	data := &ec2.ResponseLaunchTemplateData{InstanceType: aws.String(instanceType)}
+	if spot {
+		data.InstanceMarketOptions = &ec2.LaunchTemplateInstanceMarketOptions{MarketType: aws.String(ec2.MarketTypeSpot)}
+	}
+	s.On("DescribeLaunchTemplateVersions", &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateName: aws.String(name),
+		Versions:           []*string{aws.String("1")},
+	}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+		LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{{LaunchTemplateData: data}},
+	})
+}
+
+func TestAwsPriceModelNodePrice(t *testing.T) {
+	s := &EC2Mock{}
+	mockLaunchTemplate(s, "on-demand", "m5.large", false)
+	mockLaunchTemplate(s, "spot", "c5.large", true)
+	s.On("DescribeSpotPriceHistory", mock.AnythingOfType("*ec2.DescribeSpotPriceHistoryInput")).Return(&ec2.DescribeSpotPriceHistoryOutput{
+		SpotPriceHistory: []*ec2.SpotPrice{{SpotPrice: aws.String("0.03")}},
+	})
+
+	m, err := createAWSManagerInternal(nil, cloudprovider.NodeGroupDiscoveryOptions{}, nil, &ec2Wrapper{s})
+	assert.NoError(t, err)
+	onDemandAsg := m.asgCache.register(&asg{
+		AwsRef:                AwsRef{Name: "on-demand"},
+		AvailabilityZones:     []string{"us-west-2a"},
+		LaunchTemplateName:    "on-demand",
+		LaunchTemplateVersion: "1",
+	})
+	spotAsg := m.asgCache.register(&asg{
+		AwsRef:                AwsRef{Name: "spot"},
+		AvailabilityZones:     []string{"us-west-2a"},
+		LaunchTemplateName:    "spot",
+		LaunchTemplateVersion: "1",
+	})
+	instance := AwsInstanceRef{ProviderID: "aws:///us-west-2a/i-1", Name: "i-1"}
+	m.asgCache.instanceToAsg[instance] = onDemandAsg
+
+	overrides := cloudprovider.InstancePrices{"us-west-2": {"m5.large": 0.1}}
+	model := NewAwsPriceModel(m, overrides, NewEc2SpotPriceSource(m))
+	now := time.Now()
+	later := now.Add(2 * time.Hour)
+
+	// Node found by its provider id, priced with the override for its region.
+	node := BuildTestNode("n1", 2000, 8*1024*1024*1024)
+	node.Spec.ProviderID = instance.ProviderID
+	price, err := model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.2, price, 1e-9)
+
+	// Template nodes are found by the ASG annotation. Spot prices are cached.
+	template, err := m.getAsgTemplate(spotAsg)
+	assert.NoError(t, err)
+	assert.True(t, template.Spot)
+	templateNode, err := m.buildNodeFromTemplate(spotAsg, template)
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		price, err = model.NodePrice(templateNode, now, later)
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.06, price, 1e-9)
+	}
+	s.AssertNumberOfCalls(t, "DescribeSpotPriceHistory", 1)
+
+	// Launch templates are cached until the next refresh.
+	s.AssertNumberOfCalls(t, "DescribeLaunchTemplateVersions", 2)
+
+	// Nodes without an ASG are priced by their instance type label.
+	node = BuildTestNode("n2", 2000, 4*1024*1024*1024)
+	node.Labels = map[string]string{kubeletapis.LabelInstanceType: "c5.large"}
+	price, err = model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.17, price, 1e-9)
+
+	// Nodes with an unknown instance type are priced by their capacity.
+	node.Labels[kubeletapis.LabelInstanceType] = "unknown.large"
+	price, err = model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*(2*cpuPricePerHour+4*memoryPricePerHourPerGb), price, 1e-9)
+}
+
+func TestAwsPriceModelPodPrice(t *testing.T) {
+	model := NewAwsPriceModel(nil, nil, nil)
+	now := time.Now()
+	pod := BuildTestPod("p1", 500, 2*1024*1024*1024)
+	price, err := model.PodPrice(pod, now, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5*cpuPricePerHour+2*memoryPricePerHourPerGb, price, 1e-9)
+
+	// Pods are cheaper than the nodes they fit on.
+	node := BuildTestNode("n1", 2000, 8*1024*1024*1024)
+	node.Labels = map[string]string{kubeletapis.LabelInstanceType: "m5.large"}
+	nodePrice, err := model.NodePrice(node, now, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, price < nodePrice)
+
+	// Nodes without labels and capacity cost nothing.
+	nodePrice, err = model.NodePrice(&apiv1.Node{}, now, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, nodePrice)
+}
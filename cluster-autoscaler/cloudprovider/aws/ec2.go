@@ -18,6 +18,8 @@ package aws
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -25,13 +27,20 @@ import (
 
 type ec2I interface {
 	DescribeLaunchTemplateVersions(input *ec2.DescribeLaunchTemplateVersionsInput) (*ec2.DescribeLaunchTemplateVersionsOutput, error)
+	DescribeSpotPriceHistory(input *ec2.DescribeSpotPriceHistoryInput) (*ec2.DescribeSpotPriceHistoryOutput, error)
 }
 
 type ec2Wrapper struct {
 	ec2I
 }
 
-func (m ec2Wrapper) getInstanceTypeByLT(name string, version string) (string, error) {
+// launchSpec describes instances launched by a launch configuration or template.
+type launchSpec struct {
+	InstanceType string
+	Spot         bool
+}
+
+func (m ec2Wrapper) getLaunchSpecByLT(name string, version string) (*launchSpec, error) {
 	params := &ec2.DescribeLaunchTemplateVersionsInput{
 		LaunchTemplateName: aws.String(name),
 		Versions:           []*string{aws.String(version)},
@@ -39,19 +48,45 @@ func (m ec2Wrapper) getInstanceTypeByLT(name string, version string) (string, er
 
 	describeData, err := m.DescribeLaunchTemplateVersions(params)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(describeData.LaunchTemplateVersions) == 0 {
-		return "", fmt.Errorf("Unable to find template versions")
+		return nil, fmt.Errorf("Unable to find template versions")
 	}
 
 	lt := describeData.LaunchTemplateVersions[0]
 	instanceType := lt.LaunchTemplateData.InstanceType
 
 	if instanceType == nil {
-		return "", fmt.Errorf("Unable to find instance type within launch template")
+		return nil, fmt.Errorf("Unable to find instance type within launch template")
 	}
 
-	return aws.StringValue(instanceType), nil
+	spot := false
+	if marketOptions := lt.LaunchTemplateData.InstanceMarketOptions; marketOptions != nil {
+		spot = aws.StringValue(marketOptions.MarketType) == ec2.MarketTypeSpot
+	}
+	return &launchSpec{
+		InstanceType: aws.StringValue(instanceType),
+		Spot:         spot,
+	}, nil
+}
+
+// getSpotPrice returns the current spot price of Linux instances of the given type in the given
+// availability zone.
+func (m ec2Wrapper) getSpotPrice(instanceType string, availabilityZone string) (float64, error) {
+	params := &ec2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       []*string{aws.String(instanceType)},
+		AvailabilityZone:    aws.String(availabilityZone),
+		ProductDescriptions: []*string{aws.String("Linux/UNIX")},
+		StartTime:           aws.Time(time.Now()),
+	}
+	history, err := m.DescribeSpotPriceHistory(params)
+	if err != nil {
+		return 0, err
+	}
+	if len(history.SpotPriceHistory) == 0 {
+		return 0, fmt.Errorf("No spot price for %s in %s", instanceType, availabilityZone)
+	}
+	return strconv.ParseFloat(aws.StringValue(history.SpotPriceHistory[0].SpotPrice), 64)
 }
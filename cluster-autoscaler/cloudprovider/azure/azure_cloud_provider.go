@@ -38,13 +38,19 @@ const (
 // AzureCloudProvider provides implementation of CloudProvider interface for Azure.
 type AzureCloudProvider struct {
 	azureManager    *AzureManager
+	pricingModel    *AzurePriceModel
 	resourceLimiter *cloudprovider.ResourceLimiter
 }
 
 // BuildAzureCloudProvider creates new AzureCloudProvider
 func BuildAzureCloudProvider(azureManager *AzureManager, resourceLimiter *cloudprovider.ResourceLimiter) (cloudprovider.CloudProvider, error) {
+	return buildAzureCloudProvider(azureManager, NewAzurePriceModel(nil), resourceLimiter)
+}
+
+func buildAzureCloudProvider(azureManager *AzureManager, pricingModel *AzurePriceModel, resourceLimiter *cloudprovider.ResourceLimiter) (cloudprovider.CloudProvider, error) {
 	azure := &AzureCloudProvider{
 		azureManager:    azureManager,
+		pricingModel:    pricingModel,
 		resourceLimiter: resourceLimiter,
 	}
 
@@ -85,7 +91,7 @@ func (azure *AzureCloudProvider) NodeGroupForNode(node *apiv1.Node) (cloudprovid
 
 // Pricing returns pricing model for this cloud provider or error if not available.
 func (azure *AzureCloudProvider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError) {
-	return nil, cloudprovider.ErrNotImplemented
+	return azure.pricingModel, nil
 }
 
 // GetAvailableMachineTypes get all machine types that can be requested from the cloud provider.
@@ -144,7 +150,16 @@ func BuildAzure(opts config.AutoscalingOptions, do cloudprovider.NodeGroupDiscov
 	if err != nil {
 		klog.Fatalf("Failed to create Azure Manager: %v", err)
 	}
-	provider, err := BuildAzureCloudProvider(manager, rl)
+
+	var priceOverrides cloudprovider.InstancePrices
+	if opts.InstancePricesFile != "" {
+		priceOverrides, err = cloudprovider.ReadInstancePrices(opts.InstancePricesFile)
+		if err != nil {
+			klog.Fatalf("Couldn't read instance prices %s: %v", opts.InstancePricesFile, err)
+		}
+	}
+
+	provider, err := buildAzureCloudProvider(manager, NewAzurePriceModel(priceOverrides), rl)
 	if err != nil {
 		klog.Fatalf("Failed to create Azure cloud provider: %v", err)
 	}
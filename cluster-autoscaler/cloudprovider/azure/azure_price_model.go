@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+)
+
+// AzurePriceModel implements PriceModel interface for Azure.
+type AzurePriceModel struct {
+	priceOverrides cloudprovider.InstancePrices
+}
+
+const (
+	// Prices of a vCPU, of a GiB of memory and of a GPU. They are used to price instance types
+	// from InstanceTypes without a price in the price file, nodes of unknown instance types and
+	// pods. They are derived from pay-as-you-go Linux prices of Dsv3 and NC VMs in East US.
+	cpuPricePerHour         = 0.032
+	memoryPricePerHourPerGb = 0.004
+	gpuPricePerHour         = 0.5
+)
+
+var resourcePrices = cloudprovider.ResourcePrices{
+	CpuPerHour:         cpuPricePerHour,
+	MemoryPerGbPerHour: memoryPricePerHourPerGb,
+	GpuPerHour:         gpuPricePerHour,
+	GpuResource:        gpu.ResourceNvidiaGPU,
+}
+
+// NewAzurePriceModel builds a price model pricing VM sizes with priceOverrides, keyed by region
+// and VM size, and with their resources from InstanceTypes otherwise.
+func NewAzurePriceModel(priceOverrides cloudprovider.InstancePrices) *AzurePriceModel {
+	return &AzurePriceModel{
+		priceOverrides: priceOverrides,
+	}
+}
+
+// NodePrice returns a price of running the given node for a given period of time.
+// All prices are in USD.
+func (model *AzurePriceModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	hours := cloudprovider.PriceHours(startTime, endTime)
+	vmSize := node.Labels[kubeletapis.LabelInstanceType]
+	if vmSize != "" {
+		if price, found := model.priceOverrides.Price(node.Labels[kubeletapis.LabelZoneRegion], vmSize); found {
+			return price * hours, nil
+		}
+		if instanceType, found := InstanceTypes[vmSize]; found {
+			price := resourcePrices.InstancePrice(instanceType.VCPU, float64(instanceType.MemoryMb)/1024.0, instanceType.GPU)
+			return price * hours, nil
+		}
+	}
+	return resourcePrices.Price(node.Status.Capacity, startTime, endTime), nil
+}
+
+// PodPrice returns a theoretical minimum price of running a pod for a given
+// period of time on a perfectly matching machine.
+func (model *AzurePriceModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	price := 0.0
+	for _, container := range pod.Spec.Containers {
+		price += resourcePrices.Price(container.Resources.Requests, startTime, endTime)
+	}
+	return price, nil
+}
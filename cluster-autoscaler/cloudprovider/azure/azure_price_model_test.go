@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+)
+
+func TestAzurePriceModelNodePrice(t *testing.T) {
+	overrides := cloudprovider.InstancePrices{
+		"eastus":                 {"Standard_D4s_v3": 0.192},
+		cloudprovider.AllRegions: {"Standard_D4s_v3": 0.25},
+	}
+	model := NewAzurePriceModel(overrides)
+	now := time.Now()
+	later := now.Add(2 * time.Hour)
+
+	// VM sizes are priced with the override for the region of the node.
+	node := BuildTestNode("n1", 4000, 16*1024*1024*1024)
+	node.Labels = map[string]string{
+		kubeletapis.LabelInstanceType: "Standard_D4s_v3",
+		kubeletapis.LabelZoneRegion:   "eastus",
+	}
+	price, err := model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.384, price, 1e-9)
+
+	// Overrides for all regions apply to regions without their own price.
+	node.Labels[kubeletapis.LabelZoneRegion] = "westeurope"
+	price, err = model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, price, 1e-9)
+
+	// VM sizes without a price are priced by their resources in InstanceTypes.
+	node.Labels[kubeletapis.LabelInstanceType] = "Standard_NC6"
+	price, err = model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*(6*cpuPricePerHour+56*memoryPricePerHourPerGb+gpuPricePerHour), price, 1e-9)
+
+	// Nodes of unknown VM sizes are priced by their capacity.
+	node.Labels[kubeletapis.LabelInstanceType] = "Standard_Unknown"
+	price, err = model.NodePrice(node, now, later)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*(4*cpuPricePerHour+16*memoryPricePerHourPerGb), price, 1e-9)
+}
+
+func TestAzurePriceModelPodPrice(t *testing.T) {
+	model := NewAzurePriceModel(nil)
+	now := time.Now()
+	pod := BuildTestPod("p1", 500, 2*1024*1024*1024)
+	price, err := model.PodPrice(pod, now, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5*cpuPricePerHour+2*memoryPricePerHourPerGb, price, 1e-9)
+
+	// Pods are cheaper than the nodes they fit on.
+	node := BuildTestNode("n1", 2000, 8*1024*1024*1024)
+	node.Labels = map[string]string{kubeletapis.LabelInstanceType: "Standard_D2s_v3"}
+	nodePrice, err := model.NodePrice(node, now, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, price < nodePrice)
+}
@@ -1,4 +1,4 @@
-// +build !gce,!aws,!azure,!kubemark,!alicloud,!openshiftmachineapi
+// +build !gce,!aws,!azure,!kubemark,!alicloud,!openshiftmachineapi,!externalgrpc
 
 /*
 Copyright 2018 The Kubernetes Authors.
@@ -23,6 +23,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/alicloud"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/aws"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/azure"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/externalgrpc"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/gce"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/gke"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/openshiftmachineapi"
@@ -37,6 +38,7 @@ var AvailableCloudProviders = []string{
 	gke.ProviderNameGKE,
 	alicloud.ProviderName,
 	openshiftmachineapi.ProviderName,
+	externalgrpc.ProviderName,
 }
 
 // DefaultCloudProvider is GCE.
@@ -55,7 +57,9 @@ func buildCloudProvider(opts config.AutoscalingOptions, do cloudprovider.NodeGro
 	case alicloud.ProviderName:
 		return alicloud.BuildAlicloud(opts, do, rl)
 	case openshiftmachineapi.ProviderName:
-		return openshiftmachineapi.BuildOpenShiftMachineAPI(opts, do, rl)
+		return openshiftmachineapi.BuildOpenShiftMachineAPI(opts, do, rl, openshiftMachineAPIInstanceTypes)
+	case externalgrpc.ProviderName:
+		return externalgrpc.BuildExternalGrpc(opts, do, rl)
 	}
 	return nil
 }
@@ -35,7 +35,7 @@ const DefaultCloudProvider = openshiftmachineapi.ProviderName
 func buildCloudProvider(opts config.AutoscalingOptions, do cloudprovider.NodeGroupDiscoveryOptions, rl *cloudprovider.ResourceLimiter) cloudprovider.CloudProvider {
 	switch opts.CloudProviderName {
 	case openshiftmachineapi.ProviderName:
-		return openshiftmachineapi.BuildOpenShiftMachineAPI(opts, do, rl)
+		return openshiftmachineapi.BuildOpenShiftMachineAPI(opts, do, rl, openshiftMachineAPIInstanceTypes)
 	}
 
 	return nil
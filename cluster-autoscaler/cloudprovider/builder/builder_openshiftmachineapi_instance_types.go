@@ -0,0 +1,45 @@
+// +build !gce,!aws,!azure,!kubemark,!alicloud,!externalgrpc
+
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/aws"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/azure"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/openshiftmachineapi"
+)
+
+// openshiftMachineAPIInstanceTypes looks up instance types named in the
+// providerSpecs of the AWS and Azure machine providers.
+func openshiftMachineAPIInstanceTypes(name string) (openshiftmachineapi.InstanceType, bool) {
+	if instanceType, found := aws.InstanceTypes[name]; found {
+		return openshiftmachineapi.InstanceType{
+			VCPU:     instanceType.VCPU,
+			MemoryMb: instanceType.MemoryMb,
+			GPU:      instanceType.GPU,
+		}, true
+	}
+	if instanceType, found := azure.InstanceTypes[name]; found {
+		return openshiftmachineapi.InstanceType{
+			VCPU:     instanceType.VCPU,
+			MemoryMb: instanceType.MemoryMb,
+			GPU:      instanceType.GPU,
+		}, true
+	}
+	return openshiftmachineapi.InstanceType{}, false
+}
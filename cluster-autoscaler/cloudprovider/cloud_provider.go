@@ -25,7 +25,9 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/klog"
 	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
 )
 
@@ -147,6 +149,27 @@ type NodeGroup interface {
 	// Autoprovisioned returns true if the node group is autoprovisioned. An autoprovisioned group
 	// was created by CA and can be deleted when scaled to 0.
 	Autoprovisioned() bool
+
+	// GetOptions returns NodeGroupAutoscalingOptions that should be used for this particular
+	// NodeGroup. Returning ErrNotImplemented will cause global defaults to be used.
+	// Implementation optional.
+	GetOptions(defaults config.NodeGroupAutoscalingOptions) (*config.NodeGroupAutoscalingOptions, error)
+}
+
+// GetNodeGroupOptions returns options that should be used for the given node group, falling back
+// to defaults if the node group doesn't provide its own.
+func GetNodeGroupOptions(nodeGroup NodeGroup, defaults config.NodeGroupAutoscalingOptions) config.NodeGroupAutoscalingOptions {
+	options, err := nodeGroup.GetOptions(defaults)
+	if err != nil {
+		if err != ErrNotImplemented {
+			klog.Warningf("Failed to get autoscaling options for node group %s, using defaults: %v", nodeGroup.Id(), err)
+		}
+		return defaults
+	}
+	if options == nil {
+		return defaults
+	}
+	return *options
 }
 
 // Instance represents a cloud-provider node. The node does not necessarily map to k8s node
@@ -0,0 +1,423 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalgrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/externalgrpc/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/klog"
+)
+
+const (
+	// ProviderName is the cloud provider name for the external gRPC cloud provider.
+	ProviderName = "externalgrpc"
+
+	defaultTimeout = 5 * time.Second
+)
+
+// cloudConfig is the configuration of the external gRPC cloud provider, read from the file passed
+// with the --cloud-config flag.
+type cloudConfig struct {
+	// Address of the gRPC server, e.g. "provisioner.kube-system:8086".
+	Address string `json:"address"`
+	// CACert is the path to the CA certificate used to verify the server. The connection doesn't
+	// use TLS if it is empty.
+	CACert string `json:"cacert"`
+	// Cert and Key are paths to the client certificate and key, used if the server requires client
+	// authentication.
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+	// Timeout of a single call, e.g. "10s". Defaults to 5s.
+	Timeout string `json:"timeout"`
+}
+
+// externalGrpcCloudProvider implements CloudProvider by proxying calls to an external gRPC server.
+// Responses are cached until the next Refresh, so each call is sent to the server at most once per
+// loop. Calls changing a node group invalidate its cached state.
+type externalGrpcCloudProvider struct {
+	client          protos.CloudProviderClient
+	timeout         time.Duration
+	resourceLimiter *cloudprovider.ResourceLimiter
+
+	mutex sync.Mutex
+	// nodeGroups is nil if node groups weren't listed since the last Refresh.
+	nodeGroups []cloudprovider.NodeGroup
+	// nodeGroupsById keeps node groups returned since the last Refresh, so that all methods return
+	// the same object, with its cached state, for a given node group.
+	nodeGroupsById map[string]*NodeGroup
+	// nodeGroupForNode is keyed by node name. A nil value means that the node has no node group.
+	nodeGroupForNode map[string]*NodeGroup
+	machineTypes     []string
+	// serverResourceLimiter is nil if it wasn't fetched since the last Refresh.
+	serverResourceLimiter *cloudprovider.ResourceLimiter
+}
+
+// BuildExternalGrpc builds the external gRPC cloud provider from the configuration file passed with
+// the --cloud-config flag.
+func BuildExternalGrpc(opts config.AutoscalingOptions, do cloudprovider.NodeGroupDiscoveryOptions, rl *cloudprovider.ResourceLimiter) cloudprovider.CloudProvider {
+	if opts.CloudConfig == "" {
+		klog.Fatalf("No configuration of the %s cloud provider given, please set --cloud-config", ProviderName)
+	}
+	if do.DiscoverySpecified() {
+		klog.Warningf("Node groups of the %s cloud provider are returned by the server, ignoring --nodes and --node-group-auto-discovery", ProviderName)
+	}
+	data, err := ioutil.ReadFile(opts.CloudConfig)
+	if err != nil {
+		klog.Fatalf("Couldn't read cloud provider configuration %s: %v", opts.CloudConfig, err)
+	}
+	cfg, err := parseCloudConfig(data)
+	if err != nil {
+		klog.Fatalf("Couldn't parse cloud provider configuration %s: %v", opts.CloudConfig, err)
+	}
+	provider, err := newExternalGrpcCloudProviderFromConfig(cfg, rl)
+	if err != nil {
+		klog.Fatalf("Failed to create %s cloud provider: %v", ProviderName, err)
+	}
+	return provider
+}
+
+func parseCloudConfig(data []byte) (*cloudConfig, error) {
+	cfg := &cloudConfig{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("address of the gRPC server is not set")
+	}
+	return cfg, nil
+}
+
+func newExternalGrpcCloudProviderFromConfig(cfg *cloudConfig, rl *cloudprovider.ResourceLimiter) (*externalGrpcCloudProvider, error) {
+	timeout := defaultTimeout
+	if cfg.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(cfg.Timeout); err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %v", cfg.Timeout, err)
+		}
+	}
+	dialOpt, err := dialOption(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// The connection is established in the background, errors are reported by calls.
+	conn, err := grpc.Dial(cfg.Address, dialOpt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", cfg.Address, err)
+	}
+	return newExternalGrpcCloudProvider(protos.NewCloudProviderClient(conn), timeout, rl), nil
+}
+
+func dialOption(cfg *cloudConfig) (grpc.DialOption, error) {
+	if cfg.CACert == "" {
+		klog.Warningf("No CA certificate of the %s cloud provider given, connecting without TLS", ProviderName)
+		return grpc.WithInsecure(), nil
+	}
+	caCert, err := ioutil.ReadFile(cfg.CACert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate %s: %v", cfg.CACert, err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no certificates found in %s", cfg.CACert)
+	}
+	tlsConfig := &tls.Config{RootCAs: certPool}
+	if cfg.Cert != "" || cfg.Key != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+}
+
+func newExternalGrpcCloudProvider(client protos.CloudProviderClient, timeout time.Duration, rl *cloudprovider.ResourceLimiter) *externalGrpcCloudProvider {
+	p := &externalGrpcCloudProvider{
+		client:          client,
+		timeout:         timeout,
+		resourceLimiter: rl,
+	}
+	p.resetCache()
+	return p
+}
+
+func (p *externalGrpcCloudProvider) resetCache() {
+	p.nodeGroups = nil
+	p.nodeGroupsById = make(map[string]*NodeGroup)
+	p.nodeGroupForNode = make(map[string]*NodeGroup)
+	p.machineTypes = nil
+	p.serverResourceLimiter = nil
+}
+
+// callContext returns the context of a single call to the server.
+func (p *externalGrpcCloudProvider) callContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), p.timeout)
+}
+
+// convertError maps errors of unimplemented methods to cloudprovider.ErrNotImplemented.
+func convertError(err error) error {
+	if status.Code(err) == codes.Unimplemented {
+		return cloudprovider.ErrNotImplemented
+	}
+	return err
+}
+
+// nodeGroupFromProto returns the cached node group with the id of the given one, updating its
+// properties, or caches a new one. Must be called with the mutex held.
+func (p *externalGrpcCloudProvider) nodeGroupFromProto(pbNodeGroup *protos.NodeGroup) *NodeGroup {
+	if nodeGroup, found := p.nodeGroupsById[pbNodeGroup.Id]; found {
+		nodeGroup.setProperties(pbNodeGroup)
+		return nodeGroup
+	}
+	nodeGroup := newNodeGroup(p, pbNodeGroup, true)
+	p.nodeGroupsById[pbNodeGroup.Id] = nodeGroup
+	return nodeGroup
+}
+
+// invalidateNodeGroups makes the next NodeGroups call list node groups from the server again.
+func (p *externalGrpcCloudProvider) invalidateNodeGroups() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.nodeGroups = nil
+	p.nodeGroupForNode = make(map[string]*NodeGroup)
+}
+
+// Name returns name of the cloud provider.
+func (p *externalGrpcCloudProvider) Name() string {
+	return ProviderName
+}
+
+// NodeGroups returns all node groups configured for this cloud provider.
+func (p *externalGrpcCloudProvider) NodeGroups() []cloudprovider.NodeGroup {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.nodeGroups != nil {
+		return p.nodeGroups
+	}
+	ctx, cancel := p.callContext()
+	defer cancel()
+	response, err := p.client.NodeGroups(ctx, &protos.NodeGroupsRequest{})
+	if err != nil {
+		klog.Errorf("Failed to list node groups of the %s cloud provider: %v", ProviderName, err)
+		return nil
+	}
+	nodeGroups := make([]cloudprovider.NodeGroup, 0, len(response.NodeGroups))
+	for _, pbNodeGroup := range response.NodeGroups {
+		if pbNodeGroup == nil || pbNodeGroup.Id == "" {
+			continue
+		}
+		nodeGroups = append(nodeGroups, p.nodeGroupFromProto(pbNodeGroup))
+	}
+	p.nodeGroups = nodeGroups
+	return nodeGroups
+}
+
+// NodeGroupForNode returns the node group for the given node, nil if the node should not be
+// processed by cluster autoscaler, or non-nil error if such occurred.
+func (p *externalGrpcCloudProvider) NodeGroupForNode(node *apiv1.Node) (cloudprovider.NodeGroup, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	nodeGroup, found := p.nodeGroupForNode[node.Name]
+	if !found {
+		data, err := node.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := p.callContext()
+		defer cancel()
+		response, err := p.client.NodeGroupForNode(ctx, &protos.NodeGroupForNodeRequest{Node: data})
+		if err != nil {
+			return nil, convertError(err)
+		}
+		if response.NodeGroup != nil && response.NodeGroup.Id != "" {
+			nodeGroup = p.nodeGroupFromProto(response.NodeGroup)
+		}
+		p.nodeGroupForNode[node.Name] = nodeGroup
+	}
+	if nodeGroup == nil {
+		return nil, nil
+	}
+	return nodeGroup, nil
+}
+
+// Pricing returns pricing model for this cloud provider or error if not available.
+// The server may leave the pricing methods unimplemented, in which case the returned model
+// returns cloudprovider.ErrNotImplemented.
+func (p *externalGrpcCloudProvider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError) {
+	return &pricingModel{provider: p}, nil
+}
+
+// GetAvailableMachineTypes get all machine types that can be requested from the cloud provider.
+func (p *externalGrpcCloudProvider) GetAvailableMachineTypes() ([]string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.machineTypes != nil {
+		return p.machineTypes, nil
+	}
+	ctx, cancel := p.callContext()
+	defer cancel()
+	response, err := p.client.GetAvailableMachineTypes(ctx, &protos.GetAvailableMachineTypesRequest{})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	p.machineTypes = append([]string{}, response.MachineTypes...)
+	return p.machineTypes, nil
+}
+
+// NewNodeGroup builds a theoretical node group based on the node definition provided. The node group is not automatically
+// created on the cloud provider side. The node group is not returned by NodeGroups() until it is created.
+func (p *externalGrpcCloudProvider) NewNodeGroup(machineType string, labels map[string]string, systemLabels map[string]string,
+	taints []apiv1.Taint, extraResources map[string]resource.Quantity) (cloudprovider.NodeGroup, error) {
+	pbTaints, err := protos.MarshalTaints(taints)
+	if err != nil {
+		return nil, err
+	}
+	pbExtraResources := make(map[string]string, len(extraResources))
+	for name, quantity := range extraResources {
+		pbExtraResources[name] = quantity.String()
+	}
+	ctx, cancel := p.callContext()
+	defer cancel()
+	response, err := p.client.NewNodeGroup(ctx, &protos.NewNodeGroupRequest{
+		MachineType:    machineType,
+		Labels:         labels,
+		SystemLabels:   systemLabels,
+		Taints:         pbTaints,
+		ExtraResources: pbExtraResources,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if response.NodeGroup == nil || response.NodeGroup.Id == "" {
+		return nil, fmt.Errorf("server returned no node group for machine type %s", machineType)
+	}
+	return newNodeGroup(p, response.NodeGroup, false), nil
+}
+
+// GetResourceLimiter returns struct containing limits (max, min) for resources (cores, memory etc.).
+// Limits from the flags are used if the server doesn't implement it.
+func (p *externalGrpcCloudProvider) GetResourceLimiter() (*cloudprovider.ResourceLimiter, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.serverResourceLimiter != nil {
+		return p.serverResourceLimiter, nil
+	}
+	ctx, cancel := p.callContext()
+	defer cancel()
+	response, err := p.client.GetResourceLimiter(ctx, &protos.GetResourceLimiterRequest{})
+	if err != nil {
+		if convertError(err) == cloudprovider.ErrNotImplemented {
+			return p.resourceLimiter, nil
+		}
+		return nil, err
+	}
+	p.serverResourceLimiter = cloudprovider.NewResourceLimiter(response.MinLimits, response.MaxLimits)
+	return p.serverResourceLimiter, nil
+}
+
+// GetInstanceID gets the instance ID for the specified node. Instances returned by the server are
+// identified by provider ids of their nodes.
+func (p *externalGrpcCloudProvider) GetInstanceID(node *apiv1.Node) string {
+	return node.Spec.ProviderID
+}
+
+// Cleanup cleans up open resources before the cloud provider is destroyed, i.e. go routines etc.
+func (p *externalGrpcCloudProvider) Cleanup() error {
+	ctx, cancel := p.callContext()
+	defer cancel()
+	_, err := p.client.Cleanup(ctx, &protos.CleanupRequest{})
+	if err != nil && convertError(err) != cloudprovider.ErrNotImplemented {
+		return err
+	}
+	return nil
+}
+
+// Refresh is called before every main loop and can be used to dynamically update cloud provider state.
+// It drops all cached responses.
+func (p *externalGrpcCloudProvider) Refresh() error {
+	p.mutex.Lock()
+	p.resetCache()
+	p.mutex.Unlock()
+
+	ctx, cancel := p.callContext()
+	defer cancel()
+	_, err := p.client.Refresh(ctx, &protos.RefreshRequest{})
+	if err != nil && convertError(err) != cloudprovider.ErrNotImplemented {
+		return err
+	}
+	return nil
+}
+
+// pricingModel proxies pricing calls to the server.
+type pricingModel struct {
+	provider *externalGrpcCloudProvider
+}
+
+// NodePrice returns a price of running the given node for a given period of time.
+func (m *pricingModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	data, err := node.Marshal()
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := m.provider.callContext()
+	defer cancel()
+	response, err := m.provider.client.PricingNodePrice(ctx, &protos.PricingNodePriceRequest{
+		Node:      data,
+		StartTime: startTime.UnixNano(),
+		EndTime:   endTime.UnixNano(),
+	})
+	if err != nil {
+		return 0, convertError(err)
+	}
+	return response.Price, nil
+}
+
+// PodPrice returns a price of running the given pod for a given period of time.
+func (m *pricingModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	data, err := pod.Marshal()
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := m.provider.callContext()
+	defer cancel()
+	response, err := m.provider.client.PricingPodPrice(ctx, &protos.PricingPodPriceRequest{
+		Pod:       data,
+		StartTime: startTime.UnixNano(),
+		EndTime:   endTime.UnixNano(),
+	})
+	if err != nil {
+		return 0, convertError(err)
+	}
+	return response.Price, nil
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalgrpc
+
+import (
+	"testing"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/externalgrpc/fake"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/externalgrpc/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestNode(name string) *apiv1.Node {
+	node := BuildTestNode(name, 1000, 1000)
+	node.Spec.ProviderID = "fake://" + name
+	return node
+}
+
+func startTestServer(t *testing.T) (*fake.Server, *externalGrpcCloudProvider) {
+	server := fake.NewServer()
+	server.AddNodeGroup("ng1", 1, 10, 2)
+	server.AddNode("ng1", buildTestNode("n1"))
+	server.AddNode("ng1", buildTestNode("n2"))
+	server.AddNodeGroup("ng2", 0, 5, 0)
+	address, err := server.Start()
+	assert.NoError(t, err)
+
+	rl := cloudprovider.NewResourceLimiter(map[string]int64{cloudprovider.ResourceNameCores: 1}, map[string]int64{cloudprovider.ResourceNameCores: 100})
+	provider, err := newExternalGrpcCloudProviderFromConfig(&cloudConfig{Address: address}, rl)
+	assert.NoError(t, err)
+	return server, provider
+}
+
+func TestParseCloudConfig(t *testing.T) {
+	cfg, err := parseCloudConfig([]byte("address: provisioner:8086\ncacert: /etc/ca.pem\ntimeout: 10s\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "provisioner:8086", cfg.Address)
+	assert.Equal(t, "/etc/ca.pem", cfg.CACert)
+	assert.Equal(t, "10s", cfg.Timeout)
+
+	_, err = parseCloudConfig([]byte("cacert: /etc/ca.pem\n"))
+	assert.Error(t, err)
+
+	_, err = newExternalGrpcCloudProviderFromConfig(&cloudConfig{Address: "provisioner:8086", Timeout: "ten"}, nil)
+	assert.Error(t, err)
+}
+
+func TestNodeGroupsAreCachedUntilRefresh(t *testing.T) {
+	server, provider := startTestServer(t)
+	defer server.Stop()
+
+	nodeGroups := provider.NodeGroups()
+	assert.Equal(t, 2, len(nodeGroups))
+	assert.Equal(t, "ng1", nodeGroups[0].Id())
+	assert.Equal(t, 1, nodeGroups[0].MinSize())
+	assert.Equal(t, 10, nodeGroups[0].MaxSize())
+	assert.True(t, nodeGroups[0].Exist())
+	provider.NodeGroups()
+	assert.Equal(t, 1, server.Calls("NodeGroups"))
+
+	// NodeGroupForNode returns the listed node group object, so their cached state is shared.
+	nodeGroup, err := provider.NodeGroupForNode(buildTestNode("n1"))
+	assert.NoError(t, err)
+	assert.True(t, nodeGroup == nodeGroups[0])
+	_, err = provider.NodeGroupForNode(buildTestNode("n1"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, server.Calls("NodeGroupForNode"))
+
+	// Nodes without a node group are cached as well.
+	for i := 0; i < 2; i++ {
+		nodeGroup, err = provider.NodeGroupForNode(buildTestNode("unknown"))
+		assert.NoError(t, err)
+		assert.Nil(t, nodeGroup)
+	}
+	assert.Equal(t, 2, server.Calls("NodeGroupForNode"))
+
+	size, err := nodeGroups[0].TargetSize()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, size)
+	nodes, err := nodeGroups[0].Nodes()
+	assert.NoError(t, err)
+	assert.Equal(t, []cloudprovider.Instance{
+		{Id: "fake://n1", Status: &cloudprovider.InstanceStatus{State: cloudprovider.STATE_RUNNING}},
+		{Id: "fake://n2", Status: &cloudprovider.InstanceStatus{State: cloudprovider.STATE_RUNNING}},
+	}, nodes)
+	nodeGroups[0].TargetSize()
+	nodeGroups[0].Nodes()
+	assert.Equal(t, 1, server.Calls("NodeGroupTargetSize"))
+	assert.Equal(t, 1, server.Calls("NodeGroupNodes"))
+
+	assert.NoError(t, provider.Refresh())
+	assert.Equal(t, 1, server.Calls("Refresh"))
+	provider.NodeGroups()[0].TargetSize()
+	provider.NodeGroupForNode(buildTestNode("n1"))
+	assert.Equal(t, 2, server.Calls("NodeGroups"))
+	assert.Equal(t, 2, server.Calls("NodeGroupTargetSize"))
+	assert.Equal(t, 3, server.Calls("NodeGroupForNode"))
+}
+
+func TestResizeNodeGroup(t *testing.T) {
+	server, provider := startTestServer(t)
+	defer server.Stop()
+
+	nodeGroup := provider.NodeGroups()[0]
+	size, err := nodeGroup.TargetSize()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, size)
+
+	assert.NoError(t, nodeGroup.IncreaseSize(2))
+	assert.Equal(t, 4, server.TargetSize("ng1"))
+	// Resizing drops the cached size and nodes.
+	size, err = nodeGroup.TargetSize()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, size)
+	nodes, err := nodeGroup.Nodes()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(nodes))
+	assert.Equal(t, cloudprovider.STATE_BEING_CREATED, nodes[3].Status.State)
+
+	assert.Error(t, nodeGroup.IncreaseSize(7))
+	assert.Error(t, nodeGroup.IncreaseSize(0))
+
+	assert.NoError(t, nodeGroup.DeleteNodes([]*apiv1.Node{buildTestNode("n1")}))
+	size, err = nodeGroup.TargetSize()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, size)
+	assert.Error(t, nodeGroup.DeleteNodes([]*apiv1.Node{buildTestNode("n1")}))
+
+	// All instances of the node group exist, so its target size can't be decreased.
+	assert.Error(t, nodeGroup.DecreaseTargetSize(-1))
+	assert.Equal(t, 3, server.TargetSize("ng1"))
+}
+
+func TestTemplateNodeInfoAndOptions(t *testing.T) {
+	server, provider := startTestServer(t)
+	defer server.Stop()
+
+	template := schedulercache.NewNodeInfo(BuildTestPod("ds", 100, 100))
+	template.SetNode(BuildTestNode("template", 2000, 2000))
+	server.SetTemplate("ng2", template)
+	server.SetOptions("ng2", &protos.NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.3,
+		ScaleDownUnneededTime:         int64(time.Minute),
+		ScaleDownAllowedWindows:       "0 22 * * 1-5 10h",
+		HeadroomNodes:                 1,
+	})
+
+	nodeGroups := provider.NodeGroups()
+	nodeInfo, err := nodeGroups[1].TemplateNodeInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "template", nodeInfo.Node().Name)
+	assert.Equal(t, 1, len(nodeInfo.Pods()))
+	assert.Equal(t, "ds", nodeInfo.Pods()[0].Name)
+
+	options, err := nodeGroups[1].GetOptions(config.NodeGroupAutoscalingOptions{})
+	assert.NoError(t, err)
+	windows, err := schedule.Parse("0 22 * * 1-5 10h")
+	assert.NoError(t, err)
+	assert.Equal(t, &config.NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.3,
+		ScaleDownUnneededTime:         time.Minute,
+		ScaleDownAllowedWindows:       "0 22 * * 1-5 10h",
+		ScaleDownAllowedSchedule:      windows,
+		Headroom:                      config.Headroom{Nodes: 1},
+	}, options)
+
+	// Unimplemented responses are cached too.
+	for i := 0; i < 2; i++ {
+		_, err = nodeGroups[0].TemplateNodeInfo()
+		assert.Equal(t, cloudprovider.ErrNotImplemented, err)
+		_, err = nodeGroups[0].GetOptions(config.NodeGroupAutoscalingOptions{})
+		assert.Equal(t, cloudprovider.ErrNotImplemented, err)
+	}
+	assert.Equal(t, 2, server.Calls("NodeGroupTemplateNodeInfo"))
+	assert.Equal(t, 2, server.Calls("NodeGroupGetOptions"))
+}
+
+func TestResourceLimiterAndPricing(t *testing.T) {
+	server, provider := startTestServer(t)
+	defer server.Stop()
+
+	// Limits from the flags are used until the server sets its own.
+	rl, err := provider.GetResourceLimiter()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), rl.GetMax(cloudprovider.ResourceNameCores))
+
+	server.SetResourceLimits(map[string]int64{cloudprovider.ResourceNameCores: 2}, map[string]int64{cloudprovider.ResourceNameCores: 20})
+	assert.NoError(t, provider.Refresh())
+	rl, err = provider.GetResourceLimiter()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), rl.GetMax(cloudprovider.ResourceNameCores))
+	provider.GetResourceLimiter()
+	assert.Equal(t, 2, server.Calls("GetResourceLimiter"))
+
+	pricing, err := provider.Pricing()
+	assert.NoError(t, err)
+	_, err = pricing.NodePrice(buildTestNode("n1"), time.Now(), time.Now().Add(time.Hour))
+	assert.Equal(t, cloudprovider.ErrNotImplemented, err)
+
+	assert.Equal(t, "fake://n1", provider.GetInstanceID(buildTestNode("n1")))
+	assert.NoError(t, provider.Cleanup())
+}
+
+func TestAutoprovisioning(t *testing.T) {
+	server, provider := startTestServer(t)
+	defer server.Stop()
+
+	_, err := provider.NewNodeGroup("m1", nil, nil, nil, nil)
+	assert.Equal(t, cloudprovider.ErrNotImplemented, err)
+
+	server.SetMachineTypes([]string{"m1", "m2"})
+	assert.NoError(t, provider.Refresh())
+	machineTypes, err := provider.GetAvailableMachineTypes()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"m1", "m2"}, machineTypes)
+
+	nodeGroup, err := provider.NewNodeGroup("m1", map[string]string{"a": "b"}, nil,
+		[]apiv1.Taint{{Key: "dedicated", Value: "m1", Effect: apiv1.TaintEffectNoSchedule}},
+		map[string]resource.Quantity{"gpu": resource.MustParse("1")})
+	assert.NoError(t, err)
+	assert.Equal(t, "autoprovisioned-m1", nodeGroup.Id())
+	assert.False(t, nodeGroup.Exist())
+	assert.True(t, nodeGroup.Autoprovisioned())
+	assert.Equal(t, 2, len(provider.NodeGroups()))
+
+	created, err := nodeGroup.Create()
+	assert.NoError(t, err)
+	assert.True(t, created.Exist())
+	nodeGroups := provider.NodeGroups()
+	assert.Equal(t, 3, len(nodeGroups))
+	assert.True(t, created == nodeGroups[0])
+
+	assert.NoError(t, created.Delete())
+	assert.Equal(t, 2, len(provider.NodeGroups()))
+	assert.Error(t, provider.NodeGroups()[0].Delete())
+}
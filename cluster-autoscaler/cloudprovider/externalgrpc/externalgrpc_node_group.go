@@ -0,0 +1,381 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalgrpc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/externalgrpc/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
+	"k8s.io/klog"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+// NodeGroup implements cloudprovider.NodeGroup by proxying calls to the external gRPC server.
+// Responses are cached until the next Refresh of the cloud provider.
+type NodeGroup struct {
+	provider *externalGrpcCloudProvider
+	id       string
+	exist    bool
+
+	mutex           sync.Mutex
+	minSize         int
+	maxSize         int
+	debug           string
+	autoprovisioned bool
+	// Cached responses, nil if not fetched yet.
+	targetSize      *int
+	nodes           []cloudprovider.Instance
+	nodeInfo        *schedulercache.NodeInfo
+	options         *config.NodeGroupAutoscalingOptions
+	nodeInfoMissing bool
+	optionsMissing  bool
+}
+
+func newNodeGroup(provider *externalGrpcCloudProvider, pbNodeGroup *protos.NodeGroup, exist bool) *NodeGroup {
+	nodeGroup := &NodeGroup{
+		provider: provider,
+		id:       pbNodeGroup.Id,
+		exist:    exist,
+	}
+	nodeGroup.setProperties(pbNodeGroup)
+	return nodeGroup
+}
+
+func (ng *NodeGroup) setProperties(pbNodeGroup *protos.NodeGroup) {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	ng.minSize = int(pbNodeGroup.MinSize)
+	ng.maxSize = int(pbNodeGroup.MaxSize)
+	ng.debug = pbNodeGroup.Debug
+	ng.autoprovisioned = pbNodeGroup.Autoprovisioned
+}
+
+// invalidateSize drops the cached target size and nodes after the node group was resized.
+func (ng *NodeGroup) invalidateSize() {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	ng.targetSize = nil
+	ng.nodes = nil
+}
+
+// MaxSize returns maximum size of the node group.
+func (ng *NodeGroup) MaxSize() int {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	return ng.maxSize
+}
+
+// MinSize returns minimum size of the node group.
+func (ng *NodeGroup) MinSize() int {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	return ng.minSize
+}
+
+// TargetSize returns the current target size of the node group. It is possible that the
+// number of nodes in Kubernetes is different at the moment but should be equal
+// to Size() once everything stabilizes (new nodes finish startup and registration or
+// removed nodes are deleted completely).
+func (ng *NodeGroup) TargetSize() (int, error) {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	if ng.targetSize != nil {
+		return *ng.targetSize, nil
+	}
+	ctx, cancel := ng.provider.callContext()
+	defer cancel()
+	response, err := ng.provider.client.NodeGroupTargetSize(ctx, &protos.NodeGroupTargetSizeRequest{Id: ng.id})
+	if err != nil {
+		return 0, convertError(err)
+	}
+	targetSize := int(response.TargetSize)
+	ng.targetSize = &targetSize
+	return targetSize, nil
+}
+
+// IncreaseSize increases the size of the node group. To delete a node you need
+// to explicitly name it and use DeleteNode. This function should wait until
+// node group size is updated.
+func (ng *NodeGroup) IncreaseSize(delta int) error {
+	if delta <= 0 {
+		return fmt.Errorf("size increase must be positive")
+	}
+	defer ng.invalidateSize()
+	ctx, cancel := ng.provider.callContext()
+	defer cancel()
+	_, err := ng.provider.client.NodeGroupIncreaseSize(ctx, &protos.NodeGroupIncreaseSizeRequest{
+		Id:    ng.id,
+		Delta: int32(delta),
+	})
+	return convertError(err)
+}
+
+// DeleteNodes deletes nodes from this node group. Error is returned either on
+// failure or if the given node doesn't belong to this node group. This function
+// should wait until node group size is updated.
+func (ng *NodeGroup) DeleteNodes(nodes []*apiv1.Node) error {
+	data, err := protos.MarshalNodes(nodes)
+	if err != nil {
+		return err
+	}
+	defer ng.invalidateSize()
+	ctx, cancel := ng.provider.callContext()
+	defer cancel()
+	_, err = ng.provider.client.NodeGroupDeleteNodes(ctx, &protos.NodeGroupDeleteNodesRequest{
+		Id:    ng.id,
+		Nodes: data,
+	})
+	return convertError(err)
+}
+
+// DecreaseTargetSize decreases the target size of the node group. This function
+// doesn't permit to delete any existing node and can be used only to reduce the
+// request for new nodes that have not been yet fulfilled. Delta should be negative.
+func (ng *NodeGroup) DecreaseTargetSize(delta int) error {
+	if delta >= 0 {
+		return fmt.Errorf("size decrease must be negative")
+	}
+	defer ng.invalidateSize()
+	ctx, cancel := ng.provider.callContext()
+	defer cancel()
+	_, err := ng.provider.client.NodeGroupDecreaseTargetSize(ctx, &protos.NodeGroupDecreaseTargetSizeRequest{
+		Id:    ng.id,
+		Delta: int32(delta),
+	})
+	return convertError(err)
+}
+
+// Id returns an unique identifier of the node group.
+func (ng *NodeGroup) Id() string {
+	return ng.id
+}
+
+// Debug returns a string containing all information regarding this node group.
+func (ng *NodeGroup) Debug() string {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	if ng.debug != "" {
+		return ng.debug
+	}
+	return fmt.Sprintf("%s (min: %d, max: %d)", ng.id, ng.minSize, ng.maxSize)
+}
+
+// Nodes returns a list of all nodes that belong to this node group.
+func (ng *NodeGroup) Nodes() ([]cloudprovider.Instance, error) {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	if ng.nodes != nil {
+		return ng.nodes, nil
+	}
+	ctx, cancel := ng.provider.callContext()
+	defer cancel()
+	response, err := ng.provider.client.NodeGroupNodes(ctx, &protos.NodeGroupNodesRequest{Id: ng.id})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	nodes := make([]cloudprovider.Instance, 0, len(response.Instances))
+	for _, pbInstance := range response.Instances {
+		if pbInstance == nil {
+			continue
+		}
+		nodes = append(nodes, cloudprovider.Instance{
+			Id:     pbInstance.Id,
+			Status: instanceStatusFromProto(pbInstance.Status),
+		})
+	}
+	ng.nodes = nodes
+	return nodes, nil
+}
+
+func instanceStatusFromProto(pbStatus *protos.InstanceStatus) *cloudprovider.InstanceStatus {
+	if pbStatus == nil {
+		return nil
+	}
+	status := &cloudprovider.InstanceStatus{}
+	switch pbStatus.InstanceState {
+	case protos.InstanceStatus_instanceRunning:
+		status.State = cloudprovider.STATE_RUNNING
+	case protos.InstanceStatus_instanceCreating:
+		status.State = cloudprovider.STATE_BEING_CREATED
+	case protos.InstanceStatus_instanceDeleting:
+		status.State = cloudprovider.STATE_BEING_DELETED
+	}
+	if pbStatus.ErrorInfo != nil {
+		status.ErrorInfo = &cloudprovider.InstanceErrorInfo{
+			ErrorClass:   cloudprovider.ERROR_OTHER,
+			ErrorCode:    pbStatus.ErrorInfo.ErrorCode,
+			ErrorMessage: pbStatus.ErrorInfo.ErrorMessage,
+		}
+		if pbStatus.ErrorInfo.ErrorClass == protos.InstanceErrorInfo_outOfResources {
+			status.ErrorInfo.ErrorClass = cloudprovider.ERROR_OUT_OF_RESOURCES
+		}
+	}
+	return status
+}
+
+// TemplateNodeInfo returns a node template for this node group.
+func (ng *NodeGroup) TemplateNodeInfo() (*schedulercache.NodeInfo, error) {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	if ng.nodeInfoMissing {
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	if ng.nodeInfo != nil {
+		return ng.nodeInfo, nil
+	}
+	ctx, cancel := ng.provider.callContext()
+	defer cancel()
+	response, err := ng.provider.client.NodeGroupTemplateNodeInfo(ctx, &protos.NodeGroupTemplateNodeInfoRequest{Id: ng.id})
+	if err != nil {
+		err = convertError(err)
+		ng.nodeInfoMissing = err == cloudprovider.ErrNotImplemented
+		return nil, err
+	}
+	if len(response.Node) == 0 {
+		ng.nodeInfoMissing = true
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	node, err := protos.UnmarshalNode(response.Node)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := protos.UnmarshalPods(response.Pods)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfo := schedulercache.NewNodeInfo(pods...)
+	if err := nodeInfo.SetNode(node); err != nil {
+		return nil, err
+	}
+	ng.nodeInfo = nodeInfo
+	return nodeInfo, nil
+}
+
+// Exist checks if the node group really exists on the cloud provider side. Node groups built by
+// NewNodeGroup don't exist until they are created.
+func (ng *NodeGroup) Exist() bool {
+	return ng.exist
+}
+
+// Create creates the node group on the cloud provider side.
+func (ng *NodeGroup) Create() (cloudprovider.NodeGroup, error) {
+	ctx, cancel := ng.provider.callContext()
+	defer cancel()
+	response, err := ng.provider.client.NodeGroupCreate(ctx, &protos.NodeGroupCreateRequest{Id: ng.id})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if response.NodeGroup == nil || response.NodeGroup.Id == "" {
+		return nil, fmt.Errorf("server returned no node group after creating %s", ng.id)
+	}
+	ng.provider.invalidateNodeGroups()
+	ng.provider.mutex.Lock()
+	defer ng.provider.mutex.Unlock()
+	return ng.provider.nodeGroupFromProto(response.NodeGroup), nil
+}
+
+// Delete deletes the node group on the cloud provider side.
+func (ng *NodeGroup) Delete() error {
+	ctx, cancel := ng.provider.callContext()
+	defer cancel()
+	_, err := ng.provider.client.NodeGroupDelete(ctx, &protos.NodeGroupDeleteRequest{Id: ng.id})
+	if err != nil {
+		return convertError(err)
+	}
+	ng.provider.invalidateNodeGroups()
+	return nil
+}
+
+// Autoprovisioned returns true if the node group is autoprovisioned.
+func (ng *NodeGroup) Autoprovisioned() bool {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	return ng.autoprovisioned
+}
+
+// GetOptions returns NodeGroupAutoscalingOptions that should be used for this particular
+// NodeGroup. Returns ErrNotImplemented if the server doesn't override the defaults.
+func (ng *NodeGroup) GetOptions(defaults config.NodeGroupAutoscalingOptions) (*config.NodeGroupAutoscalingOptions, error) {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+	if ng.optionsMissing {
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	if ng.options != nil {
+		return ng.options, nil
+	}
+	ctx, cancel := ng.provider.callContext()
+	defer cancel()
+	response, err := ng.provider.client.NodeGroupGetOptions(ctx, &protos.NodeGroupGetOptionsRequest{
+		Id:       ng.id,
+		Defaults: optionsToProto(defaults),
+	})
+	if err != nil {
+		err = convertError(err)
+		ng.optionsMissing = err == cloudprovider.ErrNotImplemented
+		return nil, err
+	}
+	if response.NodeGroupAutoscalingOptions == nil {
+		ng.optionsMissing = true
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	options := optionsFromProto(response.NodeGroupAutoscalingOptions)
+	if windows := response.NodeGroupAutoscalingOptions.ScaleDownAllowedWindows; windows != "" {
+		// Options are cached until the next Refresh, so the windows are parsed once per loop.
+		if parsed, err := schedule.Parse(windows); err != nil {
+			klog.Warningf("Ignoring invalid scale down windows of node group %s: %v", ng.id, err)
+		} else {
+			options.ScaleDownAllowedWindows = windows
+			options.ScaleDownAllowedSchedule = parsed
+		}
+	}
+	ng.options = options
+	return ng.options, nil
+}
+
+func optionsToProto(options config.NodeGroupAutoscalingOptions) *protos.NodeGroupAutoscalingOptions {
+	return &protos.NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: options.ScaleDownUtilizationThreshold,
+		ScaleDownUnneededTime:         int64(options.ScaleDownUnneededTime),
+		ScaleDownUnreadyTime:          int64(options.ScaleDownUnreadyTime),
+		MaxNodeProvisionTime:          int64(options.MaxNodeProvisionTime),
+		ScaleDownAllowedWindows:       options.ScaleDownAllowedWindows,
+		HeadroomMilliCpu:              options.Headroom.MilliCPU,
+		HeadroomMemory:                options.Headroom.Memory,
+		HeadroomNodes:                 int32(options.Headroom.Nodes),
+	}
+}
+
+func optionsFromProto(pbOptions *protos.NodeGroupAutoscalingOptions) *config.NodeGroupAutoscalingOptions {
+	return &config.NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: pbOptions.ScaleDownUtilizationThreshold,
+		ScaleDownUnneededTime:         time.Duration(pbOptions.ScaleDownUnneededTime),
+		ScaleDownUnreadyTime:          time.Duration(pbOptions.ScaleDownUnreadyTime),
+		MaxNodeProvisionTime:          time.Duration(pbOptions.MaxNodeProvisionTime),
+		Headroom: config.Headroom{
+			MilliCPU: pbOptions.HeadroomMilliCpu,
+			Memory:   pbOptions.HeadroomMemory,
+			Nodes:    int(pbOptions.HeadroomNodes),
+		},
+	}
+}
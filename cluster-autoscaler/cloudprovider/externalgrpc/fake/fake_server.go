@@ -0,0 +1,420 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake contains an in-memory implementation of the external gRPC cloud provider server,
+// for tests of the cloud provider and of servers' clients.
+package fake
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/externalgrpc/protos"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+// Server is a fake external gRPC cloud provider. Node groups are kept in memory and nodes belong
+// to node groups by their provider ids. Pricing methods are not implemented.
+type Server struct {
+	mutex        sync.Mutex
+	nodeGroups   map[string]*nodeGroup
+	machineTypes []string
+	minLimits    map[string]int64
+	maxLimits    map[string]int64
+	calls        map[string]int
+	grpcServer   *grpc.Server
+}
+
+type nodeGroup struct {
+	pb         protos.NodeGroup
+	exist      bool
+	targetSize int
+	instances  []*protos.Instance
+	template   *schedulercache.NodeInfo
+	options    *protos.NodeGroupAutoscalingOptions
+}
+
+// NewServer creates a fake server without node groups.
+func NewServer() *Server {
+	return &Server{
+		nodeGroups: make(map[string]*nodeGroup),
+		calls:      make(map[string]int),
+	}
+}
+
+// Start starts serving on a random local port and returns the address of the server.
+func (s *Server) Start() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	s.grpcServer = grpc.NewServer(grpc.UnaryInterceptor(s.countCalls))
+	protos.RegisterCloudProviderServer(s.grpcServer, s)
+	go s.grpcServer.Serve(listener)
+	return listener.Addr().String(), nil
+}
+
+// Stop stops the server.
+func (s *Server) Stop() {
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+}
+
+func (s *Server) countCalls(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	s.mutex.Lock()
+	s.calls[path.Base(info.FullMethod)]++
+	s.mutex.Unlock()
+	return handler(ctx, req)
+}
+
+// Calls returns the number of calls of the given method, e.g. "NodeGroups", received by the server.
+func (s *Server) Calls(method string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.calls[method]
+}
+
+// AddNodeGroup adds a node group with the given size limits and target size.
+func (s *Server) AddNodeGroup(id string, minSize, maxSize, targetSize int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nodeGroups[id] = &nodeGroup{
+		pb: protos.NodeGroup{
+			Id:      id,
+			MinSize: int32(minSize),
+			MaxSize: int32(maxSize),
+		},
+		exist:      true,
+		targetSize: targetSize,
+	}
+}
+
+// AddNode adds a running instance for the given node to the node group. The node must have a
+// provider id.
+func (s *Server) AddNode(nodeGroupId string, node *apiv1.Node) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng := s.nodeGroups[nodeGroupId]
+	ng.instances = append(ng.instances, &protos.Instance{
+		Id:     node.Spec.ProviderID,
+		Status: &protos.InstanceStatus{InstanceState: protos.InstanceStatus_instanceRunning},
+	})
+}
+
+// SetTemplate sets the template node info of the node group.
+func (s *Server) SetTemplate(nodeGroupId string, template *schedulercache.NodeInfo) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nodeGroups[nodeGroupId].template = template
+}
+
+// SetOptions sets the autoscaling options of the node group.
+func (s *Server) SetOptions(nodeGroupId string, options *protos.NodeGroupAutoscalingOptions) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nodeGroups[nodeGroupId].options = options
+}
+
+// SetMachineTypes sets machine types available for autoprovisioning. NewNodeGroup isn't implemented
+// if no machine types are set.
+func (s *Server) SetMachineTypes(machineTypes []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.machineTypes = machineTypes
+}
+
+// SetResourceLimits sets the limits returned by GetResourceLimiter. GetResourceLimiter isn't
+// implemented if no limits are set.
+func (s *Server) SetResourceLimits(minLimits, maxLimits map[string]int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.minLimits = minLimits
+	s.maxLimits = maxLimits
+}
+
+// TargetSize returns the target size of the node group.
+func (s *Server) TargetSize(nodeGroupId string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.nodeGroups[nodeGroupId].targetSize
+}
+
+func (s *Server) existingNodeGroup(id string) (*nodeGroup, error) {
+	ng, found := s.nodeGroups[id]
+	if !found || !ng.exist {
+		return nil, status.Errorf(codes.NotFound, "node group %s not found", id)
+	}
+	return ng, nil
+}
+
+// NodeGroups returns all existing node groups, sorted by id.
+func (s *Server) NodeGroups(ctx context.Context, req *protos.NodeGroupsRequest) (*protos.NodeGroupsResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	response := &protos.NodeGroupsResponse{}
+	for _, ng := range s.nodeGroups {
+		if ng.exist {
+			pb := ng.pb
+			response.NodeGroups = append(response.NodeGroups, &pb)
+		}
+	}
+	sort.Slice(response.NodeGroups, func(i, j int) bool { return response.NodeGroups[i].Id < response.NodeGroups[j].Id })
+	return response, nil
+}
+
+// NodeGroupForNode returns the node group with an instance with the node's provider id.
+func (s *Server) NodeGroupForNode(ctx context.Context, req *protos.NodeGroupForNodeRequest) (*protos.NodeGroupForNodeResponse, error) {
+	node, err := protos.UnmarshalNode(req.Node)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, ng := range s.nodeGroups {
+		for _, instance := range ng.instances {
+			if instance.Id == node.Spec.ProviderID {
+				pb := ng.pb
+				return &protos.NodeGroupForNodeResponse{NodeGroup: &pb}, nil
+			}
+		}
+	}
+	return &protos.NodeGroupForNodeResponse{}, nil
+}
+
+// PricingNodePrice is not implemented.
+func (s *Server) PricingNodePrice(ctx context.Context, req *protos.PricingNodePriceRequest) (*protos.PricingNodePriceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "pricing is not implemented")
+}
+
+// PricingPodPrice is not implemented.
+func (s *Server) PricingPodPrice(ctx context.Context, req *protos.PricingPodPriceRequest) (*protos.PricingPodPriceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "pricing is not implemented")
+}
+
+// GetAvailableMachineTypes returns the machine types set with SetMachineTypes.
+func (s *Server) GetAvailableMachineTypes(ctx context.Context, req *protos.GetAvailableMachineTypesRequest) (*protos.GetAvailableMachineTypesResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return &protos.GetAvailableMachineTypesResponse{MachineTypes: s.machineTypes}, nil
+}
+
+// NewNodeGroup builds a node group named after the machine type, which exists once created.
+func (s *Server) NewNodeGroup(ctx context.Context, req *protos.NewNodeGroupRequest) (*protos.NewNodeGroupResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.machineTypes) == 0 {
+		return nil, status.Error(codes.Unimplemented, "autoprovisioning is not enabled")
+	}
+	id := fmt.Sprintf("autoprovisioned-%s", req.MachineType)
+	if _, found := s.nodeGroups[id]; !found {
+		s.nodeGroups[id] = &nodeGroup{
+			pb: protos.NodeGroup{
+				Id:              id,
+				MaxSize:         10,
+				Autoprovisioned: true,
+			},
+		}
+	}
+	pb := s.nodeGroups[id].pb
+	return &protos.NewNodeGroupResponse{NodeGroup: &pb}, nil
+}
+
+// GetResourceLimiter returns the limits set with SetResourceLimits.
+func (s *Server) GetResourceLimiter(ctx context.Context, req *protos.GetResourceLimiterRequest) (*protos.GetResourceLimiterResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.minLimits == nil && s.maxLimits == nil {
+		return nil, status.Error(codes.Unimplemented, "resource limits are not set")
+	}
+	return &protos.GetResourceLimiterResponse{MinLimits: s.minLimits, MaxLimits: s.maxLimits}, nil
+}
+
+// Cleanup does nothing.
+func (s *Server) Cleanup(ctx context.Context, req *protos.CleanupRequest) (*protos.CleanupResponse, error) {
+	return &protos.CleanupResponse{}, nil
+}
+
+// Refresh does nothing.
+func (s *Server) Refresh(ctx context.Context, req *protos.RefreshRequest) (*protos.RefreshResponse, error) {
+	return &protos.RefreshResponse{}, nil
+}
+
+// NodeGroupTargetSize returns the target size of the node group.
+func (s *Server) NodeGroupTargetSize(ctx context.Context, req *protos.NodeGroupTargetSizeRequest) (*protos.NodeGroupTargetSizeResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng, err := s.existingNodeGroup(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.NodeGroupTargetSizeResponse{TargetSize: int32(ng.targetSize)}, nil
+}
+
+// NodeGroupIncreaseSize increases the target size of the node group and adds instances that are
+// being created.
+func (s *Server) NodeGroupIncreaseSize(ctx context.Context, req *protos.NodeGroupIncreaseSizeRequest) (*protos.NodeGroupIncreaseSizeResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng, err := s.existingNodeGroup(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if ng.targetSize+int(req.Delta) > int(ng.pb.MaxSize) {
+		return nil, status.Errorf(codes.InvalidArgument, "size increase too large, desired: %d max: %d", ng.targetSize+int(req.Delta), ng.pb.MaxSize)
+	}
+	for i := 0; i < int(req.Delta); i++ {
+		ng.instances = append(ng.instances, &protos.Instance{
+			Id:     fmt.Sprintf("fake://%s/%d", req.Id, ng.targetSize+i),
+			Status: &protos.InstanceStatus{InstanceState: protos.InstanceStatus_instanceCreating},
+		})
+	}
+	ng.targetSize += int(req.Delta)
+	return &protos.NodeGroupIncreaseSizeResponse{}, nil
+}
+
+// NodeGroupDeleteNodes removes instances of the given nodes and decreases the target size.
+func (s *Server) NodeGroupDeleteNodes(ctx context.Context, req *protos.NodeGroupDeleteNodesRequest) (*protos.NodeGroupDeleteNodesResponse, error) {
+	nodes, err := protos.UnmarshalNodes(req.Nodes)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng, err := s.existingNodeGroup(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if ng.targetSize-len(nodes) < int(ng.pb.MinSize) {
+		return nil, status.Errorf(codes.FailedPrecondition, "node group %s would be below its min size", req.Id)
+	}
+	for _, node := range nodes {
+		found := false
+		for i, instance := range ng.instances {
+			if instance.Id == node.Spec.ProviderID {
+				ng.instances = append(ng.instances[:i], ng.instances[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, status.Errorf(codes.InvalidArgument, "node %s doesn't belong to node group %s", node.Name, req.Id)
+		}
+		ng.targetSize--
+	}
+	return &protos.NodeGroupDeleteNodesResponse{}, nil
+}
+
+// NodeGroupDecreaseTargetSize decreases the target size of the node group.
+func (s *Server) NodeGroupDecreaseTargetSize(ctx context.Context, req *protos.NodeGroupDecreaseTargetSizeRequest) (*protos.NodeGroupDecreaseTargetSizeResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng, err := s.existingNodeGroup(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if ng.targetSize+int(req.Delta) < len(ng.instances) {
+		return nil, status.Errorf(codes.FailedPrecondition, "attempt to delete existing nodes of node group %s", req.Id)
+	}
+	ng.targetSize += int(req.Delta)
+	return &protos.NodeGroupDecreaseTargetSizeResponse{}, nil
+}
+
+// NodeGroupNodes returns instances of the node group.
+func (s *Server) NodeGroupNodes(ctx context.Context, req *protos.NodeGroupNodesRequest) (*protos.NodeGroupNodesResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng, err := s.existingNodeGroup(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.NodeGroupNodesResponse{Instances: ng.instances}, nil
+}
+
+// NodeGroupTemplateNodeInfo returns the template set with SetTemplate.
+func (s *Server) NodeGroupTemplateNodeInfo(ctx context.Context, req *protos.NodeGroupTemplateNodeInfoRequest) (*protos.NodeGroupTemplateNodeInfoResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng, found := s.nodeGroups[req.Id]
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "node group %s not found", req.Id)
+	}
+	if ng.template == nil {
+		return nil, status.Errorf(codes.Unimplemented, "node group %s has no template", req.Id)
+	}
+	node, err := ng.template.Node().Marshal()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	pods, err := protos.MarshalPods(ng.template.Pods())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &protos.NodeGroupTemplateNodeInfoResponse{Node: node, Pods: pods}, nil
+}
+
+// NodeGroupCreate creates a node group built by NewNodeGroup.
+func (s *Server) NodeGroupCreate(ctx context.Context, req *protos.NodeGroupCreateRequest) (*protos.NodeGroupCreateResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng, found := s.nodeGroups[req.Id]
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "node group %s not found", req.Id)
+	}
+	if ng.exist {
+		return nil, status.Errorf(codes.AlreadyExists, "node group %s already exists", req.Id)
+	}
+	ng.exist = true
+	pb := ng.pb
+	return &protos.NodeGroupCreateResponse{NodeGroup: &pb}, nil
+}
+
+// NodeGroupDelete deletes an autoprovisioned node group.
+func (s *Server) NodeGroupDelete(ctx context.Context, req *protos.NodeGroupDeleteRequest) (*protos.NodeGroupDeleteResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng, err := s.existingNodeGroup(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !ng.pb.Autoprovisioned {
+		return nil, status.Errorf(codes.FailedPrecondition, "node group %s is not autoprovisioned", req.Id)
+	}
+	delete(s.nodeGroups, req.Id)
+	return &protos.NodeGroupDeleteResponse{}, nil
+}
+
+// NodeGroupGetOptions returns the options set with SetOptions.
+func (s *Server) NodeGroupGetOptions(ctx context.Context, req *protos.NodeGroupGetOptionsRequest) (*protos.NodeGroupGetOptionsResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ng, err := s.existingNodeGroup(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if ng.options == nil {
+		return nil, status.Errorf(codes.Unimplemented, "node group %s uses default options", req.Id)
+	}
+	return &protos.NodeGroupGetOptionsResponse{NodeGroupAutoscalingOptions: ng.options}, nil
+}
@@ -0,0 +1,2652 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: externalgrpc.proto
+
+package protos
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type InstanceStatus_InstanceState int32
+
+const (
+	InstanceStatus_unspecified      InstanceStatus_InstanceState = 0
+	InstanceStatus_instanceRunning  InstanceStatus_InstanceState = 1
+	InstanceStatus_instanceCreating InstanceStatus_InstanceState = 2
+	InstanceStatus_instanceDeleting InstanceStatus_InstanceState = 3
+)
+
+var InstanceStatus_InstanceState_name = map[int32]string{
+	0: "unspecified",
+	1: "instanceRunning",
+	2: "instanceCreating",
+	3: "instanceDeleting",
+}
+var InstanceStatus_InstanceState_value = map[string]int32{
+	"unspecified":      0,
+	"instanceRunning":  1,
+	"instanceCreating": 2,
+	"instanceDeleting": 3,
+}
+
+func (x InstanceStatus_InstanceState) String() string {
+	return proto.EnumName(InstanceStatus_InstanceState_name, int32(x))
+}
+func (InstanceStatus_InstanceState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{30, 0}
+}
+
+type InstanceErrorInfo_InstanceErrorClass int32
+
+const (
+	InstanceErrorInfo_unspecified    InstanceErrorInfo_InstanceErrorClass = 0
+	InstanceErrorInfo_outOfResources InstanceErrorInfo_InstanceErrorClass = 1
+	InstanceErrorInfo_other          InstanceErrorInfo_InstanceErrorClass = 99
+)
+
+var InstanceErrorInfo_InstanceErrorClass_name = map[int32]string{
+	0:  "unspecified",
+	1:  "outOfResources",
+	99: "other",
+}
+var InstanceErrorInfo_InstanceErrorClass_value = map[string]int32{
+	"unspecified":    0,
+	"outOfResources": 1,
+	"other":          99,
+}
+
+func (x InstanceErrorInfo_InstanceErrorClass) String() string {
+	return proto.EnumName(InstanceErrorInfo_InstanceErrorClass_name, int32(x))
+}
+func (InstanceErrorInfo_InstanceErrorClass) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{31, 0}
+}
+
+type NodeGroup struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	MinSize              int32    `protobuf:"varint,2,opt,name=minSize" json:"minSize,omitempty"`
+	MaxSize              int32    `protobuf:"varint,3,opt,name=maxSize" json:"maxSize,omitempty"`
+	Debug                string   `protobuf:"bytes,4,opt,name=debug" json:"debug,omitempty"`
+	Autoprovisioned      bool     `protobuf:"varint,5,opt,name=autoprovisioned" json:"autoprovisioned,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroup) Reset()         { *m = NodeGroup{} }
+func (m *NodeGroup) String() string { return proto.CompactTextString(m) }
+func (*NodeGroup) ProtoMessage()    {}
+func (*NodeGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{0}
+}
+func (m *NodeGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroup.Unmarshal(m, b)
+}
+func (m *NodeGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroup.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroup.Merge(dst, src)
+}
+func (m *NodeGroup) XXX_Size() int {
+	return xxx_messageInfo_NodeGroup.Size(m)
+}
+func (m *NodeGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroup proto.InternalMessageInfo
+
+func (m *NodeGroup) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeGroup) GetMinSize() int32 {
+	if m != nil {
+		return m.MinSize
+	}
+	return 0
+}
+
+func (m *NodeGroup) GetMaxSize() int32 {
+	if m != nil {
+		return m.MaxSize
+	}
+	return 0
+}
+
+func (m *NodeGroup) GetDebug() string {
+	if m != nil {
+		return m.Debug
+	}
+	return ""
+}
+
+func (m *NodeGroup) GetAutoprovisioned() bool {
+	if m != nil {
+		return m.Autoprovisioned
+	}
+	return false
+}
+
+type NodeGroupsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupsRequest) Reset()         { *m = NodeGroupsRequest{} }
+func (m *NodeGroupsRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupsRequest) ProtoMessage()    {}
+func (*NodeGroupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{1}
+}
+func (m *NodeGroupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupsRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupsRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupsRequest.Merge(dst, src)
+}
+func (m *NodeGroupsRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupsRequest.Size(m)
+}
+func (m *NodeGroupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupsRequest proto.InternalMessageInfo
+
+type NodeGroupsResponse struct {
+	NodeGroups           []*NodeGroup `protobuf:"bytes,1,rep,name=nodeGroups" json:"nodeGroups,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *NodeGroupsResponse) Reset()         { *m = NodeGroupsResponse{} }
+func (m *NodeGroupsResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupsResponse) ProtoMessage()    {}
+func (*NodeGroupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{2}
+}
+func (m *NodeGroupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupsResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupsResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupsResponse.Merge(dst, src)
+}
+func (m *NodeGroupsResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupsResponse.Size(m)
+}
+func (m *NodeGroupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupsResponse proto.InternalMessageInfo
+
+func (m *NodeGroupsResponse) GetNodeGroups() []*NodeGroup {
+	if m != nil {
+		return m.NodeGroups
+	}
+	return nil
+}
+
+type NodeGroupForNodeRequest struct {
+	// v1.Node object.
+	Node                 []byte   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupForNodeRequest) Reset()         { *m = NodeGroupForNodeRequest{} }
+func (m *NodeGroupForNodeRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupForNodeRequest) ProtoMessage()    {}
+func (*NodeGroupForNodeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{3}
+}
+func (m *NodeGroupForNodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupForNodeRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupForNodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupForNodeRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupForNodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupForNodeRequest.Merge(dst, src)
+}
+func (m *NodeGroupForNodeRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupForNodeRequest.Size(m)
+}
+func (m *NodeGroupForNodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupForNodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupForNodeRequest proto.InternalMessageInfo
+
+func (m *NodeGroupForNodeRequest) GetNode() []byte {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+type NodeGroupForNodeResponse struct {
+	NodeGroup            *NodeGroup `protobuf:"bytes,1,opt,name=nodeGroup" json:"nodeGroup,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *NodeGroupForNodeResponse) Reset()         { *m = NodeGroupForNodeResponse{} }
+func (m *NodeGroupForNodeResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupForNodeResponse) ProtoMessage()    {}
+func (*NodeGroupForNodeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{4}
+}
+func (m *NodeGroupForNodeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupForNodeResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupForNodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupForNodeResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupForNodeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupForNodeResponse.Merge(dst, src)
+}
+func (m *NodeGroupForNodeResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupForNodeResponse.Size(m)
+}
+func (m *NodeGroupForNodeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupForNodeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupForNodeResponse proto.InternalMessageInfo
+
+func (m *NodeGroupForNodeResponse) GetNodeGroup() *NodeGroup {
+	if m != nil {
+		return m.NodeGroup
+	}
+	return nil
+}
+
+type PricingNodePriceRequest struct {
+	// v1.Node object.
+	Node []byte `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	// Unix timestamps in nanoseconds.
+	StartTime            int64    `protobuf:"varint,2,opt,name=startTime" json:"startTime,omitempty"`
+	EndTime              int64    `protobuf:"varint,3,opt,name=endTime" json:"endTime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PricingNodePriceRequest) Reset()         { *m = PricingNodePriceRequest{} }
+func (m *PricingNodePriceRequest) String() string { return proto.CompactTextString(m) }
+func (*PricingNodePriceRequest) ProtoMessage()    {}
+func (*PricingNodePriceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{5}
+}
+func (m *PricingNodePriceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PricingNodePriceRequest.Unmarshal(m, b)
+}
+func (m *PricingNodePriceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PricingNodePriceRequest.Marshal(b, m, deterministic)
+}
+func (dst *PricingNodePriceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PricingNodePriceRequest.Merge(dst, src)
+}
+func (m *PricingNodePriceRequest) XXX_Size() int {
+	return xxx_messageInfo_PricingNodePriceRequest.Size(m)
+}
+func (m *PricingNodePriceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PricingNodePriceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PricingNodePriceRequest proto.InternalMessageInfo
+
+func (m *PricingNodePriceRequest) GetNode() []byte {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *PricingNodePriceRequest) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *PricingNodePriceRequest) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+type PricingNodePriceResponse struct {
+	Price                float64  `protobuf:"fixed64,1,opt,name=price" json:"price,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PricingNodePriceResponse) Reset()         { *m = PricingNodePriceResponse{} }
+func (m *PricingNodePriceResponse) String() string { return proto.CompactTextString(m) }
+func (*PricingNodePriceResponse) ProtoMessage()    {}
+func (*PricingNodePriceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{6}
+}
+func (m *PricingNodePriceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PricingNodePriceResponse.Unmarshal(m, b)
+}
+func (m *PricingNodePriceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PricingNodePriceResponse.Marshal(b, m, deterministic)
+}
+func (dst *PricingNodePriceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PricingNodePriceResponse.Merge(dst, src)
+}
+func (m *PricingNodePriceResponse) XXX_Size() int {
+	return xxx_messageInfo_PricingNodePriceResponse.Size(m)
+}
+func (m *PricingNodePriceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PricingNodePriceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PricingNodePriceResponse proto.InternalMessageInfo
+
+func (m *PricingNodePriceResponse) GetPrice() float64 {
+	if m != nil {
+		return m.Price
+	}
+	return 0
+}
+
+type PricingPodPriceRequest struct {
+	// v1.Pod object.
+	Pod []byte `protobuf:"bytes,1,opt,name=pod,proto3" json:"pod,omitempty"`
+	// Unix timestamps in nanoseconds.
+	StartTime            int64    `protobuf:"varint,2,opt,name=startTime" json:"startTime,omitempty"`
+	EndTime              int64    `protobuf:"varint,3,opt,name=endTime" json:"endTime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PricingPodPriceRequest) Reset()         { *m = PricingPodPriceRequest{} }
+func (m *PricingPodPriceRequest) String() string { return proto.CompactTextString(m) }
+func (*PricingPodPriceRequest) ProtoMessage()    {}
+func (*PricingPodPriceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{7}
+}
+func (m *PricingPodPriceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PricingPodPriceRequest.Unmarshal(m, b)
+}
+func (m *PricingPodPriceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PricingPodPriceRequest.Marshal(b, m, deterministic)
+}
+func (dst *PricingPodPriceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PricingPodPriceRequest.Merge(dst, src)
+}
+func (m *PricingPodPriceRequest) XXX_Size() int {
+	return xxx_messageInfo_PricingPodPriceRequest.Size(m)
+}
+func (m *PricingPodPriceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PricingPodPriceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PricingPodPriceRequest proto.InternalMessageInfo
+
+func (m *PricingPodPriceRequest) GetPod() []byte {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+func (m *PricingPodPriceRequest) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *PricingPodPriceRequest) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+type PricingPodPriceResponse struct {
+	Price                float64  `protobuf:"fixed64,1,opt,name=price" json:"price,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PricingPodPriceResponse) Reset()         { *m = PricingPodPriceResponse{} }
+func (m *PricingPodPriceResponse) String() string { return proto.CompactTextString(m) }
+func (*PricingPodPriceResponse) ProtoMessage()    {}
+func (*PricingPodPriceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{8}
+}
+func (m *PricingPodPriceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PricingPodPriceResponse.Unmarshal(m, b)
+}
+func (m *PricingPodPriceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PricingPodPriceResponse.Marshal(b, m, deterministic)
+}
+func (dst *PricingPodPriceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PricingPodPriceResponse.Merge(dst, src)
+}
+func (m *PricingPodPriceResponse) XXX_Size() int {
+	return xxx_messageInfo_PricingPodPriceResponse.Size(m)
+}
+func (m *PricingPodPriceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PricingPodPriceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PricingPodPriceResponse proto.InternalMessageInfo
+
+func (m *PricingPodPriceResponse) GetPrice() float64 {
+	if m != nil {
+		return m.Price
+	}
+	return 0
+}
+
+type GetAvailableMachineTypesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAvailableMachineTypesRequest) Reset()         { *m = GetAvailableMachineTypesRequest{} }
+func (m *GetAvailableMachineTypesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAvailableMachineTypesRequest) ProtoMessage()    {}
+func (*GetAvailableMachineTypesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{9}
+}
+func (m *GetAvailableMachineTypesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAvailableMachineTypesRequest.Unmarshal(m, b)
+}
+func (m *GetAvailableMachineTypesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAvailableMachineTypesRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetAvailableMachineTypesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAvailableMachineTypesRequest.Merge(dst, src)
+}
+func (m *GetAvailableMachineTypesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetAvailableMachineTypesRequest.Size(m)
+}
+func (m *GetAvailableMachineTypesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAvailableMachineTypesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAvailableMachineTypesRequest proto.InternalMessageInfo
+
+type GetAvailableMachineTypesResponse struct {
+	MachineTypes         []string `protobuf:"bytes,1,rep,name=machineTypes" json:"machineTypes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAvailableMachineTypesResponse) Reset()         { *m = GetAvailableMachineTypesResponse{} }
+func (m *GetAvailableMachineTypesResponse) String() string { return proto.CompactTextString(m) }
+func (*GetAvailableMachineTypesResponse) ProtoMessage()    {}
+func (*GetAvailableMachineTypesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{10}
+}
+func (m *GetAvailableMachineTypesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAvailableMachineTypesResponse.Unmarshal(m, b)
+}
+func (m *GetAvailableMachineTypesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAvailableMachineTypesResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetAvailableMachineTypesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAvailableMachineTypesResponse.Merge(dst, src)
+}
+func (m *GetAvailableMachineTypesResponse) XXX_Size() int {
+	return xxx_messageInfo_GetAvailableMachineTypesResponse.Size(m)
+}
+func (m *GetAvailableMachineTypesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAvailableMachineTypesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAvailableMachineTypesResponse proto.InternalMessageInfo
+
+func (m *GetAvailableMachineTypesResponse) GetMachineTypes() []string {
+	if m != nil {
+		return m.MachineTypes
+	}
+	return nil
+}
+
+type NewNodeGroupRequest struct {
+	MachineType  string            `protobuf:"bytes,1,opt,name=machineType" json:"machineType,omitempty"`
+	Labels       map[string]string `protobuf:"bytes,2,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SystemLabels map[string]string `protobuf:"bytes,3,rep,name=systemLabels" json:"systemLabels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// v1.Taint objects.
+	Taints [][]byte `protobuf:"bytes,4,rep,name=taints,proto3" json:"taints,omitempty"`
+	// Quantities in their string form, e.g. "1Gi".
+	ExtraResources       map[string]string `protobuf:"bytes,5,rep,name=extraResources" json:"extraResources,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *NewNodeGroupRequest) Reset()         { *m = NewNodeGroupRequest{} }
+func (m *NewNodeGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*NewNodeGroupRequest) ProtoMessage()    {}
+func (*NewNodeGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{11}
+}
+func (m *NewNodeGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewNodeGroupRequest.Unmarshal(m, b)
+}
+func (m *NewNodeGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewNodeGroupRequest.Marshal(b, m, deterministic)
+}
+func (dst *NewNodeGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewNodeGroupRequest.Merge(dst, src)
+}
+func (m *NewNodeGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_NewNodeGroupRequest.Size(m)
+}
+func (m *NewNodeGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewNodeGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewNodeGroupRequest proto.InternalMessageInfo
+
+func (m *NewNodeGroupRequest) GetMachineType() string {
+	if m != nil {
+		return m.MachineType
+	}
+	return ""
+}
+
+func (m *NewNodeGroupRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *NewNodeGroupRequest) GetSystemLabels() map[string]string {
+	if m != nil {
+		return m.SystemLabels
+	}
+	return nil
+}
+
+func (m *NewNodeGroupRequest) GetTaints() [][]byte {
+	if m != nil {
+		return m.Taints
+	}
+	return nil
+}
+
+func (m *NewNodeGroupRequest) GetExtraResources() map[string]string {
+	if m != nil {
+		return m.ExtraResources
+	}
+	return nil
+}
+
+type NewNodeGroupResponse struct {
+	NodeGroup            *NodeGroup `protobuf:"bytes,1,opt,name=nodeGroup" json:"nodeGroup,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *NewNodeGroupResponse) Reset()         { *m = NewNodeGroupResponse{} }
+func (m *NewNodeGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*NewNodeGroupResponse) ProtoMessage()    {}
+func (*NewNodeGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{12}
+}
+func (m *NewNodeGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewNodeGroupResponse.Unmarshal(m, b)
+}
+func (m *NewNodeGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewNodeGroupResponse.Marshal(b, m, deterministic)
+}
+func (dst *NewNodeGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewNodeGroupResponse.Merge(dst, src)
+}
+func (m *NewNodeGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_NewNodeGroupResponse.Size(m)
+}
+func (m *NewNodeGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewNodeGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewNodeGroupResponse proto.InternalMessageInfo
+
+func (m *NewNodeGroupResponse) GetNodeGroup() *NodeGroup {
+	if m != nil {
+		return m.NodeGroup
+	}
+	return nil
+}
+
+type GetResourceLimiterRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetResourceLimiterRequest) Reset()         { *m = GetResourceLimiterRequest{} }
+func (m *GetResourceLimiterRequest) String() string { return proto.CompactTextString(m) }
+func (*GetResourceLimiterRequest) ProtoMessage()    {}
+func (*GetResourceLimiterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{13}
+}
+func (m *GetResourceLimiterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetResourceLimiterRequest.Unmarshal(m, b)
+}
+func (m *GetResourceLimiterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetResourceLimiterRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetResourceLimiterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetResourceLimiterRequest.Merge(dst, src)
+}
+func (m *GetResourceLimiterRequest) XXX_Size() int {
+	return xxx_messageInfo_GetResourceLimiterRequest.Size(m)
+}
+func (m *GetResourceLimiterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetResourceLimiterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetResourceLimiterRequest proto.InternalMessageInfo
+
+type GetResourceLimiterResponse struct {
+	MinLimits            map[string]int64 `protobuf:"bytes,1,rep,name=minLimits" json:"minLimits,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	MaxLimits            map[string]int64 `protobuf:"bytes,2,rep,name=maxLimits" json:"maxLimits,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GetResourceLimiterResponse) Reset()         { *m = GetResourceLimiterResponse{} }
+func (m *GetResourceLimiterResponse) String() string { return proto.CompactTextString(m) }
+func (*GetResourceLimiterResponse) ProtoMessage()    {}
+func (*GetResourceLimiterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{14}
+}
+func (m *GetResourceLimiterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetResourceLimiterResponse.Unmarshal(m, b)
+}
+func (m *GetResourceLimiterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetResourceLimiterResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetResourceLimiterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetResourceLimiterResponse.Merge(dst, src)
+}
+func (m *GetResourceLimiterResponse) XXX_Size() int {
+	return xxx_messageInfo_GetResourceLimiterResponse.Size(m)
+}
+func (m *GetResourceLimiterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetResourceLimiterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetResourceLimiterResponse proto.InternalMessageInfo
+
+func (m *GetResourceLimiterResponse) GetMinLimits() map[string]int64 {
+	if m != nil {
+		return m.MinLimits
+	}
+	return nil
+}
+
+func (m *GetResourceLimiterResponse) GetMaxLimits() map[string]int64 {
+	if m != nil {
+		return m.MaxLimits
+	}
+	return nil
+}
+
+type CleanupRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CleanupRequest) Reset()         { *m = CleanupRequest{} }
+func (m *CleanupRequest) String() string { return proto.CompactTextString(m) }
+func (*CleanupRequest) ProtoMessage()    {}
+func (*CleanupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{15}
+}
+func (m *CleanupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CleanupRequest.Unmarshal(m, b)
+}
+func (m *CleanupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CleanupRequest.Marshal(b, m, deterministic)
+}
+func (dst *CleanupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CleanupRequest.Merge(dst, src)
+}
+func (m *CleanupRequest) XXX_Size() int {
+	return xxx_messageInfo_CleanupRequest.Size(m)
+}
+func (m *CleanupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CleanupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CleanupRequest proto.InternalMessageInfo
+
+type CleanupResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CleanupResponse) Reset()         { *m = CleanupResponse{} }
+func (m *CleanupResponse) String() string { return proto.CompactTextString(m) }
+func (*CleanupResponse) ProtoMessage()    {}
+func (*CleanupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{16}
+}
+func (m *CleanupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CleanupResponse.Unmarshal(m, b)
+}
+func (m *CleanupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CleanupResponse.Marshal(b, m, deterministic)
+}
+func (dst *CleanupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CleanupResponse.Merge(dst, src)
+}
+func (m *CleanupResponse) XXX_Size() int {
+	return xxx_messageInfo_CleanupResponse.Size(m)
+}
+func (m *CleanupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CleanupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CleanupResponse proto.InternalMessageInfo
+
+type RefreshRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RefreshRequest) Reset()         { *m = RefreshRequest{} }
+func (m *RefreshRequest) String() string { return proto.CompactTextString(m) }
+func (*RefreshRequest) ProtoMessage()    {}
+func (*RefreshRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{17}
+}
+func (m *RefreshRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefreshRequest.Unmarshal(m, b)
+}
+func (m *RefreshRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefreshRequest.Marshal(b, m, deterministic)
+}
+func (dst *RefreshRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefreshRequest.Merge(dst, src)
+}
+func (m *RefreshRequest) XXX_Size() int {
+	return xxx_messageInfo_RefreshRequest.Size(m)
+}
+func (m *RefreshRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefreshRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefreshRequest proto.InternalMessageInfo
+
+type RefreshResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RefreshResponse) Reset()         { *m = RefreshResponse{} }
+func (m *RefreshResponse) String() string { return proto.CompactTextString(m) }
+func (*RefreshResponse) ProtoMessage()    {}
+func (*RefreshResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{18}
+}
+func (m *RefreshResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefreshResponse.Unmarshal(m, b)
+}
+func (m *RefreshResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefreshResponse.Marshal(b, m, deterministic)
+}
+func (dst *RefreshResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefreshResponse.Merge(dst, src)
+}
+func (m *RefreshResponse) XXX_Size() int {
+	return xxx_messageInfo_RefreshResponse.Size(m)
+}
+func (m *RefreshResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefreshResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefreshResponse proto.InternalMessageInfo
+
+type NodeGroupTargetSizeRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupTargetSizeRequest) Reset()         { *m = NodeGroupTargetSizeRequest{} }
+func (m *NodeGroupTargetSizeRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupTargetSizeRequest) ProtoMessage()    {}
+func (*NodeGroupTargetSizeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{19}
+}
+func (m *NodeGroupTargetSizeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupTargetSizeRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupTargetSizeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupTargetSizeRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupTargetSizeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupTargetSizeRequest.Merge(dst, src)
+}
+func (m *NodeGroupTargetSizeRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupTargetSizeRequest.Size(m)
+}
+func (m *NodeGroupTargetSizeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupTargetSizeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupTargetSizeRequest proto.InternalMessageInfo
+
+func (m *NodeGroupTargetSizeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type NodeGroupTargetSizeResponse struct {
+	TargetSize           int32    `protobuf:"varint,1,opt,name=targetSize" json:"targetSize,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupTargetSizeResponse) Reset()         { *m = NodeGroupTargetSizeResponse{} }
+func (m *NodeGroupTargetSizeResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupTargetSizeResponse) ProtoMessage()    {}
+func (*NodeGroupTargetSizeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{20}
+}
+func (m *NodeGroupTargetSizeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupTargetSizeResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupTargetSizeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupTargetSizeResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupTargetSizeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupTargetSizeResponse.Merge(dst, src)
+}
+func (m *NodeGroupTargetSizeResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupTargetSizeResponse.Size(m)
+}
+func (m *NodeGroupTargetSizeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupTargetSizeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupTargetSizeResponse proto.InternalMessageInfo
+
+func (m *NodeGroupTargetSizeResponse) GetTargetSize() int32 {
+	if m != nil {
+		return m.TargetSize
+	}
+	return 0
+}
+
+type NodeGroupIncreaseSizeRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Delta                int32    `protobuf:"varint,2,opt,name=delta" json:"delta,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupIncreaseSizeRequest) Reset()         { *m = NodeGroupIncreaseSizeRequest{} }
+func (m *NodeGroupIncreaseSizeRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupIncreaseSizeRequest) ProtoMessage()    {}
+func (*NodeGroupIncreaseSizeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{21}
+}
+func (m *NodeGroupIncreaseSizeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupIncreaseSizeRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupIncreaseSizeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupIncreaseSizeRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupIncreaseSizeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupIncreaseSizeRequest.Merge(dst, src)
+}
+func (m *NodeGroupIncreaseSizeRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupIncreaseSizeRequest.Size(m)
+}
+func (m *NodeGroupIncreaseSizeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupIncreaseSizeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupIncreaseSizeRequest proto.InternalMessageInfo
+
+func (m *NodeGroupIncreaseSizeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeGroupIncreaseSizeRequest) GetDelta() int32 {
+	if m != nil {
+		return m.Delta
+	}
+	return 0
+}
+
+type NodeGroupIncreaseSizeResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupIncreaseSizeResponse) Reset()         { *m = NodeGroupIncreaseSizeResponse{} }
+func (m *NodeGroupIncreaseSizeResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupIncreaseSizeResponse) ProtoMessage()    {}
+func (*NodeGroupIncreaseSizeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{22}
+}
+func (m *NodeGroupIncreaseSizeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupIncreaseSizeResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupIncreaseSizeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupIncreaseSizeResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupIncreaseSizeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupIncreaseSizeResponse.Merge(dst, src)
+}
+func (m *NodeGroupIncreaseSizeResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupIncreaseSizeResponse.Size(m)
+}
+func (m *NodeGroupIncreaseSizeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupIncreaseSizeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupIncreaseSizeResponse proto.InternalMessageInfo
+
+type NodeGroupDeleteNodesRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// v1.Node objects.
+	Nodes                [][]byte `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupDeleteNodesRequest) Reset()         { *m = NodeGroupDeleteNodesRequest{} }
+func (m *NodeGroupDeleteNodesRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupDeleteNodesRequest) ProtoMessage()    {}
+func (*NodeGroupDeleteNodesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{23}
+}
+func (m *NodeGroupDeleteNodesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupDeleteNodesRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupDeleteNodesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupDeleteNodesRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupDeleteNodesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupDeleteNodesRequest.Merge(dst, src)
+}
+func (m *NodeGroupDeleteNodesRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupDeleteNodesRequest.Size(m)
+}
+func (m *NodeGroupDeleteNodesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupDeleteNodesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupDeleteNodesRequest proto.InternalMessageInfo
+
+func (m *NodeGroupDeleteNodesRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeGroupDeleteNodesRequest) GetNodes() [][]byte {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type NodeGroupDeleteNodesResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupDeleteNodesResponse) Reset()         { *m = NodeGroupDeleteNodesResponse{} }
+func (m *NodeGroupDeleteNodesResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupDeleteNodesResponse) ProtoMessage()    {}
+func (*NodeGroupDeleteNodesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{24}
+}
+func (m *NodeGroupDeleteNodesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupDeleteNodesResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupDeleteNodesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupDeleteNodesResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupDeleteNodesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupDeleteNodesResponse.Merge(dst, src)
+}
+func (m *NodeGroupDeleteNodesResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupDeleteNodesResponse.Size(m)
+}
+func (m *NodeGroupDeleteNodesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupDeleteNodesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupDeleteNodesResponse proto.InternalMessageInfo
+
+type NodeGroupDecreaseTargetSizeRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Delta                int32    `protobuf:"varint,2,opt,name=delta" json:"delta,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupDecreaseTargetSizeRequest) Reset()         { *m = NodeGroupDecreaseTargetSizeRequest{} }
+func (m *NodeGroupDecreaseTargetSizeRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupDecreaseTargetSizeRequest) ProtoMessage()    {}
+func (*NodeGroupDecreaseTargetSizeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{25}
+}
+func (m *NodeGroupDecreaseTargetSizeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupDecreaseTargetSizeRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupDecreaseTargetSizeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupDecreaseTargetSizeRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupDecreaseTargetSizeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupDecreaseTargetSizeRequest.Merge(dst, src)
+}
+func (m *NodeGroupDecreaseTargetSizeRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupDecreaseTargetSizeRequest.Size(m)
+}
+func (m *NodeGroupDecreaseTargetSizeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupDecreaseTargetSizeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupDecreaseTargetSizeRequest proto.InternalMessageInfo
+
+func (m *NodeGroupDecreaseTargetSizeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeGroupDecreaseTargetSizeRequest) GetDelta() int32 {
+	if m != nil {
+		return m.Delta
+	}
+	return 0
+}
+
+type NodeGroupDecreaseTargetSizeResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupDecreaseTargetSizeResponse) Reset()         { *m = NodeGroupDecreaseTargetSizeResponse{} }
+func (m *NodeGroupDecreaseTargetSizeResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupDecreaseTargetSizeResponse) ProtoMessage()    {}
+func (*NodeGroupDecreaseTargetSizeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{26}
+}
+func (m *NodeGroupDecreaseTargetSizeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupDecreaseTargetSizeResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupDecreaseTargetSizeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupDecreaseTargetSizeResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupDecreaseTargetSizeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupDecreaseTargetSizeResponse.Merge(dst, src)
+}
+func (m *NodeGroupDecreaseTargetSizeResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupDecreaseTargetSizeResponse.Size(m)
+}
+func (m *NodeGroupDecreaseTargetSizeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupDecreaseTargetSizeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupDecreaseTargetSizeResponse proto.InternalMessageInfo
+
+type NodeGroupNodesRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupNodesRequest) Reset()         { *m = NodeGroupNodesRequest{} }
+func (m *NodeGroupNodesRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupNodesRequest) ProtoMessage()    {}
+func (*NodeGroupNodesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{27}
+}
+func (m *NodeGroupNodesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupNodesRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupNodesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupNodesRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupNodesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupNodesRequest.Merge(dst, src)
+}
+func (m *NodeGroupNodesRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupNodesRequest.Size(m)
+}
+func (m *NodeGroupNodesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupNodesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupNodesRequest proto.InternalMessageInfo
+
+func (m *NodeGroupNodesRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type NodeGroupNodesResponse struct {
+	Instances            []*Instance `protobuf:"bytes,1,rep,name=instances" json:"instances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *NodeGroupNodesResponse) Reset()         { *m = NodeGroupNodesResponse{} }
+func (m *NodeGroupNodesResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupNodesResponse) ProtoMessage()    {}
+func (*NodeGroupNodesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{28}
+}
+func (m *NodeGroupNodesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupNodesResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupNodesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupNodesResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupNodesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupNodesResponse.Merge(dst, src)
+}
+func (m *NodeGroupNodesResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupNodesResponse.Size(m)
+}
+func (m *NodeGroupNodesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupNodesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupNodesResponse proto.InternalMessageInfo
+
+func (m *NodeGroupNodesResponse) GetInstances() []*Instance {
+	if m != nil {
+		return m.Instances
+	}
+	return nil
+}
+
+type Instance struct {
+	Id                   string          `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Status               *InstanceStatus `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *Instance) Reset()         { *m = Instance{} }
+func (m *Instance) String() string { return proto.CompactTextString(m) }
+func (*Instance) ProtoMessage()    {}
+func (*Instance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{29}
+}
+func (m *Instance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Instance.Unmarshal(m, b)
+}
+func (m *Instance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Instance.Marshal(b, m, deterministic)
+}
+func (dst *Instance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Instance.Merge(dst, src)
+}
+func (m *Instance) XXX_Size() int {
+	return xxx_messageInfo_Instance.Size(m)
+}
+func (m *Instance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Instance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Instance proto.InternalMessageInfo
+
+func (m *Instance) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Instance) GetStatus() *InstanceStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type InstanceStatus struct {
+	InstanceState        InstanceStatus_InstanceState `protobuf:"varint,1,opt,name=instanceState,enum=externalgrpc.InstanceStatus_InstanceState" json:"instanceState,omitempty"`
+	ErrorInfo            *InstanceErrorInfo           `protobuf:"bytes,2,opt,name=errorInfo" json:"errorInfo,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *InstanceStatus) Reset()         { *m = InstanceStatus{} }
+func (m *InstanceStatus) String() string { return proto.CompactTextString(m) }
+func (*InstanceStatus) ProtoMessage()    {}
+func (*InstanceStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{30}
+}
+func (m *InstanceStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstanceStatus.Unmarshal(m, b)
+}
+func (m *InstanceStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstanceStatus.Marshal(b, m, deterministic)
+}
+func (dst *InstanceStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstanceStatus.Merge(dst, src)
+}
+func (m *InstanceStatus) XXX_Size() int {
+	return xxx_messageInfo_InstanceStatus.Size(m)
+}
+func (m *InstanceStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstanceStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstanceStatus proto.InternalMessageInfo
+
+func (m *InstanceStatus) GetInstanceState() InstanceStatus_InstanceState {
+	if m != nil {
+		return m.InstanceState
+	}
+	return InstanceStatus_unspecified
+}
+
+func (m *InstanceStatus) GetErrorInfo() *InstanceErrorInfo {
+	if m != nil {
+		return m.ErrorInfo
+	}
+	return nil
+}
+
+type InstanceErrorInfo struct {
+	ErrorClass           InstanceErrorInfo_InstanceErrorClass `protobuf:"varint,1,opt,name=errorClass,enum=externalgrpc.InstanceErrorInfo_InstanceErrorClass" json:"errorClass,omitempty"`
+	ErrorCode            string                               `protobuf:"bytes,2,opt,name=errorCode" json:"errorCode,omitempty"`
+	ErrorMessage         string                               `protobuf:"bytes,3,opt,name=errorMessage" json:"errorMessage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                             `json:"-"`
+	XXX_unrecognized     []byte                               `json:"-"`
+	XXX_sizecache        int32                                `json:"-"`
+}
+
+func (m *InstanceErrorInfo) Reset()         { *m = InstanceErrorInfo{} }
+func (m *InstanceErrorInfo) String() string { return proto.CompactTextString(m) }
+func (*InstanceErrorInfo) ProtoMessage()    {}
+func (*InstanceErrorInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{31}
+}
+func (m *InstanceErrorInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstanceErrorInfo.Unmarshal(m, b)
+}
+func (m *InstanceErrorInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstanceErrorInfo.Marshal(b, m, deterministic)
+}
+func (dst *InstanceErrorInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstanceErrorInfo.Merge(dst, src)
+}
+func (m *InstanceErrorInfo) XXX_Size() int {
+	return xxx_messageInfo_InstanceErrorInfo.Size(m)
+}
+func (m *InstanceErrorInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstanceErrorInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstanceErrorInfo proto.InternalMessageInfo
+
+func (m *InstanceErrorInfo) GetErrorClass() InstanceErrorInfo_InstanceErrorClass {
+	if m != nil {
+		return m.ErrorClass
+	}
+	return InstanceErrorInfo_unspecified
+}
+
+func (m *InstanceErrorInfo) GetErrorCode() string {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ""
+}
+
+func (m *InstanceErrorInfo) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+type NodeGroupTemplateNodeInfoRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupTemplateNodeInfoRequest) Reset()         { *m = NodeGroupTemplateNodeInfoRequest{} }
+func (m *NodeGroupTemplateNodeInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupTemplateNodeInfoRequest) ProtoMessage()    {}
+func (*NodeGroupTemplateNodeInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{32}
+}
+func (m *NodeGroupTemplateNodeInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupTemplateNodeInfoRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupTemplateNodeInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupTemplateNodeInfoRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupTemplateNodeInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupTemplateNodeInfoRequest.Merge(dst, src)
+}
+func (m *NodeGroupTemplateNodeInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupTemplateNodeInfoRequest.Size(m)
+}
+func (m *NodeGroupTemplateNodeInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupTemplateNodeInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupTemplateNodeInfoRequest proto.InternalMessageInfo
+
+func (m *NodeGroupTemplateNodeInfoRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type NodeGroupTemplateNodeInfoResponse struct {
+	// v1.Node object.
+	Node []byte `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	// v1.Pod objects that would run on the node as soon as it starts, e.g. from daemon sets.
+	Pods                 [][]byte `protobuf:"bytes,2,rep,name=pods,proto3" json:"pods,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupTemplateNodeInfoResponse) Reset()         { *m = NodeGroupTemplateNodeInfoResponse{} }
+func (m *NodeGroupTemplateNodeInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupTemplateNodeInfoResponse) ProtoMessage()    {}
+func (*NodeGroupTemplateNodeInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{33}
+}
+func (m *NodeGroupTemplateNodeInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupTemplateNodeInfoResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupTemplateNodeInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupTemplateNodeInfoResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupTemplateNodeInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupTemplateNodeInfoResponse.Merge(dst, src)
+}
+func (m *NodeGroupTemplateNodeInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupTemplateNodeInfoResponse.Size(m)
+}
+func (m *NodeGroupTemplateNodeInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupTemplateNodeInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupTemplateNodeInfoResponse proto.InternalMessageInfo
+
+func (m *NodeGroupTemplateNodeInfoResponse) GetNode() []byte {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *NodeGroupTemplateNodeInfoResponse) GetPods() [][]byte {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
+type NodeGroupCreateRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupCreateRequest) Reset()         { *m = NodeGroupCreateRequest{} }
+func (m *NodeGroupCreateRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupCreateRequest) ProtoMessage()    {}
+func (*NodeGroupCreateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{34}
+}
+func (m *NodeGroupCreateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupCreateRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupCreateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupCreateRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupCreateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupCreateRequest.Merge(dst, src)
+}
+func (m *NodeGroupCreateRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupCreateRequest.Size(m)
+}
+func (m *NodeGroupCreateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupCreateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupCreateRequest proto.InternalMessageInfo
+
+func (m *NodeGroupCreateRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type NodeGroupCreateResponse struct {
+	NodeGroup            *NodeGroup `protobuf:"bytes,1,opt,name=nodeGroup" json:"nodeGroup,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *NodeGroupCreateResponse) Reset()         { *m = NodeGroupCreateResponse{} }
+func (m *NodeGroupCreateResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupCreateResponse) ProtoMessage()    {}
+func (*NodeGroupCreateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{35}
+}
+func (m *NodeGroupCreateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupCreateResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupCreateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupCreateResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupCreateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupCreateResponse.Merge(dst, src)
+}
+func (m *NodeGroupCreateResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupCreateResponse.Size(m)
+}
+func (m *NodeGroupCreateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupCreateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupCreateResponse proto.InternalMessageInfo
+
+func (m *NodeGroupCreateResponse) GetNodeGroup() *NodeGroup {
+	if m != nil {
+		return m.NodeGroup
+	}
+	return nil
+}
+
+type NodeGroupDeleteRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupDeleteRequest) Reset()         { *m = NodeGroupDeleteRequest{} }
+func (m *NodeGroupDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupDeleteRequest) ProtoMessage()    {}
+func (*NodeGroupDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{36}
+}
+func (m *NodeGroupDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupDeleteRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupDeleteRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupDeleteRequest.Merge(dst, src)
+}
+func (m *NodeGroupDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupDeleteRequest.Size(m)
+}
+func (m *NodeGroupDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupDeleteRequest proto.InternalMessageInfo
+
+func (m *NodeGroupDeleteRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type NodeGroupDeleteResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeGroupDeleteResponse) Reset()         { *m = NodeGroupDeleteResponse{} }
+func (m *NodeGroupDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupDeleteResponse) ProtoMessage()    {}
+func (*NodeGroupDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{37}
+}
+func (m *NodeGroupDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupDeleteResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupDeleteResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupDeleteResponse.Merge(dst, src)
+}
+func (m *NodeGroupDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupDeleteResponse.Size(m)
+}
+func (m *NodeGroupDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupDeleteResponse proto.InternalMessageInfo
+
+type NodeGroupAutoscalingOptions struct {
+	ScaleDownUtilizationThreshold float64 `protobuf:"fixed64,1,opt,name=scaleDownUtilizationThreshold" json:"scaleDownUtilizationThreshold,omitempty"`
+	// Durations in nanoseconds.
+	ScaleDownUnneededTime   int64    `protobuf:"varint,2,opt,name=scaleDownUnneededTime" json:"scaleDownUnneededTime,omitempty"`
+	ScaleDownUnreadyTime    int64    `protobuf:"varint,3,opt,name=scaleDownUnreadyTime" json:"scaleDownUnreadyTime,omitempty"`
+	MaxNodeProvisionTime    int64    `protobuf:"varint,4,opt,name=maxNodeProvisionTime" json:"maxNodeProvisionTime,omitempty"`
+	ScaleDownAllowedWindows string   `protobuf:"bytes,5,opt,name=scaleDownAllowedWindows" json:"scaleDownAllowedWindows,omitempty"`
+	HeadroomMilliCpu        int64    `protobuf:"varint,6,opt,name=headroomMilliCpu" json:"headroomMilliCpu,omitempty"`
+	HeadroomMemory          int64    `protobuf:"varint,7,opt,name=headroomMemory" json:"headroomMemory,omitempty"`
+	HeadroomNodes           int32    `protobuf:"varint,8,opt,name=headroomNodes" json:"headroomNodes,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
+	XXX_unrecognized        []byte   `json:"-"`
+	XXX_sizecache           int32    `json:"-"`
+}
+
+func (m *NodeGroupAutoscalingOptions) Reset()         { *m = NodeGroupAutoscalingOptions{} }
+func (m *NodeGroupAutoscalingOptions) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupAutoscalingOptions) ProtoMessage()    {}
+func (*NodeGroupAutoscalingOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{38}
+}
+func (m *NodeGroupAutoscalingOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupAutoscalingOptions.Unmarshal(m, b)
+}
+func (m *NodeGroupAutoscalingOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupAutoscalingOptions.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupAutoscalingOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupAutoscalingOptions.Merge(dst, src)
+}
+func (m *NodeGroupAutoscalingOptions) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupAutoscalingOptions.Size(m)
+}
+func (m *NodeGroupAutoscalingOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupAutoscalingOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupAutoscalingOptions proto.InternalMessageInfo
+
+func (m *NodeGroupAutoscalingOptions) GetScaleDownUtilizationThreshold() float64 {
+	if m != nil {
+		return m.ScaleDownUtilizationThreshold
+	}
+	return 0
+}
+
+func (m *NodeGroupAutoscalingOptions) GetScaleDownUnneededTime() int64 {
+	if m != nil {
+		return m.ScaleDownUnneededTime
+	}
+	return 0
+}
+
+func (m *NodeGroupAutoscalingOptions) GetScaleDownUnreadyTime() int64 {
+	if m != nil {
+		return m.ScaleDownUnreadyTime
+	}
+	return 0
+}
+
+func (m *NodeGroupAutoscalingOptions) GetMaxNodeProvisionTime() int64 {
+	if m != nil {
+		return m.MaxNodeProvisionTime
+	}
+	return 0
+}
+
+func (m *NodeGroupAutoscalingOptions) GetScaleDownAllowedWindows() string {
+	if m != nil {
+		return m.ScaleDownAllowedWindows
+	}
+	return ""
+}
+
+func (m *NodeGroupAutoscalingOptions) GetHeadroomMilliCpu() int64 {
+	if m != nil {
+		return m.HeadroomMilliCpu
+	}
+	return 0
+}
+
+func (m *NodeGroupAutoscalingOptions) GetHeadroomMemory() int64 {
+	if m != nil {
+		return m.HeadroomMemory
+	}
+	return 0
+}
+
+func (m *NodeGroupAutoscalingOptions) GetHeadroomNodes() int32 {
+	if m != nil {
+		return m.HeadroomNodes
+	}
+	return 0
+}
+
+type NodeGroupGetOptionsRequest struct {
+	Id                   string                       `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Defaults             *NodeGroupAutoscalingOptions `protobuf:"bytes,2,opt,name=defaults" json:"defaults,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *NodeGroupGetOptionsRequest) Reset()         { *m = NodeGroupGetOptionsRequest{} }
+func (m *NodeGroupGetOptionsRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupGetOptionsRequest) ProtoMessage()    {}
+func (*NodeGroupGetOptionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{39}
+}
+func (m *NodeGroupGetOptionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupGetOptionsRequest.Unmarshal(m, b)
+}
+func (m *NodeGroupGetOptionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupGetOptionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupGetOptionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupGetOptionsRequest.Merge(dst, src)
+}
+func (m *NodeGroupGetOptionsRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupGetOptionsRequest.Size(m)
+}
+func (m *NodeGroupGetOptionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupGetOptionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupGetOptionsRequest proto.InternalMessageInfo
+
+func (m *NodeGroupGetOptionsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeGroupGetOptionsRequest) GetDefaults() *NodeGroupAutoscalingOptions {
+	if m != nil {
+		return m.Defaults
+	}
+	return nil
+}
+
+type NodeGroupGetOptionsResponse struct {
+	NodeGroupAutoscalingOptions *NodeGroupAutoscalingOptions `protobuf:"bytes,1,opt,name=nodeGroupAutoscalingOptions" json:"nodeGroupAutoscalingOptions,omitempty"`
+	XXX_NoUnkeyedLiteral        struct{}                     `json:"-"`
+	XXX_unrecognized            []byte                       `json:"-"`
+	XXX_sizecache               int32                        `json:"-"`
+}
+
+func (m *NodeGroupGetOptionsResponse) Reset()         { *m = NodeGroupGetOptionsResponse{} }
+func (m *NodeGroupGetOptionsResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeGroupGetOptionsResponse) ProtoMessage()    {}
+func (*NodeGroupGetOptionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_externalgrpc_42c42934c42f47b4, []int{40}
+}
+func (m *NodeGroupGetOptionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeGroupGetOptionsResponse.Unmarshal(m, b)
+}
+func (m *NodeGroupGetOptionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeGroupGetOptionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeGroupGetOptionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeGroupGetOptionsResponse.Merge(dst, src)
+}
+func (m *NodeGroupGetOptionsResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeGroupGetOptionsResponse.Size(m)
+}
+func (m *NodeGroupGetOptionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeGroupGetOptionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeGroupGetOptionsResponse proto.InternalMessageInfo
+
+func (m *NodeGroupGetOptionsResponse) GetNodeGroupAutoscalingOptions() *NodeGroupAutoscalingOptions {
+	if m != nil {
+		return m.NodeGroupAutoscalingOptions
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*NodeGroup)(nil), "externalgrpc.NodeGroup")
+	proto.RegisterType((*NodeGroupsRequest)(nil), "externalgrpc.NodeGroupsRequest")
+	proto.RegisterType((*NodeGroupsResponse)(nil), "externalgrpc.NodeGroupsResponse")
+	proto.RegisterType((*NodeGroupForNodeRequest)(nil), "externalgrpc.NodeGroupForNodeRequest")
+	proto.RegisterType((*NodeGroupForNodeResponse)(nil), "externalgrpc.NodeGroupForNodeResponse")
+	proto.RegisterType((*PricingNodePriceRequest)(nil), "externalgrpc.PricingNodePriceRequest")
+	proto.RegisterType((*PricingNodePriceResponse)(nil), "externalgrpc.PricingNodePriceResponse")
+	proto.RegisterType((*PricingPodPriceRequest)(nil), "externalgrpc.PricingPodPriceRequest")
+	proto.RegisterType((*PricingPodPriceResponse)(nil), "externalgrpc.PricingPodPriceResponse")
+	proto.RegisterType((*GetAvailableMachineTypesRequest)(nil), "externalgrpc.GetAvailableMachineTypesRequest")
+	proto.RegisterType((*GetAvailableMachineTypesResponse)(nil), "externalgrpc.GetAvailableMachineTypesResponse")
+	proto.RegisterType((*NewNodeGroupRequest)(nil), "externalgrpc.NewNodeGroupRequest")
+	proto.RegisterMapType((map[string]string)(nil), "externalgrpc.NewNodeGroupRequest.ExtraResourcesEntry")
+	proto.RegisterMapType((map[string]string)(nil), "externalgrpc.NewNodeGroupRequest.LabelsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "externalgrpc.NewNodeGroupRequest.SystemLabelsEntry")
+	proto.RegisterType((*NewNodeGroupResponse)(nil), "externalgrpc.NewNodeGroupResponse")
+	proto.RegisterType((*GetResourceLimiterRequest)(nil), "externalgrpc.GetResourceLimiterRequest")
+	proto.RegisterType((*GetResourceLimiterResponse)(nil), "externalgrpc.GetResourceLimiterResponse")
+	proto.RegisterMapType((map[string]int64)(nil), "externalgrpc.GetResourceLimiterResponse.MaxLimitsEntry")
+	proto.RegisterMapType((map[string]int64)(nil), "externalgrpc.GetResourceLimiterResponse.MinLimitsEntry")
+	proto.RegisterType((*CleanupRequest)(nil), "externalgrpc.CleanupRequest")
+	proto.RegisterType((*CleanupResponse)(nil), "externalgrpc.CleanupResponse")
+	proto.RegisterType((*RefreshRequest)(nil), "externalgrpc.RefreshRequest")
+	proto.RegisterType((*RefreshResponse)(nil), "externalgrpc.RefreshResponse")
+	proto.RegisterType((*NodeGroupTargetSizeRequest)(nil), "externalgrpc.NodeGroupTargetSizeRequest")
+	proto.RegisterType((*NodeGroupTargetSizeResponse)(nil), "externalgrpc.NodeGroupTargetSizeResponse")
+	proto.RegisterType((*NodeGroupIncreaseSizeRequest)(nil), "externalgrpc.NodeGroupIncreaseSizeRequest")
+	proto.RegisterType((*NodeGroupIncreaseSizeResponse)(nil), "externalgrpc.NodeGroupIncreaseSizeResponse")
+	proto.RegisterType((*NodeGroupDeleteNodesRequest)(nil), "externalgrpc.NodeGroupDeleteNodesRequest")
+	proto.RegisterType((*NodeGroupDeleteNodesResponse)(nil), "externalgrpc.NodeGroupDeleteNodesResponse")
+	proto.RegisterType((*NodeGroupDecreaseTargetSizeRequest)(nil), "externalgrpc.NodeGroupDecreaseTargetSizeRequest")
+	proto.RegisterType((*NodeGroupDecreaseTargetSizeResponse)(nil), "externalgrpc.NodeGroupDecreaseTargetSizeResponse")
+	proto.RegisterType((*NodeGroupNodesRequest)(nil), "externalgrpc.NodeGroupNodesRequest")
+	proto.RegisterType((*NodeGroupNodesResponse)(nil), "externalgrpc.NodeGroupNodesResponse")
+	proto.RegisterType((*Instance)(nil), "externalgrpc.Instance")
+	proto.RegisterType((*InstanceStatus)(nil), "externalgrpc.InstanceStatus")
+	proto.RegisterType((*InstanceErrorInfo)(nil), "externalgrpc.InstanceErrorInfo")
+	proto.RegisterType((*NodeGroupTemplateNodeInfoRequest)(nil), "externalgrpc.NodeGroupTemplateNodeInfoRequest")
+	proto.RegisterType((*NodeGroupTemplateNodeInfoResponse)(nil), "externalgrpc.NodeGroupTemplateNodeInfoResponse")
+	proto.RegisterType((*NodeGroupCreateRequest)(nil), "externalgrpc.NodeGroupCreateRequest")
+	proto.RegisterType((*NodeGroupCreateResponse)(nil), "externalgrpc.NodeGroupCreateResponse")
+	proto.RegisterType((*NodeGroupDeleteRequest)(nil), "externalgrpc.NodeGroupDeleteRequest")
+	proto.RegisterType((*NodeGroupDeleteResponse)(nil), "externalgrpc.NodeGroupDeleteResponse")
+	proto.RegisterType((*NodeGroupAutoscalingOptions)(nil), "externalgrpc.NodeGroupAutoscalingOptions")
+	proto.RegisterType((*NodeGroupGetOptionsRequest)(nil), "externalgrpc.NodeGroupGetOptionsRequest")
+	proto.RegisterType((*NodeGroupGetOptionsResponse)(nil), "externalgrpc.NodeGroupGetOptionsResponse")
+	proto.RegisterEnum("externalgrpc.InstanceStatus_InstanceState", InstanceStatus_InstanceState_name, InstanceStatus_InstanceState_value)
+	proto.RegisterEnum("externalgrpc.InstanceErrorInfo_InstanceErrorClass", InstanceErrorInfo_InstanceErrorClass_name, InstanceErrorInfo_InstanceErrorClass_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for CloudProvider service
+
+type CloudProviderClient interface {
+	// NodeGroups returns all node groups configured for this cloud provider.
+	NodeGroups(ctx context.Context, in *NodeGroupsRequest, opts ...grpc.CallOption) (*NodeGroupsResponse, error)
+	// NodeGroupForNode returns the node group for the given node. A node group with an empty id
+	// means that the node should not be processed by Cluster Autoscaler.
+	NodeGroupForNode(ctx context.Context, in *NodeGroupForNodeRequest, opts ...grpc.CallOption) (*NodeGroupForNodeResponse, error)
+	// PricingNodePrice returns a theoretical minimum price of running a node for a given period of
+	// time on a perfectly matching machine. Optional.
+	PricingNodePrice(ctx context.Context, in *PricingNodePriceRequest, opts ...grpc.CallOption) (*PricingNodePriceResponse, error)
+	// PricingPodPrice returns a theoretical minimum price of running a pod for a given period of
+	// time on a perfectly matching machine. Optional.
+	PricingPodPrice(ctx context.Context, in *PricingPodPriceRequest, opts ...grpc.CallOption) (*PricingPodPriceResponse, error)
+	// GetAvailableMachineTypes returns all machine types that can be requested from the cloud
+	// provider. Optional.
+	GetAvailableMachineTypes(ctx context.Context, in *GetAvailableMachineTypesRequest, opts ...grpc.CallOption) (*GetAvailableMachineTypesResponse, error)
+	// NewNodeGroup builds a theoretical node group based on the node definition provided. The node
+	// group is not created on the cloud provider side until NodeGroupCreate is called. Optional.
+	NewNodeGroup(ctx context.Context, in *NewNodeGroupRequest, opts ...grpc.CallOption) (*NewNodeGroupResponse, error)
+	// GetResourceLimiter returns limits (max, min) for resources (cores, memory etc.). Optional, the
+	// limits from Cluster Autoscaler flags are used if it is not implemented.
+	GetResourceLimiter(ctx context.Context, in *GetResourceLimiterRequest, opts ...grpc.CallOption) (*GetResourceLimiterResponse, error)
+	// Cleanup cleans up open resources before the cloud provider is destroyed.
+	Cleanup(ctx context.Context, in *CleanupRequest, opts ...grpc.CallOption) (*CleanupResponse, error)
+	// Refresh is called before every main loop and can be used to dynamically update cloud provider
+	// state.
+	Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*RefreshResponse, error)
+	// NodeGroupTargetSize returns the current target size of the node group.
+	NodeGroupTargetSize(ctx context.Context, in *NodeGroupTargetSizeRequest, opts ...grpc.CallOption) (*NodeGroupTargetSizeResponse, error)
+	// NodeGroupIncreaseSize increases the size of the node group.
+	NodeGroupIncreaseSize(ctx context.Context, in *NodeGroupIncreaseSizeRequest, opts ...grpc.CallOption) (*NodeGroupIncreaseSizeResponse, error)
+	// NodeGroupDeleteNodes deletes nodes from the node group and decreases its size.
+	NodeGroupDeleteNodes(ctx context.Context, in *NodeGroupDeleteNodesRequest, opts ...grpc.CallOption) (*NodeGroupDeleteNodesResponse, error)
+	// NodeGroupDecreaseTargetSize decreases the target size of the node group without deleting
+	// any existing node.
+	NodeGroupDecreaseTargetSize(ctx context.Context, in *NodeGroupDecreaseTargetSizeRequest, opts ...grpc.CallOption) (*NodeGroupDecreaseTargetSizeResponse, error)
+	// NodeGroupNodes returns the instances belonging to the node group.
+	NodeGroupNodes(ctx context.Context, in *NodeGroupNodesRequest, opts ...grpc.CallOption) (*NodeGroupNodesResponse, error)
+	// NodeGroupTemplateNodeInfo returns a template of a node in the node group. Optional.
+	NodeGroupTemplateNodeInfo(ctx context.Context, in *NodeGroupTemplateNodeInfoRequest, opts ...grpc.CallOption) (*NodeGroupTemplateNodeInfoResponse, error)
+	// NodeGroupCreate creates a node group built by NewNodeGroup. Optional.
+	NodeGroupCreate(ctx context.Context, in *NodeGroupCreateRequest, opts ...grpc.CallOption) (*NodeGroupCreateResponse, error)
+	// NodeGroupDelete deletes an autoprovisioned node group. Optional.
+	NodeGroupDelete(ctx context.Context, in *NodeGroupDeleteRequest, opts ...grpc.CallOption) (*NodeGroupDeleteResponse, error)
+	// NodeGroupGetOptions returns autoscaling options of the node group. Optional, the defaults are
+	// used if it is not implemented.
+	NodeGroupGetOptions(ctx context.Context, in *NodeGroupGetOptionsRequest, opts ...grpc.CallOption) (*NodeGroupGetOptionsResponse, error)
+}
+
+type cloudProviderClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCloudProviderClient(cc *grpc.ClientConn) CloudProviderClient {
+	return &cloudProviderClient{cc}
+}
+
+func (c *cloudProviderClient) NodeGroups(ctx context.Context, in *NodeGroupsRequest, opts ...grpc.CallOption) (*NodeGroupsResponse, error) {
+	out := new(NodeGroupsResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroups", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupForNode(ctx context.Context, in *NodeGroupForNodeRequest, opts ...grpc.CallOption) (*NodeGroupForNodeResponse, error) {
+	out := new(NodeGroupForNodeResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupForNode", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) PricingNodePrice(ctx context.Context, in *PricingNodePriceRequest, opts ...grpc.CallOption) (*PricingNodePriceResponse, error) {
+	out := new(PricingNodePriceResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/PricingNodePrice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) PricingPodPrice(ctx context.Context, in *PricingPodPriceRequest, opts ...grpc.CallOption) (*PricingPodPriceResponse, error) {
+	out := new(PricingPodPriceResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/PricingPodPrice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) GetAvailableMachineTypes(ctx context.Context, in *GetAvailableMachineTypesRequest, opts ...grpc.CallOption) (*GetAvailableMachineTypesResponse, error) {
+	out := new(GetAvailableMachineTypesResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/GetAvailableMachineTypes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NewNodeGroup(ctx context.Context, in *NewNodeGroupRequest, opts ...grpc.CallOption) (*NewNodeGroupResponse, error) {
+	out := new(NewNodeGroupResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NewNodeGroup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) GetResourceLimiter(ctx context.Context, in *GetResourceLimiterRequest, opts ...grpc.CallOption) (*GetResourceLimiterResponse, error) {
+	out := new(GetResourceLimiterResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/GetResourceLimiter", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) Cleanup(ctx context.Context, in *CleanupRequest, opts ...grpc.CallOption) (*CleanupResponse, error) {
+	out := new(CleanupResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/Cleanup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*RefreshResponse, error) {
+	out := new(RefreshResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/Refresh", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupTargetSize(ctx context.Context, in *NodeGroupTargetSizeRequest, opts ...grpc.CallOption) (*NodeGroupTargetSizeResponse, error) {
+	out := new(NodeGroupTargetSizeResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupTargetSize", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupIncreaseSize(ctx context.Context, in *NodeGroupIncreaseSizeRequest, opts ...grpc.CallOption) (*NodeGroupIncreaseSizeResponse, error) {
+	out := new(NodeGroupIncreaseSizeResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupIncreaseSize", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupDeleteNodes(ctx context.Context, in *NodeGroupDeleteNodesRequest, opts ...grpc.CallOption) (*NodeGroupDeleteNodesResponse, error) {
+	out := new(NodeGroupDeleteNodesResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupDeleteNodes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupDecreaseTargetSize(ctx context.Context, in *NodeGroupDecreaseTargetSizeRequest, opts ...grpc.CallOption) (*NodeGroupDecreaseTargetSizeResponse, error) {
+	out := new(NodeGroupDecreaseTargetSizeResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupDecreaseTargetSize", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupNodes(ctx context.Context, in *NodeGroupNodesRequest, opts ...grpc.CallOption) (*NodeGroupNodesResponse, error) {
+	out := new(NodeGroupNodesResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupNodes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupTemplateNodeInfo(ctx context.Context, in *NodeGroupTemplateNodeInfoRequest, opts ...grpc.CallOption) (*NodeGroupTemplateNodeInfoResponse, error) {
+	out := new(NodeGroupTemplateNodeInfoResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupTemplateNodeInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupCreate(ctx context.Context, in *NodeGroupCreateRequest, opts ...grpc.CallOption) (*NodeGroupCreateResponse, error) {
+	out := new(NodeGroupCreateResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupCreate", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupDelete(ctx context.Context, in *NodeGroupDeleteRequest, opts ...grpc.CallOption) (*NodeGroupDeleteResponse, error) {
+	out := new(NodeGroupDeleteResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupDelete", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudProviderClient) NodeGroupGetOptions(ctx context.Context, in *NodeGroupGetOptionsRequest, opts ...grpc.CallOption) (*NodeGroupGetOptionsResponse, error) {
+	out := new(NodeGroupGetOptionsResponse)
+	err := grpc.Invoke(ctx, "/externalgrpc.CloudProvider/NodeGroupGetOptions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for CloudProvider service
+
+type CloudProviderServer interface {
+	// NodeGroups returns all node groups configured for this cloud provider.
+	NodeGroups(context.Context, *NodeGroupsRequest) (*NodeGroupsResponse, error)
+	// NodeGroupForNode returns the node group for the given node. A node group with an empty id
+	// means that the node should not be processed by Cluster Autoscaler.
+	NodeGroupForNode(context.Context, *NodeGroupForNodeRequest) (*NodeGroupForNodeResponse, error)
+	// PricingNodePrice returns a theoretical minimum price of running a node for a given period of
+	// time on a perfectly matching machine. Optional.
+	PricingNodePrice(context.Context, *PricingNodePriceRequest) (*PricingNodePriceResponse, error)
+	// PricingPodPrice returns a theoretical minimum price of running a pod for a given period of
+	// time on a perfectly matching machine. Optional.
+	PricingPodPrice(context.Context, *PricingPodPriceRequest) (*PricingPodPriceResponse, error)
+	// GetAvailableMachineTypes returns all machine types that can be requested from the cloud
+	// provider. Optional.
+	GetAvailableMachineTypes(context.Context, *GetAvailableMachineTypesRequest) (*GetAvailableMachineTypesResponse, error)
+	// NewNodeGroup builds a theoretical node group based on the node definition provided. The node
+	// group is not created on the cloud provider side until NodeGroupCreate is called. Optional.
+	NewNodeGroup(context.Context, *NewNodeGroupRequest) (*NewNodeGroupResponse, error)
+	// GetResourceLimiter returns limits (max, min) for resources (cores, memory etc.). Optional, the
+	// limits from Cluster Autoscaler flags are used if it is not implemented.
+	GetResourceLimiter(context.Context, *GetResourceLimiterRequest) (*GetResourceLimiterResponse, error)
+	// Cleanup cleans up open resources before the cloud provider is destroyed.
+	Cleanup(context.Context, *CleanupRequest) (*CleanupResponse, error)
+	// Refresh is called before every main loop and can be used to dynamically update cloud provider
+	// state.
+	Refresh(context.Context, *RefreshRequest) (*RefreshResponse, error)
+	// NodeGroupTargetSize returns the current target size of the node group.
+	NodeGroupTargetSize(context.Context, *NodeGroupTargetSizeRequest) (*NodeGroupTargetSizeResponse, error)
+	// NodeGroupIncreaseSize increases the size of the node group.
+	NodeGroupIncreaseSize(context.Context, *NodeGroupIncreaseSizeRequest) (*NodeGroupIncreaseSizeResponse, error)
+	// NodeGroupDeleteNodes deletes nodes from the node group and decreases its size.
+	NodeGroupDeleteNodes(context.Context, *NodeGroupDeleteNodesRequest) (*NodeGroupDeleteNodesResponse, error)
+	// NodeGroupDecreaseTargetSize decreases the target size of the node group without deleting
+	// any existing node.
+	NodeGroupDecreaseTargetSize(context.Context, *NodeGroupDecreaseTargetSizeRequest) (*NodeGroupDecreaseTargetSizeResponse, error)
+	// NodeGroupNodes returns the instances belonging to the node group.
+	NodeGroupNodes(context.Context, *NodeGroupNodesRequest) (*NodeGroupNodesResponse, error)
+	// NodeGroupTemplateNodeInfo returns a template of a node in the node group. Optional.
+	NodeGroupTemplateNodeInfo(context.Context, *NodeGroupTemplateNodeInfoRequest) (*NodeGroupTemplateNodeInfoResponse, error)
+	// NodeGroupCreate creates a node group built by NewNodeGroup. Optional.
+	NodeGroupCreate(context.Context, *NodeGroupCreateRequest) (*NodeGroupCreateResponse, error)
+	// NodeGroupDelete deletes an autoprovisioned node group. Optional.
+	NodeGroupDelete(context.Context, *NodeGroupDeleteRequest) (*NodeGroupDeleteResponse, error)
+	// NodeGroupGetOptions returns autoscaling options of the node group. Optional, the defaults are
+	// used if it is not implemented.
+	NodeGroupGetOptions(context.Context, *NodeGroupGetOptionsRequest) (*NodeGroupGetOptionsResponse, error)
+}
+
+func RegisterCloudProviderServer(s *grpc.Server, srv CloudProviderServer) {
+	s.RegisterService(&_CloudProvider_serviceDesc, srv)
+}
+
+func _CloudProvider_NodeGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroups(ctx, req.(*NodeGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupForNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupForNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupForNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupForNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupForNode(ctx, req.(*NodeGroupForNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_PricingNodePrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PricingNodePriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).PricingNodePrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/PricingNodePrice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).PricingNodePrice(ctx, req.(*PricingNodePriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_PricingPodPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PricingPodPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).PricingPodPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/PricingPodPrice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).PricingPodPrice(ctx, req.(*PricingPodPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_GetAvailableMachineTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAvailableMachineTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).GetAvailableMachineTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/GetAvailableMachineTypes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).GetAvailableMachineTypes(ctx, req.(*GetAvailableMachineTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NewNodeGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewNodeGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NewNodeGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NewNodeGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NewNodeGroup(ctx, req.(*NewNodeGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_GetResourceLimiter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetResourceLimiterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).GetResourceLimiter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/GetResourceLimiter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).GetResourceLimiter(ctx, req.(*GetResourceLimiterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_Cleanup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).Cleanup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/Cleanup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).Cleanup(ctx, req.(*CleanupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).Refresh(ctx, req.(*RefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupTargetSize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupTargetSizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupTargetSize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupTargetSize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupTargetSize(ctx, req.(*NodeGroupTargetSizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupIncreaseSize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupIncreaseSizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupIncreaseSize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupIncreaseSize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupIncreaseSize(ctx, req.(*NodeGroupIncreaseSizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupDeleteNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupDeleteNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupDeleteNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupDeleteNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupDeleteNodes(ctx, req.(*NodeGroupDeleteNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupDecreaseTargetSize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupDecreaseTargetSizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupDecreaseTargetSize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupDecreaseTargetSize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupDecreaseTargetSize(ctx, req.(*NodeGroupDecreaseTargetSizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupNodes(ctx, req.(*NodeGroupNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupTemplateNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupTemplateNodeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupTemplateNodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupTemplateNodeInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupTemplateNodeInfo(ctx, req.(*NodeGroupTemplateNodeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupCreate(ctx, req.(*NodeGroupCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupDelete(ctx, req.(*NodeGroupDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudProvider_NodeGroupGetOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGroupGetOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudProviderServer).NodeGroupGetOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/externalgrpc.CloudProvider/NodeGroupGetOptions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudProviderServer).NodeGroupGetOptions(ctx, req.(*NodeGroupGetOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _CloudProvider_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "externalgrpc.CloudProvider",
+	HandlerType: (*CloudProviderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NodeGroups",
+			Handler:    _CloudProvider_NodeGroups_Handler,
+		},
+		{
+			MethodName: "NodeGroupForNode",
+			Handler:    _CloudProvider_NodeGroupForNode_Handler,
+		},
+		{
+			MethodName: "PricingNodePrice",
+			Handler:    _CloudProvider_PricingNodePrice_Handler,
+		},
+		{
+			MethodName: "PricingPodPrice",
+			Handler:    _CloudProvider_PricingPodPrice_Handler,
+		},
+		{
+			MethodName: "GetAvailableMachineTypes",
+			Handler:    _CloudProvider_GetAvailableMachineTypes_Handler,
+		},
+		{
+			MethodName: "NewNodeGroup",
+			Handler:    _CloudProvider_NewNodeGroup_Handler,
+		},
+		{
+			MethodName: "GetResourceLimiter",
+			Handler:    _CloudProvider_GetResourceLimiter_Handler,
+		},
+		{
+			MethodName: "Cleanup",
+			Handler:    _CloudProvider_Cleanup_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _CloudProvider_Refresh_Handler,
+		},
+		{
+			MethodName: "NodeGroupTargetSize",
+			Handler:    _CloudProvider_NodeGroupTargetSize_Handler,
+		},
+		{
+			MethodName: "NodeGroupIncreaseSize",
+			Handler:    _CloudProvider_NodeGroupIncreaseSize_Handler,
+		},
+		{
+			MethodName: "NodeGroupDeleteNodes",
+			Handler:    _CloudProvider_NodeGroupDeleteNodes_Handler,
+		},
+		{
+			MethodName: "NodeGroupDecreaseTargetSize",
+			Handler:    _CloudProvider_NodeGroupDecreaseTargetSize_Handler,
+		},
+		{
+			MethodName: "NodeGroupNodes",
+			Handler:    _CloudProvider_NodeGroupNodes_Handler,
+		},
+		{
+			MethodName: "NodeGroupTemplateNodeInfo",
+			Handler:    _CloudProvider_NodeGroupTemplateNodeInfo_Handler,
+		},
+		{
+			MethodName: "NodeGroupCreate",
+			Handler:    _CloudProvider_NodeGroupCreate_Handler,
+		},
+		{
+			MethodName: "NodeGroupDelete",
+			Handler:    _CloudProvider_NodeGroupDelete_Handler,
+		},
+		{
+			MethodName: "NodeGroupGetOptions",
+			Handler:    _CloudProvider_NodeGroupGetOptions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "externalgrpc.proto",
+}
+
+func init() { proto.RegisterFile("externalgrpc.proto", fileDescriptor_externalgrpc_42c42934c42f47b4) }
+
+var fileDescriptor_externalgrpc_42c42934c42f47b4 = []byte{
+	// 1598 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xdf, 0x52, 0x14, 0x47,
+	0x17, 0x67, 0x77, 0x01, 0xd9, 0x03, 0x2c, 0x4b, 0x83, 0x30, 0x0e, 0x20, 0x6b, 0x2b, 0xba, 0xf2,
+	0x7d, 0xa2, 0x1f, 0x6a, 0xe9, 0x97, 0x8a, 0x95, 0x22, 0x80, 0xc4, 0x44, 0x14, 0x47, 0x2c, 0xab,
+	0x52, 0x65, 0x55, 0x9a, 0x9d, 0x06, 0xa6, 0x9c, 0x9d, 0xde, 0x4c, 0xf7, 0x0a, 0x98, 0xaa, 0x5c,
+	0x79, 0x95, 0xaa, 0x54, 0x9e, 0x26, 0xaf, 0x90, 0xcb, 0xbc, 0x4a, 0x5e, 0x21, 0x35, 0x3d, 0x3d,
+	0xb3, 0xf3, 0xaf, 0x77, 0x41, 0xaf, 0xd8, 0x3e, 0xe7, 0x77, 0x7e, 0xbf, 0xd3, 0xd3, 0xa7, 0xbb,
+	0x4f, 0x03, 0x88, 0x9e, 0x0a, 0xea, 0x7b, 0xc4, 0x3d, 0xf2, 0x3b, 0xad, 0xb5, 0x8e, 0xcf, 0x04,
+	0x43, 0x13, 0x49, 0x1b, 0xfe, 0xa3, 0x04, 0xd5, 0x17, 0xcc, 0xa6, 0x3b, 0x3e, 0xeb, 0x76, 0x50,
+	0x0d, 0xca, 0x8e, 0x6d, 0x94, 0x1a, 0xa5, 0x66, 0xd5, 0x2a, 0x3b, 0x36, 0x32, 0xe0, 0x52, 0xdb,
+	0xf1, 0x5e, 0x3b, 0x1f, 0xa9, 0x51, 0x6e, 0x94, 0x9a, 0x23, 0x56, 0x34, 0x94, 0x1e, 0x72, 0x2a,
+	0x3d, 0x15, 0xe5, 0x09, 0x87, 0x68, 0x16, 0x46, 0x6c, 0x7a, 0xd0, 0x3d, 0x32, 0x86, 0x25, 0x4d,
+	0x38, 0x40, 0x4d, 0x98, 0x22, 0x5d, 0xc1, 0x3a, 0x3e, 0xfb, 0xe0, 0x70, 0x87, 0x79, 0xd4, 0x36,
+	0x46, 0x1a, 0xa5, 0xe6, 0x98, 0x95, 0x35, 0xe3, 0x19, 0x98, 0x8e, 0x13, 0xe2, 0x16, 0xfd, 0xb9,
+	0x4b, 0xb9, 0xc0, 0xbb, 0x80, 0x92, 0x46, 0xde, 0x61, 0x1e, 0xa7, 0xe8, 0x11, 0x80, 0x17, 0x5b,
+	0x8d, 0x52, 0xa3, 0xd2, 0x1c, 0x5f, 0x9f, 0x5f, 0x4b, 0xcd, 0x39, 0x8e, 0xb2, 0x12, 0x50, 0x7c,
+	0x07, 0xe6, 0x63, 0xc7, 0x53, 0xe6, 0x07, 0xbf, 0x95, 0x12, 0x42, 0x30, 0x1c, 0x00, 0xe5, 0x47,
+	0x98, 0xb0, 0xe4, 0x6f, 0xfc, 0x0a, 0x8c, 0x3c, 0x5c, 0xe5, 0xf0, 0x10, 0xaa, 0x31, 0xb1, 0x0c,
+	0xea, 0x93, 0x42, 0x0f, 0x89, 0x29, 0xcc, 0xef, 0xf9, 0x4e, 0xcb, 0xf1, 0x8e, 0x02, 0x77, 0xf0,
+	0xb3, 0x5f, 0x06, 0x68, 0x11, 0xaa, 0x5c, 0x10, 0x5f, 0xec, 0x3b, 0xed, 0x70, 0x29, 0x2a, 0x56,
+	0xcf, 0x10, 0x2c, 0x06, 0xf5, 0x6c, 0xe9, 0xab, 0x48, 0x5f, 0x34, 0xc4, 0xf7, 0xc0, 0xc8, 0xcb,
+	0xa8, 0xcc, 0x67, 0x61, 0xa4, 0x13, 0x18, 0xa4, 0x50, 0xc9, 0x0a, 0x07, 0xf8, 0x00, 0xe6, 0x54,
+	0xc4, 0x1e, 0xb3, 0x53, 0x79, 0xd5, 0xa1, 0xd2, 0x61, 0xb6, 0x4a, 0x2b, 0xf8, 0xf9, 0xd9, 0x59,
+	0xdd, 0x85, 0xf9, 0x9c, 0x46, 0xdf, 0xa4, 0xae, 0xc1, 0xf2, 0x0e, 0x15, 0x1b, 0x1f, 0x88, 0xe3,
+	0x92, 0x03, 0x97, 0xee, 0x92, 0xd6, 0xb1, 0xe3, 0xd1, 0xfd, 0xb3, 0x0e, 0x8d, 0x2b, 0xe4, 0x29,
+	0x34, 0xf4, 0x10, 0x45, 0x8e, 0x61, 0xa2, 0x9d, 0xb0, 0xcb, 0x8a, 0xa9, 0x5a, 0x29, 0x1b, 0xfe,
+	0x7d, 0x18, 0x66, 0x5e, 0xd0, 0x93, 0xde, 0xa2, 0xa9, 0xd9, 0x37, 0x60, 0x3c, 0x81, 0x53, 0x7b,
+	0x24, 0x69, 0x42, 0xdb, 0x30, 0xea, 0x92, 0x03, 0xea, 0x72, 0xa3, 0x2c, 0x2b, 0xf1, 0x4e, 0xa6,
+	0x0c, 0xf2, 0xa4, 0x6b, 0xcf, 0x25, 0x7e, 0xdb, 0x13, 0xfe, 0x99, 0xa5, 0x82, 0xd1, 0x5b, 0x98,
+	0xe0, 0x67, 0x5c, 0xd0, 0x76, 0xe8, 0x34, 0x2a, 0x92, 0xec, 0xfe, 0x60, 0xb2, 0xd7, 0x89, 0xa8,
+	0x90, 0x32, 0x45, 0x84, 0xe6, 0x60, 0x54, 0x10, 0xc7, 0x13, 0xdc, 0x18, 0x6e, 0x54, 0x9a, 0x13,
+	0x96, 0x1a, 0xa1, 0x77, 0x50, 0xa3, 0xa7, 0xc2, 0x27, 0x16, 0xe5, 0xac, 0xeb, 0xb7, 0x28, 0x37,
+	0x46, 0xa4, 0xe4, 0xc3, 0xc1, 0x92, 0xdb, 0xa9, 0xb8, 0x50, 0x34, 0x43, 0x66, 0xfe, 0x1f, 0xc6,
+	0x13, 0x39, 0x05, 0x55, 0xf4, 0x9e, 0x9e, 0xa9, 0xef, 0x17, 0xfc, 0x0c, 0x96, 0xfc, 0x03, 0x71,
+	0xbb, 0x61, 0x05, 0x55, 0xad, 0x70, 0xf0, 0x55, 0xf9, 0x71, 0xc9, 0xfc, 0x06, 0xa6, 0x73, 0x93,
+	0xba, 0x10, 0xc1, 0x06, 0xcc, 0x14, 0xa4, 0x78, 0x11, 0x0a, 0xbc, 0x0b, 0xb3, 0xe9, 0x99, 0x7f,
+	0xd9, 0xbe, 0x5f, 0x80, 0x2b, 0x3b, 0x54, 0x44, 0xf9, 0x3c, 0x77, 0xda, 0x8e, 0xa0, 0x7e, 0x54,
+	0xc3, 0x7f, 0x95, 0xc1, 0x2c, 0xf2, 0x2a, 0xc9, 0x37, 0x50, 0x6d, 0x3b, 0x9e, 0xb4, 0x46, 0xa7,
+	0xdd, 0xa3, 0xb4, 0xa4, 0x3e, 0x78, 0x6d, 0x37, 0x8a, 0x0c, 0x57, 0xa9, 0xc7, 0x24, 0x69, 0xc9,
+	0xa9, 0xa2, 0x2d, 0x5f, 0x94, 0x96, 0x9c, 0xa6, 0x69, 0xa3, 0xb1, 0xf9, 0x35, 0xd4, 0xd2, 0x9a,
+	0x83, 0x3e, 0x7b, 0x25, 0xb9, 0x72, 0x41, 0x34, 0x39, 0xfd, 0xcc, 0x68, 0x5c, 0x87, 0xda, 0xa6,
+	0x4b, 0x89, 0x17, 0x57, 0x2a, 0x9e, 0x86, 0xa9, 0xd8, 0x12, 0xa6, 0x1e, 0x80, 0x2c, 0x7a, 0xe8,
+	0x53, 0x7e, 0x9c, 0x00, 0xc5, 0x16, 0x05, 0xfa, 0x2f, 0x98, 0xf1, 0x3a, 0xee, 0x13, 0xff, 0x88,
+	0x8a, 0xe0, 0x92, 0x8b, 0x0e, 0x85, 0xcc, 0x7d, 0x89, 0x9f, 0xc0, 0x42, 0x21, 0x5a, 0x2d, 0xe0,
+	0x55, 0x00, 0x11, 0x5b, 0x65, 0xd8, 0x88, 0x95, 0xb0, 0xe0, 0x2d, 0x58, 0x8c, 0xc3, 0x9f, 0x79,
+	0x2d, 0x9f, 0x12, 0x4e, 0xfb, 0xc8, 0x85, 0x57, 0xad, 0x2b, 0x88, 0xba, 0x9c, 0xc3, 0x01, 0x5e,
+	0x86, 0x25, 0x0d, 0x8b, 0x9a, 0xd3, 0x66, 0x22, 0xcb, 0x2d, 0xea, 0x52, 0x41, 0x83, 0x21, 0xef,
+	0xa3, 0x12, 0xd4, 0x6f, 0x58, 0x1b, 0x13, 0x56, 0x38, 0xc0, 0x57, 0x61, 0xb1, 0x98, 0x44, 0x89,
+	0x7c, 0x0f, 0x38, 0xe1, 0x0f, 0xb3, 0x18, 0xf8, 0x01, 0x35, 0x33, 0x5a, 0x81, 0xeb, 0x7d, 0xb9,
+	0x94, 0xe4, 0x2d, 0xb8, 0x1c, 0xc3, 0xfa, 0xcd, 0x08, 0xbf, 0x80, 0xb9, 0x2c, 0x50, 0xad, 0xd0,
+	0x03, 0xa8, 0x3a, 0x1e, 0x17, 0xc4, 0x6b, 0xd1, 0x68, 0x8b, 0xcd, 0xa5, 0xf7, 0xc2, 0x33, 0xe5,
+	0xb6, 0x7a, 0x40, 0xbc, 0x07, 0x63, 0x91, 0x39, 0x37, 0xa3, 0x07, 0x30, 0xca, 0x05, 0x11, 0x5d,
+	0x2e, 0xa7, 0x34, 0xbe, 0xbe, 0x58, 0x4c, 0xf7, 0x5a, 0x62, 0x2c, 0x85, 0xc5, 0x9f, 0xca, 0x50,
+	0x4b, 0xbb, 0xd0, 0x1e, 0x4c, 0x3a, 0x09, 0x4b, 0x58, 0x3f, 0xb5, 0xf5, 0xd5, 0x7e, 0x7c, 0xa9,
+	0x21, 0xb5, 0xd2, 0x04, 0xe8, 0x09, 0x54, 0xa9, 0xef, 0x33, 0xff, 0x99, 0x77, 0xc8, 0x54, 0x76,
+	0xcb, 0xc5, 0x6c, 0xdb, 0x11, 0xcc, 0xea, 0x45, 0x60, 0x02, 0x93, 0x29, 0x7a, 0x34, 0x05, 0xe3,
+	0x5d, 0x8f, 0x77, 0x68, 0xcb, 0x39, 0x74, 0xa8, 0x5d, 0x1f, 0x42, 0x33, 0x30, 0x15, 0x29, 0x5a,
+	0x5d, 0xcf, 0x73, 0xbc, 0xa3, 0x7a, 0x09, 0xcd, 0x42, 0x3d, 0x32, 0x6e, 0xfa, 0x94, 0x88, 0xc0,
+	0x5a, 0x4e, 0x5a, 0x65, 0x35, 0x05, 0xd6, 0x0a, 0xfe, 0xa7, 0x04, 0xd3, 0xb9, 0x1c, 0x90, 0x05,
+	0x20, 0xb3, 0xd8, 0x74, 0x09, 0xe7, 0xea, 0x33, 0xac, 0x0f, 0x48, 0x3c, 0x6d, 0x91, 0x91, 0x56,
+	0x82, 0x25, 0x68, 0x65, 0xc2, 0x51, 0xd0, 0x79, 0x85, 0x97, 0x40, 0xcf, 0x10, 0x34, 0x0e, 0x72,
+	0xb0, 0x4b, 0x39, 0x27, 0x47, 0x61, 0x3f, 0x53, 0xb5, 0x52, 0x36, 0xbc, 0x05, 0x28, 0xaf, 0x91,
+	0xff, 0x26, 0x08, 0x6a, 0xac, 0x2b, 0x5e, 0x1e, 0xc6, 0x57, 0x52, 0xbd, 0x84, 0xaa, 0x30, 0xc2,
+	0xc4, 0x31, 0xf5, 0xeb, 0x2d, 0xbc, 0x0e, 0x8d, 0xde, 0x09, 0x42, 0xdb, 0x1d, 0x97, 0x84, 0x1b,
+	0x4b, 0x7e, 0x7c, 0x4d, 0x39, 0xff, 0x00, 0xd7, 0xfa, 0xc4, 0xa8, 0xca, 0x2e, 0xea, 0x2a, 0x11,
+	0x0c, 0x77, 0x98, 0x1d, 0x6d, 0x6c, 0xf9, 0x1b, 0x37, 0x13, 0x7b, 0x43, 0xae, 0x8f, 0xf6, 0xb0,
+	0xdb, 0x83, 0xf9, 0x1c, 0xf2, 0xcb, 0x2e, 0xc7, 0xa4, 0x76, 0x78, 0xa6, 0xe8, 0xb4, 0xaf, 0xc0,
+	0x7c, 0x0e, 0xa9, 0x4e, 0x81, 0x3f, 0x2b, 0x89, 0xe3, 0x6d, 0xa3, 0x2b, 0x18, 0x6f, 0x11, 0xd7,
+	0xf1, 0x8e, 0x5e, 0x76, 0x84, 0xc3, 0x3c, 0x8e, 0xb6, 0x60, 0x29, 0xb0, 0xd0, 0x2d, 0x76, 0xe2,
+	0xbd, 0x11, 0x8e, 0xeb, 0x7c, 0x24, 0x81, 0x63, 0xff, 0x38, 0x38, 0xf7, 0x99, 0x6b, 0xab, 0xce,
+	0xb3, 0x3f, 0x08, 0x3d, 0x80, 0xcb, 0x3d, 0x80, 0xe7, 0x51, 0x6a, 0x53, 0x3b, 0xd1, 0x06, 0x17,
+	0x3b, 0xd1, 0x3a, 0xcc, 0x26, 0x1c, 0x3e, 0x25, 0xf6, 0x59, 0xa2, 0x3f, 0x2e, 0xf4, 0x05, 0x31,
+	0x6d, 0x72, 0x1a, 0xb6, 0xef, 0xea, 0x95, 0x24, 0x63, 0x86, 0xc3, 0x98, 0x22, 0x1f, 0x7a, 0x0c,
+	0xf3, 0x31, 0xd7, 0x86, 0xeb, 0xb2, 0x13, 0x6a, 0xbf, 0x75, 0x3c, 0x9b, 0x9d, 0x70, 0xf9, 0xea,
+	0xaa, 0x5a, 0x3a, 0x37, 0x5a, 0x85, 0xfa, 0x31, 0x25, 0xb6, 0xcf, 0x58, 0x7b, 0xd7, 0x71, 0x5d,
+	0x67, 0xb3, 0xd3, 0x35, 0x46, 0xa5, 0x52, 0xce, 0x8e, 0x6e, 0x42, 0x2d, 0xb6, 0xd1, 0x36, 0xf3,
+	0xcf, 0x8c, 0x4b, 0x12, 0x99, 0xb1, 0xa2, 0x1b, 0x30, 0x19, 0x59, 0xe4, 0x69, 0x6b, 0x8c, 0xc9,
+	0xc3, 0x3d, 0x6d, 0xc4, 0x3c, 0x71, 0xd3, 0xee, 0x50, 0xa1, 0x96, 0x4b, 0x77, 0x51, 0x6c, 0xc3,
+	0x98, 0x4d, 0x0f, 0x49, 0xd7, 0x15, 0xd1, 0xc1, 0x7a, 0x5b, 0x53, 0x60, 0xf9, 0x12, 0xb0, 0xe2,
+	0x50, 0xfc, 0x5b, 0x09, 0x16, 0x0a, 0x55, 0x55, 0x21, 0xbf, 0x87, 0x05, 0x4f, 0x4f, 0x64, 0x94,
+	0x2e, 0xaa, 0xdc, 0x8f, 0x6d, 0xfd, 0xef, 0x1a, 0x4c, 0x6e, 0xba, 0xac, 0x6b, 0xcb, 0xc5, 0xb4,
+	0xa9, 0x8f, 0x5e, 0x01, 0xc4, 0x6c, 0x1c, 0x2d, 0x6b, 0x74, 0xa2, 0x8f, 0x64, 0x36, 0xf4, 0x00,
+	0xb5, 0x39, 0x86, 0x50, 0x0b, 0xea, 0xd9, 0xb7, 0x2c, 0x5a, 0xd1, 0xc4, 0xa5, 0x9f, 0xc6, 0xe6,
+	0xcd, 0x41, 0xb0, 0xa4, 0x48, 0xf6, 0xd9, 0x99, 0x15, 0xd1, 0xbc, 0x7e, 0xb3, 0x22, 0xba, 0xd7,
+	0x2b, 0x1e, 0x42, 0x3f, 0xc1, 0x54, 0xe6, 0x15, 0x89, 0x6e, 0x14, 0x06, 0x67, 0x1e, 0xb2, 0xe6,
+	0xca, 0x00, 0x54, 0xac, 0xf0, 0x0b, 0x18, 0xba, 0x37, 0x25, 0xba, 0x93, 0x6b, 0x91, 0xfb, 0x3d,
+	0x4f, 0xcd, 0xb5, 0xf3, 0xc2, 0x63, 0xf1, 0xb7, 0x30, 0x91, 0x7c, 0x78, 0xa0, 0x6b, 0x03, 0x9f,
+	0x63, 0x26, 0xee, 0x07, 0x89, 0x89, 0x1d, 0x40, 0xf9, 0x86, 0x1e, 0xdd, 0x1a, 0xdc, 0xf2, 0x87,
+	0x22, 0xcd, 0xf3, 0xbe, 0x0d, 0xf0, 0x10, 0xfa, 0x0e, 0x2e, 0xa9, 0xae, 0x1b, 0x65, 0xfa, 0x9e,
+	0x74, 0x7b, 0x6e, 0x2e, 0x69, 0xbc, 0x49, 0x26, 0xd5, 0x9a, 0x67, 0x99, 0xd2, 0x3d, 0xbc, 0xb9,
+	0xa4, 0xf1, 0xc6, 0x4c, 0x2e, 0xcc, 0x14, 0xf4, 0xe8, 0xa8, 0xa9, 0x29, 0xee, 0x5c, 0xcf, 0x6a,
+	0xde, 0x3e, 0x07, 0x32, 0x56, 0xf3, 0xe1, 0x72, 0x61, 0x33, 0x8e, 0x56, 0x35, 0x2c, 0x05, 0x7d,
+	0xbf, 0xf9, 0x9f, 0x73, 0x61, 0x63, 0x4d, 0x06, 0xb3, 0x45, 0xad, 0x39, 0xd2, 0x25, 0x9e, 0x7f,
+	0x03, 0x98, 0xab, 0xe7, 0x81, 0xc6, 0x82, 0x9f, 0x4a, 0xa9, 0x17, 0x45, 0xb6, 0x41, 0x47, 0xf7,
+	0xb4, 0x6c, 0x9a, 0x77, 0x81, 0xf9, 0xbf, 0x0b, 0x44, 0xc4, 0x69, 0xbc, 0x83, 0x5a, 0xba, 0xad,
+	0x47, 0xd7, 0x35, 0x34, 0xa9, 0xb9, 0xde, 0xe8, 0x0f, 0x8a, 0xe9, 0x7f, 0x85, 0x2b, 0xda, 0x36,
+	0x0b, 0xad, 0xe9, 0x8a, 0xa2, 0xb8, 0x87, 0x33, 0xef, 0x9e, 0x1b, 0x9f, 0x3c, 0xef, 0x32, 0xfd,
+	0x16, 0xd2, 0xa5, 0x9e, 0x6a, 0xdc, 0xcc, 0x95, 0x01, 0xa8, 0x42, 0x85, 0x70, 0xa5, 0xb5, 0x0a,
+	0xa9, 0xf6, 0xcc, 0x5c, 0x19, 0x80, 0x2a, 0xdc, 0x7c, 0xbd, 0xeb, 0x56, 0xbb, 0xf9, 0x72, 0x7d,
+	0x80, 0x79, 0xfb, 0x1c, 0xc8, 0x48, 0xed, 0xdb, 0xb1, 0x1f, 0x47, 0xe5, 0xff, 0xbc, 0xf9, 0x41,
+	0xf8, 0xf7, 0xfe, 0xbf, 0x03, 0x00, 0xcc, 0x65, 0x89, 0x9f, 0x11, 0x17, 0x00, 0x00,
+}
@@ -0,0 +1,30 @@
+#!/bin/bash
+
+# Copyright 2019 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Regenerates externalgrpc.pb.go from externalgrpc.proto. Requires protoc 3.5.1 and
+# protoc-gen-go v1.1.0 (the vendored github.com/golang/protobuf version) on PATH.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+cd "$(dirname "${BASH_SOURCE}")"
+boilerplate=../../../../hack/boilerplate/boilerplate.generatego.txt
+
+protoc -I . --go_out=plugins=grpc:. externalgrpc.proto
+{ cat "${boilerplate}"; echo; cat externalgrpc.pb.go; } > externalgrpc.pb.go.tmp
+mv externalgrpc.pb.go.tmp externalgrpc.pb.go
+gofmt -w externalgrpc.pb.go
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//go:generate ./generate.sh
+
+// Package protos contains messages and the gRPC service of the external cloud provider protocol,
+// defined in externalgrpc.proto, together with helpers serializing the Kubernetes objects they carry.
+package protos
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// MarshalNodes serializes nodes for NodeGroupDeleteNodesRequest.Nodes.
+func MarshalNodes(nodes []*v1.Node) ([][]byte, error) {
+	result := make([][]byte, 0, len(nodes))
+	for _, node := range nodes {
+		data, err := node.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
+
+// UnmarshalNode deserializes a node.
+func UnmarshalNode(data []byte) (*v1.Node, error) {
+	node := &v1.Node{}
+	if err := node.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// UnmarshalNodes deserializes nodes from NodeGroupDeleteNodesRequest.Nodes.
+func UnmarshalNodes(data [][]byte) ([]*v1.Node, error) {
+	result := make([]*v1.Node, 0, len(data))
+	for _, nodeData := range data {
+		node, err := UnmarshalNode(nodeData)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, node)
+	}
+	return result, nil
+}
+
+// MarshalPods serializes pods for NodeGroupTemplateNodeInfoResponse.Pods.
+func MarshalPods(pods []*v1.Pod) ([][]byte, error) {
+	result := make([][]byte, 0, len(pods))
+	for _, pod := range pods {
+		data, err := pod.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
+
+// UnmarshalPods deserializes pods from NodeGroupTemplateNodeInfoResponse.Pods.
+func UnmarshalPods(data [][]byte) ([]*v1.Pod, error) {
+	result := make([]*v1.Pod, 0, len(data))
+	for _, podData := range data {
+		pod := &v1.Pod{}
+		if err := pod.Unmarshal(podData); err != nil {
+			return nil, err
+		}
+		result = append(result, pod)
+	}
+	return result, nil
+}
+
+// MarshalTaints serializes taints for NewNodeGroupRequest.Taints.
+func MarshalTaints(taints []v1.Taint) ([][]byte, error) {
+	result := make([][]byte, 0, len(taints))
+	for i := range taints {
+		data, err := taints[i].Marshal()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
+
+// UnmarshalTaints deserializes taints from NewNodeGroupRequest.Taints.
+func UnmarshalTaints(data [][]byte) ([]v1.Taint, error) {
+	result := make([]v1.Taint, 0, len(data))
+	for _, taintData := range data {
+		taint := v1.Taint{}
+		if err := taint.Unmarshal(taintData); err != nil {
+			return nil, err
+		}
+		result = append(result, taint)
+	}
+	return result, nil
+}
@@ -410,6 +410,12 @@ func (mig *GkeMig) Autoprovisioned() bool {
 	return mig.autoprovisioned
 }
 
+// GetOptions returns NodeGroupAutoscalingOptions that should be used for this particular
+// NodeGroup. Returning ErrNotImplemented will cause global defaults to be used.
+func (mig *GkeMig) GetOptions(defaults config.NodeGroupAutoscalingOptions) (*config.NodeGroupAutoscalingOptions, error) {
+	return nil, cloudprovider.ErrNotImplemented
+}
+
 // TemplateNodeInfo returns a node template for this node group.
 func (mig *GkeMig) TemplateNodeInfo() (*schedulercache.NodeInfo, error) {
 	node, err := mig.gkeManager.GetMigTemplateNode(mig)
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"io/ioutil"
+	"math"
+	"time"
+
+	"github.com/ghodss/yaml"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+)
+
+// AllRegions is the region key of InstancePrices entries that apply to all regions.
+const AllRegions = "*"
+
+// InstancePrices maps regions to hourly prices of instance types in them, in USD. It is read from
+// the file passed with the --instance-prices-file flag and overrides the prices bundled with
+// cloud providers.
+type InstancePrices map[string]map[string]float64
+
+// ReadInstancePrices reads instance prices from a YAML or JSON file, e.g.:
+//
+//	us-east-1:
+//	  m5.large: 0.096
+//	"*":
+//	  m5.xlarge: 0.192
+func ReadInstancePrices(path string) (InstancePrices, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	prices := InstancePrices{}
+	if err := yaml.Unmarshal(data, &prices); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// Price returns the hourly price of the instance type in the region. Prices set for the region
+// take precedence over prices set for AllRegions.
+func (p InstancePrices) Price(region, instanceType string) (float64, bool) {
+	if price, found := p[region][instanceType]; found {
+		return price, true
+	}
+	price, found := p[AllRegions][instanceType]
+	return price, found
+}
+
+// ResourcePrices are hourly prices of compute resources, in USD. Cloud providers use them to price
+// pods and nodes of instance types without a known price.
+type ResourcePrices struct {
+	// CpuPerHour is the price of a vCPU.
+	CpuPerHour float64
+	// MemoryPerGbPerHour is the price of a GiB of memory.
+	MemoryPerGbPerHour float64
+	// GpuPerHour is the price of a GPU.
+	GpuPerHour float64
+	// GpuResource is the resource name under which GPUs are requested and reported by nodes.
+	GpuResource apiv1.ResourceName
+}
+
+// InstancePrice returns the hourly price of an instance with the given number of vCPUs, GiBs of
+// memory and GPUs.
+func (p ResourcePrices) InstancePrice(vcpu int64, memoryGb float64, gpu int64) float64 {
+	return float64(vcpu)*p.CpuPerHour + memoryGb*p.MemoryPerGbPerHour + float64(gpu)*p.GpuPerHour
+}
+
+// Price returns the price of the cpu, memory and GPUs in resources for the given period of time.
+func (p ResourcePrices) Price(resources apiv1.ResourceList, startTime time.Time, endTime time.Time) float64 {
+	if len(resources) == 0 {
+		return 0
+	}
+	cpu := resources[apiv1.ResourceCPU]
+	mem := resources[apiv1.ResourceMemory]
+	gpu := resources[p.GpuResource]
+	price := float64(cpu.MilliValue()) / 1000.0 * p.CpuPerHour
+	price += float64(mem.Value()) / float64(units.GiB) * p.MemoryPerGbPerHour
+	price += float64(gpu.MilliValue()) / 1000.0 * p.GpuPerHour
+	return price * PriceHours(startTime, endTime)
+}
+
+// PriceHours returns the number of hours between startTime and endTime that are paid for, with
+// minute granularity.
+func PriceHours(startTime time.Time, endTime time.Time) float64 {
+	minutes := math.Ceil(float64(endTime.Sub(startTime)) / float64(time.Minute))
+	return minutes / 60.0
+}
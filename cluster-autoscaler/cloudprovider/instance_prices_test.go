@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestReadInstancePrices(t *testing.T) {
+	dir, err := ioutil.TempDir("", "instance-prices")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "prices.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("us-east-1:\n  m5.large: 0.096\n\"*\":\n  m5.large: 0.1\n  m5.xlarge: 0.2\n"), 0644))
+
+	prices, err := ReadInstancePrices(path)
+	assert.NoError(t, err)
+
+	price, found := prices.Price("us-east-1", "m5.large")
+	assert.True(t, found)
+	assert.Equal(t, 0.096, price)
+	price, found = prices.Price("eu-west-1", "m5.large")
+	assert.True(t, found)
+	assert.Equal(t, 0.1, price)
+	price, found = prices.Price("us-east-1", "m5.xlarge")
+	assert.True(t, found)
+	assert.Equal(t, 0.2, price)
+	_, found = prices.Price("us-east-1", "c5.large")
+	assert.False(t, found)
+
+	// Lookups in empty prices don't fail.
+	_, found = InstancePrices(nil).Price("us-east-1", "m5.large")
+	assert.False(t, found)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("us-east-1: [m5.large]\n"), 0644))
+	_, err = ReadInstancePrices(path)
+	assert.Error(t, err)
+}
+
+func TestResourcePrices(t *testing.T) {
+	prices := ResourcePrices{
+		CpuPerHour:         0.03,
+		MemoryPerGbPerHour: 0.004,
+		GpuPerHour:         0.5,
+		GpuResource:        "nvidia.com/gpu",
+	}
+	assert.InDelta(t, 4*0.03+16*0.004+0.5, prices.InstancePrice(4, 16, 1), 1e-9)
+
+	now := time.Now()
+	resources := apiv1.ResourceList{
+		apiv1.ResourceCPU:    resource.MustParse("500m"),
+		apiv1.ResourceMemory: resource.MustParse("2Gi"),
+		"nvidia.com/gpu":     resource.MustParse("1"),
+	}
+	assert.InDelta(t, 2*(0.5*0.03+2*0.004+0.5), prices.Price(resources, now, now.Add(2*time.Hour)), 1e-9)
+	// Started minutes are paid for in full.
+	assert.InDelta(t, (0.5*0.03+2*0.004+0.5)/60, prices.Price(resources, now, now.Add(time.Second)), 1e-9)
+	assert.Equal(t, 0.0, prices.Price(nil, now, now.Add(time.Hour)))
+}
@@ -29,11 +29,15 @@ const (
 	autoDiscovererTypeASG   = "asg"
 	autoDiscovererTypeLabel = "label"
 
+	autoDiscovererTypeMachineAPI = "machineapi"
+
 	migAutoDiscovererKeyPrefix   = "namePrefix"
 	migAutoDiscovererKeyMinNodes = "min"
 	migAutoDiscovererKeyMaxNodes = "max"
 
 	asgAutoDiscovererKeyTag = "tag"
+
+	machineAPIAutoDiscovererKeyNamespace = "namespace"
 )
 
 var validMIGAutoDiscovererKeys = strings.Join([]string{
@@ -108,6 +112,20 @@ func (o NodeGroupDiscoveryOptions) ParseLabelAutoDiscoverySpecs() ([]LabelAutoDi
 	return cfgs, nil
 }
 
+// ParseMachineAPIAutoDiscoverySpecs returns any provided NodeGroupAutoDiscoverySpecs
+// parsed into configuration appropriate for Machine API autodiscovery.
+func (o NodeGroupDiscoveryOptions) ParseMachineAPIAutoDiscoverySpecs() ([]MachineAPIAutoDiscoveryConfig, error) {
+	cfgs := make([]MachineAPIAutoDiscoveryConfig, len(o.NodeGroupAutoDiscoverySpecs))
+	var err error
+	for i, spec := range o.NodeGroupAutoDiscoverySpecs {
+		cfgs[i], err = parseMachineAPIAutoDiscoverySpec(spec)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cfgs, nil
+}
+
 // A MIGAutoDiscoveryConfig specifies how to autodiscover GCE MIGs.
 type MIGAutoDiscoveryConfig struct {
 	// Re is a regexp passed using the eq filter to the GCE list API.
@@ -245,3 +263,43 @@ func parseLabelAutoDiscoverySpec(spec string) (LabelAutoDiscoveryConfig, error)
 
 	return cfg, nil
 }
+
+// A MachineAPIAutoDiscoveryConfig specifies how to autodiscover Machine API
+// MachineSets and MachineDeployments.
+type MachineAPIAutoDiscoveryConfig struct {
+	// Namespace to match on. All namespaces match if empty.
+	Namespace string
+	// Label key-values to match on.
+	Selector map[string]string
+}
+
+func parseMachineAPIAutoDiscoverySpec(spec string) (MachineAPIAutoDiscoveryConfig, error) {
+	cfg := MachineAPIAutoDiscoveryConfig{
+		Selector: make(map[string]string),
+	}
+
+	tokens := strings.Split(spec, ":")
+	if len(tokens) != 2 {
+		return cfg, fmt.Errorf("spec \"%s\" should be discoverer:key=value,key=value", spec)
+	}
+	discoverer := tokens[0]
+	if discoverer != autoDiscovererTypeMachineAPI {
+		return cfg, fmt.Errorf("unsupported discoverer specified: %s", discoverer)
+	}
+
+	for _, arg := range strings.Split(tokens[1], ",") {
+		kv := strings.Split(arg, "=")
+		if len(kv) != 2 || kv[0] == "" {
+			return cfg, fmt.Errorf("invalid key=value pair %s", kv)
+		}
+
+		k, v := kv[0], kv[1]
+		if k == machineAPIAutoDiscovererKeyNamespace {
+			cfg.Namespace = v
+			continue
+		}
+		cfg.Selector[k] = v
+	}
+
+	return cfg, nil
+}
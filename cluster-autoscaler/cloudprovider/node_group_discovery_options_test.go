@@ -187,3 +187,59 @@ func TestParseASGAutoDiscoverySpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMachineAPIAutoDiscoverySpecs(t *testing.T) {
+	cases := []struct {
+		name    string
+		specs   []string
+		want    []MachineAPIAutoDiscoveryConfig
+		wantErr bool
+	}{
+		{
+			name: "GoodSpecs",
+			specs: []string{
+				"machineapi:namespace=openshift-machine-api",
+				"machineapi:machine.openshift.io/cluster-api-machine-role=worker,tenant=a",
+				"machineapi:namespace=tenant-b,tenant=b",
+			},
+			want: []MachineAPIAutoDiscoveryConfig{
+				{Namespace: "openshift-machine-api", Selector: map[string]string{}},
+				{Selector: map[string]string{"machine.openshift.io/cluster-api-machine-role": "worker", "tenant": "a"}},
+				{Namespace: "tenant-b", Selector: map[string]string{"tenant": "b"}},
+			},
+		},
+		{
+			name:    "MissingMachineAPIType",
+			specs:   []string{"namespace=openshift-machine-api"},
+			wantErr: true,
+		},
+		{
+			name:    "WrongType",
+			specs:   []string{"label:namespace=openshift-machine-api"},
+			wantErr: true,
+		},
+		{
+			name:    "ValueMissingKey",
+			specs:   []string{"machineapi:=worker"},
+			wantErr: true,
+		},
+		{
+			name:    "KeyMissingSeparator",
+			specs:   []string{"machineapi:namespace"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			do := NodeGroupDiscoveryOptions{NodeGroupAutoDiscoverySpecs: tc.specs}
+			got, err := do.ParseMachineAPIAutoDiscoverySpecs()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, assert.ObjectsAreEqualValues(tc.want, got), "\ngot: %#v\nwant: %#v", got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshiftmachineapi
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// defaultAutoprovisionedMaxSize is the max size of
+	// autoprovisioned MachineSets whose template has no max size
+	// annotation.
+	defaultAutoprovisionedMaxSize = 1000
+)
+
+// templateMachineSets returns the MachineSets annotated with
+// c.annotationKeys.nodeGroupTemplate that match the auto discovery specs,
+// sorted by namespace and name.
+func (c *machineController) templateMachineSets() ([]*v1beta1.MachineSet, error) {
+	var templates []*v1beta1.MachineSet
+
+	if err := c.filterAllMachineSets(func(machineSet *v1beta1.MachineSet) error {
+		if machineSet.Annotations[c.annotationKeys.nodeGroupTemplate] != "true" {
+			return nil
+		}
+		if !c.allowedByAutoDiscoverySpecs("MachineSet", machineSet) {
+			return nil
+		}
+		templates = append(templates, machineSet.DeepCopy())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Namespace != templates[j].Namespace {
+			return templates[i].Namespace < templates[j].Namespace
+		}
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// availableMachineTypes returns the instance types of the template
+// MachineSets.
+func (c *machineController) availableMachineTypes() ([]string, error) {
+	templates, err := c.templateMachineSets()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var machineTypes []string
+
+	for _, template := range templates {
+		machineType := providerSpecInstanceTypeName(template.Spec.Template.Spec.ProviderSpec)
+		if machineType == "" || seen[machineType] {
+			continue
+		}
+		seen[machineType] = true
+		machineTypes = append(machineTypes, machineType)
+	}
+
+	sort.Strings(machineTypes)
+	return machineTypes, nil
+}
+
+// newAutoprovisionedNodeGroup returns a node group that doesn't exist
+// yet. Its MachineSet is a copy of the first template MachineSet of
+// machineType, with labels and taints added to its machines.
+func (c *machineController) newAutoprovisionedNodeGroup(machineType string, labels map[string]string, taints []apiv1.Taint) (*nodegroup, error) {
+	templates, err := c.templateMachineSets()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, template := range templates {
+		if providerSpecInstanceTypeName(template.Spec.Template.Spec.ProviderSpec) != machineType {
+			continue
+		}
+		machineSet, err := newAutoprovisionedMachineSet(c.annotationKeys, template, labels, taints)
+		if err != nil {
+			return nil, err
+		}
+		templateObject, err := c.findMachineSetObject(template.Namespace, template.Name)
+		if err != nil {
+			return nil, err
+		}
+		if templateObject == nil {
+			return nil, fmt.Errorf("template MachineSet %s/%s not found", template.Namespace, template.Name)
+		}
+		machineSetObject, err := autoprovisionedMachineSetObject(templateObject, machineSet)
+		if err != nil {
+			return nil, err
+		}
+		scalableResource, err := newMachineSetScalableResource(c, machineSet)
+		if err != nil {
+			return nil, err
+		}
+		return &nodegroup{
+			machineController: c,
+			scalableResource:  scalableResource,
+			machineSetObject:  machineSetObject,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no template MachineSet for machine type %q", machineType)
+}
+
+// newAutoprovisionedMachineSet returns a copy of template scaled to 0
+// and annotated as autoprovisioned. The name of the copy is derived
+// from the template name and from labels and taints, which are added
+// to its machines. Labels of the template whose value is the template
+// name, e.g. in its selector, are set to the name of the copy.
+func newAutoprovisionedMachineSet(keys *annotationKeys, template *v1beta1.MachineSet, labels map[string]string, taints []apiv1.Taint) (*v1beta1.MachineSet, error) {
+	max, err := maxSize(keys, template.Annotations)
+	if err == errMissingMaxAnnotation {
+		max = defaultAutoprovisionedMaxSize
+	} else if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s-%08x", template.Name, autoprovisionedHash(labels, taints))
+
+	annotations := map[string]string{}
+	for k, v := range template.Annotations {
+		annotations[k] = v
+	}
+	delete(annotations, keys.nodeGroupTemplate)
+	annotations[keys.nodeGroupMinSize] = "0"
+	annotations[keys.nodeGroupMaxSize] = strconv.Itoa(max)
+	annotations[keys.autoprovisioned] = "true"
+
+	machineSet := &v1beta1.MachineSet{
+		TypeMeta: template.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   template.Namespace,
+			Labels:      renameLabelValues(template.Labels, template.Name, name),
+			Annotations: annotations,
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+
+	machineSet.Spec.Replicas = pointer.Int32Ptr(0)
+	machineSet.Spec.Selector.MatchLabels = renameLabelValues(machineSet.Spec.Selector.MatchLabels, template.Name, name)
+	machineSet.Spec.Template.Labels = renameLabelValues(machineSet.Spec.Template.Labels, template.Name, name)
+
+	machineSpec := &machineSet.Spec.Template.Spec
+	machineSpec.Labels = cloudprovider.JoinStringMaps(machineSpec.Labels, labels)
+	for _, taint := range taints {
+		if !hasTaint(machineSpec.Taints, taint) {
+			machineSpec.Taints = append(machineSpec.Taints, taint)
+		}
+	}
+
+	return machineSet, nil
+}
+
+// autoprovisionedMachineSetObject returns a copy of the template
+// MachineSet object with the metadata, replicas, labels and taints of
+// machineSet, as built by newAutoprovisionedMachineSet(). Other fields
+// of the template are kept as they are, including the ones that the
+// v1beta1 types don't know about, e.g. the infrastructureRef and
+// bootstrap of cluster.x-k8s.io MachineSets.
+func autoprovisionedMachineSetObject(template *unstructured.Unstructured, machineSet *v1beta1.MachineSet) (*unstructured.Unstructured, error) {
+	obj := template.DeepCopy()
+	obj.Object["metadata"] = map[string]interface{}{}
+	obj.SetName(machineSet.Name)
+	obj.SetNamespace(machineSet.Namespace)
+	obj.SetLabels(machineSet.Labels)
+	obj.SetAnnotations(machineSet.Annotations)
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	if err := unstructured.SetNestedField(obj.Object, int64(pointer.Int32PtrDerefOr(machineSet.Spec.Replicas, 0)), "spec", "replicas"); err != nil {
+		return nil, err
+	}
+	for _, field := range []struct {
+		labels map[string]string
+		path   []string
+	}{
+		{machineSet.Spec.Selector.MatchLabels, []string{"spec", "selector", "matchLabels"}},
+		{machineSet.Spec.Template.Labels, []string{"spec", "template", "metadata", "labels"}},
+		{machineSet.Spec.Template.Spec.Labels, []string{"spec", "template", "spec", "metadata", "labels"}},
+	} {
+		if field.labels == nil {
+			continue
+		}
+		if err := unstructured.SetNestedStringMap(obj.Object, field.labels, field.path...); err != nil {
+			return nil, err
+		}
+	}
+
+	if taints := machineSet.Spec.Template.Spec.Taints; len(taints) > 0 {
+		taintObjects := make([]interface{}, 0, len(taints))
+		for i := range taints {
+			taintObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&taints[i])
+			if err != nil {
+				return nil, err
+			}
+			taintObjects = append(taintObjects, taintObject)
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, taintObjects, "spec", "template", "spec", "taints"); err != nil {
+			return nil, err
+		}
+	}
+
+	return obj, nil
+}
+
+// renameLabelValues returns a copy of labels with the values equal to
+// oldName set to newName.
+func renameLabelValues(labels map[string]string, oldName, newName string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if v == oldName {
+			v = newName
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// autoprovisionedHash returns a hash of labels and taints, so that
+// the same requirements map to the same autoprovisioned MachineSet.
+func autoprovisionedHash(labels map[string]string, taints []apiv1.Taint) uint32 {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	taintStrings := make([]string, 0, len(taints))
+	for _, taint := range taints {
+		taintStrings = append(taintStrings, taint.ToString())
+	}
+	sort.Strings(taintStrings)
+
+	h := fnv.New32a()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s,", k, labels[k])
+	}
+	for _, taint := range taintStrings {
+		fmt.Fprintf(h, "%s,", taint)
+	}
+	return h.Sum32()
+}
+
+func hasTaint(taints []apiv1.Taint, taint apiv1.Taint) bool {
+	for _, t := range taints {
+		if t.MatchTaint(&taint) && t.Value == taint.Value {
+			return true
+		}
+	}
+	return false
+}
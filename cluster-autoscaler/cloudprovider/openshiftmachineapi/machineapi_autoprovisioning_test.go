@@ -0,0 +1,290 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshiftmachineapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const machineSetLabelKey = "machine.openshift.io/cluster-api-machineset"
+
+func createTemplateMachineSetTestConfig(namespace string, instanceType string) *testConfig {
+	config := createMachineSetTestConfig(namespace, 0, 0, map[string]string{
+		testAnnotationKeys.nodeGroupTemplate: "true",
+	})
+	machineSet := config.machineSet
+	machineSet.Labels = map[string]string{machineSetLabelKey: machineSet.Name}
+	machineSet.Spec.Selector.MatchLabels = map[string]string{machineSetLabelKey: machineSet.Name}
+	machineSet.Spec.Template.Labels = map[string]string{machineSetLabelKey: machineSet.Name}
+	machineSet.Spec.Template.Spec.ProviderSpec = providerSpec(`{"instanceType": "` + instanceType + `"}`)
+	return config
+}
+
+func TestControllerAvailableMachineTypes(t *testing.T) {
+	configs := []*testConfig{
+		createTemplateMachineSetTestConfig("namespace-0", "m5.large"),
+		createTemplateMachineSetTestConfig("namespace-1", "c5.xlarge"),
+		createTemplateMachineSetTestConfig("namespace-2", "m5.large"),
+		createMachineSetTestConfig("namespace-3", 1, 1, map[string]string{
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "2",
+		}),
+	}
+
+	controller, stop := mustCreateTestController(t, configs...)
+	defer stop()
+
+	machineTypes, err := controller.availableMachineTypes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"c5.xlarge", "m5.large"}; !reflect.DeepEqual(machineTypes, expected) {
+		t.Errorf("expected %v, got %v", expected, machineTypes)
+	}
+
+	// Templates are not node groups themselves.
+	nodegroups, err := controller.nodeGroups()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodegroups) != 1 {
+		t.Errorf("expected 1 nodegroup, got %d", len(nodegroups))
+	}
+}
+
+func TestNodeGroupAutoprovisioning(t *testing.T) {
+	config := createTemplateMachineSetTestConfig(testNamespace, "m5.large")
+	template := config.machineSet
+
+	controller, stop := mustCreateTestController(t, config)
+	defer stop()
+
+	if _, err := controller.newAutoprovisionedNodeGroup("c5.xlarge", nil, nil); err == nil {
+		t.Error("expected an error for a machine type without template")
+	}
+
+	labels := map[string]string{"foo": "bar"}
+	taints := []apiv1.Taint{{Key: "dedicated", Value: "foo", Effect: apiv1.TaintEffectNoSchedule}}
+
+	ng, err := controller.newAutoprovisionedNodeGroup("m5.large", labels, taints)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ng.Exist() {
+		t.Error("expected nodegroup to not exist")
+	}
+	if !ng.Autoprovisioned() {
+		t.Error("expected nodegroup to be autoprovisioned")
+	}
+	if !strings.HasPrefix(ng.Name(), template.Name+"-") {
+		t.Errorf("expected name with prefix %q, got %q", template.Name, ng.Name())
+	}
+	if ng.MinSize() != 0 || ng.MaxSize() != defaultAutoprovisionedMaxSize {
+		t.Errorf("expected min 0 and max %d, got %d and %d", defaultAutoprovisionedMaxSize, ng.MinSize(), ng.MaxSize())
+	}
+	if size, err := ng.TargetSize(); err != nil || size != 0 {
+		t.Errorf("expected target size 0, got %d (err: %v)", size, err)
+	}
+
+	same, err := controller.newAutoprovisionedNodeGroup("m5.large", map[string]string{"foo": "bar"}, taints)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if same.Id() != ng.Id() {
+		t.Errorf("expected the same requirements to give the same id %q, got %q", ng.Id(), same.Id())
+	}
+	other, err := controller.newAutoprovisionedNodeGroup("m5.large", map[string]string{"foo": "baz"}, taints)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other.Id() == ng.Id() {
+		t.Errorf("expected different requirements to give different ids, got %q", ng.Id())
+	}
+
+	nodeInfo, err := ng.TemplateNodeInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := nodeInfo.Node().Labels["foo"]; got != "bar" {
+		t.Errorf("expected label foo=bar, got %q", got)
+	}
+	if got := nodeInfo.Node().Spec.Taints; !reflect.DeepEqual(got, taints) {
+		t.Errorf("expected taints %v, got %v", taints, got)
+	}
+
+	created, err := ng.Create()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created.Exist() || !created.Autoprovisioned() || created.Id() != ng.Id() {
+		t.Errorf("unexpected created nodegroup: %s", created.Debug())
+	}
+	if _, err := created.Create(); err == nil {
+		t.Error("expected an error creating an existing nodegroup")
+	}
+
+	machineSet, err := getMachineSet(controller, testNamespace, ng.Name())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for description, labels := range map[string]map[string]string{
+		"labels":          machineSet.Labels,
+		"selector":        machineSet.Spec.Selector.MatchLabels,
+		"template labels": machineSet.Spec.Template.Labels,
+	} {
+		if got := labels[machineSetLabelKey]; got != ng.Name() {
+			t.Errorf("expected %s to select %q, got %q", description, ng.Name(), got)
+		}
+	}
+	if _, found := machineSet.Annotations[testAnnotationKeys.nodeGroupTemplate]; found {
+		t.Error("expected the template annotation to be removed")
+	}
+
+	if err := created.Delete(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := getMachineSet(controller, testNamespace, ng.Name()); !errors.IsNotFound(err) {
+		t.Errorf("expected MachineSet to be deleted, got %v", err)
+	}
+}
+
+func TestAutoprovisionedMachineSetObject(t *testing.T) {
+	// A cluster.x-k8s.io MachineSet has no providerSpec, its machines
+	// are described by references instead.
+	infrastructureRef := map[string]interface{}{
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha2",
+		"kind":       "AWSMachineTemplate",
+		"name":       "m5-large",
+	}
+	template := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cluster.x-k8s.io/v1alpha2",
+		"kind":       "MachineSet",
+		"metadata": map[string]interface{}{
+			"name":            "template",
+			"namespace":       testNamespace,
+			"uid":             "template-uid",
+			"resourceVersion": "42",
+			"labels":          map[string]interface{}{machineSetLabelKey: "template"},
+			"annotations":     map[string]interface{}{testAnnotationKeys.nodeGroupTemplate: "true"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{machineSetLabelKey: "template"},
+			},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{machineSetLabelKey: "template"},
+				},
+				"spec": map[string]interface{}{
+					"bootstrap":         map[string]interface{}{"dataSecretName": "bootstrap"},
+					"infrastructureRef": infrastructureRef,
+				},
+			},
+		},
+		"status": map[string]interface{}{"replicas": int64(3)},
+	}}
+	original := template.DeepCopy()
+
+	machineSet, err := machineSetFromUnstructured(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	taints := []apiv1.Taint{{Key: "dedicated", Value: "foo", Effect: apiv1.TaintEffectNoSchedule}}
+	machineSet, err = newAutoprovisionedMachineSet(testAnnotationKeys, machineSet, map[string]string{"foo": "bar"}, taints)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := autoprovisionedMachineSetObject(template, machineSet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(template, original) {
+		t.Error("expected the template to be left unchanged")
+	}
+
+	if obj.GetAPIVersion() != "cluster.x-k8s.io/v1alpha2" || obj.GetKind() != "MachineSet" {
+		t.Errorf("unexpected apiVersion and kind: %q %q", obj.GetAPIVersion(), obj.GetKind())
+	}
+	if obj.GetName() != machineSet.Name || obj.GetNamespace() != testNamespace {
+		t.Errorf("expected %s/%s, got %s/%s", testNamespace, machineSet.Name, obj.GetNamespace(), obj.GetName())
+	}
+	if obj.GetUID() != "" || obj.GetResourceVersion() != "" {
+		t.Errorf("expected uid and resourceVersion of the template to be removed, got %q and %q", obj.GetUID(), obj.GetResourceVersion())
+	}
+	if _, found := obj.Object["status"]; found {
+		t.Error("expected status to be removed")
+	}
+	if obj.GetAnnotations()[testAnnotationKeys.autoprovisioned] != "true" {
+		t.Error("expected the autoprovisioned annotation")
+	}
+	if replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); replicas != 0 {
+		t.Errorf("expected 0 replicas, got %d", replicas)
+	}
+	for _, path := range [][]string{
+		{"metadata", "labels"},
+		{"spec", "selector", "matchLabels"},
+		{"spec", "template", "metadata", "labels"},
+	} {
+		if labels, _, _ := unstructured.NestedStringMap(obj.Object, path...); labels[machineSetLabelKey] != machineSet.Name {
+			t.Errorf("expected %v to select %q, got %v", path, machineSet.Name, labels)
+		}
+	}
+	if labels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "spec", "metadata", "labels"); labels["foo"] != "bar" {
+		t.Errorf("expected machine label foo=bar, got %v", labels)
+	}
+	if taintObjects, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "taints"); len(taintObjects) != 1 {
+		t.Errorf("expected 1 taint, got %v", taintObjects)
+	}
+
+	// Fields unknown to the v1beta1 types are kept.
+	if ref, _, _ := unstructured.NestedMap(obj.Object, "spec", "template", "spec", "infrastructureRef"); !reflect.DeepEqual(ref, infrastructureRef) {
+		t.Errorf("expected infrastructureRef %v, got %v", infrastructureRef, ref)
+	}
+	if secret, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "spec", "bootstrap", "dataSecretName"); secret != "bootstrap" {
+		t.Errorf("expected bootstrap to be kept, got %q", secret)
+	}
+}
+
+func TestNodeGroupDeleteNotAutoprovisioned(t *testing.T) {
+	config := createMachineSetTestConfig(testNamespace, 1, 1, map[string]string{
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "2",
+	})
+
+	controller, stop := mustCreateTestController(t, config)
+	defer stop()
+
+	ng, err := controller.nodeGroupForNode(config.nodes[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ng.Autoprovisioned() {
+		t.Error("expected nodegroup to not be autoprovisioned")
+	}
+	if err := ng.Delete(); err == nil {
+		t.Error("expected an error")
+	}
+}
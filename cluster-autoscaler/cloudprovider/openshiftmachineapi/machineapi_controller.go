@@ -18,14 +18,20 @@ package openshiftmachineapi
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
-	clusterclient "github.com/openshift/cluster-api/pkg/client/clientset_generated/clientset"
-	clusterinformers "github.com/openshift/cluster-api/pkg/client/informers_generated/externalversions"
-	machinev1beta1 "github.com/openshift/cluster-api/pkg/client/informers_generated/externalversions/machine/v1beta1"
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
+	"k8s.io/client-go/dynamic"
 	kubeinformers "k8s.io/client-go/informers"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -33,22 +39,42 @@ import (
 )
 
 const (
-	nodeProviderIDIndex = "openshiftmachineapi-nodeProviderIDIndex"
+	nodeProviderIDIndex    = "openshiftmachineapi-nodeProviderIDIndex"
+	machineProviderIDIndex = "openshiftmachineapi-machineProviderIDIndex"
+
+	machineResource           = "machines"
+	machineSetResource        = "machinesets"
+	machineDeploymentResource = "machinedeployments"
 )
 
 // machineController watches for Nodes, Machines, MachineSets and
 // MachineDeployments as they are added, updated and deleted on the
 // cluster. Additionally, it adds indices to the node informers to
-// satisfy lookup by node.Spec.ProviderID.
+// satisfy lookup by node.Spec.ProviderID. Machine API resources are
+// watched and updated as unstructured objects through a dynamic
+// client, so that any API group and version with the same schema,
+// e.g. upstream cluster-api, can be used.
 type machineController struct {
-	clusterClientset          clusterclient.Interface
-	clusterInformerFactory    clusterinformers.SharedInformerFactory
+	dynamicclient             dynamic.Interface
+	groupVersion              schema.GroupVersion
+	annotationKeys            *annotationKeys
 	kubeInformerFactory       kubeinformers.SharedInformerFactory
-	machineDeploymentInformer machinev1beta1.MachineDeploymentInformer
-	machineInformer           machinev1beta1.MachineInformer
-	machineSetInformer        machinev1beta1.MachineSetInformer
+	machineDeploymentInformer cache.SharedIndexInformer
+	machineInformer           cache.SharedIndexInformer
+	machineSetInformer        cache.SharedIndexInformer
 	nodeInformer              cache.SharedIndexInformer
 	enableMachineDeployments  bool
+	autoDiscoveryConfigs      []cloudprovider.MachineAPIAutoDiscoveryConfig
+	// instanceTypes looks up the capacity of instance types named
+	// in providerSpecs, for template nodes of node groups without
+	// capacity annotations.
+	instanceTypes InstanceTypeLookup
+
+	// scaleDownAllowedWindows caches the parsed scale down windows
+	// annotation of every node group by node group id. Node group
+	// objects are built on every call, so the cache lives here.
+	scaleDownAllowedWindowsMutex sync.Mutex
+	scaleDownAllowedWindows      map[string]*schedule.Cache
 }
 
 type machineSetFilterFunc func(machineSet *v1beta1.MachineSet) error
@@ -60,8 +86,19 @@ func indexNodeByNodeProviderID(obj interface{}) ([]string, error) {
 	return []string{}, nil
 }
 
+func indexMachineByProviderID(obj interface{}) ([]string, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		providerID, found, err := unstructured.NestedString(u.Object, "spec", "providerID")
+		if err != nil || !found || providerID == "" {
+			return []string{}, nil
+		}
+		return []string{providerID}, nil
+	}
+	return []string{}, nil
+}
+
 func (c *machineController) findMachine(id string) (*v1beta1.Machine, error) {
-	item, exists, err := c.machineInformer.Informer().GetStore().GetByKey(id)
+	item, exists, err := c.machineInformer.GetStore().GetByKey(id)
 	if err != nil {
 		return nil, err
 	}
@@ -70,16 +107,11 @@ func (c *machineController) findMachine(id string) (*v1beta1.Machine, error) {
 		return nil, nil
 	}
 
-	machine, ok := item.(*v1beta1.Machine)
-	if !ok {
-		return nil, fmt.Errorf("internal error; unexpected type %T", machine)
-	}
-
-	return machine.DeepCopy(), nil
+	return machineFromUnstructured(item)
 }
 
 func (c *machineController) findMachineDeployment(id string) (*v1beta1.MachineDeployment, error) {
-	item, exists, err := c.machineDeploymentInformer.Informer().GetStore().GetByKey(id)
+	item, exists, err := c.machineDeploymentInformer.GetStore().GetByKey(id)
 	if err != nil {
 		return nil, err
 	}
@@ -88,12 +120,25 @@ func (c *machineController) findMachineDeployment(id string) (*v1beta1.MachineDe
 		return nil, nil
 	}
 
-	machineDeployment, ok := item.(*v1beta1.MachineDeployment)
+	return machineDeploymentFromUnstructured(item)
+}
+
+// findMachineSetObject returns the unstructured MachineSet identified
+// by namespace and name, or nil if it doesn't exist. A DeepCopy() of
+// the object is returned on success.
+func (c *machineController) findMachineSetObject(namespace, name string) (*unstructured.Unstructured, error) {
+	item, exists, err := c.machineSetInformer.GetStore().GetByKey(fmt.Sprintf("%s/%s", namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	obj, ok := item.(*unstructured.Unstructured)
 	if !ok {
-		return nil, fmt.Errorf("internal error; unexpected type %T", machineDeployment)
+		return nil, fmt.Errorf("internal error; unexpected type %T", item)
 	}
-
-	return machineDeployment.DeepCopy(), nil
+	return obj.DeepCopy(), nil
 }
 
 // findMachineOwner returns the machine set owner for machine, or nil
@@ -105,7 +150,7 @@ func (c *machineController) findMachineOwner(machine *v1beta1.Machine) (*v1beta1
 		return nil, nil
 	}
 
-	store := c.machineSetInformer.Informer().GetStore()
+	store := c.machineSetInformer.GetStore()
 	item, exists, err := store.GetByKey(fmt.Sprintf("%s/%s", machine.Namespace, machineOwnerRef.Name))
 	if err != nil {
 		return nil, err
@@ -114,32 +159,34 @@ func (c *machineController) findMachineOwner(machine *v1beta1.Machine) (*v1beta1
 		return nil, nil
 	}
 
-	machineSet, ok := item.(*v1beta1.MachineSet)
-	if !ok {
-		return nil, fmt.Errorf("internal error; unexpected type: %T", machineSet)
+	machineSet, err := machineSetFromUnstructured(item)
+	if err != nil {
+		return nil, err
 	}
 
 	if !machineIsOwnedByMachineSet(machine, machineSet) {
 		return nil, nil
 	}
 
-	return machineSet.DeepCopy(), nil
+	return machineSet, nil
 }
 
 // run starts shared informers and waits for the informer cache to
 // synchronize.
 func (c *machineController) run(stopCh <-chan struct{}) error {
 	c.kubeInformerFactory.Start(stopCh)
-	c.clusterInformerFactory.Start(stopCh)
+	go c.machineInformer.Run(stopCh)
+	go c.machineSetInformer.Run(stopCh)
 
 	syncFuncs := []cache.InformerSynced{
 		c.nodeInformer.HasSynced,
-		c.machineInformer.Informer().HasSynced,
-		c.machineSetInformer.Informer().HasSynced,
+		c.machineInformer.HasSynced,
+		c.machineSetInformer.HasSynced,
 	}
 
 	if c.enableMachineDeployments {
-		syncFuncs = append(syncFuncs, c.machineDeploymentInformer.Informer().HasSynced)
+		go c.machineDeploymentInformer.Run(stopCh)
+		syncFuncs = append(syncFuncs, c.machineDeploymentInformer.HasSynced)
 	}
 
 	klog.V(4).Infof("waiting for caches to sync")
@@ -172,13 +219,43 @@ func (c *machineController) findMachineByNodeProviderID(node *apiv1.Node) (*v1be
 		return nil, fmt.Errorf("internal error; unexpected type %T", node)
 	}
 
-	if machineName, found := node.Annotations[machineAnnotationKey]; found {
+	if machineName, found := node.Annotations[c.annotationKeys.machine]; found {
 		return c.findMachine(machineName)
 	}
 
 	return nil, nil
 }
 
+// findMachineByProviderID finds the machine identified by
+// providerID, which is either the ProviderID of the machine's node or
+// an id returned by pendingMachineID() for machines without a node.
+// Returns nil if the machine cannot be found. A DeepCopy() of the
+// object is returned on success.
+func (c *machineController) findMachineByProviderID(providerID string) (*v1beta1.Machine, error) {
+	machine, err := c.findMachineByNodeProviderID(&apiv1.Node{Spec: apiv1.NodeSpec{ProviderID: providerID}})
+	if err != nil || machine != nil {
+		return machine, err
+	}
+
+	if strings.HasPrefix(providerID, pendingMachinePrefix) {
+		return c.findMachine(strings.TrimPrefix(providerID, pendingMachinePrefix))
+	}
+
+	objs, err := c.machineInformer.GetIndexer().ByIndex(machineProviderIDIndex, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n := len(objs); {
+	case n == 0:
+		return nil, nil
+	case n > 1:
+		return nil, fmt.Errorf("internal error; expected len==1, got %v", n)
+	}
+
+	return machineFromUnstructured(objs[0])
+}
+
 // findNodeByNodeName find the Node object keyed by node.Name. Returns
 // nil if it cannot be found. A DeepCopy() of the object is returned
 // on success.
@@ -205,7 +282,7 @@ func (c *machineController) findNodeByNodeName(name string) (*apiv1.Node, error)
 // is returned.
 func (c *machineController) machinesInMachineSet(machineSet *v1beta1.MachineSet) ([]*v1beta1.Machine, error) {
 	listOptions := labels.SelectorFromSet(labels.Set(machineSet.Labels))
-	machines, err := c.machineInformer.Lister().Machines(machineSet.Namespace).List(listOptions)
+	machines, err := c.listMachines(machineSet.Namespace, listOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -214,35 +291,96 @@ func (c *machineController) machinesInMachineSet(machineSet *v1beta1.MachineSet)
 
 	for _, machine := range machines {
 		if machineIsOwnedByMachineSet(machine, machineSet) {
-			result = append(result, machine.DeepCopy())
+			result = append(result, machine)
 		}
 	}
 
 	return result, nil
 }
 
+// listMachines returns the machines in namespace that match selector.
+func (c *machineController) listMachines(namespace string, selector labels.Selector) ([]*v1beta1.Machine, error) {
+	var result []*v1beta1.Machine
+	var convertErr error
+
+	if err := cache.ListAllByNamespace(c.machineInformer.GetIndexer(), namespace, selector, func(obj interface{}) {
+		machine, err := machineFromUnstructured(obj)
+		if err != nil {
+			convertErr = err
+			return
+		}
+		result = append(result, machine)
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, convertErr
+}
+
+// resource returns the dynamic client of the named Machine API
+// resource, e.g. machineSetResource.
+func (c *machineController) resource(name string) dynamic.NamespaceableResourceInterface {
+	return c.dynamicclient.Resource(c.groupVersion.WithResource(name))
+}
+
+// updateResource gets the latest version of the named Machine API
+// resource from the API server, applies mutate to it and updates it.
+// Fields unknown to this provider are preserved.
+func (c *machineController) updateResource(resource, namespace, name string, mutate func(obj *unstructured.Unstructured) error) error {
+	obj, err := c.resource(resource).Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := mutate(obj); err != nil {
+		return err
+	}
+	_, err = c.resource(resource).Namespace(namespace).Update(obj, metav1.UpdateOptions{})
+	return err
+}
+
+// newResourceInformer returns an informer of the unstructured objects
+// of the named Machine API resource in all namespaces.
+func newResourceInformer(dynamicclient dynamic.Interface, gvr schema.GroupVersionResource) cache.SharedIndexInformer {
+	client := dynamicclient.Resource(gvr).Namespace(metav1.NamespaceAll)
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.Watch(options)
+			},
+		},
+		&unstructured.Unstructured{},
+		0,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
 // newMachineController constructs a controller that watches Nodes,
 // Machines and MachineSet as they are added, updated and deleted on
-// the cluster.
+// the cluster. Machine API resources are read from groupVersion.
 func newMachineController(
 	kubeclient kubeclient.Interface,
-	clusterclient clusterclient.Interface,
+	dynamicclient dynamic.Interface,
+	groupVersion schema.GroupVersion,
 	enableMachineDeployments bool,
+	autoDiscoveryConfigs []cloudprovider.MachineAPIAutoDiscoveryConfig,
+	instanceTypes InstanceTypeLookup,
 ) (*machineController, error) {
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclient, 0)
-	clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterclient, 0)
 
-	var machineDeploymentInformer machinev1beta1.MachineDeploymentInformer
+	var machineDeploymentInformer cache.SharedIndexInformer
 	if enableMachineDeployments {
-		machineDeploymentInformer = clusterInformerFactory.Machine().V1beta1().MachineDeployments()
-		machineDeploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{})
+		machineDeploymentInformer = newResourceInformer(dynamicclient, groupVersion.WithResource(machineDeploymentResource))
+		machineDeploymentInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{})
 	}
 
-	machineInformer := clusterInformerFactory.Machine().V1beta1().Machines()
-	machineSetInformer := clusterInformerFactory.Machine().V1beta1().MachineSets()
+	machineInformer := newResourceInformer(dynamicclient, groupVersion.WithResource(machineResource))
+	machineSetInformer := newResourceInformer(dynamicclient, groupVersion.WithResource(machineSetResource))
 
-	machineInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{})
-	machineSetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{})
+	machineInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{})
+	machineSetInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{})
 
 	nodeInformer := kubeInformerFactory.Core().V1().Nodes().Informer()
 	nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{})
@@ -255,49 +393,100 @@ func newMachineController(
 		return nil, fmt.Errorf("cannot add indexers: %v", err)
 	}
 
+	if err := machineInformer.GetIndexer().AddIndexers(cache.Indexers{
+		machineProviderIDIndex: indexMachineByProviderID,
+	}); err != nil {
+		return nil, fmt.Errorf("cannot add indexers: %v", err)
+	}
+
 	return &machineController{
-		clusterClientset:          clusterclient,
-		clusterInformerFactory:    clusterInformerFactory,
+		dynamicclient:             dynamicclient,
+		groupVersion:              groupVersion,
+		annotationKeys:            newAnnotationKeys(groupVersion.Group),
 		kubeInformerFactory:       kubeInformerFactory,
 		machineDeploymentInformer: machineDeploymentInformer,
 		machineInformer:           machineInformer,
 		machineSetInformer:        machineSetInformer,
 		nodeInformer:              nodeInformer,
 		enableMachineDeployments:  enableMachineDeployments,
+		autoDiscoveryConfigs:      autoDiscoveryConfigs,
+		instanceTypes:             instanceTypes,
+		scaleDownAllowedWindows:   make(map[string]*schedule.Cache),
 	}, nil
 }
 
-func (c *machineController) machineSetNodeNames(machineSet *v1beta1.MachineSet) ([]string, error) {
+// parseScaleDownAllowedWindows parses the scale down windows of the
+// node group with the given id. The windows are parsed again only if
+// they changed since the last call for the node group.
+func (c *machineController) parseScaleDownAllowedWindows(id, windows string) (*schedule.Schedule, error) {
+	c.scaleDownAllowedWindowsMutex.Lock()
+	defer c.scaleDownAllowedWindowsMutex.Unlock()
+	cache, found := c.scaleDownAllowedWindows[id]
+	if !found {
+		cache = &schedule.Cache{}
+		c.scaleDownAllowedWindows[id] = cache
+	}
+	return cache.Parse(windows)
+}
+
+// machineSetInstances returns the instances of all the machines
+// that belong to machineSet. Machines backed by a node are identified
+// by the node's ProviderID, other machines by pendingMachineID().
+func (c *machineController) machineSetInstances(machineSet *v1beta1.MachineSet) ([]cloudprovider.Instance, error) {
 	machines, err := c.machinesInMachineSet(machineSet)
 	if err != nil {
 		return nil, fmt.Errorf("error listing machines: %v", err)
 	}
 
-	var nodes []string
+	var instances []cloudprovider.Instance
 
 	for _, machine := range machines {
+		var node *apiv1.Node
+
 		if machine.Status.NodeRef == nil {
 			klog.V(4).Infof("Status.NodeRef of machine %q is currently nil", machine.Name)
-			continue
-		}
-		if machine.Status.NodeRef.Kind != "Node" {
+		} else if machine.Status.NodeRef.Kind != "Node" {
 			klog.Errorf("Status.NodeRef of machine %q does not reference a node (rather %q)", machine.Name, machine.Status.NodeRef.Kind)
 			continue
-		}
-
-		node, err := c.findNodeByNodeName(machine.Status.NodeRef.Name)
-		if err != nil {
-			return nil, fmt.Errorf("unknown node %q", machine.Status.NodeRef.Name)
+		} else {
+			node, err = c.findNodeByNodeName(machine.Status.NodeRef.Name)
+			if err != nil {
+				return nil, fmt.Errorf("unknown node %q", machine.Status.NodeRef.Name)
+			}
 		}
 
 		if node != nil {
-			nodes = append(nodes, node.Spec.ProviderID)
+			instances = append(instances, cloudprovider.Instance{
+				Id:     node.Spec.ProviderID,
+				Status: machineInstanceStatus(machine, true),
+			})
+		} else {
+			instances = append(instances, cloudprovider.Instance{
+				Id:     pendingMachineID(machine),
+				Status: machineInstanceStatus(machine, false),
+			})
 		}
 	}
 
-	klog.V(4).Infof("nodegroup %s has nodes %v", machineSet.Name, nodes)
+	klog.V(4).Infof("nodegroup %s has instances %v", machineSet.Name, instances)
 
-	return nodes, nil
+	return instances, nil
+}
+
+// allowedByAutoDiscoverySpecs returns true if obj, a MachineSet or
+// MachineDeployment, matches at least one of the auto discovery
+// specs, or if there are none.
+func (c *machineController) allowedByAutoDiscoverySpecs(kind string, obj metav1.Object) bool {
+	if len(c.autoDiscoveryConfigs) == 0 {
+		return true
+	}
+	for _, cfg := range c.autoDiscoveryConfigs {
+		if autoDiscoveryConfigMatches(cfg, obj) {
+			return true
+		}
+	}
+	klog.V(4).Infof("ignoring %s %s/%s: does not match any auto discovery spec", kind, obj.GetNamespace(), obj.GetName())
+	return false
 }
 
 func (c *machineController) filterAllMachineSets(f machineSetFilterFunc) error {
@@ -305,10 +494,23 @@ func (c *machineController) filterAllMachineSets(f machineSetFilterFunc) error {
 }
 
 func (c *machineController) filterMachineSets(namespace string, f machineSetFilterFunc) error {
-	machineSets, err := c.machineSetInformer.Lister().MachineSets(namespace).List(labels.Everything())
-	if err != nil {
+	var machineSets []*v1beta1.MachineSet
+	var convertErr error
+
+	if err := cache.ListAllByNamespace(c.machineSetInformer.GetIndexer(), namespace, labels.Everything(), func(obj interface{}) {
+		machineSet, err := machineSetFromUnstructured(obj)
+		if err != nil {
+			convertErr = err
+			return
+		}
+		machineSets = append(machineSets, machineSet)
+	}); err != nil {
 		return nil
 	}
+	if convertErr != nil {
+		return convertErr
+	}
+
 	for _, machineSet := range machineSets {
 		if err := f(machineSet); err != nil {
 			return err
@@ -324,6 +526,9 @@ func (c *machineController) machineSetNodeGroups() ([]*nodegroup, error) {
 		if machineSetHasMachineDeploymentOwnerRef(machineSet) {
 			return nil
 		}
+		if !c.allowedByAutoDiscoverySpecs("MachineSet", machineSet) {
+			return nil
+		}
 		ng, err := newNodegroupFromMachineSet(c, machineSet.DeepCopy())
 		if err != nil {
 			return err
@@ -344,15 +549,17 @@ func (c *machineController) machineDeploymentNodeGroups() ([]*nodegroup, error)
 		return nil, nil
 	}
 
-	machineDeployments, err := c.machineDeploymentInformer.Lister().MachineDeployments(apiv1.NamespaceAll).List(labels.Everything())
-	if err != nil {
-		return nil, err
-	}
-
 	var nodegroups []*nodegroup
 
-	for _, md := range machineDeployments {
-		ng, err := newNodegroupFromMachineDeployment(c, md.DeepCopy())
+	for _, obj := range c.machineDeploymentInformer.GetStore().List() {
+		md, err := machineDeploymentFromUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		if !c.allowedByAutoDiscoverySpecs("MachineDeployment", md) {
+			continue
+		}
+		ng, err := newNodegroupFromMachineDeployment(c, md)
 		if err != nil {
 			return nil, err
 		}
@@ -379,7 +586,7 @@ func (c *machineController) nodeGroups() ([]*nodegroup, error) {
 }
 
 func (c *machineController) nodeGroupForNode(node *apiv1.Node) (*nodegroup, error) {
-	machine, err := c.findMachineByNodeProviderID(node)
+	machine, err := c.findMachineByProviderID(node.Spec.ProviderID)
 	if err != nil {
 		return nil, err
 	}
@@ -406,13 +613,15 @@ func (c *machineController) nodeGroupForNode(node *apiv1.Node) (*nodegroup, erro
 			if machineDeployment == nil {
 				return nil, fmt.Errorf("unknown MachineDeployment %q", key)
 			}
+			if !c.allowedByAutoDiscoverySpecs("MachineDeployment", machineDeployment) {
+				return nil, nil
+			}
 			nodegroup, err := newNodegroupFromMachineDeployment(c, machineDeployment)
 			if err != nil {
 				return nil, fmt.Errorf("failed to build nodegroup for node %q: %v", node.Name, err)
 			}
-			// We don't scale from 0 so nodes must belong
-			// to a nodegroup that has a scale size of at
-			// least 1.
+			// Nodes must belong to a nodegroup that has a
+			// scale size of at least 1.
 			if nodegroup.MaxSize()-nodegroup.MinSize() < 1 {
 				return nil, nil
 			}
@@ -420,13 +629,17 @@ func (c *machineController) nodeGroupForNode(node *apiv1.Node) (*nodegroup, erro
 		}
 	}
 
+	if !c.allowedByAutoDiscoverySpecs("MachineSet", machineSet) {
+		return nil, nil
+	}
+
 	nodegroup, err := newNodegroupFromMachineSet(c, machineSet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build nodegroup for node %q: %v", node.Name, err)
 	}
 
-	// We don't scale from 0 so nodes must belong to a nodegroup
-	// that has a scale size of at least 1.
+	// Nodes must belong to a nodegroup that has a scale size of
+	// at least 1.
 	if nodegroup.MaxSize()-nodegroup.MinSize() < 1 {
 		return nil, nil
 	}
@@ -25,17 +25,24 @@ import (
 	"testing"
 
 	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
-	fakeclusterapi "github.com/openshift/cluster-api/pkg/client/clientset_generated/clientset/fake"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	fakekube "k8s.io/client-go/kubernetes/fake"
 )
 
 type testControllerShutdownFunc func()
 
+// testAnnotationKeys are the annotation keys of the controllers
+// created by mustCreateTestController().
+var testAnnotationKeys = newAnnotationKeys(defaultMachineAPIGroup)
+
 type testConfig struct {
 	spec              *testSpec
 	machineDeployment *v1beta1.MachineDeployment
@@ -57,8 +64,18 @@ type testSpec struct {
 func mustCreateTestController(t *testing.T, testConfigs ...*testConfig) (*machineController, testControllerShutdownFunc) {
 	t.Helper()
 
+	groupVersion := schema.GroupVersion{
+		Group:   defaultMachineAPIGroup,
+		Version: defaultMachineAPIVersion,
+	}
+	return mustCreateTestControllerForGroupVersion(t, groupVersion, testConfigs...)
+}
+
+func mustCreateTestControllerForGroupVersion(t *testing.T, groupVersion schema.GroupVersion, testConfigs ...*testConfig) (*machineController, testControllerShutdownFunc) {
+	t.Helper()
+
 	nodeObjects := make([]runtime.Object, 0)
-	machineObjects := make([]runtime.Object, 0)
+	machineObjects := make([]*unstructured.Unstructured, 0)
 
 	for _, config := range testConfigs {
 		for i := range config.nodes {
@@ -66,18 +83,18 @@ func mustCreateTestController(t *testing.T, testConfigs ...*testConfig) (*machin
 		}
 
 		for i := range config.machines {
-			machineObjects = append(machineObjects, config.machines[i])
+			machineObjects = append(machineObjects, mustUnstructured(t, groupVersion, config.machines[i]))
 		}
 
-		machineObjects = append(machineObjects, config.machineSet)
+		machineObjects = append(machineObjects, mustUnstructured(t, groupVersion, config.machineSet))
 		if config.machineDeployment != nil {
-			machineObjects = append(machineObjects, config.machineDeployment)
+			machineObjects = append(machineObjects, mustUnstructured(t, groupVersion, config.machineDeployment))
 		}
 	}
 
 	kubeclientSet := fakekube.NewSimpleClientset(nodeObjects...)
-	clusterclientSet := fakeclusterapi.NewSimpleClientset(machineObjects...)
-	controller, err := newMachineController(kubeclientSet, clusterclientSet, true)
+	dynamicclient := newFakeDynamicClient(machineObjects...)
+	controller, err := newMachineController(kubeclientSet, dynamicclient, groupVersion, true, nil, testInstanceTypes)
 	if err != nil {
 		t.Fatal("failed to create test controller")
 	}
@@ -138,7 +155,8 @@ func createTestConfigs(specs ...testSpec) []*testConfig {
 
 		config.machineSet = &v1beta1.MachineSet{
 			TypeMeta: v1.TypeMeta{
-				Kind: "MachineSet",
+				APIVersion: v1beta1.SchemeGroupVersion.String(),
+				Kind:       "MachineSet",
 			},
 			ObjectMeta: v1.ObjectMeta{
 				Name:      spec.machineSetName,
@@ -153,7 +171,8 @@ func createTestConfigs(specs ...testSpec) []*testConfig {
 		} else {
 			config.machineDeployment = &v1beta1.MachineDeployment{
 				TypeMeta: v1.TypeMeta{
-					Kind: "MachineDeployment",
+					APIVersion: v1beta1.SchemeGroupVersion.String(),
+					Kind:       "MachineDeployment",
 				},
 				ObjectMeta: v1.ObjectMeta{
 					Name:        spec.machineDeploymentName,
@@ -201,7 +220,7 @@ func makeLinkedNodeAndMachine(i int, namespace string, owner v1.OwnerReference)
 		ObjectMeta: v1.ObjectMeta{
 			Name: fmt.Sprintf("%s-%s-node-%d", namespace, owner.Name, i),
 			Annotations: map[string]string{
-				machineAnnotationKey: fmt.Sprintf("%s/%s-%s-machine-%d", namespace, namespace, owner.Name, i),
+				testAnnotationKeys.machine: fmt.Sprintf("%s/%s-%s-machine-%d", namespace, namespace, owner.Name, i),
 			},
 		},
 		Spec: apiv1.NodeSpec{
@@ -211,7 +230,8 @@ func makeLinkedNodeAndMachine(i int, namespace string, owner v1.OwnerReference)
 
 	machine := &v1beta1.Machine{
 		TypeMeta: v1.TypeMeta{
-			Kind: "Machine",
+			APIVersion: v1beta1.SchemeGroupVersion.String(),
+			Kind:       "Machine",
 		},
 		ObjectMeta: v1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-%s-machine-%d", namespace, owner.Name, i),
@@ -242,15 +262,15 @@ func addTestConfigs(t *testing.T, controller *machineController, testConfigs ...
 
 	for _, config := range testConfigs {
 		if config.machineDeployment != nil {
-			if err := controller.machineDeploymentInformer.Informer().GetStore().Add(config.machineDeployment); err != nil {
+			if err := controller.machineDeploymentInformer.GetStore().Add(mustUnstructured(t, controller.groupVersion, config.machineDeployment)); err != nil {
 				return err
 			}
 		}
-		if err := controller.machineSetInformer.Informer().GetStore().Add(config.machineSet); err != nil {
+		if err := controller.machineSetInformer.GetStore().Add(mustUnstructured(t, controller.groupVersion, config.machineSet)); err != nil {
 			return err
 		}
 		for i := range config.machines {
-			if err := controller.machineInformer.Informer().GetStore().Add(config.machines[i]); err != nil {
+			if err := controller.machineInformer.GetStore().Add(mustUnstructured(t, controller.groupVersion, config.machines[i])); err != nil {
 				return err
 			}
 		}
@@ -273,15 +293,15 @@ func deleteTestConfigs(t *testing.T, controller *machineController, testConfigs
 			}
 		}
 		for i := range config.machines {
-			if err := controller.machineInformer.Informer().GetStore().Delete(config.machines[i]); err != nil {
+			if err := controller.machineInformer.GetStore().Delete(config.machines[i]); err != nil {
 				return err
 			}
 		}
-		if err := controller.machineSetInformer.Informer().GetStore().Delete(config.machineSet); err != nil {
+		if err := controller.machineSetInformer.GetStore().Delete(config.machineSet); err != nil {
 			return err
 		}
 		if config.machineDeployment != nil {
-			if err := controller.machineDeploymentInformer.Informer().GetStore().Delete(config.machineDeployment); err != nil {
+			if err := controller.machineDeploymentInformer.GetStore().Delete(config.machineDeployment); err != nil {
 				return err
 			}
 		}
@@ -337,8 +357,8 @@ func TestControllerFindMachineByID(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
 			testConfig := createMachineSetTestConfig(testNamespace, 1, 1, map[string]string{
-				nodeGroupMinSizeAnnotationKey: "1",
-				nodeGroupMaxSizeAnnotationKey: "10",
+				testAnnotationKeys.nodeGroupMinSize: "1",
+				testAnnotationKeys.nodeGroupMaxSize: "10",
 			})
 			if tc.name == "" {
 				tc.name = testConfig.machines[0].Name
@@ -353,8 +373,8 @@ func TestControllerFindMachineByID(t *testing.T) {
 
 func TestControllerFindMachineOwner(t *testing.T) {
 	testConfig := createMachineSetTestConfig(testNamespace, 1, 1, map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "10",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
 	})
 
 	controller, stop := mustCreateTestController(t, testConfig)
@@ -384,7 +404,7 @@ func TestControllerFindMachineOwner(t *testing.T) {
 	}
 
 	// Test #3: Delete the MachineSet and lookup should fail
-	if err := controller.machineSetInformer.Informer().GetStore().Delete(testResult1); err != nil {
+	if err := controller.machineSetInformer.GetStore().Delete(testResult1); err != nil {
 		t.Fatalf("unexpected error, got %v", err)
 	}
 	testResult3, err := controller.findMachineOwner(testConfig.machines[0].DeepCopy())
@@ -398,8 +418,8 @@ func TestControllerFindMachineOwner(t *testing.T) {
 
 func TestControllerFindMachineByNodeProviderID(t *testing.T) {
 	testConfig := createMachineSetTestConfig(testNamespace, 1, 1, map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "10",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
 	})
 
 	controller, stop := mustCreateTestController(t, testConfig)
@@ -432,7 +452,7 @@ func TestControllerFindMachineByNodeProviderID(t *testing.T) {
 	// Test #3: Verify node is not found if the stored object has
 	// no "machine" annotation
 	node = testConfig.nodes[0].DeepCopy()
-	delete(node.Annotations, machineAnnotationKey)
+	delete(node.Annotations, testAnnotationKeys.machine)
 	if err := controller.nodeInformer.GetStore().Update(node); err != nil {
 		t.Fatalf("unexpected error updating node, got %v", err)
 	}
@@ -445,10 +465,50 @@ func TestControllerFindMachineByNodeProviderID(t *testing.T) {
 	}
 }
 
+func TestControllerFindMachineByProviderID(t *testing.T) {
+	testConfig := createMachineSetTestConfig(testNamespace, 1, 1, map[string]string{
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
+	})
+
+	controller, stop := mustCreateTestController(t, testConfig)
+	defer stop()
+
+	// Test #1: Verify a machine whose node has not registered yet
+	// can be found by the ProviderID in its spec.
+	providerID := "machine-without-node"
+	machine := testConfig.machines[0].DeepCopy()
+	machine.Spec.ProviderID = &providerID
+	machine.Status.NodeRef = nil
+	if err := controller.machineInformer.GetStore().Update(mustUnstructured(t, controller.groupVersion, machine)); err != nil {
+		t.Fatalf("unexpected error updating machine, got %v", err)
+	}
+
+	foundMachine, err := controller.findMachineByProviderID(providerID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if foundMachine == nil {
+		t.Fatal("expected to find machine")
+	}
+	if !reflect.DeepEqual(foundMachine, machine) {
+		t.Fatalf("expected machines to be equal - expected %+v, got %+v", machine, foundMachine)
+	}
+
+	// Test #2: Verify no machine is found for an unknown ProviderID
+	nonExistentMachine, err := controller.findMachineByProviderID("does-not-exist")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nonExistentMachine != nil {
+		t.Fatal("expected find to fail")
+	}
+}
+
 func TestControllerFindNodeByNodeName(t *testing.T) {
 	testConfig := createMachineSetTestConfig(testNamespace, 1, 1, map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "10",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
 	})
 
 	controller, stop := mustCreateTestController(t, testConfig)
@@ -475,8 +535,8 @@ func TestControllerFindNodeByNodeName(t *testing.T) {
 
 func TestControllerMachinesInMachineSet(t *testing.T) {
 	testConfig1 := createMachineSetTestConfig("testConfig1", 5, 5, map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "10",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
 	})
 
 	controller, stop := mustCreateTestController(t, testConfig1)
@@ -487,20 +547,20 @@ func TestControllerMachinesInMachineSet(t *testing.T) {
 	// test objects in the controller. This gives us two
 	// machinesets, each with their own machines and linked nodes.
 	testConfig2 := createMachineSetTestConfig("testConfig2", 5, 5, map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "10",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
 	})
 
 	if err := addTestConfigs(t, controller, testConfig2); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	machinesInTestObjs1, err := controller.machineInformer.Lister().Machines(testConfig1.spec.namespace).List(labels.Everything())
+	machinesInTestObjs1, err := controller.listMachines(testConfig1.spec.namespace, labels.Everything())
 	if err != nil {
 		t.Fatalf("error listing machines: %v", err)
 	}
 
-	machinesInTestObjs2, err := controller.machineInformer.Lister().Machines(testConfig2.spec.namespace).List(labels.Everything())
+	machinesInTestObjs2, err := controller.listMachines(testConfig2.spec.namespace, labels.Everything())
 	if err != nil {
 		t.Fatalf("error listing machines: %v", err)
 	}
@@ -548,8 +608,8 @@ func TestControllerMachinesInMachineSet(t *testing.T) {
 
 func TestControllerLookupNodeGroupForNonExistentNode(t *testing.T) {
 	testConfig := createMachineSetTestConfig(testNamespace, 1, 1, map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "10",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
 	})
 
 	controller, stop := mustCreateTestController(t, testConfig)
@@ -578,7 +638,7 @@ func TestControllerNodeGroupForNodeWithMissingMachineOwner(t *testing.T) {
 
 		machine := testConfig.machines[0].DeepCopy()
 		machine.OwnerReferences = []v1.OwnerReference{}
-		if err := controller.machineInformer.Informer().GetStore().Update(machine); err != nil {
+		if err := controller.machineInformer.GetStore().Update(mustUnstructured(t, controller.groupVersion, machine)); err != nil {
 			t.Fatalf("unexpected error updating machine, got %v", err)
 		}
 
@@ -594,16 +654,16 @@ func TestControllerNodeGroupForNodeWithMissingMachineOwner(t *testing.T) {
 
 	t.Run("MachineSet", func(t *testing.T) {
 		testConfig := createMachineSetTestConfig(testNamespace, 1, 1, map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "10",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "10",
 		})
 		test(t, testConfig)
 	})
 
 	t.Run("MachineDeployment", func(t *testing.T) {
 		testConfig := createMachineDeploymentTestConfig(testNamespace, 1, 1, map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "10",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "10",
 		})
 		test(t, testConfig)
 	})
@@ -627,16 +687,16 @@ func TestControllerNodeGroupForNodeWithPositiveScalingBounds(t *testing.T) {
 
 	t.Run("MachineSet", func(t *testing.T) {
 		testConfig := createMachineSetTestConfig(testNamespace, 1, 1, map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "1",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "1",
 		})
 		test(t, testConfig)
 	})
 
 	t.Run("MachineDeployment", func(t *testing.T) {
 		testConfig := createMachineDeploymentTestConfig(testNamespace, 1, 1, map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "1",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "1",
 		})
 		test(t, testConfig)
 	})
@@ -655,8 +715,8 @@ func TestControllerNodeGroups(t *testing.T) {
 	}
 
 	annotations := map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "2",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "2",
 	}
 
 	controller, stop := mustCreateTestController(t)
@@ -692,8 +752,8 @@ func TestControllerNodeGroups(t *testing.T) {
 	assertNodegroupLen(t, controller, 0)
 
 	annotations = map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "1",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "1",
 	}
 
 	// Test #5: machineset with no scaling bounds results in no nodegroups
@@ -711,8 +771,8 @@ func TestControllerNodeGroups(t *testing.T) {
 	assertNodegroupLen(t, controller, 0)
 
 	annotations = map[string]string{
-		nodeGroupMinSizeAnnotationKey: "-1",
-		nodeGroupMaxSizeAnnotationKey: "1",
+		testAnnotationKeys.nodeGroupMinSize: "-1",
+		testAnnotationKeys.nodeGroupMaxSize: "1",
 	}
 
 	// Test #7: machineset with bad scaling bounds results in an error and no nodegroups
@@ -734,6 +794,79 @@ func TestControllerNodeGroups(t *testing.T) {
 	}
 }
 
+func TestControllerNodeGroupsAutoDiscovery(t *testing.T) {
+	annotations := map[string]string{
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "2",
+	}
+
+	tenantA := createMachineSetTestConfigs("tenant-a", 2, 1, 1, annotations)
+	tenantB := createMachineSetTestConfigs("tenant-b", 1, 1, 1, annotations)
+	deployments := createMachineDeploymentTestConfigs("tenant-c", 2, 1, 1, annotations)
+	deployments[0].machineDeployment.Labels = map[string]string{"tenant": "c"}
+
+	controller, stop := mustCreateTestController(t, append(append(tenantA, tenantB...), deployments...)...)
+	defer stop()
+
+	for _, tc := range []struct {
+		description string
+		specs       []string
+		allowed     []*testConfig
+	}{{
+		description: "no specs allow all node groups",
+		allowed:     append(append(tenantA, tenantB...), deployments...),
+	}, {
+		description: "namespace",
+		specs:       []string{"machineapi:namespace=tenant-a"},
+		allowed:     tenantA,
+	}, {
+		description: "labels",
+		specs:       []string{"machineapi:tenant=c"},
+		allowed:     deployments[:1],
+	}, {
+		description: "namespace and labels",
+		specs:       []string{"machineapi:namespace=tenant-a,tenant=c"},
+	}, {
+		description: "any spec matches",
+		specs:       []string{"machineapi:namespace=tenant-b", "machineapi:namespace=tenant-c,tenant=c"},
+		allowed:     append(tenantB, deployments[0]),
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			do := cloudprovider.NodeGroupDiscoveryOptions{NodeGroupAutoDiscoverySpecs: tc.specs}
+			configs, err := do.ParseMachineAPIAutoDiscoverySpecs()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			controller.autoDiscoveryConfigs = configs
+
+			nodegroups, err := controller.nodeGroups()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, expected := len(nodegroups), len(tc.allowed); got != expected {
+				t.Fatalf("expected %d nodegroups, got %d", expected, got)
+			}
+
+			allowed := map[*testConfig]bool{}
+			for _, config := range tc.allowed {
+				allowed[config] = true
+			}
+			for _, config := range append(append(tenantA, tenantB...), deployments...) {
+				ng, err := controller.nodeGroupForNode(config.nodes[0])
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if allowed[config] && ng == nil {
+					t.Errorf("expected a nodegroup for node %q", config.nodes[0].Name)
+				}
+				if !allowed[config] && ng != nil {
+					t.Errorf("expected no nodegroup for node %q, got %q", config.nodes[0].Name, ng.Id())
+				}
+			}
+		})
+	}
+}
+
 func TestControllerNodeGroupsNodeCount(t *testing.T) {
 	type testCase struct {
 		nodeGroups    int
@@ -775,8 +908,8 @@ func TestControllerNodeGroupsNodeCount(t *testing.T) {
 	}
 
 	annotations := map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "10",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
 	}
 
 	t.Run("MachineSet", func(t *testing.T) {
@@ -791,3 +924,81 @@ func TestControllerNodeGroupsNodeCount(t *testing.T) {
 		}
 	})
 }
+
+func TestControllerCustomMachineAPIGroup(t *testing.T) {
+	keys := newAnnotationKeys("cluster.k8s.io")
+	if keys.machineDelete != "cluster.k8s.io/delete-machine" {
+		t.Errorf("expected delete annotation %q, got %q", "cluster.k8s.io/delete-machine", keys.machineDelete)
+	}
+	if testAnnotationKeys.machineDelete != "machine.openshift.io/cluster-api-delete-machine" {
+		t.Errorf("expected delete annotation %q, got %q", "machine.openshift.io/cluster-api-delete-machine", testAnnotationKeys.machineDelete)
+	}
+
+	testConfig := createMachineSetTestConfig(testNamespace, 3, 3, map[string]string{
+		keys.nodeGroupMinSize: "1",
+		keys.nodeGroupMaxSize: "10",
+	})
+	for _, node := range testConfig.nodes {
+		node.Annotations[keys.machine] = node.Annotations[testAnnotationKeys.machine]
+		delete(node.Annotations, testAnnotationKeys.machine)
+	}
+
+	groupVersion := schema.GroupVersion{Group: "cluster.k8s.io", Version: "v1alpha1"}
+	controller, stop := mustCreateTestControllerForGroupVersion(t, groupVersion, testConfig)
+	defer stop()
+
+	ng, err := controller.nodeGroupForNode(testConfig.nodes[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ng == nil {
+		t.Fatal("expected a nodegroup")
+	}
+
+	if err := ng.IncreaseSize(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := controller.dynamicclient.Resource(groupVersion.WithResource(machineSetResource)).Namespace(testNamespace).Get(ng.Name(), v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj.GetAPIVersion() != "cluster.k8s.io/v1alpha1" {
+		t.Errorf("expected apiVersion %q, got %q", "cluster.k8s.io/v1alpha1", obj.GetAPIVersion())
+	}
+	replicas, _, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replicas != 5 {
+		t.Errorf("expected 5 replicas, got %d", replicas)
+	}
+}
+
+func TestControllerParseScaleDownAllowedWindows(t *testing.T) {
+	controller := &machineController{scaleDownAllowedWindows: make(map[string]*schedule.Cache)}
+
+	first, err := controller.parseScaleDownAllowedWindows("ng1", "0 22 * * 1-5 10h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := controller.parseScaleDownAllowedWindows("ng1", "0 22 * * 1-5 10h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second {
+		t.Error("expected the windows to be parsed once")
+	}
+
+	other, err := controller.parseScaleDownAllowedWindows("ng2", "0 22 * * 1-5 10h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other == first {
+		t.Error("expected the windows of each node group to be cached separately")
+	}
+
+	if _, err := controller.parseScaleDownAllowedWindows("ng1", "0 22 * * *"); err == nil {
+		t.Error("expected an error")
+	}
+}
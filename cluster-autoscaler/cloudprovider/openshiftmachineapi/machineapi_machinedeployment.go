@@ -21,13 +21,12 @@ import (
 	"path"
 
 	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
-	machinev1beta1 "github.com/openshift/cluster-api/pkg/client/clientset_generated/clientset/typed/machine/v1beta1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/utils/pointer"
 )
 
 type machineDeploymentScalableResource struct {
-	machineapiClient  machinev1beta1.MachineV1beta1Interface
 	controller        *machineController
 	machineDeployment *v1beta1.MachineDeployment
 	maxSize           int
@@ -56,16 +55,16 @@ func (r machineDeploymentScalableResource) Namespace() string {
 	return r.machineDeployment.Namespace
 }
 
-func (r machineDeploymentScalableResource) Nodes() ([]string, error) {
-	result := []string{}
+func (r machineDeploymentScalableResource) Nodes() ([]cloudprovider.Instance, error) {
+	result := []cloudprovider.Instance{}
 
 	if err := r.controller.filterAllMachineSets(func(machineSet *v1beta1.MachineSet) error {
 		if machineSetIsOwnedByMachineDeployment(machineSet, r.machineDeployment) {
-			names, err := r.controller.machineSetNodeNames(machineSet)
+			instances, err := r.controller.machineSetInstances(machineSet)
 			if err != nil {
 				return err
 			}
-			result = append(result, names...)
+			result = append(result, instances...)
 		}
 		return nil
 	}); err != nil {
@@ -79,30 +78,30 @@ func (r machineDeploymentScalableResource) Replicas() int32 {
 	return pointer.Int32PtrDerefOr(r.machineDeployment.Spec.Replicas, 0)
 }
 
-func (r machineDeploymentScalableResource) SetSize(nreplicas int32) error {
-	machineDeployment, err := r.machineapiClient.MachineDeployments(r.Namespace()).Get(r.Name(), metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("unable to get MachineDeployment %q: %v", r.ID(), err)
-	}
+func (r machineDeploymentScalableResource) Annotations() map[string]string {
+	return r.machineDeployment.Annotations
+}
 
-	machineDeployment = machineDeployment.DeepCopy()
-	machineDeployment.Spec.Replicas = &nreplicas
+func (r machineDeploymentScalableResource) MachineSpec() v1beta1.MachineSpec {
+	return r.machineDeployment.Spec.Template.Spec
+}
 
-	_, err = r.machineapiClient.MachineDeployments(r.Namespace()).Update(machineDeployment)
-	if err != nil {
+func (r machineDeploymentScalableResource) SetSize(nreplicas int32) error {
+	if err := r.controller.updateResource(machineDeploymentResource, r.Namespace(), r.Name(), func(obj *unstructured.Unstructured) error {
+		return unstructured.SetNestedField(obj.Object, int64(nreplicas), "spec", "replicas")
+	}); err != nil {
 		return fmt.Errorf("unable to update number of replicas of machineDeployment %q: %v", r.ID(), err)
 	}
 	return nil
 }
 
 func newMachineDeploymentScalableResource(controller *machineController, machineDeployment *v1beta1.MachineDeployment) (*machineDeploymentScalableResource, error) {
-	minSize, maxSize, err := parseScalingBounds(machineDeployment.Annotations)
+	minSize, maxSize, err := parseScalingBounds(controller.annotationKeys, machineDeployment.Annotations)
 	if err != nil {
 		return nil, fmt.Errorf("error validating min/max annotations: %v", err)
 	}
 
 	return &machineDeploymentScalableResource{
-		machineapiClient:  controller.clusterClientset.MachineV1beta1(),
 		controller:        controller,
 		machineDeployment: machineDeployment,
 		maxSize:           maxSize,
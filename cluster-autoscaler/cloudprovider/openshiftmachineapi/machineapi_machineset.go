@@ -21,17 +21,16 @@ import (
 	"path"
 
 	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
-	machinev1beta1 "github.com/openshift/cluster-api/pkg/client/clientset_generated/clientset/typed/machine/v1beta1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/utils/pointer"
 )
 
 type machineSetScalableResource struct {
-	machineapiClient machinev1beta1.MachineV1beta1Interface
-	controller       *machineController
-	machineSet       *v1beta1.MachineSet
-	maxSize          int
-	minSize          int
+	controller *machineController
+	machineSet *v1beta1.MachineSet
+	maxSize    int
+	minSize    int
 }
 
 var _ scalableResource = (*machineSetScalableResource)(nil)
@@ -56,41 +55,41 @@ func (r machineSetScalableResource) Namespace() string {
 	return r.machineSet.Namespace
 }
 
-func (r machineSetScalableResource) Nodes() ([]string, error) {
-	return r.controller.machineSetNodeNames(r.machineSet)
+func (r machineSetScalableResource) Nodes() ([]cloudprovider.Instance, error) {
+	return r.controller.machineSetInstances(r.machineSet)
 }
 
 func (r machineSetScalableResource) Replicas() int32 {
 	return pointer.Int32PtrDerefOr(r.machineSet.Spec.Replicas, 0)
 }
 
-func (r machineSetScalableResource) SetSize(nreplicas int32) error {
-	machineSet, err := r.machineapiClient.MachineSets(r.Namespace()).Get(r.Name(), metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("unable to get MachineSet %q: %v", r.ID(), err)
-	}
+func (r machineSetScalableResource) Annotations() map[string]string {
+	return r.machineSet.Annotations
+}
 
-	machineSet = machineSet.DeepCopy()
-	machineSet.Spec.Replicas = &nreplicas
+func (r machineSetScalableResource) MachineSpec() v1beta1.MachineSpec {
+	return r.machineSet.Spec.Template.Spec
+}
 
-	_, err = r.machineapiClient.MachineSets(r.Namespace()).Update(machineSet)
-	if err != nil {
+func (r machineSetScalableResource) SetSize(nreplicas int32) error {
+	if err := r.controller.updateResource(machineSetResource, r.Namespace(), r.Name(), func(obj *unstructured.Unstructured) error {
+		return unstructured.SetNestedField(obj.Object, int64(nreplicas), "spec", "replicas")
+	}); err != nil {
 		return fmt.Errorf("unable to update number of replicas of machineset %q: %v", r.ID(), err)
 	}
 	return nil
 }
 
 func newMachineSetScalableResource(controller *machineController, machineSet *v1beta1.MachineSet) (*machineSetScalableResource, error) {
-	minSize, maxSize, err := parseScalingBounds(machineSet.Annotations)
+	minSize, maxSize, err := parseScalingBounds(controller.annotationKeys, machineSet.Annotations)
 	if err != nil {
 		return nil, fmt.Errorf("error validating min/max annotations: %v", err)
 	}
 
 	return &machineSetScalableResource{
-		machineapiClient: controller.clusterClientset.MachineV1beta1(),
-		controller:       controller,
-		machineSet:       machineSet,
-		maxSize:          maxSize,
-		minSize:          minSize,
+		controller: controller,
+		machineSet: machineSet,
+		maxSize:    maxSize,
+		minSize:    minSize,
 	}, nil
 }
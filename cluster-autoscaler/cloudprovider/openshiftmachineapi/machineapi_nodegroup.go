@@ -21,22 +21,26 @@ import (
 	"time"
 
 	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
-	machinev1beta1 "github.com/openshift/cluster-api/pkg/client/clientset_generated/clientset/typed/machine/v1beta1"
 	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
 )
 
 const (
-	machineDeleteAnnotationKey = "machine.openshift.io/cluster-api-delete-machine"
-	machineAnnotationKey       = "machine.openshift.io/machine"
-	debugFormat                = "%s (min: %d, max: %d, replicas: %d)"
+	debugFormat = "%s (min: %d, max: %d, replicas: %d)"
 )
 
 type nodegroup struct {
-	machineapiClient  machinev1beta1.MachineV1beta1Interface
 	machineController *machineController
 	scalableResource  scalableResource
+	exists            bool
+	// machineSetObject is the MachineSet that Create() creates. It's
+	// only set for node groups returned by NewNodeGroup().
+	machineSetObject *unstructured.Unstructured
 }
 
 var _ cloudprovider.NodeGroup = (*nodegroup)(nil)
@@ -92,11 +96,11 @@ func (ng *nodegroup) DeleteNodes(nodes []*apiv1.Node) error {
 			return nil
 		}
 
-		if actualNodeGroup.Id() != ng.Id() {
+		if actualNodeGroup == nil || actualNodeGroup.Id() != ng.Id() {
 			return fmt.Errorf("node %q doesn't belong to node group %q", node.Spec.ProviderID, ng.Id())
 		}
 
-		machine, err := ng.machineController.findMachineByNodeProviderID(node)
+		machine, err := ng.machineController.findMachineByProviderID(node.Spec.ProviderID)
 		if err != nil {
 			return err
 		}
@@ -104,21 +108,21 @@ func (ng *nodegroup) DeleteNodes(nodes []*apiv1.Node) error {
 			return fmt.Errorf("unknown machine for node %q", node.Spec.ProviderID)
 		}
 
-		machine = machine.DeepCopy()
-
-		if machine.Annotations == nil {
-			machine.Annotations = map[string]string{}
-		}
-
-		machine.Annotations[machineDeleteAnnotationKey] = time.Now().String()
-
-		if _, err := ng.machineapiClient.Machines(machine.Namespace).Update(machine); err != nil {
+		if err := ng.machineController.updateResource(machineResource, machine.Namespace, machine.Name, func(obj *unstructured.Unstructured) error {
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[ng.machineController.annotationKeys.machineDelete] = time.Now().String()
+			obj.SetAnnotations(annotations)
+			return nil
+		}); err != nil {
 			return fmt.Errorf("failed to update machine %s/%s: %v", machine.Namespace, machine.Name, err)
 		}
 	}
 
-	if int(ng.scalableResource.Replicas())-len(nodes) <= 0 {
-		return fmt.Errorf("unable to delete %d machines in %q, machine replicas are <= 0", len(nodes), ng.Id())
+	if int(ng.scalableResource.Replicas())-len(nodes) < ng.MinSize() {
+		return fmt.Errorf("unable to delete %d machines in %q, machine replicas would be below the minimum size %d", len(nodes), ng.Id(), ng.MinSize())
 	}
 
 	return ng.scalableResource.SetSize(ng.scalableResource.Replicas() - int32(len(nodes)))
@@ -145,9 +149,18 @@ func (ng *nodegroup) DecreaseTargetSize(delta int) error {
 		return err
 	}
 
-	if size+delta < len(nodes) {
+	// Only machines backed by a node are existing nodes, the
+	// remaining ones are the requests for new nodes.
+	existingNodes := 0
+	for _, node := range nodes {
+		if node.Status != nil && node.Status.State == cloudprovider.STATE_RUNNING {
+			existingNodes++
+		}
+	}
+
+	if size+delta < existingNodes {
 		return fmt.Errorf("attempt to delete existing nodes targetSize:%d delta:%d existingNodes: %d",
-			size, delta, len(nodes))
+			size, delta, existingNodes)
 	}
 
 	return ng.scalableResource.SetSize(int32(size + delta))
@@ -164,20 +177,11 @@ func (ng *nodegroup) Debug() string {
 }
 
 // Nodes returns a list of all nodes that belong to this node group.
+// Machines that are not backed by a node yet are reported as being
+// created, together with the errors reported by the machine
+// controller.
 func (ng *nodegroup) Nodes() ([]cloudprovider.Instance, error) {
-	nodes, err := ng.scalableResource.Nodes()
-	if err != nil {
-		return nil, err
-	}
-
-	instances := make([]cloudprovider.Instance, len(nodes))
-	for i := range nodes {
-		instances[i] = cloudprovider.Instance{
-			Id: nodes[i],
-		}
-	}
-
-	return instances, nil
+	return ng.scalableResource.Nodes()
 }
 
 // TemplateNodeInfo returns a schedulercache.NodeInfo structure of an
@@ -187,36 +191,87 @@ func (ng *nodegroup) Nodes() ([]cloudprovider.Instance, error) {
 // fully populated Node object, with all of the labels, capacity and
 // allocatable information as well as all pods that are started on the
 // node by default, using manifest (most likely only kube-proxy).
-// Implementation optional.
+// The node is built from the annotations of the underlying scalable
+// resource and the instance type of its providerSpec. Returns
+// ErrNotImplemented if neither describe the node capacity.
 func (ng *nodegroup) TemplateNodeInfo() (*schedulercache.NodeInfo, error) {
-	return nil, cloudprovider.ErrNotImplemented
+	node, err := buildTemplateNode(ng.machineController.annotationKeys, ng.Name(), ng.scalableResource.Annotations(), ng.scalableResource.MachineSpec(), ng.machineController.instanceTypes)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, cloudprovider.ErrNotImplemented
+	}
+
+	nodeInfo := schedulercache.NewNodeInfo(cloudprovider.BuildKubeProxy(ng.Name()))
+	if err := nodeInfo.SetNode(node); err != nil {
+		return nil, err
+	}
+	return nodeInfo, nil
 }
 
 // Exist checks if the node group really exists on the cloud nodegroup
 // side. Allows to tell the theoretical node group from the real one.
-// Implementation required.
+// Only node groups returned by NewNodeGroup() don't exist until they
+// are created. Implementation required.
 func (ng *nodegroup) Exist() bool {
-	return true
+	return ng.exists
 }
 
-// Create creates the node group on the cloud nodegroup side.
+// Create creates the node group on the cloud nodegroup side. The
+// MachineSet of a node group returned by NewNodeGroup() is created.
 // Implementation optional.
 func (ng *nodegroup) Create() (cloudprovider.NodeGroup, error) {
-	return nil, cloudprovider.ErrAlreadyExist
+	if ng.Exist() {
+		return nil, cloudprovider.ErrAlreadyExist
+	}
+	if ng.machineSetObject == nil {
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	obj, err := ng.machineController.resource(machineSetResource).Namespace(ng.Namespace()).Create(ng.machineSetObject, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create MachineSet %q: %v", ng.Id(), err)
+	}
+	machineSet, err := machineSetFromUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return newNodegroupFromMachineSet(ng.machineController, machineSet)
 }
 
 // Delete deletes the node group on the cloud nodegroup side. This will
 // be executed only for autoprovisioned node groups, once their size
 // drops to 0. Implementation optional.
 func (ng *nodegroup) Delete() error {
-	return cloudprovider.ErrNotImplemented
+	if !ng.Autoprovisioned() {
+		return cloudprovider.ErrNotImplemented
+	}
+	if err := ng.machineController.resource(machineSetResource).Namespace(ng.Namespace()).Delete(ng.Name(), &metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete MachineSet %q: %v", ng.Id(), err)
+	}
+	return nil
 }
 
 // Autoprovisioned returns true if the node group is autoprovisioned.
 // An autoprovisioned group was created by CA and can be deleted when
-// scaled to 0.
+// scaled to 0. Autoprovisioned MachineSets are annotated with
+// the autoprovisioned annotation key.
 func (ng *nodegroup) Autoprovisioned() bool {
-	return false
+	return ng.scalableResource.Annotations()[ng.machineController.annotationKeys.autoprovisioned] == "true"
+}
+
+// GetOptions returns NodeGroupAutoscalingOptions that should be used
+// for this particular NodeGroup. Options are read from the
+// annotations of the underlying scalable resource. Returning
+// ErrNotImplemented will cause global defaults to be used.
+func (ng *nodegroup) GetOptions(defaults config.NodeGroupAutoscalingOptions) (*config.NodeGroupAutoscalingOptions, error) {
+	options := parseAutoscalingOptions(ng.machineController.annotationKeys, ng.scalableResource.Annotations(), defaults, func(windows string) (*schedule.Schedule, error) {
+		return ng.machineController.parseScaleDownAllowedWindows(ng.Id(), windows)
+	})
+	if options == nil {
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	return options, nil
 }
 
 func newNodegroupFromMachineSet(controller *machineController, machineSet *v1beta1.MachineSet) (*nodegroup, error) {
@@ -225,9 +280,9 @@ func newNodegroupFromMachineSet(controller *machineController, machineSet *v1bet
 		return nil, err
 	}
 	return &nodegroup{
-		machineapiClient:  controller.clusterClientset.MachineV1beta1(),
 		machineController: controller,
 		scalableResource:  scalableResource,
+		exists:            true,
 	}, nil
 }
 
@@ -237,8 +292,8 @@ func newNodegroupFromMachineDeployment(controller *machineController, machineDep
 		return nil, err
 	}
 	return &nodegroup{
-		machineapiClient:  controller.clusterClientset.MachineV1beta1(),
 		machineController: controller,
 		scalableResource:  scalableResource,
+		exists:            true,
 	}, nil
 }
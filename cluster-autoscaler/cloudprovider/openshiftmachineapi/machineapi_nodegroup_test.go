@@ -23,7 +23,6 @@ import (
 	"strings"
 	"testing"
 
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/utils/pointer"
 )
@@ -46,29 +45,29 @@ func TestNodeGroupNewNodeGroupConstructor(t *testing.T) {
 	var testCases = []testCase{{
 		description: "errors because minSize is invalid",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "-1",
-			nodeGroupMaxSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMinSize: "-1",
+			testAnnotationKeys.nodeGroupMaxSize: "0",
 		},
 		errors: true,
 	}, {
 		description: "errors because maxSize is invalid",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "0",
-			nodeGroupMaxSizeAnnotationKey: "-1",
+			testAnnotationKeys.nodeGroupMinSize: "0",
+			testAnnotationKeys.nodeGroupMaxSize: "-1",
 		},
 		errors: true,
 	}, {
 		description: "errors because minSize > maxSize",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "0",
 		},
 		errors: true,
 	}, {
 		description: "errors because maxSize < minSize",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "0",
 		},
 		errors: true,
 	}, {
@@ -80,7 +79,7 @@ func TestNodeGroupNewNodeGroupConstructor(t *testing.T) {
 	}, {
 		description: "no error: min=0, max=1",
 		annotations: map[string]string{
-			nodeGroupMaxSizeAnnotationKey: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "1",
 		},
 		minSize:  0,
 		maxSize:  1,
@@ -89,8 +88,8 @@ func TestNodeGroupNewNodeGroupConstructor(t *testing.T) {
 	}, {
 		description: "no error: min=1, max=10, replicas=5",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "10",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "10",
 		},
 		minSize:   1,
 		maxSize:   10,
@@ -268,7 +267,7 @@ func TestNodeGroupIncreaseSizeErrors(t *testing.T) {
 		switch v := (ng.scalableResource).(type) {
 		case *machineSetScalableResource:
 			// A nodegroup is immutable; get a fresh copy.
-			ms, err := ng.machineapiClient.MachineSets(ng.Namespace()).Get(ng.Name(), v1.GetOptions{})
+			ms, err := getMachineSet(ng.machineController, ng.Namespace(), ng.Name())
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -277,7 +276,7 @@ func TestNodeGroupIncreaseSizeErrors(t *testing.T) {
 			}
 		case *machineDeploymentScalableResource:
 			// A nodegroup is immutable; get a fresh copy.
-			md, err := ng.machineapiClient.MachineDeployments(ng.Namespace()).Get(ng.Name(), v1.GetOptions{})
+			md, err := getMachineDeployment(ng.machineController, ng.Namespace(), ng.Name())
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -293,8 +292,8 @@ func TestNodeGroupIncreaseSizeErrors(t *testing.T) {
 		for _, tc := range testCases {
 			t.Run(tc.description, func(t *testing.T) {
 				annotations := map[string]string{
-					nodeGroupMinSizeAnnotationKey: "1",
-					nodeGroupMaxSizeAnnotationKey: "10",
+					testAnnotationKeys.nodeGroupMinSize: "1",
+					testAnnotationKeys.nodeGroupMaxSize: "10",
 				}
 				test(t, &tc, createMachineSetTestConfig(testNamespace, int(tc.initial), tc.initial, annotations))
 			})
@@ -305,8 +304,8 @@ func TestNodeGroupIncreaseSizeErrors(t *testing.T) {
 		for _, tc := range testCases {
 			t.Run(tc.description, func(t *testing.T) {
 				annotations := map[string]string{
-					nodeGroupMinSizeAnnotationKey: "1",
-					nodeGroupMaxSizeAnnotationKey: "10",
+					testAnnotationKeys.nodeGroupMinSize: "1",
+					testAnnotationKeys.nodeGroupMaxSize: "10",
 				}
 				test(t, &tc, createMachineDeploymentTestConfig(testNamespace, int(tc.initial), tc.initial, annotations))
 			})
@@ -352,7 +351,7 @@ func TestNodeGroupIncreaseSize(t *testing.T) {
 		switch v := (ng.scalableResource).(type) {
 		case *machineSetScalableResource:
 			// A nodegroup is immutable; get a fresh copy.
-			ms, err := ng.machineapiClient.MachineSets(ng.Namespace()).Get(ng.Name(), v1.GetOptions{})
+			ms, err := getMachineSet(ng.machineController, ng.Namespace(), ng.Name())
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -361,7 +360,7 @@ func TestNodeGroupIncreaseSize(t *testing.T) {
 			}
 		case *machineDeploymentScalableResource:
 			// A nodegroup is immutable; get a fresh copy.
-			md, err := ng.machineapiClient.MachineDeployments(ng.Namespace()).Get(ng.Name(), v1.GetOptions{})
+			md, err := getMachineDeployment(ng.machineController, ng.Namespace(), ng.Name())
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -374,8 +373,8 @@ func TestNodeGroupIncreaseSize(t *testing.T) {
 	}
 
 	annotations := map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "10",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
 	}
 
 	t.Run("MachineSet", func(t *testing.T) {
@@ -433,7 +432,7 @@ func TestNodeGroupDecreaseTargetSize(t *testing.T) {
 			t.Fatalf("failed to add new node: %v", err)
 		}
 
-		if err := controller.machineInformer.Informer().GetStore().Add(testConfig.machines[0]); err != nil {
+		if err := controller.machineInformer.GetStore().Add(mustUnstructured(t, controller.groupVersion, testConfig.machines[0])); err != nil {
 			t.Fatalf("failed to add new machine: %v", err)
 		}
 
@@ -444,7 +443,7 @@ func TestNodeGroupDecreaseTargetSize(t *testing.T) {
 		switch v := (ng.scalableResource).(type) {
 		case *machineSetScalableResource:
 			// A nodegroup is immutable; get a fresh copy.
-			ms, err := ng.machineapiClient.MachineSets(ng.Namespace()).Get(ng.Name(), v1.GetOptions{})
+			ms, err := getMachineSet(ng.machineController, ng.Namespace(), ng.Name())
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -453,7 +452,7 @@ func TestNodeGroupDecreaseTargetSize(t *testing.T) {
 			}
 		case *machineDeploymentScalableResource:
 			// A nodegroup is immutable; get a fresh copy.
-			md, err := ng.machineapiClient.MachineDeployments(ng.Namespace()).Get(ng.Name(), v1.GetOptions{})
+			md, err := getMachineDeployment(ng.machineController, ng.Namespace(), ng.Name())
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -466,8 +465,8 @@ func TestNodeGroupDecreaseTargetSize(t *testing.T) {
 	}
 
 	annotations := map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "10",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "10",
 	}
 
 	t.Run("MachineSet", func(t *testing.T) {
@@ -552,7 +551,7 @@ func TestNodeGroupDecreaseSizeErrors(t *testing.T) {
 		switch v := (ng.scalableResource).(type) {
 		case *machineSetScalableResource:
 			// A nodegroup is immutable; get a fresh copy.
-			ms, err := ng.machineapiClient.MachineSets(ng.Namespace()).Get(ng.Name(), v1.GetOptions{})
+			ms, err := getMachineSet(ng.machineController, ng.Namespace(), ng.Name())
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -561,7 +560,7 @@ func TestNodeGroupDecreaseSizeErrors(t *testing.T) {
 			}
 		case *machineDeploymentScalableResource:
 			// A nodegroup is immutable; get a fresh copy.
-			md, err := ng.machineapiClient.MachineDeployments(ng.Namespace()).Get(ng.Name(), v1.GetOptions{})
+			md, err := getMachineDeployment(ng.machineController, ng.Namespace(), ng.Name())
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -577,8 +576,8 @@ func TestNodeGroupDecreaseSizeErrors(t *testing.T) {
 		for _, tc := range testCases {
 			t.Run(tc.description, func(t *testing.T) {
 				annotations := map[string]string{
-					nodeGroupMinSizeAnnotationKey: "1",
-					nodeGroupMaxSizeAnnotationKey: "10",
+					testAnnotationKeys.nodeGroupMinSize: "1",
+					testAnnotationKeys.nodeGroupMaxSize: "10",
 				}
 				test(t, &tc, createMachineSetTestConfig(testNamespace, int(tc.initial), tc.initial, annotations))
 			})
@@ -589,8 +588,8 @@ func TestNodeGroupDecreaseSizeErrors(t *testing.T) {
 		for _, tc := range testCases {
 			t.Run(tc.description, func(t *testing.T) {
 				annotations := map[string]string{
-					nodeGroupMinSizeAnnotationKey: "1",
-					nodeGroupMaxSizeAnnotationKey: "10",
+					testAnnotationKeys.nodeGroupMinSize: "1",
+					testAnnotationKeys.nodeGroupMaxSize: "10",
 				}
 				test(t, &tc, createMachineDeploymentTestConfig(testNamespace, int(tc.initial), tc.initial, annotations))
 			})
@@ -637,18 +636,18 @@ func TestNodeGroupDeleteNodes(t *testing.T) {
 		}
 
 		for i := 5; i < len(testConfig.machines); i++ {
-			machine, err := controller.clusterClientset.MachineV1beta1().Machines(testConfig.machines[i].Namespace).Get(testConfig.machines[i].Name, v1.GetOptions{})
+			machine, err := getMachine(controller, testConfig.machines[i].Namespace, testConfig.machines[i].Name)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if _, found := machine.Annotations[machineDeleteAnnotationKey]; !found {
-				t.Errorf("expected annotation %q on machine %s", machineDeleteAnnotationKey, machine.Name)
+			if _, found := machine.Annotations[testAnnotationKeys.machineDelete]; !found {
+				t.Errorf("expected annotation %q on machine %s", testAnnotationKeys.machineDelete, machine.Name)
 			}
 		}
 
 		switch v := (ng.scalableResource).(type) {
 		case *machineSetScalableResource:
-			updatedMachineSet, err := controller.clusterClientset.MachineV1beta1().MachineSets(testConfig.machineSet.Namespace).Get(testConfig.machineSet.Name, v1.GetOptions{})
+			updatedMachineSet, err := getMachineSet(controller, testConfig.machineSet.Namespace, testConfig.machineSet.Name)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -656,7 +655,7 @@ func TestNodeGroupDeleteNodes(t *testing.T) {
 				t.Fatalf("expected 5 nodes, got %v", actual)
 			}
 		case *machineDeploymentScalableResource:
-			updatedMachineDeployment, err := controller.clusterClientset.MachineV1beta1().MachineDeployments(testConfig.machineDeployment.Namespace).Get(testConfig.machineDeployment.Name, v1.GetOptions{})
+			updatedMachineDeployment, err := getMachineDeployment(controller, testConfig.machineDeployment.Namespace, testConfig.machineDeployment.Name)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -675,15 +674,15 @@ func TestNodeGroupDeleteNodes(t *testing.T) {
 
 	t.Run("MachineSet", func(t *testing.T) {
 		test(t, createMachineSetTestConfig(testNamespace, 10, 10, map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "10",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "10",
 		}))
 	})
 
 	t.Run("MachineDeployment", func(t *testing.T) {
 		test(t, createMachineDeploymentTestConfig(testNamespace, 10, 10, map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "10",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "10",
 		}))
 	})
 }
@@ -757,8 +756,8 @@ func TestNodeGroupMachineSetDeleteNodesWithMismatchedNodes(t *testing.T) {
 	}
 
 	annotations := map[string]string{
-		nodeGroupMinSizeAnnotationKey: "1",
-		nodeGroupMaxSizeAnnotationKey: "3",
+		testAnnotationKeys.nodeGroupMinSize: "1",
+		testAnnotationKeys.nodeGroupMaxSize: "3",
 	}
 
 	t.Run("MachineSet", func(t *testing.T) {
@@ -17,14 +17,16 @@ limitations under the License.
 package openshiftmachineapi
 
 import (
+	"os"
 	"reflect"
 
-	clusterclientset "github.com/openshift/cluster-api/pkg/client/clientset_generated/clientset"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -34,6 +36,14 @@ import (
 const (
 	// ProviderName is the name of cluster-api cloud provider.
 	ProviderName = "openshift-machine-api"
+
+	// machineAPIGroupEnvVar overrides the API group of the Machine
+	// API resources, e.g. "cluster.k8s.io" for upstream cluster-api.
+	machineAPIGroupEnvVar = "MACHINE_API_GROUP"
+
+	// machineAPIVersionEnvVar overrides the API version of the
+	// Machine API resources.
+	machineAPIVersionEnvVar = "MACHINE_API_VERSION"
 )
 
 var _ cloudprovider.CloudProvider = (*provider)(nil)
@@ -81,18 +91,23 @@ func (*provider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError)
 	return nil, cloudprovider.ErrNotImplemented
 }
 
-func (*provider) GetAvailableMachineTypes() ([]string, error) {
-	return []string{}, nil
+// GetAvailableMachineTypes returns the instance types of the
+// MachineSets annotated as autoprovisioning templates.
+func (p *provider) GetAvailableMachineTypes() ([]string, error) {
+	return p.controller.availableMachineTypes()
 }
 
-func (*provider) NewNodeGroup(
+// NewNodeGroup builds a theoretical node group from the template
+// MachineSet of machineType. The node group is not created until its
+// Create() method is called.
+func (p *provider) NewNodeGroup(
 	machineType string,
 	labels map[string]string,
 	systemLabels map[string]string,
 	taints []apiv1.Taint,
 	extraResources map[string]resource.Quantity,
 ) (cloudprovider.NodeGroup, error) {
-	return nil, cloudprovider.ErrNotImplemented
+	return p.controller.newAutoprovisionedNodeGroup(machineType, cloudprovider.JoinStringMaps(systemLabels, labels), taints)
 }
 
 func (*provider) Cleanup() error {
@@ -121,7 +136,9 @@ func newProvider(
 }
 
 // BuildOpenShiftMachineAPI builds CloudProvider implementation for machine api.
-func BuildOpenShiftMachineAPI(opts config.AutoscalingOptions, do cloudprovider.NodeGroupDiscoveryOptions, rl *cloudprovider.ResourceLimiter) cloudprovider.CloudProvider {
+// Capacity of template nodes is looked up in instanceTypes, which may be nil
+// if node groups carry capacity annotations.
+func BuildOpenShiftMachineAPI(opts config.AutoscalingOptions, do cloudprovider.NodeGroupDiscoveryOptions, rl *cloudprovider.ResourceLimiter, instanceTypes InstanceTypeLookup) cloudprovider.CloudProvider {
 	var err error
 	var externalConfig *rest.Config
 
@@ -142,13 +159,24 @@ func BuildOpenShiftMachineAPI(opts config.AutoscalingOptions, do cloudprovider.N
 		klog.Fatalf("create kube clientset failed: %v", err)
 	}
 
-	clusterclient, err := clusterclientset.NewForConfig(externalConfig)
+	dynamicclient, err := dynamic.NewForConfig(externalConfig)
 	if err != nil {
-		klog.Fatalf("create cluster clientset failed: %v", err)
+		klog.Fatalf("create dynamic client failed: %v", err)
+	}
+
+	groupVersion := schema.GroupVersion{
+		Group:   getEnvOrDefault(machineAPIGroupEnvVar, defaultMachineAPIGroup),
+		Version: getEnvOrDefault(machineAPIVersionEnvVar, defaultMachineAPIVersion),
+	}
+	klog.V(1).Infof("Using Machine API resources of %s", groupVersion)
+
+	autoDiscoveryConfigs, err := do.ParseMachineAPIAutoDiscoverySpecs()
+	if err != nil {
+		klog.Fatalf("cannot parse node group auto discovery specs: %v", err)
 	}
 
 	enableMachineDeployments := false
-	controller, err := newMachineController(kubeclient, clusterclient, enableMachineDeployments)
+	controller, err := newMachineController(kubeclient, dynamicclient, groupVersion, enableMachineDeployments, autoDiscoveryConfigs, instanceTypes)
 
 	if err != nil {
 		klog.Fatal(err)
@@ -169,3 +197,10 @@ func BuildOpenShiftMachineAPI(opts config.AutoscalingOptions, do cloudprovider.N
 
 	return provider
 }
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -16,6 +16,11 @@ limitations under the License.
 
 package openshiftmachineapi
 
+import (
+	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+)
+
 // scalableResource is a resource that can be scaled up and down by
 // adjusting its replica count field.
 type scalableResource interface {
@@ -34,13 +39,20 @@ type scalableResource interface {
 	// Namespace returns the namespace the resource is in
 	Namespace() string
 
-	// Nodes returns a list of all nodes that belong to this
-	// resource
-	Nodes() ([]string, error)
+	// Nodes returns a list of the instances of all machines that
+	// belong to this resource
+	Nodes() ([]cloudprovider.Instance, error)
 
 	// SetSize() sets the replica count of the resource
 	SetSize(nreplicas int32) error
 
 	// Replicas returns the current replica count of the resource
 	Replicas() int32
+
+	// Annotations returns the annotations of the resource
+	Annotations() map[string]string
+
+	// MachineSpec returns the spec of the machines created by the
+	// resource
+	MachineSpec() v1beta1.MachineSpec
 }
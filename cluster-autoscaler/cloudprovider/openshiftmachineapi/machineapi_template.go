@@ -0,0 +1,249 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshiftmachineapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+	"k8s.io/kubernetes/pkg/util/taints"
+)
+
+const (
+	// defaultMaxPods is the pod capacity of template nodes without
+	// the nodeGroupMaxPods annotation.
+	defaultMaxPods = 110
+)
+
+// providerSpecInstanceType holds the fields of the providerSpec of
+// the supported machine providers that name the instance type.
+type providerSpecInstanceType struct {
+	// InstanceType is set by the AWS provider.
+	InstanceType string `json:"instanceType"`
+	// VMSize is set by the Azure provider.
+	VMSize string `json:"vmSize"`
+}
+
+// InstanceType is the capacity of an instance type of a cloud
+// provider.
+type InstanceType struct {
+	VCPU     int64
+	MemoryMb int64
+	GPU      int64
+}
+
+// InstanceTypeLookup returns the capacity of the instance type of the
+// given name, as named in the providerSpec of machines. The second
+// result is false if the instance type is unknown.
+type InstanceTypeLookup func(name string) (InstanceType, bool)
+
+// templateCapacity is the capacity of the nodes of a scalable
+// resource.
+type templateCapacity struct {
+	instanceType string
+	cpu          *resource.Quantity
+	memory       *resource.Quantity
+	gpu          int64
+	maxPods      int64
+}
+
+// providerSpecInstanceTypeName returns the instance type found in the
+// providerSpec, or "" if there is none or if the providerSpec cannot
+// be decoded.
+func providerSpecInstanceTypeName(providerSpec v1beta1.ProviderSpec) string {
+	if providerSpec.Value == nil || len(providerSpec.Value.Raw) == 0 {
+		return ""
+	}
+
+	spec := providerSpecInstanceType{}
+	if err := json.Unmarshal(providerSpec.Value.Raw, &spec); err != nil {
+		return ""
+	}
+
+	if spec.InstanceType != "" {
+		return spec.InstanceType
+	}
+	return spec.VMSize
+}
+
+// instanceTypeCapacity returns the instance type found in the
+// providerSpec and its capacity. The capacity is nil if the instance
+// type is unknown to instanceTypes or if the providerSpec cannot be
+// decoded.
+func instanceTypeCapacity(providerSpec v1beta1.ProviderSpec, instanceTypes InstanceTypeLookup) (string, *templateCapacity) {
+	name := providerSpecInstanceTypeName(providerSpec)
+	if name == "" || instanceTypes == nil {
+		return name, nil
+	}
+	instanceType, found := instanceTypes(name)
+	if !found {
+		return name, nil
+	}
+	return name, newTemplateCapacity(instanceType.VCPU, instanceType.MemoryMb, instanceType.GPU)
+}
+
+func newTemplateCapacity(vcpu, memoryMb, gpuCount int64) *templateCapacity {
+	return &templateCapacity{
+		cpu:    resource.NewQuantity(vcpu, resource.DecimalSI),
+		memory: resource.NewQuantity(memoryMb*1024*1024, resource.BinarySI),
+		gpu:    gpuCount,
+	}
+}
+
+// parseTemplateCapacity returns the capacity of the nodes of a
+// scalable resource. Values encoded in the annotations take
+// precedence over the capacity of the instance type in the
+// providerSpec. Returns nil if neither describe both CPU and memory
+// or an error if any of the annotations cannot be parsed.
+func parseTemplateCapacity(keys *annotationKeys, annotations map[string]string, providerSpec v1beta1.ProviderSpec, instanceTypes InstanceTypeLookup) (*templateCapacity, error) {
+	instanceType, capacity := instanceTypeCapacity(providerSpec, instanceTypes)
+	if capacity == nil {
+		capacity = &templateCapacity{}
+	}
+	capacity.instanceType = instanceType
+	capacity.maxPods = defaultMaxPods
+
+	if val, ok := annotations[keys.nodeGroupCPU]; ok {
+		quantity, err := resource.ParseQuantity(val)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s annotation", keys.nodeGroupCPU)
+		}
+		capacity.cpu = &quantity
+	}
+
+	if val, ok := annotations[keys.nodeGroupMemory]; ok {
+		quantity, err := resource.ParseQuantity(val)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s annotation", keys.nodeGroupMemory)
+		}
+		capacity.memory = &quantity
+	}
+
+	for key, target := range map[string]*int64{
+		keys.nodeGroupGPU:     &capacity.gpu,
+		keys.nodeGroupMaxPods: &capacity.maxPods,
+	} {
+		if val, ok := annotations[key]; ok {
+			i, err := strconv.ParseInt(val, 10, 64)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("invalid %s annotation: %q", key, val)
+			}
+			*target = i
+		}
+	}
+
+	if capacity.cpu == nil || capacity.memory == nil {
+		return nil, nil
+	}
+	return capacity, nil
+}
+
+// parseTemplateLabels returns the labels encoded in the annotation
+// keyed by keys.nodeGroupLabels, e.g. "key1=value1,key2=value2".
+func parseTemplateLabels(keys *annotationKeys, annotations map[string]string) (map[string]string, error) {
+	val, ok := annotations[keys.nodeGroupLabels]
+	if !ok {
+		return nil, nil
+	}
+	result, err := labels.ConvertSelectorToLabelsMap(val)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s annotation", keys.nodeGroupLabels)
+	}
+	return result, nil
+}
+
+// parseTemplateTaints returns the taints encoded in the annotation
+// keyed by keys.nodeGroupTaints, e.g.
+// "key1=value1:NoSchedule,key2=value2:NoExecute".
+func parseTemplateTaints(keys *annotationKeys, annotations map[string]string) ([]apiv1.Taint, error) {
+	val, ok := annotations[keys.nodeGroupTaints]
+	if !ok || val == "" {
+		return nil, nil
+	}
+	result, toRemove, err := taints.ParseTaints(strings.Split(val, ","))
+	if err == nil && len(toRemove) > 0 {
+		err = fmt.Errorf("taint removals are not supported")
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s annotation", keys.nodeGroupTaints)
+	}
+	return result, nil
+}
+
+// buildTemplateNode returns a node as it would be created by the
+// scalable resource of the given name, annotations and machine spec.
+// Returns nil if the capacity of the node is unknown.
+func buildTemplateNode(keys *annotationKeys, name string, annotations map[string]string, machineSpec v1beta1.MachineSpec, instanceTypes InstanceTypeLookup) (*apiv1.Node, error) {
+	capacity, err := parseTemplateCapacity(keys, annotations, machineSpec.ProviderSpec, instanceTypes)
+	if err != nil || capacity == nil {
+		return nil, err
+	}
+
+	extraLabels, err := parseTemplateLabels(keys, annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	extraTaints, err := parseTemplateTaints(keys, annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeName := fmt.Sprintf("%s-template-%d", name, rand.Int63())
+	node := &apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:     nodeName,
+			SelfLink: fmt.Sprintf("/api/v1/nodes/%s", nodeName),
+		},
+		Status: apiv1.NodeStatus{
+			Capacity: apiv1.ResourceList{
+				apiv1.ResourcePods:    *resource.NewQuantity(capacity.maxPods, resource.DecimalSI),
+				apiv1.ResourceCPU:     *capacity.cpu,
+				apiv1.ResourceMemory:  *capacity.memory,
+				gpu.ResourceNvidiaGPU: *resource.NewQuantity(capacity.gpu, resource.DecimalSI),
+			},
+			Conditions: cloudprovider.BuildReadyConditions(),
+		},
+	}
+	node.Status.Allocatable = node.Status.Capacity
+
+	genericLabels := map[string]string{
+		kubeletapis.LabelArch:     cloudprovider.DefaultArch,
+		kubeletapis.LabelOS:       cloudprovider.DefaultOS,
+		kubeletapis.LabelHostname: nodeName,
+	}
+	if capacity.instanceType != "" {
+		genericLabels[kubeletapis.LabelInstanceType] = capacity.instanceType
+	}
+	node.Labels = cloudprovider.JoinStringMaps(genericLabels, machineSpec.Labels, extraLabels)
+
+	node.Spec.Taints = append(append([]apiv1.Taint{}, machineSpec.Taints...), extraTaints...)
+
+	return node, nil
+}
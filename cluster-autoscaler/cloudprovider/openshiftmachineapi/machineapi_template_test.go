@@ -0,0 +1,317 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshiftmachineapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+)
+
+func providerSpec(raw string) v1beta1.ProviderSpec {
+	return v1beta1.ProviderSpec{
+		Value: &runtime.RawExtension{Raw: []byte(raw)},
+	}
+}
+
+// testInstanceTypes knows a single AWS instance type and a single
+// Azure VM size.
+func testInstanceTypes(name string) (InstanceType, bool) {
+	instanceType, found := map[string]InstanceType{
+		"m5.large":        {VCPU: 2, MemoryMb: 8 * 1024},
+		"Standard_D4s_v3": {VCPU: 4, MemoryMb: 16 * 1024},
+	}[name]
+	return instanceType, found
+}
+
+func TestParseTemplateCapacity(t *testing.T) {
+	for _, tc := range []struct {
+		description  string
+		annotations  map[string]string
+		providerSpec v1beta1.ProviderSpec
+		error        bool
+		nilCapacity  bool
+		instanceType string
+		cpu          string
+		memory       string
+		gpu          int64
+		maxPods      int64
+	}{{
+		description: "no annotations and no providerSpec",
+		nilCapacity: true,
+	}, {
+		description: "missing memory annotation",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupCPU: "2",
+		},
+		nilCapacity: true,
+	}, {
+		description:  "unknown instance type",
+		providerSpec: providerSpec(`{"instanceType": "unknown.large"}`),
+		nilCapacity:  true,
+	}, {
+		description: "invalid cpu annotation",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupCPU:    "two",
+			testAnnotationKeys.nodeGroupMemory: "8Gi",
+		},
+		error: true,
+	}, {
+		description: "invalid gpu annotation",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupCPU:    "2",
+			testAnnotationKeys.nodeGroupMemory: "8Gi",
+			testAnnotationKeys.nodeGroupGPU:    "-1",
+		},
+		error: true,
+	}, {
+		description: "annotations",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupCPU:     "2",
+			testAnnotationKeys.nodeGroupMemory:  "8Gi",
+			testAnnotationKeys.nodeGroupGPU:     "1",
+			testAnnotationKeys.nodeGroupMaxPods: "50",
+		},
+		cpu:     "2",
+		memory:  "8Gi",
+		gpu:     1,
+		maxPods: 50,
+	}, {
+		description:  "AWS instance type",
+		providerSpec: providerSpec(`{"instanceType": "m5.large"}`),
+		instanceType: "m5.large",
+		cpu:          "2",
+		memory:       "8Gi",
+		maxPods:      defaultMaxPods,
+	}, {
+		description:  "Azure VM size",
+		providerSpec: providerSpec(`{"vmSize": "Standard_D4s_v3"}`),
+		instanceType: "Standard_D4s_v3",
+		cpu:          "4",
+		memory:       "16Gi",
+		maxPods:      defaultMaxPods,
+	}, {
+		description: "annotations override instance type",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupMemory: "6Gi",
+		},
+		providerSpec: providerSpec(`{"instanceType": "m5.large"}`),
+		instanceType: "m5.large",
+		cpu:          "2",
+		memory:       "6Gi",
+		maxPods:      defaultMaxPods,
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			capacity, err := parseTemplateCapacity(testAnnotationKeys, tc.annotations, tc.providerSpec, testInstanceTypes)
+			if tc.error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.nilCapacity {
+				if capacity != nil {
+					t.Fatalf("expected nil capacity, got %+v", capacity)
+				}
+				return
+			}
+			if capacity == nil {
+				t.Fatal("expected capacity to be non-nil")
+			}
+			if capacity.instanceType != tc.instanceType {
+				t.Errorf("expected instance type %q, got %q", tc.instanceType, capacity.instanceType)
+			}
+			if capacity.cpu.Cmp(resource.MustParse(tc.cpu)) != 0 {
+				t.Errorf("expected cpu %s, got %s", tc.cpu, capacity.cpu.String())
+			}
+			if capacity.memory.Cmp(resource.MustParse(tc.memory)) != 0 {
+				t.Errorf("expected memory %s, got %s", tc.memory, capacity.memory.String())
+			}
+			if capacity.gpu != tc.gpu {
+				t.Errorf("expected %d gpus, got %d", tc.gpu, capacity.gpu)
+			}
+			if capacity.maxPods != tc.maxPods {
+				t.Errorf("expected %d max pods, got %d", tc.maxPods, capacity.maxPods)
+			}
+		})
+	}
+}
+
+func TestParseTemplateTaints(t *testing.T) {
+	for _, tc := range []struct {
+		description string
+		value       string
+		error       bool
+		expected    []apiv1.Taint
+	}{{
+		description: "empty",
+		value:       "",
+	}, {
+		description: "removal",
+		value:       "key1-",
+		error:       true,
+	}, {
+		description: "invalid effect",
+		value:       "key1=value1:Never",
+		error:       true,
+	}, {
+		description: "multiple taints",
+		value:       "key1=value1:NoSchedule,key2=value2:NoExecute",
+		expected: []apiv1.Taint{
+			{Key: "key1", Value: "value1", Effect: apiv1.TaintEffectNoSchedule},
+			{Key: "key2", Value: "value2", Effect: apiv1.TaintEffectNoExecute},
+		},
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			taints, err := parseTemplateTaints(testAnnotationKeys, map[string]string{testAnnotationKeys.nodeGroupTaints: tc.value})
+			if tc.error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expected, taints) {
+				t.Errorf("expected %v, got %v", tc.expected, taints)
+			}
+		})
+	}
+}
+
+func TestNodeGroupTemplateNodeInfo(t *testing.T) {
+	annotations := map[string]string{
+		testAnnotationKeys.nodeGroupMinSize: "0",
+		testAnnotationKeys.nodeGroupMaxSize: "3",
+		testAnnotationKeys.nodeGroupGPU:     "1",
+		testAnnotationKeys.nodeGroupMaxPods: "50",
+		testAnnotationKeys.nodeGroupLabels:  "annotation-label=true",
+		testAnnotationKeys.nodeGroupTaints:  "dedicated=gpu:NoSchedule",
+	}
+	machineSpec := v1beta1.MachineSpec{
+		ProviderSpec: providerSpec(`{"instanceType": "m5.large"}`),
+		Taints: []apiv1.Taint{
+			{Key: "machine-taint", Value: "true", Effect: apiv1.TaintEffectNoExecute},
+		},
+	}
+	machineSpec.Labels = map[string]string{"machine-label": "true"}
+
+	test := func(t *testing.T, testConfig *testConfig) {
+		controller, stop := mustCreateTestController(t, testConfig)
+		defer stop()
+
+		nodegroups, err := controller.nodeGroups()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if l := len(nodegroups); l != 1 {
+			t.Fatalf("expected 1 nodegroup, got %d", l)
+		}
+		ng := nodegroups[0]
+
+		if size, _ := ng.TargetSize(); size != 0 {
+			t.Fatalf("expected target size 0, got %d", size)
+		}
+
+		nodeInfo, err := ng.TemplateNodeInfo()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		node := nodeInfo.Node()
+
+		capacity := node.Status.Capacity
+		if cpu := capacity[apiv1.ResourceCPU]; cpu.Value() != 2 {
+			t.Errorf("expected 2 cpus, got %s", cpu.String())
+		}
+		if memory := capacity[apiv1.ResourceMemory]; memory.Value() != 8*1024*1024*1024 {
+			t.Errorf("expected 8Gi memory, got %s", memory.String())
+		}
+		if gpus := capacity[gpu.ResourceNvidiaGPU]; gpus.Value() != 1 {
+			t.Errorf("expected 1 gpu, got %s", gpus.String())
+		}
+		if pods := capacity[apiv1.ResourcePods]; pods.Value() != 50 {
+			t.Errorf("expected 50 pods, got %s", pods.String())
+		}
+		if !reflect.DeepEqual(node.Status.Allocatable, capacity) {
+			t.Errorf("expected allocatable %v, got %v", capacity, node.Status.Allocatable)
+		}
+
+		for key, value := range map[string]string{
+			"machine-label":               "true",
+			"annotation-label":            "true",
+			kubeletapis.LabelInstanceType: "m5.large",
+			kubeletapis.LabelHostname:     node.Name,
+		} {
+			if node.Labels[key] != value {
+				t.Errorf("expected label %s=%s, got %q", key, value, node.Labels[key])
+			}
+		}
+
+		expectedTaints := []apiv1.Taint{
+			{Key: "machine-taint", Value: "true", Effect: apiv1.TaintEffectNoExecute},
+			{Key: "dedicated", Value: "gpu", Effect: apiv1.TaintEffectNoSchedule},
+		}
+		if !reflect.DeepEqual(expectedTaints, node.Spec.Taints) {
+			t.Errorf("expected taints %v, got %v", expectedTaints, node.Spec.Taints)
+		}
+
+		// kube-proxy runs on every node.
+		if l := len(nodeInfo.Pods()); l != 1 {
+			t.Errorf("expected 1 pod, got %d", l)
+		}
+	}
+
+	t.Run("MachineSet", func(t *testing.T) {
+		testConfig := createMachineSetTestConfig(testNamespace, 0, 0, annotations)
+		testConfig.machineSet.Spec.Template.Spec = machineSpec
+		test(t, testConfig)
+	})
+
+	t.Run("MachineDeployment", func(t *testing.T) {
+		testConfig := createMachineDeploymentTestConfig(testNamespace, 0, 0, annotations)
+		testConfig.machineDeployment.Spec.Template.Spec = machineSpec
+		test(t, testConfig)
+	})
+
+	t.Run("MachineSetWithoutCapacity", func(t *testing.T) {
+		testConfig := createMachineSetTestConfig(testNamespace, 0, 0, annotations)
+		controller, stop := mustCreateTestController(t, testConfig)
+		defer stop()
+
+		nodegroups, err := controller.nodeGroups()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if l := len(nodegroups); l != 1 {
+			t.Fatalf("expected 1 nodegroup, got %d", l)
+		}
+		if _, err := nodegroups[0].TemplateNodeInfo(); err != cloudprovider.ErrNotImplemented {
+			t.Errorf("expected ErrNotImplemented, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshiftmachineapi
+
+import (
+	"fmt"
+
+	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// The Machine API resources are read as unstructured objects and
+// converted to the v1beta1 types, whose schema is shared by the
+// supported API groups for the fields used by this provider. The
+// converted objects are only read, fields unknown to the v1beta1 types
+// are dropped. Writes mutate the unstructured objects instead, see
+// updateResource() and autoprovisionedMachineSetObject().
+
+func machineFromUnstructured(obj interface{}) (*v1beta1.Machine, error) {
+	machine := &v1beta1.Machine{}
+	if err := fromUnstructured(obj, machine); err != nil {
+		return nil, err
+	}
+	return machine, nil
+}
+
+func machineSetFromUnstructured(obj interface{}) (*v1beta1.MachineSet, error) {
+	machineSet := &v1beta1.MachineSet{}
+	if err := fromUnstructured(obj, machineSet); err != nil {
+		return nil, err
+	}
+	return machineSet, nil
+}
+
+func machineDeploymentFromUnstructured(obj interface{}) (*v1beta1.MachineDeployment, error) {
+	machineDeployment := &v1beta1.MachineDeployment{}
+	if err := fromUnstructured(obj, machineDeployment); err != nil {
+		return nil, err
+	}
+	return machineDeployment, nil
+}
+
+func fromUnstructured(obj interface{}, into interface{}) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("internal error; unexpected type %T", obj)
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), into)
+}
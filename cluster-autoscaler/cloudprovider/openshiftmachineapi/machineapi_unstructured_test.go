@@ -0,0 +1,292 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshiftmachineapi
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// fakeDynamicClient is an in-memory dynamic.Interface. Watchers are
+// notified of the objects created, updated and deleted through it.
+type fakeDynamicClient struct {
+	sync.Mutex
+	objects  map[schema.GroupVersionResource]map[string]*unstructured.Unstructured
+	watchers map[schema.GroupVersionResource][]*watch.RaceFreeFakeWatcher
+}
+
+var _ dynamic.Interface = (*fakeDynamicClient)(nil)
+
+type fakeResourceClient struct {
+	client    *fakeDynamicClient
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+var _ dynamic.NamespaceableResourceInterface = (*fakeResourceClient)(nil)
+
+// newFakeDynamicClient returns a fake client seeded with objects. The
+// resource of each object is its lowercase plural kind.
+func newFakeDynamicClient(objects ...*unstructured.Unstructured) *fakeDynamicClient {
+	c := &fakeDynamicClient{
+		objects:  map[schema.GroupVersionResource]map[string]*unstructured.Unstructured{},
+		watchers: map[schema.GroupVersionResource][]*watch.RaceFreeFakeWatcher{},
+	}
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		gvr := gvk.GroupVersion().WithResource(strings.ToLower(gvk.Kind) + "s")
+		if c.objects[gvr] == nil {
+			c.objects[gvr] = map[string]*unstructured.Unstructured{}
+		}
+		c.objects[gvr][path.Join(obj.GetNamespace(), obj.GetName())] = obj.DeepCopy()
+	}
+	return c
+}
+
+func (c *fakeDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &fakeResourceClient{client: c, gvr: gvr}
+}
+
+func (c *fakeDynamicClient) notify(gvr schema.GroupVersionResource, eventType watch.EventType, obj *unstructured.Unstructured) {
+	for _, w := range c.watchers[gvr] {
+		switch eventType {
+		case watch.Added:
+			w.Add(obj.DeepCopy())
+		case watch.Modified:
+			w.Modify(obj.DeepCopy())
+		case watch.Deleted:
+			w.Delete(obj.DeepCopy())
+		}
+	}
+}
+
+func (r *fakeResourceClient) Namespace(namespace string) dynamic.ResourceInterface {
+	return &fakeResourceClient{client: r.client, gvr: r.gvr, namespace: namespace}
+}
+
+func (r *fakeResourceClient) key(name string) string {
+	return path.Join(r.namespace, name)
+}
+
+func (r *fakeResourceClient) Create(obj *unstructured.Unstructured, options v1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	obj = obj.DeepCopy()
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(r.namespace)
+	}
+	if obj.GetUID() == "" {
+		obj.SetUID(types.UID(r.key(obj.GetName())))
+	}
+
+	objects := r.client.objects[r.gvr]
+	if objects == nil {
+		objects = map[string]*unstructured.Unstructured{}
+		r.client.objects[r.gvr] = objects
+	}
+	if _, found := objects[r.key(obj.GetName())]; found {
+		return nil, apierrors.NewAlreadyExists(r.gvr.GroupResource(), obj.GetName())
+	}
+	objects[r.key(obj.GetName())] = obj
+	r.client.notify(r.gvr, watch.Added, obj)
+	return obj.DeepCopy(), nil
+}
+
+func (r *fakeResourceClient) Update(obj *unstructured.Unstructured, options v1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	if _, found := r.client.objects[r.gvr][r.key(obj.GetName())]; !found {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), obj.GetName())
+	}
+	obj = obj.DeepCopy()
+	r.client.objects[r.gvr][r.key(obj.GetName())] = obj
+	r.client.notify(r.gvr, watch.Modified, obj)
+	return obj.DeepCopy(), nil
+}
+
+func (r *fakeResourceClient) UpdateStatus(obj *unstructured.Unstructured, options v1.UpdateOptions) (*unstructured.Unstructured, error) {
+	return r.Update(obj, options, "status")
+}
+
+func (r *fakeResourceClient) Delete(name string, options *v1.DeleteOptions, subresources ...string) error {
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	obj, found := r.client.objects[r.gvr][r.key(name)]
+	if !found {
+		return apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	delete(r.client.objects[r.gvr], r.key(name))
+	r.client.notify(r.gvr, watch.Deleted, obj)
+	return nil
+}
+
+func (r *fakeResourceClient) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return fmt.Errorf("DeleteCollection is not implemented")
+}
+
+func (r *fakeResourceClient) Get(name string, options v1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	obj, found := r.client.objects[r.gvr][r.key(name)]
+	if !found {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (r *fakeResourceClient) List(opts v1.ListOptions) (*unstructured.UnstructuredList, error) {
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &unstructured.UnstructuredList{}
+	for _, obj := range r.client.objects[r.gvr] {
+		if r.namespace != v1.NamespaceAll && obj.GetNamespace() != r.namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		list.Items = append(list.Items, *obj.DeepCopy())
+	}
+	return list, nil
+}
+
+func (r *fakeResourceClient) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	if r.namespace != v1.NamespaceAll {
+		return nil, fmt.Errorf("only watching all namespaces is implemented")
+	}
+
+	r.client.Lock()
+	defer r.client.Unlock()
+
+	w := watch.NewRaceFreeFake()
+	r.client.watchers[r.gvr] = append(r.client.watchers[r.gvr], w)
+	return w, nil
+}
+
+func (r *fakeResourceClient) Patch(name string, pt types.PatchType, data []byte, options v1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return nil, fmt.Errorf("Patch is not implemented")
+}
+
+// mustUnstructured converts a Machine API object to an unstructured
+// object of groupVersion.
+func mustUnstructured(t *testing.T, groupVersion schema.GroupVersion, obj runtime.Object) *unstructured.Unstructured {
+	t.Helper()
+
+	var kind string
+	switch obj.(type) {
+	case *v1beta1.Machine:
+		kind = "Machine"
+	case *v1beta1.MachineSet:
+		kind = "MachineSet"
+	case *v1beta1.MachineDeployment:
+		kind = "MachineDeployment"
+	default:
+		t.Fatalf("unexpected type: %T", obj)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(groupVersion.WithKind(kind))
+	return u
+}
+
+func getMachine(controller *machineController, namespace, name string) (*v1beta1.Machine, error) {
+	obj, err := controller.resource(machineResource).Namespace(namespace).Get(name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return machineFromUnstructured(obj)
+}
+
+func getMachineSet(controller *machineController, namespace, name string) (*v1beta1.MachineSet, error) {
+	obj, err := controller.resource(machineSetResource).Namespace(namespace).Get(name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return machineSetFromUnstructured(obj)
+}
+
+func getMachineDeployment(controller *machineController, namespace, name string) (*v1beta1.MachineDeployment, error) {
+	obj, err := controller.resource(machineDeploymentResource).Namespace(namespace).Get(name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return machineDeploymentFromUnstructured(obj)
+}
+
+func TestUnstructuredRoundTrip(t *testing.T) {
+	machineSet := &v1beta1.MachineSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "machineset",
+			Namespace:   "test",
+			Annotations: map[string]string{testAnnotationKeys.nodeGroupMaxSize: "3"},
+		},
+		Spec: v1beta1.MachineSetSpec{
+			Replicas: int32ptr(2),
+		},
+	}
+
+	u := mustUnstructured(t, schema.GroupVersion{Group: defaultMachineAPIGroup, Version: defaultMachineAPIVersion}, machineSet)
+	if u.GetAPIVersion() != "machine.openshift.io/v1beta1" || u.GetKind() != "MachineSet" {
+		t.Errorf("unexpected apiVersion and kind: %q %q", u.GetAPIVersion(), u.GetKind())
+	}
+
+	actual, err := machineSetFromUnstructured(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual.Name != machineSet.Name || actual.Namespace != machineSet.Namespace {
+		t.Errorf("expected %s/%s, got %s/%s", machineSet.Namespace, machineSet.Name, actual.Namespace, actual.Name)
+	}
+	if actual.Annotations[testAnnotationKeys.nodeGroupMaxSize] != "3" {
+		t.Errorf("expected annotation %q to be preserved", testAnnotationKeys.nodeGroupMaxSize)
+	}
+	if actual.Spec.Replicas == nil || *actual.Spec.Replicas != 2 {
+		t.Errorf("expected 2 replicas, got %v", actual.Spec.Replicas)
+	}
+
+	if _, err := machineSetFromUnstructured(machineSet); err == nil {
+		t.Error("expected an error converting a typed object")
+	}
+}
@@ -17,27 +17,137 @@ limitations under the License.
 package openshiftmachineapi
 
 import (
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/openshift/cluster-api/pkg/apis/machine/common"
 	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
+	"k8s.io/klog"
 )
 
 const (
-	nodeGroupMinSizeAnnotationKey = "machine.openshift.io/cluster-api-autoscaler-node-group-min-size"
-	nodeGroupMaxSizeAnnotationKey = "machine.openshift.io/cluster-api-autoscaler-node-group-max-size"
+	// defaultMachineAPIGroup is the API group of the OpenShift
+	// Machine API resources.
+	defaultMachineAPIGroup = "machine.openshift.io"
+	// defaultMachineAPIVersion is the API version of the OpenShift
+	// Machine API resources.
+	defaultMachineAPIVersion = "v1beta1"
+
+	// pendingMachinePrefix prefixes the namespace/name key of
+	// machines that have neither a node nor a ProviderID to build
+	// their instance id.
+	pendingMachinePrefix = "openshiftmachineapi-pending:///"
 )
 
+// annotationKeys are the keys of the annotations read and written by
+// the provider. They are prefixed with the API group of the Machine
+// API resources, see newAnnotationKeys().
+type annotationKeys struct {
+	nodeGroupMinSize string
+	nodeGroupMaxSize string
+
+	nodeGroupScaleDownUtilizationThreshold string
+	nodeGroupScaleDownUnneededTime         string
+	nodeGroupScaleDownUnreadyTime          string
+	nodeGroupMaxNodeProvisionTime          string
+	nodeGroupScaleDownAllowedWindows       string
+	nodeGroupHeadroomCPU                   string
+	nodeGroupHeadroomMemory                string
+	nodeGroupHeadroomNodes                 string
+
+	nodeGroupCPU     string
+	nodeGroupMemory  string
+	nodeGroupGPU     string
+	nodeGroupMaxPods string
+	nodeGroupLabels  string
+	nodeGroupTaints  string
+
+	// nodeGroupTemplate marks the MachineSets that autoprovisioned
+	// MachineSets are copied from, one per instance type.
+	nodeGroupTemplate string
+	// autoprovisioned marks the MachineSets created by the
+	// autoscaler.
+	autoprovisioned string
+
+	// machineDelete marks the machines that the MachineSet
+	// controller should delete first when scaling down.
+	machineDelete string
+	// machine is set on nodes to the namespace/name key of their
+	// machine.
+	machine string
+}
+
+// newAnnotationKeys derives the annotation keys from the API group
+// of the Machine API resources, e.g. "machine.openshift.io" or
+// "cluster.k8s.io" for upstream cluster-api.
+func newAnnotationKeys(group string) *annotationKeys {
+	key := func(name string) string {
+		return group + "/" + name
+	}
+
+	keys := &annotationKeys{
+		nodeGroupMinSize: key("cluster-api-autoscaler-node-group-min-size"),
+		nodeGroupMaxSize: key("cluster-api-autoscaler-node-group-max-size"),
+
+		nodeGroupScaleDownUtilizationThreshold: key("cluster-api-autoscaler-node-group-scale-down-utilization-threshold"),
+		nodeGroupScaleDownUnneededTime:         key("cluster-api-autoscaler-node-group-scale-down-unneeded-time"),
+		nodeGroupScaleDownUnreadyTime:          key("cluster-api-autoscaler-node-group-scale-down-unready-time"),
+		nodeGroupMaxNodeProvisionTime:          key("cluster-api-autoscaler-node-group-max-node-provision-time"),
+		nodeGroupScaleDownAllowedWindows:       key("cluster-api-autoscaler-node-group-scale-down-allowed-windows"),
+		nodeGroupHeadroomCPU:                   key("cluster-api-autoscaler-node-group-headroom-cpu"),
+		nodeGroupHeadroomMemory:                key("cluster-api-autoscaler-node-group-headroom-memory"),
+		nodeGroupHeadroomNodes:                 key("cluster-api-autoscaler-node-group-headroom-nodes"),
+
+		nodeGroupCPU:     key("cluster-api-autoscaler-node-group-cpu"),
+		nodeGroupMemory:  key("cluster-api-autoscaler-node-group-memory"),
+		nodeGroupGPU:     key("cluster-api-autoscaler-node-group-gpu"),
+		nodeGroupMaxPods: key("cluster-api-autoscaler-node-group-max-pods"),
+		nodeGroupLabels:  key("cluster-api-autoscaler-node-group-labels"),
+		nodeGroupTaints:  key("cluster-api-autoscaler-node-group-taints"),
+
+		nodeGroupTemplate: key("cluster-api-autoscaler-node-group-template"),
+		autoprovisioned:   key("cluster-api-autoscaler-autoprovisioned"),
+
+		machine: key("machine"),
+	}
+
+	// The OpenShift fork of cluster-api prefixes the delete
+	// annotation, upstream cluster-api does not.
+	if group == defaultMachineAPIGroup {
+		keys.machineDelete = key("cluster-api-delete-machine")
+	} else {
+		keys.machineDelete = key("delete-machine")
+	}
+
+	return keys
+}
+
+// outOfResourcesErrorMessages are substrings of the error messages
+// set by machine controllers when the cloud provider is out of
+// quota or capacity, e.g. "InsufficientInstanceCapacity" on AWS.
+var outOfResourcesErrorMessages = []string{
+	"quota",
+	"capacity",
+}
+
 var (
 	// errMissingMinAnnotation is the error returned when a
 	// machine set does not have an annotation keyed by
-	// nodeGroupMinSizeAnnotationKey.
+	// keys.nodeGroupMinSize.
 	errMissingMinAnnotation = errors.New("missing min annotation")
 
 	// errMissingMaxAnnotation is the error returned when a
 	// machine set does not have an annotation keyed by
-	// nodeGroupMaxSizeAnnotationKey.
+	// keys.nodeGroupMaxSize.
 	errMissingMaxAnnotation = errors.New("missing max annotation")
 
 	// errInvalidMinAnnotationValue is the error returned when a
@@ -50,11 +160,11 @@ var (
 )
 
 // minSize returns the minimum value encoded in the annotations keyed
-// by nodeGroupMinSizeAnnotationKey. Returns errMissingMinAnnotation
+// by keys.nodeGroupMinSize. Returns errMissingMinAnnotation
 // if the annotation doesn't exist or errInvalidMinAnnotation if the
 // value is not of type int.
-func minSize(annotations map[string]string) (int, error) {
-	val, found := annotations[nodeGroupMinSizeAnnotationKey]
+func minSize(keys *annotationKeys, annotations map[string]string) (int, error) {
+	val, found := annotations[keys.nodeGroupMinSize]
 	if !found {
 		return 0, errMissingMinAnnotation
 	}
@@ -66,11 +176,11 @@ func minSize(annotations map[string]string) (int, error) {
 }
 
 // maxSize returns the maximum value encoded in the annotations keyed
-// by nodeGroupMaxSizeAnnotationKey. Returns errMissingMaxAnnotation
+// by keys.nodeGroupMaxSize. Returns errMissingMaxAnnotation
 // if the annotation doesn't exist or errInvalidMaxAnnotation if the
 // value is not of type int.
-func maxSize(annotations map[string]string) (int, error) {
-	val, found := annotations[nodeGroupMaxSizeAnnotationKey]
+func maxSize(keys *annotationKeys, annotations map[string]string) (int, error) {
+	val, found := annotations[keys.nodeGroupMaxSize]
 	if !found {
 		return 0, errMissingMaxAnnotation
 	}
@@ -81,8 +191,8 @@ func maxSize(annotations map[string]string) (int, error) {
 	return i, nil
 }
 
-func parseScalingBounds(annotations map[string]string) (int, int, error) {
-	minSize, err := minSize(annotations)
+func parseScalingBounds(keys *annotationKeys, annotations map[string]string) (int, int, error) {
+	minSize, err := minSize(keys, annotations)
 	if err != nil && err != errMissingMinAnnotation {
 		return 0, 0, err
 	}
@@ -91,7 +201,7 @@ func parseScalingBounds(annotations map[string]string) (int, int, error) {
 		return 0, 0, errInvalidMinAnnotation
 	}
 
-	maxSize, err := maxSize(annotations)
+	maxSize, err := maxSize(keys, annotations)
 	if err != nil && err != errMissingMaxAnnotation {
 		return 0, 0, err
 	}
@@ -107,6 +217,142 @@ func parseScalingBounds(annotations map[string]string) (int, int, error) {
 	return minSize, maxSize, nil
 }
 
+// parseAutoscalingOptions returns defaults overridden with the values
+// encoded in the node group autoscaling options annotations. Returns
+// nil if none of the annotations exist. Annotations whose values
+// cannot be parsed are skipped with a warning. Scale down windows are
+// parsed with parseWindows.
+func parseAutoscalingOptions(keys *annotationKeys, annotations map[string]string, defaults config.NodeGroupAutoscalingOptions, parseWindows func(string) (*schedule.Schedule, error)) *config.NodeGroupAutoscalingOptions {
+	found := false
+	if val, ok := annotations[keys.nodeGroupScaleDownUtilizationThreshold]; ok {
+		if threshold, err := strconv.ParseFloat(val, 64); err != nil {
+			warnInvalidAnnotation(keys.nodeGroupScaleDownUtilizationThreshold, val, err)
+		} else {
+			defaults.ScaleDownUtilizationThreshold = threshold
+			found = true
+		}
+	}
+
+	for key, target := range map[string]*time.Duration{
+		keys.nodeGroupScaleDownUnneededTime: &defaults.ScaleDownUnneededTime,
+		keys.nodeGroupScaleDownUnreadyTime:  &defaults.ScaleDownUnreadyTime,
+		keys.nodeGroupMaxNodeProvisionTime:  &defaults.MaxNodeProvisionTime,
+	} {
+		if val, ok := annotations[key]; ok {
+			if duration, err := time.ParseDuration(val); err != nil {
+				warnInvalidAnnotation(key, val, err)
+			} else {
+				*target = duration
+				found = true
+			}
+		}
+	}
+
+	if val, ok := annotations[keys.nodeGroupScaleDownAllowedWindows]; ok {
+		if windows, err := parseWindows(val); err != nil {
+			warnInvalidAnnotation(keys.nodeGroupScaleDownAllowedWindows, val, err)
+		} else {
+			defaults.ScaleDownAllowedWindows = val
+			defaults.ScaleDownAllowedSchedule = windows
+			found = true
+		}
+	}
+
+	if val, ok := annotations[keys.nodeGroupHeadroomCPU]; ok {
+		if quantity, err := resource.ParseQuantity(val); err != nil {
+			warnInvalidAnnotation(keys.nodeGroupHeadroomCPU, val, err)
+		} else {
+			defaults.Headroom.MilliCPU = quantity.MilliValue()
+			found = true
+		}
+	}
+
+	if val, ok := annotations[keys.nodeGroupHeadroomMemory]; ok {
+		if quantity, err := resource.ParseQuantity(val); err != nil {
+			warnInvalidAnnotation(keys.nodeGroupHeadroomMemory, val, err)
+		} else {
+			defaults.Headroom.Memory = quantity.Value()
+			found = true
+		}
+	}
+
+	if val, ok := annotations[keys.nodeGroupHeadroomNodes]; ok {
+		if nodes, err := strconv.Atoi(val); err != nil {
+			warnInvalidAnnotation(keys.nodeGroupHeadroomNodes, val, err)
+		} else {
+			defaults.Headroom.Nodes = nodes
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return &defaults
+}
+
+func warnInvalidAnnotation(key, val string, err error) {
+	klog.Warningf("ignoring invalid %s annotation %q: %v", key, val, err)
+}
+
+// pendingMachineID returns the instance id of a machine that is not
+// backed by a node: its ProviderID if it's already set, otherwise
+// its namespace/name key prefixed with pendingMachinePrefix.
+func pendingMachineID(machine *v1beta1.Machine) string {
+	if machine.Spec.ProviderID != nil && *machine.Spec.ProviderID != "" {
+		return *machine.Spec.ProviderID
+	}
+	return pendingMachinePrefix + path.Join(machine.Namespace, machine.Name)
+}
+
+// machineInstanceStatus returns the instance status of machine.
+// Machines that are not backed by a node are being created, and the
+// error reason and message set on them by the machine controller are
+// reported as ErrorInfo. InsufficientResources errors, and errors
+// caused by missing quota or capacity, are out of resources errors.
+func machineInstanceStatus(machine *v1beta1.Machine, hasNode bool) *cloudprovider.InstanceStatus {
+	if machine.DeletionTimestamp != nil {
+		return &cloudprovider.InstanceStatus{State: cloudprovider.STATE_BEING_DELETED}
+	}
+	if hasNode {
+		return &cloudprovider.InstanceStatus{State: cloudprovider.STATE_RUNNING}
+	}
+
+	status := &cloudprovider.InstanceStatus{State: cloudprovider.STATE_BEING_CREATED}
+	if machine.Status.ErrorReason == nil && machine.Status.ErrorMessage == nil {
+		return status
+	}
+
+	errorInfo := &cloudprovider.InstanceErrorInfo{ErrorClass: cloudprovider.ERROR_OTHER}
+	if machine.Status.ErrorReason != nil {
+		errorInfo.ErrorCode = string(*machine.Status.ErrorReason)
+		if *machine.Status.ErrorReason == common.InsufficientResourcesMachineError {
+			errorInfo.ErrorClass = cloudprovider.ERROR_OUT_OF_RESOURCES
+		}
+	}
+	if machine.Status.ErrorMessage != nil {
+		errorInfo.ErrorMessage = *machine.Status.ErrorMessage
+		message := strings.ToLower(errorInfo.ErrorMessage)
+		for _, substring := range outOfResourcesErrorMessages {
+			if strings.Contains(message, substring) {
+				errorInfo.ErrorClass = cloudprovider.ERROR_OUT_OF_RESOURCES
+				break
+			}
+		}
+	}
+	status.ErrorInfo = errorInfo
+	return status
+}
+
+// autoDiscoveryConfigMatches returns true if obj is in the namespace
+// of cfg, if any, and its labels match the selector of cfg.
+func autoDiscoveryConfigMatches(cfg cloudprovider.MachineAPIAutoDiscoveryConfig, obj metav1.Object) bool {
+	if cfg.Namespace != "" && cfg.Namespace != obj.GetNamespace() {
+		return false
+	}
+	return labels.SelectorFromSet(labels.Set(cfg.Selector)).Matches(labels.Set(obj.GetLabels()))
+}
+
 func machineOwnerRef(machine *v1beta1.Machine) *metav1.OwnerReference {
 	for _, ref := range machine.OwnerReferences {
 		if ref.Kind == "MachineSet" && ref.Name != "" {
@@ -17,11 +17,17 @@ limitations under the License.
 package openshiftmachineapi
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/openshift/cluster-api/pkg/apis/machine/common"
 	"github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 )
 
 const (
@@ -39,75 +45,75 @@ func TestParseScalingBounds(t *testing.T) {
 	}{{
 		description: "missing min annotation defaults to 0 and no error",
 		annotations: map[string]string{
-			nodeGroupMaxSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMaxSize: "0",
 		},
 	}, {
 		description: "missing max annotation defaults to 0 and no error",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMinSize: "0",
 		},
 	}, {
 		description: "invalid min errors",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "-1",
-			nodeGroupMaxSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMinSize: "-1",
+			testAnnotationKeys.nodeGroupMaxSize: "0",
 		},
 		error: errInvalidMinAnnotation,
 	}, {
 		description: "invalid min errors",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "not-an-int",
-			nodeGroupMaxSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMinSize: "not-an-int",
+			testAnnotationKeys.nodeGroupMaxSize: "0",
 		},
 		error: errInvalidMinAnnotation,
 	}, {
 		description: "invalid max errors",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "0",
-			nodeGroupMaxSizeAnnotationKey: "-1",
+			testAnnotationKeys.nodeGroupMinSize: "0",
+			testAnnotationKeys.nodeGroupMaxSize: "-1",
 		},
 		error: errInvalidMaxAnnotation,
 	}, {
 		description: "invalid max errors",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "0",
-			nodeGroupMaxSizeAnnotationKey: "not-an-int",
+			testAnnotationKeys.nodeGroupMinSize: "0",
+			testAnnotationKeys.nodeGroupMaxSize: "not-an-int",
 		},
 		error: errInvalidMaxAnnotation,
 	}, {
 		description: "negative min errors",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "-1",
-			nodeGroupMaxSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMinSize: "-1",
+			testAnnotationKeys.nodeGroupMaxSize: "0",
 		},
 		error: errInvalidMinAnnotation,
 	}, {
 		description: "negative max errors",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "0",
-			nodeGroupMaxSizeAnnotationKey: "-1",
+			testAnnotationKeys.nodeGroupMinSize: "0",
+			testAnnotationKeys.nodeGroupMaxSize: "-1",
 		},
 		error: errInvalidMaxAnnotation,
 	}, {
 		description: "max < min errors",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "1",
-			nodeGroupMaxSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMinSize: "1",
+			testAnnotationKeys.nodeGroupMaxSize: "0",
 		},
 		error: errInvalidMaxAnnotation,
 	}, {
 		description: "result is: min 0, max 0",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "0",
-			nodeGroupMaxSizeAnnotationKey: "0",
+			testAnnotationKeys.nodeGroupMinSize: "0",
+			testAnnotationKeys.nodeGroupMaxSize: "0",
 		},
 		min: 0,
 		max: 0,
 	}, {
 		description: "result is min 0, max 1",
 		annotations: map[string]string{
-			nodeGroupMinSizeAnnotationKey: "0",
-			nodeGroupMaxSizeAnnotationKey: "1",
+			testAnnotationKeys.nodeGroupMinSize: "0",
+			testAnnotationKeys.nodeGroupMaxSize: "1",
 		},
 		min: 0,
 		max: 1,
@@ -119,7 +125,7 @@ func TestParseScalingBounds(t *testing.T) {
 				},
 			}
 
-			min, max, err := parseScalingBounds(machineSet.Annotations)
+			min, max, err := parseScalingBounds(testAnnotationKeys, machineSet.Annotations)
 			if tc.error != nil && err == nil {
 				t.Fatalf("test #%d: expected an error", i)
 			}
@@ -142,6 +148,120 @@ func TestParseScalingBounds(t *testing.T) {
 	}
 }
 
+func TestParseAutoscalingOptions(t *testing.T) {
+	defaults := config.NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.5,
+		ScaleDownUnneededTime:         10 * time.Minute,
+		ScaleDownUnreadyTime:          20 * time.Minute,
+		MaxNodeProvisionTime:          15 * time.Minute,
+	}
+
+	for _, tc := range []struct {
+		description string
+		annotations map[string]string
+		expected    *config.NodeGroupAutoscalingOptions
+	}{{
+		description: "no option annotations returns nil",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupMinSize: "0",
+		},
+	}, {
+		description: "only invalid annotations returns nil",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupScaleDownUtilizationThreshold: "not-a-float",
+		},
+	}, {
+		description: "invalid duration is ignored",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupMaxNodeProvisionTime:  "not-a-duration",
+			testAnnotationKeys.nodeGroupScaleDownUnneededTime: "1h",
+		},
+		expected: &config.NodeGroupAutoscalingOptions{
+			ScaleDownUtilizationThreshold: 0.5,
+			ScaleDownUnneededTime:         time.Hour,
+			ScaleDownUnreadyTime:          20 * time.Minute,
+			MaxNodeProvisionTime:          15 * time.Minute,
+		},
+	}, {
+		description: "invalid scale down windows are ignored",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupScaleDownAllowedWindows:       "0 22 * * *",
+			testAnnotationKeys.nodeGroupScaleDownUtilizationThreshold: "0.7",
+		},
+		expected: &config.NodeGroupAutoscalingOptions{
+			ScaleDownUtilizationThreshold: 0.7,
+			ScaleDownUnneededTime:         10 * time.Minute,
+			ScaleDownUnreadyTime:          20 * time.Minute,
+			MaxNodeProvisionTime:          15 * time.Minute,
+		},
+	}, {
+		description: "scale down windows are set",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupScaleDownAllowedWindows: "0 22 * * 1-5 10h",
+		},
+		expected: &config.NodeGroupAutoscalingOptions{
+			ScaleDownUtilizationThreshold: 0.5,
+			ScaleDownUnneededTime:         10 * time.Minute,
+			ScaleDownUnreadyTime:          20 * time.Minute,
+			MaxNodeProvisionTime:          15 * time.Minute,
+			ScaleDownAllowedWindows:       "0 22 * * 1-5 10h",
+			ScaleDownAllowedSchedule:      mustParseSchedule("0 22 * * 1-5 10h"),
+		},
+	}, {
+		description: "invalid headroom is ignored",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupHeadroomCPU:    "1",
+			testAnnotationKeys.nodeGroupHeadroomMemory: "lots",
+		},
+		expected: &config.NodeGroupAutoscalingOptions{
+			ScaleDownUtilizationThreshold: 0.5,
+			ScaleDownUnneededTime:         10 * time.Minute,
+			ScaleDownUnreadyTime:          20 * time.Minute,
+			MaxNodeProvisionTime:          15 * time.Minute,
+			Headroom: config.Headroom{
+				MilliCPU: 1000,
+			},
+		},
+	}, {
+		description: "headroom is set",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupHeadroomCPU:    "500m",
+			testAnnotationKeys.nodeGroupHeadroomMemory: "2Gi",
+			testAnnotationKeys.nodeGroupHeadroomNodes:  "1",
+		},
+		expected: &config.NodeGroupAutoscalingOptions{
+			ScaleDownUtilizationThreshold: 0.5,
+			ScaleDownUnneededTime:         10 * time.Minute,
+			ScaleDownUnreadyTime:          20 * time.Minute,
+			MaxNodeProvisionTime:          15 * time.Minute,
+			Headroom: config.Headroom{
+				MilliCPU: 500,
+				Memory:   2 * 1024 * 1024 * 1024,
+				Nodes:    1,
+			},
+		},
+	}, {
+		description: "annotations override defaults",
+		annotations: map[string]string{
+			testAnnotationKeys.nodeGroupScaleDownUtilizationThreshold: "0.7",
+			testAnnotationKeys.nodeGroupScaleDownUnneededTime:         "1h",
+		},
+		expected: &config.NodeGroupAutoscalingOptions{
+			ScaleDownUtilizationThreshold: 0.7,
+			ScaleDownUnneededTime:         time.Hour,
+			ScaleDownUnreadyTime:          20 * time.Minute,
+			MaxNodeProvisionTime:          15 * time.Minute,
+		},
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			options := parseAutoscalingOptions(testAnnotationKeys, tc.annotations, defaults, schedule.Parse)
+			if !reflect.DeepEqual(tc.expected, options) {
+				t.Errorf("expected %+v, got %+v", tc.expected, options)
+			}
+		})
+	}
+}
+
 func TestMachineSetIsOwnedByMachineDeployment(t *testing.T) {
 	for _, tc := range []struct {
 		description       string
@@ -370,3 +490,106 @@ func TestMachineSetMachineDeploymentOwnerRef(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineInstanceStatus(t *testing.T) {
+	insufficientResources := common.InsufficientResourcesMachineError
+	invalidConfiguration := common.InvalidConfigurationMachineError
+	quotaMessage := "You have requested more vCPU capacity than your current vCPU limit allows"
+	otherMessage := "AMI not found"
+	now := v1.Now()
+
+	for _, tc := range []struct {
+		description string
+		machine     v1beta1.Machine
+		hasNode     bool
+		state       cloudprovider.InstanceState
+		errorClass  cloudprovider.InstanceErrorClass
+		errorCode   string
+		noError     bool
+	}{{
+		description: "machine with a node is running",
+		hasNode:     true,
+		state:       cloudprovider.STATE_RUNNING,
+		noError:     true,
+	}, {
+		description: "machine without a node is being created",
+		state:       cloudprovider.STATE_BEING_CREATED,
+		noError:     true,
+	}, {
+		description: "deleted machine is being deleted",
+		machine: v1beta1.Machine{
+			ObjectMeta: v1.ObjectMeta{DeletionTimestamp: &now},
+		},
+		hasNode: true,
+		state:   cloudprovider.STATE_BEING_DELETED,
+		noError: true,
+	}, {
+		description: "insufficient resources reason is out of resources",
+		machine: v1beta1.Machine{
+			Status: v1beta1.MachineStatus{ErrorReason: &insufficientResources},
+		},
+		state:      cloudprovider.STATE_BEING_CREATED,
+		errorClass: cloudprovider.ERROR_OUT_OF_RESOURCES,
+		errorCode:  string(insufficientResources),
+	}, {
+		description: "capacity message is out of resources",
+		machine: v1beta1.Machine{
+			Status: v1beta1.MachineStatus{ErrorReason: &invalidConfiguration, ErrorMessage: &quotaMessage},
+		},
+		state:      cloudprovider.STATE_BEING_CREATED,
+		errorClass: cloudprovider.ERROR_OUT_OF_RESOURCES,
+		errorCode:  string(invalidConfiguration),
+	}, {
+		description: "other errors",
+		machine: v1beta1.Machine{
+			Status: v1beta1.MachineStatus{ErrorReason: &invalidConfiguration, ErrorMessage: &otherMessage},
+		},
+		state:      cloudprovider.STATE_BEING_CREATED,
+		errorClass: cloudprovider.ERROR_OTHER,
+		errorCode:  string(invalidConfiguration),
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			status := machineInstanceStatus(&tc.machine, tc.hasNode)
+			if status.State != tc.state {
+				t.Errorf("expected state %v, got %v", tc.state, status.State)
+			}
+			if tc.noError {
+				if status.ErrorInfo != nil {
+					t.Errorf("expected no error info, got %+v", status.ErrorInfo)
+				}
+				return
+			}
+			if status.ErrorInfo == nil {
+				t.Fatalf("expected error info")
+			}
+			if status.ErrorInfo.ErrorClass != tc.errorClass {
+				t.Errorf("expected error class %v, got %v", tc.errorClass, status.ErrorInfo.ErrorClass)
+			}
+			if status.ErrorInfo.ErrorCode != tc.errorCode {
+				t.Errorf("expected error code %q, got %q", tc.errorCode, status.ErrorInfo.ErrorCode)
+			}
+		})
+	}
+}
+
+func TestPendingMachineID(t *testing.T) {
+	providerID := "aws:///us-east-1a/i-0123"
+	machine := &v1beta1.Machine{
+		ObjectMeta: v1.ObjectMeta{Namespace: "test-namespace", Name: "machine-0"},
+	}
+	if got, want := pendingMachineID(machine), pendingMachinePrefix+"test-namespace/machine-0"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	machine.Spec.ProviderID = &providerID
+	if got := pendingMachineID(machine); got != providerID {
+		t.Errorf("expected %q, got %q", providerID, got)
+	}
+}
+
+func mustParseSchedule(spec string) *schedule.Schedule {
+	s, err := schedule.Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
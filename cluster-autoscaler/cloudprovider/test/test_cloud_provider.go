@@ -23,6 +23,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
 )
@@ -44,6 +45,7 @@ type OnNodeGroupDeleteFunc func(string) error
 type TestCloudProvider struct {
 	sync.Mutex
 	nodes             map[string]string
+	instanceStatuses  map[string]*cloudprovider.InstanceStatus
 	groups            map[string]cloudprovider.NodeGroup
 	onScaleUp         func(string, int) error
 	onScaleDown       func(string, string) error
@@ -52,16 +54,18 @@ type TestCloudProvider struct {
 	machineTypes      []string
 	machineTemplates  map[string]*schedulercache.NodeInfo
 	resourceLimiter   *cloudprovider.ResourceLimiter
+	pricingModel      cloudprovider.PricingModel
 }
 
 // NewTestCloudProvider builds new TestCloudProvider
 func NewTestCloudProvider(onScaleUp OnScaleUpFunc, onScaleDown OnScaleDownFunc) *TestCloudProvider {
 	return &TestCloudProvider{
-		nodes:           make(map[string]string),
-		groups:          make(map[string]cloudprovider.NodeGroup),
-		onScaleUp:       onScaleUp,
-		onScaleDown:     onScaleDown,
-		resourceLimiter: cloudprovider.NewResourceLimiter(make(map[string]int64), make(map[string]int64)),
+		nodes:            make(map[string]string),
+		instanceStatuses: make(map[string]*cloudprovider.InstanceStatus),
+		groups:           make(map[string]cloudprovider.NodeGroup),
+		onScaleUp:        onScaleUp,
+		onScaleDown:      onScaleDown,
+		resourceLimiter:  cloudprovider.NewResourceLimiter(make(map[string]int64), make(map[string]int64)),
 	}
 }
 
@@ -71,6 +75,7 @@ func NewTestAutoprovisioningCloudProvider(onScaleUp OnScaleUpFunc, onScaleDown O
 	machineTypes []string, machineTemplates map[string]*schedulercache.NodeInfo) *TestCloudProvider {
 	return &TestCloudProvider{
 		nodes:             make(map[string]string),
+		instanceStatuses:  make(map[string]*cloudprovider.InstanceStatus),
 		groups:            make(map[string]cloudprovider.NodeGroup),
 		onScaleUp:         onScaleUp,
 		onScaleDown:       onScaleDown,
@@ -126,7 +131,15 @@ func (tcp *TestCloudProvider) NodeGroupForNode(node *apiv1.Node) (cloudprovider.
 
 // Pricing returns pricing model for this cloud provider or error if not available.
 func (tcp *TestCloudProvider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError) {
-	return nil, cloudprovider.ErrNotImplemented
+	if tcp.pricingModel == nil {
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	return tcp.pricingModel, nil
+}
+
+// SetPricingModel sets the pricing model returned by Pricing().
+func (tcp *TestCloudProvider) SetPricingModel(pricingModel cloudprovider.PricingModel) {
+	tcp.pricingModel = pricingModel
 }
 
 // GetAvailableMachineTypes get all machine types that can be requested from the cloud provider.
@@ -203,6 +216,14 @@ func (tcp *TestCloudProvider) AddNode(nodeGroupId string, node *apiv1.Node) {
 	tcp.nodes[node.Name] = nodeGroupId
 }
 
+// SetInstanceStatus sets the status reported for the instance backing the given node.
+func (tcp *TestCloudProvider) SetInstanceStatus(nodeName string, status *cloudprovider.InstanceStatus) {
+	tcp.Lock()
+	defer tcp.Unlock()
+
+	tcp.instanceStatuses[nodeName] = status
+}
+
 // GetResourceLimiter returns struct containing limits (max, min) for resources (cores, memory etc.).
 func (tcp *TestCloudProvider) GetResourceLimiter() (*cloudprovider.ResourceLimiter, error) {
 	return tcp.resourceLimiter, nil
@@ -242,6 +263,7 @@ type TestNodeGroup struct {
 	machineType     string
 	labels          map[string]string
 	taints          []apiv1.Taint
+	options         *config.NodeGroupAutoscalingOptions
 }
 
 // MaxSize returns maximum size of the node group.
@@ -368,7 +390,7 @@ func (tng *TestNodeGroup) Nodes() ([]cloudprovider.Instance, error) {
 	instances := make([]cloudprovider.Instance, 0)
 	for node, nodegroup := range tng.cloudProvider.nodes {
 		if nodegroup == tng.id {
-			instances = append(instances, cloudprovider.Instance{Id: node})
+			instances = append(instances, cloudprovider.Instance{Id: node, Status: tng.cloudProvider.instanceStatuses[node]})
 		}
 	}
 	return instances, nil
@@ -379,6 +401,26 @@ func (tng *TestNodeGroup) Autoprovisioned() bool {
 	return tng.autoprovisioned
 }
 
+// GetOptions returns NodeGroupAutoscalingOptions set with SetOptions, or ErrNotImplemented
+// if there are none.
+func (tng *TestNodeGroup) GetOptions(defaults config.NodeGroupAutoscalingOptions) (*config.NodeGroupAutoscalingOptions, error) {
+	tng.Lock()
+	defer tng.Unlock()
+
+	if tng.options == nil {
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	return tng.options, nil
+}
+
+// SetOptions sets NodeGroupAutoscalingOptions returned by GetOptions.
+func (tng *TestNodeGroup) SetOptions(options *config.NodeGroupAutoscalingOptions) {
+	tng.Lock()
+	defer tng.Unlock()
+
+	tng.options = options
+}
+
 // TemplateNodeInfo returns a node template for this node group.
 func (tng *TestNodeGroup) TemplateNodeInfo() (*schedulercache.NodeInfo, error) {
 	if tng.cloudProvider.machineTemplates == nil {
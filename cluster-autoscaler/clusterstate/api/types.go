@@ -35,6 +35,9 @@ const (
 	// ClusterAutoscalerScaleUp is a condition that explains what is the current status
 	// of a node group with regard to scale up activities.
 	ClusterAutoscalerScaleUp ClusterAutoscalerConditionType = "ScaleUp"
+	// ClusterAutoscalerHeadroom is a condition that explains whether the configured headroom
+	// is available in the cluster or in a node group.
+	ClusterAutoscalerHeadroom ClusterAutoscalerConditionType = "Headroom"
 )
 
 // ClusterAutoscalerConditionStatus is a status of ClusterAutoscalerCondition.
@@ -54,6 +57,9 @@ const (
 	ClusterAutoscalerCandidatesPresent ClusterAutoscalerConditionStatus = "CandidatesPresent"
 	//ClusterAutoscalerNoCandidates status means that there are no candidates for scale down.
 	ClusterAutoscalerNoCandidates ClusterAutoscalerConditionStatus = "NoCandidates"
+	// ClusterAutoscalerScaleDownBlackout status means that scale down is not allowed at the moment,
+	// because it's outside of the configured scale down windows.
+	ClusterAutoscalerScaleDownBlackout ClusterAutoscalerConditionStatus = "Blackout"
 
 	// Statuses for ScaleUp condition type.
 
@@ -67,6 +73,13 @@ const (
 	ClusterAutoscalerNoActivity ClusterAutoscalerConditionStatus = "NoActivity"
 	// ClusterAutoscalerBackoff status means that due to a recently failed scale-up no further scale-ups attempts will be made for some time.
 	ClusterAutoscalerBackoff ClusterAutoscalerConditionStatus = "Backoff"
+
+	// Statuses for Headroom condition type.
+
+	// ClusterAutoscalerHeadroomAvailable status means that all of the headroom fits on existing nodes.
+	ClusterAutoscalerHeadroomAvailable ClusterAutoscalerConditionStatus = "Available"
+	// ClusterAutoscalerHeadroomMissing status means that some of the headroom doesn't fit on existing nodes.
+	ClusterAutoscalerHeadroomMissing ClusterAutoscalerConditionStatus = "Missing"
 )
 
 // ClusterAutoscalerCondition describes some aspect of ClusterAutoscaler work.
@@ -92,6 +105,8 @@ type ClusterAutoscalerStatus struct {
 	NodeGroupStatuses []NodeGroupStatus `json:"nodeGroupStatuses,omitempty"`
 	// ClusterwideConditions contains conditions that apply to the whole autoscaler.
 	ClusterwideConditions []ClusterAutoscalerCondition `json:"clusterwideConditions,omitempty"`
+	// Nodes contains numbers of nodes in the whole cluster.
+	Nodes NodeCounts `json:"nodes,omitempty"`
 }
 
 // NodeGroupStatus contains status of a group of nodes controlled by ClusterAutoscaler.
@@ -101,4 +116,32 @@ type NodeGroupStatus struct {
 	ProviderID string `json:"providerID,omitempty"`
 	// Conditions is a list of conditions that describe the state of the node group.
 	Conditions []ClusterAutoscalerCondition `json:"conditions,omitempty"`
+	// MinSize is the minimum size of the node group.
+	MinSize int `json:"minSize,omitempty"`
+	// MaxSize is the maximum size of the node group.
+	MaxSize int `json:"maxSize,omitempty"`
+	// TargetSize is the target size of the node group in the cloud provider.
+	TargetSize int `json:"targetSize,omitempty"`
+	// Nodes contains numbers of nodes in the node group.
+	Nodes NodeCounts `json:"nodes,omitempty"`
+	// ScaleDownCandidates contains names of nodes that are candidates for scale down.
+	ScaleDownCandidates []string `json:"scaleDownCandidates,omitempty"`
+	// BackoffUntil is set if scale up of the node group is backed off after a failed scale up.
+	BackoffUntil *metav1.Time `json:"backoffUntil,omitempty"`
+}
+
+// NodeCounts contains numbers of nodes in different states.
+type NodeCounts struct {
+	// Ready is the number of ready nodes.
+	Ready int `json:"ready,omitempty"`
+	// Unready is the number of unready nodes that broke down after they started.
+	Unready int `json:"unready,omitempty"`
+	// NotStarted is the number of nodes that are not yet fully started.
+	NotStarted int `json:"notStarted,omitempty"`
+	// LongNotStarted is the number of nodes that failed to start within a reasonable limit.
+	LongNotStarted int `json:"longNotStarted,omitempty"`
+	// Registered is the number of all nodes registered in Kubernetes.
+	Registered int `json:"registered,omitempty"`
+	// LongUnregistered is the number of nodes that failed to register within a reasonable limit.
+	LongUnregistered int `json:"longUnregistered,omitempty"`
 }
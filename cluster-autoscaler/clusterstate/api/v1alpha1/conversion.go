@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
+)
+
+// FromInternal builds a ClusterAutoscalerStatus object with the given name and namespace out of
+// the status computed by ClusterStateRegistry.
+func FromInternal(name, namespace string, status *api.ClusterAutoscalerStatus, now time.Time) *ClusterAutoscalerStatus {
+	result := &ClusterAutoscalerStatus{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Status: ClusterAutoscalerStatusDetails{
+			LastUpdateTime:        metav1.Time{Time: now},
+			ClusterwideConditions: conditionsFromInternal(status.ClusterwideConditions),
+			Nodes:                 nodeCountsFromInternal(status.Nodes),
+		},
+	}
+	for _, nodeGroupStatus := range status.NodeGroupStatuses {
+		result.Status.NodeGroups = append(result.Status.NodeGroups, NodeGroupStatus{
+			Name:                nodeGroupStatus.ProviderID,
+			MinSize:             nodeGroupStatus.MinSize,
+			MaxSize:             nodeGroupStatus.MaxSize,
+			TargetSize:          nodeGroupStatus.TargetSize,
+			Nodes:               nodeCountsFromInternal(nodeGroupStatus.Nodes),
+			Conditions:          conditionsFromInternal(nodeGroupStatus.Conditions),
+			BackoffUntil:        nodeGroupStatus.BackoffUntil.DeepCopy(),
+			ScaleDownCandidates: append([]string(nil), nodeGroupStatus.ScaleDownCandidates...),
+		})
+	}
+	return result
+}
+
+func conditionsFromInternal(conditions []api.ClusterAutoscalerCondition) []Condition {
+	result := make([]Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		result = append(result, Condition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Message:            condition.Message,
+			Reason:             condition.Reason,
+			LastProbeTime:      condition.LastProbeTime,
+			LastTransitionTime: condition.LastTransitionTime,
+		})
+	}
+	return result
+}
+
+func nodeCountsFromInternal(counts api.NodeCounts) NodeCounts {
+	return NodeCounts{
+		Ready:            counts.Ready,
+		Unready:          counts.Unready,
+		NotStarted:       counts.NotStarted,
+		LongNotStarted:   counts.LongNotStarted,
+		Registered:       counts.Registered,
+		LongUnregistered: counts.LongUnregistered,
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterAutoscalerStatus) DeepCopyInto(out *ClusterAutoscalerStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *ClusterAutoscalerStatus) DeepCopy() *ClusterAutoscalerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAutoscalerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject returns a deep copy of the receiver as runtime.Object.
+func (in *ClusterAutoscalerStatus) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterAutoscalerStatusList) DeepCopyInto(out *ClusterAutoscalerStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]ClusterAutoscalerStatus, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *ClusterAutoscalerStatusList) DeepCopy() *ClusterAutoscalerStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAutoscalerStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject returns a deep copy of the receiver as runtime.Object.
+func (in *ClusterAutoscalerStatusList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ClusterAutoscalerStatusDetails) DeepCopyInto(out *ClusterAutoscalerStatusDetails) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	out.ClusterwideConditions = deepCopyConditions(in.ClusterwideConditions)
+	if in.NodeGroups != nil {
+		out.NodeGroups = make([]NodeGroupStatus, len(in.NodeGroups))
+		for i := range in.NodeGroups {
+			in.NodeGroups[i].DeepCopyInto(&out.NodeGroups[i])
+		}
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *NodeGroupStatus) DeepCopyInto(out *NodeGroupStatus) {
+	*out = *in
+	out.Conditions = deepCopyConditions(in.Conditions)
+	if in.BackoffUntil != nil {
+		out.BackoffUntil = in.BackoffUntil.DeepCopy()
+	}
+	if in.ScaleDownCandidates != nil {
+		out.ScaleDownCandidates = make([]string, len(in.ScaleDownCandidates))
+		copy(out.ScaleDownCandidates, in.ScaleDownCandidates)
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+func deepCopyConditions(in []Condition) []Condition {
+	if in == nil {
+		return nil
+	}
+	out := make([]Condition, len(in))
+	for i := range in {
+		in[i].DeepCopyInto(&out[i])
+	}
+	return out
+}
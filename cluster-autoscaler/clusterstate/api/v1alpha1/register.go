@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName is the API group of the ClusterAutoscalerStatus resource.
+	GroupName = "cluster-autoscaler.kubernetes.io"
+	// Kind is the kind of the ClusterAutoscalerStatus resource.
+	Kind = "ClusterAutoscalerStatus"
+	// Resource is the plural name of the ClusterAutoscalerStatus resource.
+	Resource = "clusterautoscalerstatuses"
+)
+
+var (
+	// SchemeGroupVersion is the group version of the ClusterAutoscalerStatus resource.
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+	// SchemeGroupVersionResource is the group version resource of the ClusterAutoscalerStatus resource.
+	SchemeGroupVersionResource = SchemeGroupVersion.WithResource(Resource)
+
+	// SchemeBuilder registers the types of this package in a scheme.
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the types of this package to a scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion, &ClusterAutoscalerStatus{}, &ClusterAutoscalerStatusList{})
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterAutoscalerStatus is the status of Cluster Autoscaler, written by Cluster Autoscaler after
+// every loop.
+type ClusterAutoscalerStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Status is the most recently observed status of Cluster Autoscaler.
+	Status ClusterAutoscalerStatusDetails `json:"status,omitempty"`
+}
+
+// ClusterAutoscalerStatusList is a list of ClusterAutoscalerStatus objects.
+type ClusterAutoscalerStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterAutoscalerStatus `json:"items"`
+}
+
+// ClusterAutoscalerStatusDetails contains the status of the cluster and of individual node groups.
+type ClusterAutoscalerStatusDetails struct {
+	// LastUpdateTime is the time the status was written.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// ClusterwideConditions contains conditions that apply to the whole cluster.
+	ClusterwideConditions []Condition `json:"clusterwideConditions,omitempty"`
+	// Nodes contains numbers of nodes in the whole cluster.
+	Nodes NodeCounts `json:"nodes,omitempty"`
+	// NodeGroups contains statuses of individual node groups.
+	NodeGroups []NodeGroupStatus `json:"nodeGroups,omitempty"`
+}
+
+// NodeGroupStatus is the status of a single node group.
+type NodeGroupStatus struct {
+	// Name is the cloud-provider-specific id of the node group.
+	Name string `json:"name"`
+	// MinSize is the minimum size of the node group.
+	MinSize int `json:"minSize"`
+	// MaxSize is the maximum size of the node group.
+	MaxSize int `json:"maxSize"`
+	// TargetSize is the target size of the node group in the cloud provider.
+	TargetSize int `json:"targetSize"`
+	// Nodes contains numbers of nodes in the node group.
+	Nodes NodeCounts `json:"nodes,omitempty"`
+	// Conditions describe the state of the node group.
+	Conditions []Condition `json:"conditions,omitempty"`
+	// BackoffUntil is set if scale up of the node group is backed off after a failed scale up.
+	BackoffUntil *metav1.Time `json:"backoffUntil,omitempty"`
+	// ScaleDownCandidates contains names of nodes from the node group that are candidates for scale down.
+	ScaleDownCandidates []string `json:"scaleDownCandidates,omitempty"`
+}
+
+// NodeCounts contains numbers of nodes in different states.
+type NodeCounts struct {
+	// Ready is the number of ready nodes.
+	Ready int `json:"ready"`
+	// Unready is the number of unready nodes that broke down after they started.
+	Unready int `json:"unready"`
+	// NotStarted is the number of nodes that are not yet fully started.
+	NotStarted int `json:"notStarted"`
+	// LongNotStarted is the number of nodes that failed to start within a reasonable limit.
+	LongNotStarted int `json:"longNotStarted"`
+	// Registered is the number of all nodes registered in Kubernetes.
+	Registered int `json:"registered"`
+	// LongUnregistered is the number of nodes that failed to register within a reasonable limit.
+	LongUnregistered int `json:"longUnregistered"`
+}
+
+// Condition describes one aspect of the state of the cluster or of a node group, e.g. health or
+// scale-up activity.
+type Condition struct {
+	// Type is the type of the condition, e.g. Health, ScaleUp or ScaleDown.
+	Type string `json:"type"`
+	// Status is the status of the condition, e.g. Healthy or InProgress.
+	Status string `json:"status"`
+	// Message is a human readable description of the condition.
+	Message string `json:"message,omitempty"`
+	// Reason is a machine readable reason of the condition.
+	Reason string `json:"reason,omitempty"`
+	// LastProbeTime is the last time the condition was probed.
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+	// LastTransitionTime is the last time the condition changed its status.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/deletetaint"
@@ -110,6 +111,9 @@ type UnregisteredNode struct {
 	Node *apiv1.Node
 	// UnregisteredSince is the time when the node was first spotted.
 	UnregisteredSince time.Time
+	// ErrorInfo is set if the cloud provider reported that the node failed to be created. Such nodes
+	// are considered long unregistered right away.
+	ErrorInfo *cloudprovider.InstanceErrorInfo
 }
 
 // ClusterStateRegistry is a structure to keep track the current state of the cluster.
@@ -126,7 +130,12 @@ type ClusterStateRegistry struct {
 	acceptableRanges        map[string]AcceptableRange
 	incorrectNodeGroupSizes map[string]IncorrectNodeGroupSize
 	unregisteredNodes       map[string]UnregisteredNode
+	failedInstances         map[string]bool
 	candidatesForScaleDown  map[string][]string
+	scaleDownBlackouts      map[string]ScaleDownBlackout
+	clusterwideBlackout     *ScaleDownBlackout
+	headroom                map[string]HeadroomStatus
+	clusterwideHeadroom     *HeadroomStatus
 	nodeGroupBackoffInfo    backoff.Backoff
 	lastStatus              *api.ClusterAutoscalerStatus
 	lastScaleDownUpdateTime time.Time
@@ -149,7 +158,10 @@ func NewClusterStateRegistry(cloudProvider cloudprovider.CloudProvider, config C
 		acceptableRanges:        make(map[string]AcceptableRange),
 		incorrectNodeGroupSizes: make(map[string]IncorrectNodeGroupSize),
 		unregisteredNodes:       make(map[string]UnregisteredNode),
+		failedInstances:         make(map[string]bool),
 		candidatesForScaleDown:  make(map[string][]string),
+		scaleDownBlackouts:      make(map[string]ScaleDownBlackout),
+		headroom:                make(map[string]HeadroomStatus),
 		nodeGroupBackoffInfo:    backoff,
 		lastStatus:              emptyStatus,
 		logRecorder:             logRecorder,
@@ -223,6 +235,47 @@ func (csr *ClusterStateRegistry) backoffNodeGroup(nodeGroup cloudprovider.NodeGr
 	klog.Warningf("Disabling scale-up for node group %v until %v", nodeGroup.Id(), backoffUntil)
 }
 
+// handleInstanceCreationErrors backs off node groups with instances that the cloud provider
+// reported as failed to be created since the last call, instead of waiting for the scale-up to
+// time out. To be executed under a lock.
+func (csr *ClusterStateRegistry) handleInstanceCreationErrors(instances map[string][]cloudprovider.Instance, currentTime time.Time) {
+	failedInstances := make(map[string]bool)
+	for _, nodeGroup := range csr.cloudProvider.NodeGroups() {
+		var newlyFailed []cloudprovider.Instance
+		for _, instance := range instances[nodeGroup.Id()] {
+			if !isInstanceCreationFailed(instance) {
+				continue
+			}
+			failedInstances[instance.Id] = true
+			if !csr.failedInstances[instance.Id] {
+				newlyFailed = append(newlyFailed, instance)
+			}
+		}
+		if len(newlyFailed) == 0 {
+			continue
+		}
+
+		errorInfo := newlyFailed[0].Status.ErrorInfo
+		reason := metrics.CloudProviderError
+		if errorInfo.ErrorClass == cloudprovider.ERROR_OUT_OF_RESOURCES {
+			reason = metrics.OutOfResources
+		}
+		klog.Warningf("Failed to create %d instances in node group %v: %s %s",
+			len(newlyFailed), nodeGroup.Id(), errorInfo.ErrorCode, errorInfo.ErrorMessage)
+		csr.logRecorder.Eventf(apiv1.EventTypeWarning, "ScaleUpFailed",
+			"Failed to create %d nodes in group %s: %s %s",
+			len(newlyFailed), nodeGroup.Id(), errorInfo.ErrorCode, errorInfo.ErrorMessage)
+		metrics.RegisterFailedScaleUp(reason)
+		csr.backoffNodeGroup(nodeGroup, currentTime)
+		delete(csr.scaleUpRequests, nodeGroup.Id())
+	}
+	csr.failedInstances = failedInstances
+}
+
+func isInstanceCreationFailed(instance cloudprovider.Instance) bool {
+	return instance.Status != nil && instance.Status.State == cloudprovider.STATE_BEING_CREATED && instance.Status.ErrorInfo != nil
+}
+
 // RegisterFailedScaleUp should be called after getting error from cloudprovider
 // when trying to scale-up node group. It will mark this group as not safe to autoscale
 // for some time.
@@ -241,10 +294,11 @@ func (csr *ClusterStateRegistry) UpdateNodes(nodes []*apiv1.Node, nodeInfosForGr
 	if err != nil {
 		return err
 	}
-	notRegistered, err := getNotRegisteredNodes(nodes, csr.cloudProvider, currentTime)
+	instances, err := getCloudProviderInstances(csr.cloudProvider)
 	if err != nil {
 		return err
 	}
+	notRegistered := getNotRegisteredNodes(nodes, csr.cloudProvider, instances, currentTime)
 
 	csr.Lock()
 	defer csr.Unlock()
@@ -253,6 +307,7 @@ func (csr *ClusterStateRegistry) UpdateNodes(nodes []*apiv1.Node, nodeInfosForGr
 	csr.nodeInfosForGroups = nodeInfosForGroups
 
 	csr.updateUnregisteredNodes(notRegistered)
+	csr.handleInstanceCreationErrors(instances, currentTime)
 	csr.updateReadinessStats(currentTime)
 
 	// update acceptable ranges based on requests from last loop and targetSizes
@@ -518,8 +573,12 @@ func (csr *ClusterStateRegistry) updateReadinessStats(currentTime time.Time) {
 			klog.Warningf("Failed to get nodegroup for %s: %v", unregistered.Node.Name, errNg)
 			continue
 		}
+		if nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
+			klog.Warningf("No nodegroup for unregistered node %s", unregistered.Node.Name)
+			continue
+		}
 		perNgCopy := perNodeGroup[nodeGroup.Id()]
-		if unregistered.UnregisteredSince.Add(csr.config.MaxNodeProvisionTime).Before(currentTime) {
+		if unregistered.ErrorInfo != nil || unregistered.UnregisteredSince.Add(csr.maxNodeProvisionTime(nodeGroup)).Before(currentTime) {
 			perNgCopy.LongUnregistered += 1
 			total.LongUnregistered += 1
 		} else {
@@ -537,6 +596,13 @@ func (csr *ClusterStateRegistry) updateReadinessStats(currentTime time.Time) {
 	csr.totalReadiness = total
 }
 
+// maxNodeProvisionTime returns the time after which a node from the given node group is
+// considered long unregistered, taking into account per node group autoscaling options.
+func (csr *ClusterStateRegistry) maxNodeProvisionTime(nodeGroup cloudprovider.NodeGroup) time.Duration {
+	defaults := config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: csr.config.MaxNodeProvisionTime}
+	return cloudprovider.GetNodeGroupOptions(nodeGroup, defaults).MaxNodeProvisionTime
+}
+
 // Calculates which node groups have incorrect size.
 func (csr *ClusterStateRegistry) updateIncorrectNodeGroupSizes(currentTime time.Time) {
 	result := make(map[string]IncorrectNodeGroupSize)
@@ -578,6 +644,7 @@ func (csr *ClusterStateRegistry) updateUnregisteredNodes(unregisteredNodes []Unr
 	result := make(map[string]UnregisteredNode)
 	for _, unregistered := range unregisteredNodes {
 		if prev, found := csr.unregisteredNodes[unregistered.Node.Name]; found {
+			prev.ErrorInfo = unregistered.ErrorInfo
 			result[unregistered.Node.Name] = prev
 		} else {
 			result[unregistered.Node.Name] = unregistered
@@ -616,6 +683,34 @@ func (csr *ClusterStateRegistry) UpdateScaleDownCandidates(nodes []*apiv1.Node,
 	csr.lastScaleDownUpdateTime = now
 }
 
+// ScaleDownBlackout tells that scale down is not allowed until NextAllowed, because it's outside
+// of the configured scale down windows. Zero NextAllowed means that the next window is unknown.
+type ScaleDownBlackout struct {
+	NextAllowed time.Time
+}
+
+// UpdateScaleDownBlackouts updates the scale down blackouts of the whole cluster and of individual
+// node groups, keyed by node group id. Nil clusterwide means that scale down is allowed in the cluster.
+func (csr *ClusterStateRegistry) UpdateScaleDownBlackouts(clusterwide *ScaleDownBlackout, perNodeGroup map[string]ScaleDownBlackout) {
+	csr.clusterwideBlackout = clusterwide
+	csr.scaleDownBlackouts = perNodeGroup
+}
+
+// HeadroomStatus describes how much of the configured headroom is available.
+type HeadroomStatus struct {
+	// Pods is the number of synthetic pods the headroom is represented with.
+	Pods int
+	// Missing is the number of headroom pods that don't fit on existing nodes.
+	Missing int
+}
+
+// UpdateHeadroom updates the status of the cluster-wide headroom and of headroom of individual node
+// groups, keyed by node group id. Nil clusterwide means that no cluster-wide headroom is configured.
+func (csr *ClusterStateRegistry) UpdateHeadroom(clusterwide *HeadroomStatus, perNodeGroup map[string]HeadroomStatus) {
+	csr.clusterwideHeadroom = clusterwide
+	csr.headroom = perNodeGroup
+}
+
 // GetStatus returns ClusterAutoscalerStatus with the current cluster autoscaler status.
 func (csr *ClusterStateRegistry) GetStatus(now time.Time) *api.ClusterAutoscalerStatus {
 	result := &api.ClusterAutoscalerStatus{
@@ -623,12 +718,20 @@ func (csr *ClusterStateRegistry) GetStatus(now time.Time) *api.ClusterAutoscaler
 		NodeGroupStatuses:     make([]api.NodeGroupStatus, 0),
 	}
 	for _, nodeGroup := range csr.cloudProvider.NodeGroups() {
-		nodeGroupStatus := api.NodeGroupStatus{
-			ProviderID: nodeGroup.Id(),
-			Conditions: make([]api.ClusterAutoscalerCondition, 0),
-		}
 		readiness := csr.perNodeGroupReadiness[nodeGroup.Id()]
 		acceptable := csr.acceptableRanges[nodeGroup.Id()]
+		nodeGroupStatus := api.NodeGroupStatus{
+			ProviderID:          nodeGroup.Id(),
+			Conditions:          make([]api.ClusterAutoscalerCondition, 0),
+			MinSize:             nodeGroup.MinSize(),
+			MaxSize:             nodeGroup.MaxSize(),
+			TargetSize:          acceptable.CurrentTarget,
+			Nodes:               buildNodeCounts(readiness),
+			ScaleDownCandidates: csr.candidatesForScaleDown[nodeGroup.Id()],
+		}
+		if backoffUntil, backedOff := csr.nodeGroupBackoffInfo.BackoffUntil(nodeGroup, now); backedOff {
+			nodeGroupStatus.BackoffUntil = &metav1.Time{Time: backoffUntil}
+		}
 
 		// Health.
 		nodeGroupStatus.Conditions = append(nodeGroupStatus.Conditions, buildHealthStatusNodeGroup(
@@ -642,17 +745,31 @@ func (csr *ClusterStateRegistry) GetStatus(now time.Time) *api.ClusterAutoscaler
 			acceptable))
 
 		// Scale down.
+		blackout := csr.clusterwideBlackout
+		if ngBlackout, found := csr.scaleDownBlackouts[nodeGroup.Id()]; found && blackout == nil {
+			blackout = &ngBlackout
+		}
 		nodeGroupStatus.Conditions = append(nodeGroupStatus.Conditions, buildScaleDownStatusNodeGroup(
-			csr.candidatesForScaleDown[nodeGroup.Id()], csr.lastScaleDownUpdateTime))
+			csr.candidatesForScaleDown[nodeGroup.Id()], csr.lastScaleDownUpdateTime, blackout))
+
+		// Headroom.
+		if headroom, found := csr.headroom[nodeGroup.Id()]; found {
+			nodeGroupStatus.Conditions = append(nodeGroupStatus.Conditions, buildHeadroomStatus(headroom, now))
+		}
 
 		result.NodeGroupStatuses = append(result.NodeGroupStatuses, nodeGroupStatus)
 	}
+	result.Nodes = buildNodeCounts(csr.totalReadiness)
 	result.ClusterwideConditions = append(result.ClusterwideConditions,
 		buildHealthStatusClusterwide(csr.IsClusterHealthy(), csr.totalReadiness))
 	result.ClusterwideConditions = append(result.ClusterwideConditions,
 		buildScaleUpStatusClusterwide(result.NodeGroupStatuses, csr.totalReadiness))
 	result.ClusterwideConditions = append(result.ClusterwideConditions,
-		buildScaleDownStatusClusterwide(csr.candidatesForScaleDown, csr.lastScaleDownUpdateTime))
+		buildScaleDownStatusClusterwide(csr.candidatesForScaleDown, csr.lastScaleDownUpdateTime, csr.clusterwideBlackout))
+	if csr.clusterwideHeadroom != nil {
+		result.ClusterwideConditions = append(result.ClusterwideConditions,
+			buildHeadroomStatus(*csr.clusterwideHeadroom, now))
+	}
 
 	updateLastTransition(csr.lastStatus, result)
 	csr.lastStatus = result
@@ -664,6 +781,17 @@ func (csr *ClusterStateRegistry) GetClusterReadiness() Readiness {
 	return csr.totalReadiness
 }
 
+func buildNodeCounts(readiness Readiness) api.NodeCounts {
+	return api.NodeCounts{
+		Ready:            readiness.Ready,
+		Unready:          readiness.Unready,
+		NotStarted:       readiness.NotStarted,
+		LongNotStarted:   readiness.LongNotStarted,
+		Registered:       readiness.Registered,
+		LongUnregistered: readiness.LongUnregistered,
+	}
+}
+
 func buildHealthStatusNodeGroup(isReady bool, readiness Readiness, acceptable AcceptableRange, minSize, maxSize int) api.ClusterAutoscalerCondition {
 	condition := api.ClusterAutoscalerCondition{
 		Type: api.ClusterAutoscalerHealth,
@@ -705,13 +833,16 @@ func buildScaleUpStatusNodeGroup(isScaleUpInProgress bool, isSafeToScaleUp bool,
 	return condition
 }
 
-func buildScaleDownStatusNodeGroup(candidates []string, lastProbed time.Time) api.ClusterAutoscalerCondition {
+func buildScaleDownStatusNodeGroup(candidates []string, lastProbed time.Time, blackout *ScaleDownBlackout) api.ClusterAutoscalerCondition {
 	condition := api.ClusterAutoscalerCondition{
 		Type:          api.ClusterAutoscalerScaleDown,
 		Message:       fmt.Sprintf("candidates=%d", len(candidates)),
 		LastProbeTime: metav1.Time{Time: lastProbed},
 	}
-	if len(candidates) > 0 {
+	if blackout != nil {
+		condition.Status = api.ClusterAutoscalerScaleDownBlackout
+		condition.Message += " " + blackout.message()
+	} else if len(candidates) > 0 {
 		condition.Status = api.ClusterAutoscalerCandidatesPresent
 	} else {
 		condition.Status = api.ClusterAutoscalerNoCandidates
@@ -766,7 +897,7 @@ func buildScaleUpStatusClusterwide(nodeGroupStatuses []api.NodeGroupStatus, read
 	return condition
 }
 
-func buildScaleDownStatusClusterwide(candidates map[string][]string, lastProbed time.Time) api.ClusterAutoscalerCondition {
+func buildScaleDownStatusClusterwide(candidates map[string][]string, lastProbed time.Time, blackout *ScaleDownBlackout) api.ClusterAutoscalerCondition {
 	totalCandidates := 0
 	for _, val := range candidates {
 		totalCandidates += len(val)
@@ -776,7 +907,10 @@ func buildScaleDownStatusClusterwide(candidates map[string][]string, lastProbed
 		Message:       fmt.Sprintf("candidates=%d", totalCandidates),
 		LastProbeTime: metav1.Time{Time: lastProbed},
 	}
-	if totalCandidates > 0 {
+	if blackout != nil {
+		condition.Status = api.ClusterAutoscalerScaleDownBlackout
+		condition.Message += " " + blackout.message()
+	} else if totalCandidates > 0 {
 		condition.Status = api.ClusterAutoscalerCandidatesPresent
 	} else {
 		condition.Status = api.ClusterAutoscalerNoCandidates
@@ -784,6 +918,27 @@ func buildScaleDownStatusClusterwide(candidates map[string][]string, lastProbed
 	return condition
 }
 
+func buildHeadroomStatus(headroom HeadroomStatus, now time.Time) api.ClusterAutoscalerCondition {
+	condition := api.ClusterAutoscalerCondition{
+		Type:          api.ClusterAutoscalerHeadroom,
+		Message:       fmt.Sprintf("pods=%d missing=%d", headroom.Pods, headroom.Missing),
+		LastProbeTime: metav1.Time{Time: now},
+	}
+	if headroom.Missing > 0 {
+		condition.Status = api.ClusterAutoscalerHeadroomMissing
+	} else {
+		condition.Status = api.ClusterAutoscalerHeadroomAvailable
+	}
+	return condition
+}
+
+func (b *ScaleDownBlackout) message() string {
+	if b.NextAllowed.IsZero() {
+		return "nextAllowedWindow=unknown"
+	}
+	return fmt.Sprintf("nextAllowedWindow=%s", b.NextAllowed.Format(time.RFC3339))
+}
+
 func isNodeStillStarting(node *apiv1.Node) bool {
 	for _, condition := range node.Status.Conditions {
 		if condition.Type == apiv1.NodeReady &&
@@ -817,13 +972,8 @@ func updateLastTransition(oldStatus, newStatus *api.ClusterAutoscalerStatus) {
 				break
 			}
 		}
-		newConds := updateLastTransitionSingleList(oldConds, ngStatus.Conditions)
-		updatedNgStatuses = append(
-			updatedNgStatuses,
-			api.NodeGroupStatus{
-				ProviderID: ngStatus.ProviderID,
-				Conditions: newConds,
-			})
+		ngStatus.Conditions = updateLastTransitionSingleList(oldConds, ngStatus.Conditions)
+		updatedNgStatuses = append(updatedNgStatuses, ngStatus)
 	}
 	newStatus.NodeGroupStatuses = updatedNgStatuses
 }
@@ -877,35 +1027,48 @@ func (csr *ClusterStateRegistry) GetUpcomingNodes() map[string]int {
 	return result
 }
 
+// getCloudProviderInstances returns the instances of all node groups, keyed by node group id.
+func getCloudProviderInstances(cloudProvider cloudprovider.CloudProvider) (map[string][]cloudprovider.Instance, error) {
+	result := make(map[string][]cloudprovider.Instance)
+	for _, nodeGroup := range cloudProvider.NodeGroups() {
+		instances, err := nodeGroup.Nodes()
+		if err != nil {
+			return map[string][]cloudprovider.Instance{}, err
+		}
+		result[nodeGroup.Id()] = instances
+	}
+	return result, nil
+}
+
 // Calculates which of the existing cloud provider nodes are not registered in Kubernetes.
-func getNotRegisteredNodes(allNodes []*apiv1.Node, cloudProvider cloudprovider.CloudProvider, time time.Time) ([]UnregisteredNode, error) {
+func getNotRegisteredNodes(allNodes []*apiv1.Node, cloudProvider cloudprovider.CloudProvider, instances map[string][]cloudprovider.Instance, time time.Time) []UnregisteredNode {
 	registered := sets.NewString()
 	for _, node := range allNodes {
 		registered.Insert(cloudProvider.GetInstanceID(node))
 	}
 	notRegistered := make([]UnregisteredNode, 0)
-	for _, nodeGroup := range cloudProvider.NodeGroups() {
-		nodes, err := nodeGroup.Nodes()
-		if err != nil {
-			return []UnregisteredNode{}, err
-		}
-		for _, node := range nodes {
-			if !registered.Has(node.Id) {
-				notRegistered = append(notRegistered, UnregisteredNode{
+	for _, nodeGroupInstances := range instances {
+		for _, instance := range nodeGroupInstances {
+			if !registered.Has(instance.Id) {
+				unregistered := UnregisteredNode{
 					Node: &apiv1.Node{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: node.Id,
+							Name: instance.Id,
 						},
 						Spec: apiv1.NodeSpec{
-							ProviderID: node.Id,
+							ProviderID: instance.Id,
 						},
 					},
 					UnregisteredSince: time,
-				})
+				}
+				if isInstanceCreationFailed(instance) {
+					unregistered.ErrorInfo = instance.Status.ErrorInfo
+				}
+				notRegistered = append(notRegistered, unregistered)
 			}
 		}
 	}
-	return notRegistered, nil
+	return notRegistered
 }
 
 // GetClusterSize calculates and returns cluster's current size and target size. The current size is the
@@ -22,6 +22,7 @@ import (
 
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
@@ -230,6 +231,11 @@ func TestOKOneUnreadyNodeWithScaleDownCandidate(t *testing.T) {
 
 			assert.Equal(t, api.ClusterAutoscalerCandidatesPresent,
 				api.GetConditionByType(api.ClusterAutoscalerScaleDown, nodeStatus.Conditions).Status)
+			assert.Equal(t, []string{"ng1-1"}, nodeStatus.ScaleDownCandidates)
+			assert.Equal(t, api.NodeCounts{Ready: 1, Registered: 1}, nodeStatus.Nodes)
+			assert.Equal(t, 1, nodeStatus.MinSize)
+			assert.Equal(t, 10, nodeStatus.MaxSize)
+			assert.Equal(t, 1, nodeStatus.TargetSize)
 
 			ng1Checked = true
 		}
@@ -239,12 +245,97 @@ func TestOKOneUnreadyNodeWithScaleDownCandidate(t *testing.T) {
 
 			assert.Equal(t, api.ClusterAutoscalerNoCandidates,
 				api.GetConditionByType(api.ClusterAutoscalerScaleDown, nodeStatus.Conditions).Status)
+			assert.Empty(t, nodeStatus.ScaleDownCandidates)
+			assert.Equal(t, api.NodeCounts{Unready: 1, Registered: 1}, nodeStatus.Nodes)
 
 			ng2Checked = true
 		}
 	}
 	assert.True(t, ng1Checked)
 	assert.True(t, ng2Checked)
+	assert.Equal(t, api.NodeCounts{Ready: 1, Unready: 1, Registered: 2}, status.Nodes)
+}
+
+func TestScaleDownBlackout(t *testing.T) {
+	now := time.Now()
+
+	ng1_1 := BuildTestNode("ng1-1", 1000, 1000)
+	SetNodeReadyState(ng1_1, true, now.Add(-time.Minute))
+	ng2_1 := BuildTestNode("ng2-1", 1000, 1000)
+	SetNodeReadyState(ng2_1, true, now.Add(-time.Minute))
+
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+	provider.AddNode("ng1", ng1_1)
+	provider.AddNode("ng2", ng2_1)
+
+	fakeClient := &fake.Clientset{}
+	fakeLogRecorder, _ := utils.NewStatusMapRecorder(fakeClient, "kube-system", kube_record.NewFakeRecorder(5), false)
+	clusterstate := NewClusterStateRegistry(provider, ClusterStateRegistryConfig{
+		MaxTotalUnreadyPercentage: 10,
+		OkTotalUnreadyCount:       1,
+	}, fakeLogRecorder, newBackoff())
+	err := clusterstate.UpdateNodes([]*apiv1.Node{ng1_1, ng2_1}, nil, now)
+	assert.NoError(t, err)
+	clusterstate.UpdateScaleDownCandidates([]*apiv1.Node{ng1_1, ng2_1}, now)
+
+	nextAllowed := time.Date(2019, time.January, 7, 22, 0, 0, 0, time.UTC)
+	clusterstate.UpdateScaleDownBlackouts(nil, map[string]ScaleDownBlackout{"ng1": {NextAllowed: nextAllowed}})
+
+	status := clusterstate.GetStatus(now)
+	assert.Equal(t, api.ClusterAutoscalerCandidatesPresent,
+		api.GetConditionByType(api.ClusterAutoscalerScaleDown, status.ClusterwideConditions).Status)
+	for _, nodeStatus := range status.NodeGroupStatuses {
+		condition := api.GetConditionByType(api.ClusterAutoscalerScaleDown, nodeStatus.Conditions)
+		if nodeStatus.ProviderID == "ng1" {
+			assert.Equal(t, api.ClusterAutoscalerScaleDownBlackout, condition.Status)
+			assert.Equal(t, "candidates=1 nextAllowedWindow=2019-01-07T22:00:00Z", condition.Message)
+		} else {
+			assert.Equal(t, api.ClusterAutoscalerCandidatesPresent, condition.Status)
+		}
+	}
+
+	// Clusterwide blackout applies to all node groups.
+	clusterstate.UpdateScaleDownBlackouts(&ScaleDownBlackout{}, nil)
+	status = clusterstate.GetStatus(now)
+	condition := api.GetConditionByType(api.ClusterAutoscalerScaleDown, status.ClusterwideConditions)
+	assert.Equal(t, api.ClusterAutoscalerScaleDownBlackout, condition.Status)
+	assert.Equal(t, "candidates=2 nextAllowedWindow=unknown", condition.Message)
+	for _, nodeStatus := range status.NodeGroupStatuses {
+		assert.Equal(t, api.ClusterAutoscalerScaleDownBlackout,
+			api.GetConditionByType(api.ClusterAutoscalerScaleDown, nodeStatus.Conditions).Status)
+	}
+}
+
+func TestHeadroomStatus(t *testing.T) {
+	now := time.Now()
+
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+
+	fakeClient := &fake.Clientset{}
+	fakeLogRecorder, _ := utils.NewStatusMapRecorder(fakeClient, "kube-system", kube_record.NewFakeRecorder(5), false)
+	clusterstate := NewClusterStateRegistry(provider, ClusterStateRegistryConfig{}, fakeLogRecorder, newBackoff())
+
+	status := clusterstate.GetStatus(now)
+	assert.Nil(t, api.GetConditionByType(api.ClusterAutoscalerHeadroom, status.ClusterwideConditions))
+
+	clusterstate.UpdateHeadroom(&HeadroomStatus{Pods: 2}, map[string]HeadroomStatus{"ng1": {Pods: 3, Missing: 1}})
+	status = clusterstate.GetStatus(now)
+	condition := api.GetConditionByType(api.ClusterAutoscalerHeadroom, status.ClusterwideConditions)
+	assert.Equal(t, api.ClusterAutoscalerHeadroomAvailable, condition.Status)
+	assert.Equal(t, "pods=2 missing=0", condition.Message)
+	for _, nodeStatus := range status.NodeGroupStatuses {
+		condition := api.GetConditionByType(api.ClusterAutoscalerHeadroom, nodeStatus.Conditions)
+		if nodeStatus.ProviderID == "ng1" {
+			assert.Equal(t, api.ClusterAutoscalerHeadroomMissing, condition.Status)
+			assert.Equal(t, "pods=3 missing=1", condition.Message)
+		} else {
+			assert.Nil(t, condition)
+		}
+	}
 }
 
 func TestMissingNodes(t *testing.T) {
@@ -634,12 +725,17 @@ func TestScaleUpBackoff(t *testing.T) {
 	assert.True(t, clusterstate.IsClusterHealthy())
 	assert.True(t, clusterstate.IsNodeGroupHealthy("ng1"))
 	assert.False(t, clusterstate.IsNodeGroupSafeToScaleUp(ng1, now))
+	status := clusterstate.GetStatus(now)
+	assert.Equal(t, 1, len(status.NodeGroupStatuses))
+	assert.NotNil(t, status.NodeGroupStatuses[0].BackoffUntil)
+	assert.Equal(t, now.Add(InitialNodeGroupBackoffDuration), status.NodeGroupStatuses[0].BackoffUntil.Time)
 
 	// Backoff should expire after timeout
 	now = now.Add(InitialNodeGroupBackoffDuration).Add(time.Second)
 	assert.True(t, clusterstate.IsClusterHealthy())
 	assert.True(t, clusterstate.IsNodeGroupHealthy("ng1"))
 	assert.True(t, clusterstate.IsNodeGroupSafeToScaleUp(ng1, now))
+	assert.Nil(t, clusterstate.GetStatus(now).NodeGroupStatuses[0].BackoffUntil)
 
 	// Another failed scale up should cause longer backoff
 	clusterstate.RegisterScaleUp(&ScaleUpRequest{
@@ -675,6 +771,60 @@ func TestScaleUpBackoff(t *testing.T) {
 	assert.False(t, clusterstate.nodeGroupBackoffInfo.IsBackedOff(ng1, now))
 }
 
+func TestInstanceCreationErrors(t *testing.T) {
+	now := time.Now()
+
+	ng1_1 := BuildTestNode("ng1-1", 1000, 1000)
+	SetNodeReadyState(ng1_1, true, now.Add(-time.Minute))
+	ng1_2 := BuildTestNode("ng1-2", 1000, 1000)
+
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 2)
+	ng1 := provider.GetNodeGroup("ng1")
+	provider.AddNode("ng1", ng1_1)
+	provider.AddNode("ng1", ng1_2)
+	provider.SetInstanceStatus("ng1-2", &cloudprovider.InstanceStatus{
+		State: cloudprovider.STATE_BEING_CREATED,
+		ErrorInfo: &cloudprovider.InstanceErrorInfo{
+			ErrorClass:   cloudprovider.ERROR_OUT_OF_RESOURCES,
+			ErrorCode:    "InsufficientResources",
+			ErrorMessage: "quota exceeded",
+		},
+	})
+
+	fakeClient := &fake.Clientset{}
+	fakeLogRecorder, _ := utils.NewStatusMapRecorder(fakeClient, "kube-system", kube_record.NewFakeRecorder(5), false)
+	clusterstate := NewClusterStateRegistry(provider, ClusterStateRegistryConfig{
+		MaxTotalUnreadyPercentage: 10,
+		OkTotalUnreadyCount:       1,
+		MaxNodeProvisionTime:      15 * time.Minute,
+	}, fakeLogRecorder, newBackoff())
+
+	clusterstate.RegisterScaleUp(&ScaleUpRequest{
+		NodeGroup:       ng1,
+		Increase:        1,
+		Time:            now,
+		ExpectedAddTime: now.Add(15 * time.Minute),
+	})
+	err := clusterstate.UpdateNodes([]*apiv1.Node{ng1_1}, nil, now)
+	assert.NoError(t, err)
+
+	// The failed instance should not wait for MaxNodeProvisionTime.
+	unregistered := clusterstate.GetUnregisteredNodes()
+	assert.Equal(t, 1, len(unregistered))
+	assert.NotNil(t, unregistered[0].ErrorInfo)
+	assert.Equal(t, 1, clusterstate.GetClusterReadiness().LongUnregistered)
+	assert.Equal(t, 0, clusterstate.GetUpcomingNodes()["ng1"])
+	assert.False(t, clusterstate.IsNodeGroupSafeToScaleUp(ng1, now))
+	assert.Nil(t, clusterstate.scaleUpRequests["ng1"])
+
+	// The same failure should not extend the backoff again.
+	now = now.Add(InitialNodeGroupBackoffDuration).Add(time.Second)
+	err = clusterstate.UpdateNodes([]*apiv1.Node{ng1_1}, nil, now)
+	assert.NoError(t, err)
+	assert.True(t, clusterstate.IsNodeGroupSafeToScaleUp(ng1, now))
+}
+
 func TestGetClusterSize(t *testing.T) {
 	now := time.Now()
 
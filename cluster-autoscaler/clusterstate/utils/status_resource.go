@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api/v1alpha1"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/klog"
+)
+
+// StatusResourceName is the name of the ClusterAutoscalerStatus object with status.
+const StatusResourceName = "cluster-autoscaler-status"
+
+// WriteStatusResource writes the status to the ClusterAutoscalerStatus custom resource, creating
+// the object if it doesn't exist. The ClusterAutoscalerStatus custom resource definition has to be
+// installed in the cluster.
+func WriteStatusResource(client dynamic.Interface, namespace string, status *api.ClusterAutoscalerStatus, now time.Time) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(v1alpha1.FromInternal(StatusResourceName, namespace, status, now))
+	if err != nil {
+		return fmt.Errorf("failed to convert status resource: %v", err)
+	}
+	statusObj := &unstructured.Unstructured{Object: content}
+	resources := client.Resource(v1alpha1.SchemeGroupVersionResource).Namespace(namespace)
+
+	existing, err := resources.Get(StatusResourceName, metav1.GetOptions{})
+	if err == nil {
+		statusObj.SetResourceVersion(existing.GetResourceVersion())
+		_, err = resources.Update(statusObj, metav1.UpdateOptions{})
+	} else if kube_errors.IsNotFound(err) {
+		_, err = resources.Create(statusObj, metav1.CreateOptions{})
+	} else {
+		return fmt.Errorf("failed to retrieve status resource for update: %v", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write status resource: %v", err)
+	}
+	klog.V(8).Infof("Successfully wrote status resource %s/%s", namespace, StatusResourceName)
+	return nil
+}
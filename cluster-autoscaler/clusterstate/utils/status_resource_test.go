@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api/v1alpha1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStatusResources implements the parts of the dynamic client used to write the status resource.
+type fakeStatusResources struct {
+	dynamic.NamespaceableResourceInterface
+	t         *testing.T
+	namespace string
+	existing  *unstructured.Unstructured
+	getError  error
+	written   *unstructured.Unstructured
+	created   bool
+	updated   bool
+}
+
+func (f *fakeStatusResources) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	assert.Equal(f.t, v1alpha1.SchemeGroupVersionResource, resource)
+	return f
+}
+
+func (f *fakeStatusResources) Namespace(namespace string) dynamic.ResourceInterface {
+	f.namespace = namespace
+	return f
+}
+
+func (f *fakeStatusResources) Get(name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	assert.Equal(f.t, StatusResourceName, name)
+	if f.getError != nil {
+		return nil, f.getError
+	}
+	if f.existing == nil {
+		return nil, kube_errors.NewNotFound(v1alpha1.SchemeGroupVersionResource.GroupResource(), name)
+	}
+	return f.existing, nil
+}
+
+func (f *fakeStatusResources) Create(obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	f.created = true
+	f.written = obj
+	return obj, nil
+}
+
+func (f *fakeStatusResources) Update(obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	f.updated = true
+	f.written = obj
+	return obj, nil
+}
+
+func (f *fakeStatusResources) writtenStatus() *v1alpha1.ClusterAutoscalerStatus {
+	result := &v1alpha1.ClusterAutoscalerStatus{}
+	assert.NoError(f.t, runtime.DefaultUnstructuredConverter.FromUnstructured(f.written.Object, result))
+	return result
+}
+
+func testStatus(now time.Time) *api.ClusterAutoscalerStatus {
+	backoffUntil := metav1.NewTime(now.Add(5 * time.Minute))
+	return &api.ClusterAutoscalerStatus{
+		ClusterwideConditions: []api.ClusterAutoscalerCondition{{
+			Type:               api.ClusterAutoscalerHealth,
+			Status:             api.ClusterAutoscalerHealthy,
+			LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+		}},
+		Nodes: api.NodeCounts{Ready: 3, Registered: 3},
+		NodeGroupStatuses: []api.NodeGroupStatus{{
+			ProviderID: "ng1",
+			Conditions: []api.ClusterAutoscalerCondition{{
+				Type:   api.ClusterAutoscalerScaleUp,
+				Status: api.ClusterAutoscalerBackoff,
+			}},
+			MinSize:             1,
+			MaxSize:             10,
+			TargetSize:          3,
+			Nodes:               api.NodeCounts{Ready: 3, Registered: 3},
+			ScaleDownCandidates: []string{"n1"},
+			BackoffUntil:        &backoffUntil,
+		}},
+	}
+}
+
+func TestWriteStatusResourceCreate(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := &fakeStatusResources{t: t}
+	err := WriteStatusResource(client, "kube-system", testStatus(now), now)
+	assert.NoError(t, err)
+	assert.True(t, client.created)
+	assert.False(t, client.updated)
+	assert.Equal(t, "kube-system", client.namespace)
+
+	written := client.writtenStatus()
+	assert.Equal(t, v1alpha1.SchemeGroupVersion.String(), written.APIVersion)
+	assert.Equal(t, v1alpha1.Kind, written.Kind)
+	assert.Equal(t, StatusResourceName, written.Name)
+	assert.Equal(t, "kube-system", written.Namespace)
+	assert.True(t, now.Equal(written.Status.LastUpdateTime.Time))
+	assert.Equal(t, 1, len(written.Status.ClusterwideConditions))
+	assert.Equal(t, "Health", written.Status.ClusterwideConditions[0].Type)
+	assert.Equal(t, "Healthy", written.Status.ClusterwideConditions[0].Status)
+	assert.True(t, now.Add(-time.Hour).Equal(written.Status.ClusterwideConditions[0].LastTransitionTime.Time))
+	assert.Equal(t, 3, written.Status.Nodes.Ready)
+	assert.Equal(t, 1, len(written.Status.NodeGroups))
+	nodeGroup := written.Status.NodeGroups[0]
+	assert.Equal(t, "ng1", nodeGroup.Name)
+	assert.Equal(t, 1, nodeGroup.MinSize)
+	assert.Equal(t, 10, nodeGroup.MaxSize)
+	assert.Equal(t, 3, nodeGroup.TargetSize)
+	assert.Equal(t, "Backoff", nodeGroup.Conditions[0].Status)
+	assert.Equal(t, []string{"n1"}, nodeGroup.ScaleDownCandidates)
+	assert.True(t, now.Add(5*time.Minute).Equal(nodeGroup.BackoffUntil.Time))
+}
+
+func TestWriteStatusResourceUpdate(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	existing := &unstructured.Unstructured{}
+	existing.SetResourceVersion("42")
+	client := &fakeStatusResources{t: t, existing: existing}
+	err := WriteStatusResource(client, "kube-system", testStatus(now), now)
+	assert.NoError(t, err)
+	assert.False(t, client.created)
+	assert.True(t, client.updated)
+	assert.Equal(t, "42", client.writtenStatus().ResourceVersion)
+}
+
+func TestWriteStatusResourceGetError(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := &fakeStatusResources{t: t, getError: errors.New("nope")}
+	err := WriteStatusResource(client, "kube-system", testStatus(now), now)
+	assert.Error(t, err)
+	assert.False(t, client.created)
+	assert.False(t, client.updated)
+}
@@ -18,6 +18,8 @@ package config
 
 import (
 	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 )
 
 // GpuLimits define lower and upper bound on GPU instances of given type in cluster
@@ -30,10 +32,53 @@ type GpuLimits struct {
 	Max int64
 }
 
+// Headroom describes spare capacity that is kept available in the cluster for pods that don't exist yet.
+type Headroom struct {
+	// MilliCPU is the amount of spare CPU, in millicores.
+	MilliCPU int64
+	// Memory is the amount of spare memory, in bytes.
+	Memory int64
+	// Nodes is the number of spare nodes. A spare node has the capacity of the node group's template node,
+	// so it can only be set for node groups.
+	Nodes int
+}
+
+// IsZero returns true if no headroom is requested.
+func (h Headroom) IsZero() bool {
+	return h.MilliCPU <= 0 && h.Memory <= 0 && h.Nodes <= 0
+}
+
+// NodeGroupAutoscalingOptions contain various options that can be overridden for a single node group.
+// Global flags from AutoscalingOptions are used for node groups that don't specify their own values.
+type NodeGroupAutoscalingOptions struct {
+	// ScaleDownUtilizationThreshold sets threshold for nodes to be considered for scale down.
+	ScaleDownUtilizationThreshold float64
+	// ScaleDownUnneededTime sets the duration CA expects a node to be unneeded/eligible for removal
+	// before scaling down the node.
+	ScaleDownUnneededTime time.Duration
+	// ScaleDownUnreadyTime represents how long an unready node should be unneeded before it is eligible for scale down
+	ScaleDownUnreadyTime time.Duration
+	// MaxNodeProvisionTime is the maximum time CA waits for node to be provisioned
+	MaxNodeProvisionTime time.Duration
+	// ScaleDownAllowedWindows is a schedule of windows in which nodes from the node group can be scaled down,
+	// on top of the cluster-wide schedule. Empty means no additional restrictions, so it is not set by
+	// AutoscalingOptions.NodeGroupDefaults.
+	ScaleDownAllowedWindows string
+	// ScaleDownAllowedSchedule is ScaleDownAllowedWindows parsed by the cloud provider when the options
+	// are read, so that the windows aren't parsed again in every loop. It is not recorded.
+	ScaleDownAllowedSchedule *schedule.Schedule `json:"-"`
+	// Headroom is spare capacity kept in the node group, on top of the cluster-wide headroom. It is not set
+	// by AutoscalingOptions.NodeGroupDefaults.
+	Headroom Headroom
+}
+
 // AutoscalingOptions contain various options to customize how autoscaling works
 type AutoscalingOptions struct {
 	// MaxEmptyBulkDelete is a number of empty nodes that can be removed at the same time.
 	MaxEmptyBulkDelete int
+	// MaxBulkSoftTaintCount is the maximum number of unneeded nodes that are tainted with PreferNoSchedule
+	// in one loop. Zero disables soft tainting.
+	MaxBulkSoftTaintCount int
 	// ScaleDownUtilizationThreshold sets threshold for nodes to be considered for scale down.
 	// Well-utilized nodes are not touched.
 	ScaleDownUtilizationThreshold float64
@@ -60,6 +105,13 @@ type AutoscalingOptions struct {
 	EstimatorName string
 	// ExpanderName sets the type of node group expander to be used in scale up
 	ExpanderName string
+	// GRPCExpanderURL is the url of the gRPC expander server, used by the grpc expander
+	GRPCExpanderURL string
+	// GRPCExpanderCert is the path to the CA certificate verifying the gRPC expander server. The
+	// connection doesn't use TLS if empty.
+	GRPCExpanderCert string
+	// GRPCExpanderTimeout is the timeout of requests to the gRPC expander server
+	GRPCExpanderTimeout time.Duration
 	// IgnoreDaemonSetsUtilization is whether CA will ignore DaemonSet pods when calculating resource utilization for scaling down
 	IgnoreDaemonSetsUtilization bool
 	// IgnoreMirrorPodsUtilization is whether CA will ignore Mirror pods when calculating resource utilization for scaling down
@@ -67,6 +119,9 @@ type AutoscalingOptions struct {
 	// MaxGracefulTerminationSec is maximum number of seconds scale down waits for pods to terminate before
 	// removing the node from cloud provider.
 	MaxGracefulTerminationSec int
+	// ReplacementPodsTimeout is the maximum time scale down waits after draining a node for the evicted pods'
+	// replacements to be running elsewhere before removing the node from cloud provider. Zero disables waiting.
+	ReplacementPodsTimeout time.Duration
 	//  Maximum time CA waits for node to be provisioned
 	MaxNodeProvisionTime time.Duration
 	// MaxTotalUnreadyPercentage is the maximum percentage of unready nodes after which CA halts operations
@@ -75,18 +130,29 @@ type AutoscalingOptions struct {
 	OkTotalUnreadyCount int
 	// CloudConfig is the path to the cloud provider configuration file. Empty string for no configuration file.
 	CloudConfig string
+	// InstancePricesFile is the path to a file with instance prices overriding prices bundled with the cloud provider.
+	// Empty string to use bundled prices only.
+	InstancePricesFile string
 	// CloudProviderName sets the type of the cloud provider CA is about to run in. Allowed values: gce, aws
 	CloudProviderName string
 	// NodeGroups is the list of node groups a.k.a autoscaling targets
 	NodeGroups []string
 	// ScaleDownEnabled is used to allow CA to scale down the cluster
 	ScaleDownEnabled bool
+	// ScaleDownAllowedWindows is a schedule of windows in which scale down is allowed in the whole cluster.
+	// Empty means scale down is always allowed. See utils/schedule for the format.
+	ScaleDownAllowedWindows string
+	// ScaleDownAllowedSchedule is ScaleDownAllowedWindows parsed once at startup. Nil means scale down is
+	// always allowed. It is not recorded.
+	ScaleDownAllowedSchedule *schedule.Schedule `json:"-"`
 	// ScaleDownDelayAfterAdd sets the duration from the last scale up to the time when CA starts to check scale down options
 	ScaleDownDelayAfterAdd time.Duration
 	// ScaleDownDelayAfterDelete sets the duration between scale down attempts if scale down removes one or more nodes
 	ScaleDownDelayAfterDelete time.Duration
 	// ScaleDownDelayAfterFailure sets the duration before the next scale down attempt if scale down results in an error
 	ScaleDownDelayAfterFailure time.Duration
+	// MaxDrainParallelism is the maximum number of non empty nodes that can be drained and deleted at the same time.
+	MaxDrainParallelism int
 	// ScaleDownNonEmptyCandidatesCount is the maximum number of non empty nodes
 	// considered at once as candidates for scale down.
 	ScaleDownNonEmptyCandidatesCount int
@@ -100,10 +166,18 @@ type AutoscalingOptions struct {
 	// The formula to calculate additional candidates number is following:
 	// max(#nodes * ScaleDownCandidatesPoolRatio, ScaleDownCandidatesPoolMinCount)
 	ScaleDownCandidatesPoolMinCount int
+	// ScaleDownMaxDisruptionCost is the maximum sum of disruption costs of the pods that have to be
+	// moved for a node to be scaled down. Zero means no limit.
+	ScaleDownMaxDisruptionCost float64
 	// WriteStatusConfigMap tells if the status information should be written to a ConfigMap
 	WriteStatusConfigMap bool
+	// WriteStatusResource tells if the status information should be written to the ClusterAutoscalerStatus custom resource
+	WriteStatusResource bool
 	// BalanceSimilarNodeGroups enables logic that identifies node groups with similar machines and tries to balance node count between them.
 	BalanceSimilarNodeGroups bool
+	// ScaleDownBalanceMaxSkew is the maximum difference in node count between similar node groups
+	// that scale-down may cause when BalanceSimilarNodeGroups is enabled.
+	ScaleDownBalanceMaxSkew int
 	// ConfigNamespace is the namespace cluster-autoscaler is running in and all related configmaps live in
 	ConfigNamespace string
 	// ClusterName if available
@@ -117,10 +191,31 @@ type AutoscalingOptions struct {
 	// Pods with priority below cutoff are expendable. They can be killed without any consideration during scale down and they don't cause scale-up.
 	// Pods with null priority (PodPriority disabled) are non-expendable.
 	ExpendablePodsPriorityCutoff int
+	// Headroom is spare capacity kept in the whole cluster. Only MilliCPU and Memory are used.
+	Headroom Headroom
 	// Regional tells whether the cluster is regional.
 	Regional bool
 	// Pods newer than this will not be considered as unschedulable for scale-up.
 	NewPodScaleUpDelay time.Duration
 	// Path to kube configuration if available
 	KubeConfigPath string
+	// DryRun tells CA to compute scale-up and scale-down plans without executing them. Planned actions are
+	// still reported via status processors, events and metrics, marked as simulated.
+	DryRun bool
+	// RecordLoopsDir is a directory to which inputs of every loop are written, so that they can be
+	// replayed offline. Loops are not recorded if empty.
+	RecordLoopsDir string
+	// RecordLoopsMaxFiles is the maximum number of loop recordings kept in RecordLoopsDir. Older
+	// recordings are removed. Zero means no limit.
+	RecordLoopsMaxFiles int
+}
+
+// NodeGroupDefaults returns the node group options set by the global flags.
+func (o AutoscalingOptions) NodeGroupDefaults() NodeGroupAutoscalingOptions {
+	return NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: o.ScaleDownUtilizationThreshold,
+		ScaleDownUnneededTime:         o.ScaleDownUnneededTime,
+		ScaleDownUnreadyTime:          o.ScaleDownUnreadyTime,
+		MaxNodeProvisionTime:          o.MaxNodeProvisionTime,
+	}
 }
@@ -41,10 +41,13 @@ type AutoscalerOptions struct {
 	AutoscalingKubeClients *context.AutoscalingKubeClients
 	CloudProvider          cloudprovider.CloudProvider
 	PredicateChecker       *simulator.PredicateChecker
+	ClusterSnapshot        simulator.ClusterSnapshot
 	ExpanderStrategy       expander.Strategy
 	EstimatorBuilder       estimator.EstimatorBuilder
 	Processors             *ca_processors.AutoscalingProcessors
 	Backoff                backoff.Backoff
+	// StopChannel is closed by the caller on shutdown to stop informers started for the autoscaler.
+	StopChannel <-chan struct{}
 }
 
 // Autoscaler is the main component of CA which scales up/down node groups according to its configuration
@@ -65,6 +68,7 @@ func NewAutoscaler(opts AutoscalerOptions) (Autoscaler, errors.AutoscalerError)
 	return NewStaticAutoscaler(
 		opts.AutoscalingOptions,
 		opts.PredicateChecker,
+		opts.ClusterSnapshot,
 		opts.AutoscalingKubeClients,
 		opts.Processors, opts.CloudProvider,
 		opts.ExpanderStrategy,
@@ -88,12 +92,15 @@ func initializeDefaultOptions(opts *AutoscalerOptions) error {
 		}
 		opts.PredicateChecker = predicateChecker
 	}
+	if opts.ClusterSnapshot == nil {
+		opts.ClusterSnapshot = simulator.NewDeltaClusterSnapshot()
+	}
 	if opts.CloudProvider == nil {
 		opts.CloudProvider = cloudBuilder.NewCloudProvider(opts.AutoscalingOptions)
 	}
 	if opts.ExpanderStrategy == nil {
 		expanderStrategy, err := factory.ExpanderStrategyFromString(opts.ExpanderName,
-			opts.CloudProvider, opts.AutoscalingKubeClients.AllNodeLister())
+			opts.CloudProvider, opts.AutoscalingKubeClients.AllNodeLister(), opts.KubeClient, opts.AutoscalingOptions, opts.StopChannel)
 		if err != nil {
 			return err
 		}
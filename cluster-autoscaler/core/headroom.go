@@ -0,0 +1,323 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"k8s.io/klog"
+)
+
+const (
+	// clusterwideHeadroomName is used in names of pods representing cluster-wide headroom.
+	clusterwideHeadroomName = "cluster"
+)
+
+// headroomPods holds synthetic pods that represent the configured headroom.
+type headroomPods struct {
+	// clusterwide are pods for the cluster-wide headroom. They can run on any node.
+	clusterwide []*apiv1.Pod
+	// perNodeGroup are pods for node group headroom, keyed by node group id. They can only run on
+	// nodes from the given node group.
+	perNodeGroup map[string][]*apiv1.Pod
+}
+
+// buildHeadroomPods creates synthetic pods for the cluster-wide headroom and for headroom configured
+// for node groups. Pods are sized so that each of them fits on an empty template node.
+func buildHeadroomPods(context *context.AutoscalingContext, nodeInfosForGroups map[string]*schedulercache.NodeInfo) headroomPods {
+	result := headroomPods{perNodeGroup: make(map[string][]*apiv1.Pod)}
+	var smallestFree *schedulercache.Resource
+
+	for _, nodeGroup := range context.CloudProvider.NodeGroups() {
+		nodeInfo, found := nodeInfosForGroups[nodeGroup.Id()]
+		if !found {
+			continue
+		}
+		free := freeTemplateResources(nodeInfo)
+		if free.MilliCPU <= 0 || free.Memory <= 0 {
+			klog.V(4).Infof("Template node for %s has no free resources, ignoring it for headroom", nodeGroup.Id())
+			continue
+		}
+		if smallestFree == nil {
+			smallestFree = free.Clone()
+		}
+		smallestFree.MilliCPU = min64(smallestFree.MilliCPU, free.MilliCPU)
+		smallestFree.Memory = min64(smallestFree.Memory, free.Memory)
+
+		headroom := cloudprovider.GetNodeGroupOptions(nodeGroup, context.NodeGroupDefaults()).Headroom
+		if headroom.IsZero() {
+			continue
+		}
+		tolerations, err := tolerationsForTemplate(nodeInfo)
+		if err != nil {
+			klog.Warningf("Failed to get taints of template node for %s, ignoring its headroom: %v", nodeGroup.Id(), err)
+			continue
+		}
+		pods := splitHeadroom(nodeGroup.Id(), headroom, free, tolerations)
+		for i := 0; i < headroom.Nodes; i++ {
+			pods = append(pods, buildHeadroomPod(nodeGroup.Id(), len(pods), free.MilliCPU, free.Memory, tolerations))
+		}
+		result.perNodeGroup[nodeGroup.Id()] = pods
+	}
+
+	if clusterwide := context.Headroom; clusterwide.MilliCPU > 0 || clusterwide.Memory > 0 {
+		if smallestFree == nil {
+			klog.Warningf("No template node with free resources found, cluster-wide headroom can't be computed")
+		} else {
+			result.clusterwide = splitHeadroom("", clusterwide, smallestFree, nil)
+		}
+	}
+	return result
+}
+
+// splitHeadroom represents CPU and memory headroom with the smallest number of equal pods, each of
+// them not bigger than the given free resources.
+func splitHeadroom(nodeGroupId string, headroom config.Headroom, free *schedulercache.Resource, tolerations []apiv1.Toleration) []*apiv1.Pod {
+	count := max64(divideRoundingUp(headroom.MilliCPU, free.MilliCPU), divideRoundingUp(headroom.Memory, free.Memory))
+	pods := make([]*apiv1.Pod, 0, count)
+	for i := int64(0); i < count; i++ {
+		pods = append(pods, buildHeadroomPod(nodeGroupId, len(pods),
+			divideRoundingUp(headroom.MilliCPU, count), divideRoundingUp(headroom.Memory, count), tolerations))
+	}
+	return pods
+}
+
+func buildHeadroomPod(nodeGroupId string, index int, milliCPU int64, memory int64, tolerations []apiv1.Toleration) *apiv1.Pod {
+	owner := nodeGroupId
+	if owner == "" {
+		owner = clusterwideHeadroomName
+	}
+	name := fmt.Sprintf("headroom-%s-%d", owner, index)
+	requests := apiv1.ResourceList{}
+	if milliCPU > 0 {
+		requests[apiv1.ResourceCPU] = *resource.NewMilliQuantity(milliCPU, resource.DecimalSI)
+	}
+	if memory > 0 {
+		requests[apiv1.ResourceMemory] = *resource.NewQuantity(memory, resource.BinarySI)
+	}
+	return &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			UID:       types.UID(name),
+			Annotations: map[string]string{
+				drain.HeadroomPodKey: nodeGroupId,
+				// Headroom pods can always be moved when simulating scale down.
+				drain.PodSafeToEvictKey: "true",
+			},
+		},
+		Spec: apiv1.PodSpec{
+			Containers: []apiv1.Container{{
+				Name:      "headroom",
+				Resources: apiv1.ResourceRequirements{Requests: requests},
+			}},
+			Tolerations: tolerations,
+		},
+	}
+}
+
+// freeTemplateResources returns resources of a template node that are left after running its pods,
+// e.g. daemon sets.
+func freeTemplateResources(nodeInfo *schedulercache.NodeInfo) *schedulercache.Resource {
+	allocatable := nodeInfo.AllocatableResource()
+	requested := nodeInfo.RequestedResource()
+	return &schedulercache.Resource{
+		MilliCPU: allocatable.MilliCPU - requested.MilliCPU,
+		Memory:   allocatable.Memory - requested.Memory,
+	}
+}
+
+// tolerationsForTemplate returns tolerations needed to run a pod on the given template node.
+func tolerationsForTemplate(nodeInfo *schedulercache.NodeInfo) ([]apiv1.Toleration, error) {
+	taints, err := nodeInfo.Taints()
+	if err != nil {
+		return nil, err
+	}
+	tolerations := make([]apiv1.Toleration, 0, len(taints))
+	for _, taint := range taints {
+		tolerations = append(tolerations, apiv1.Toleration{
+			Key:      taint.Key,
+			Operator: apiv1.TolerationOpEqual,
+			Value:    taint.Value,
+			Effect:   taint.Effect,
+		})
+	}
+	return tolerations, nil
+}
+
+// placeHeadroomPods simulates running headroom pods on existing nodes next to pods already in the cluster snapshot.
+// Returns copies of pods that fit, with NodeName set and added to the snapshot, and pods that don't fit anywhere. Placed copies
+// of node group headroom are pinned to the nodes of their node group, so that scale down simulation
+// doesn't move them to other node groups.
+func placeHeadroomPods(context *context.AutoscalingContext, headroom headroomPods, nodes []*apiv1.Node) (placed []*apiv1.Pod, missing []*apiv1.Pod) {
+	nodeGroupIds := make(map[string]string, len(nodes))
+	nodeGroupNodeNames := make(map[string][]string)
+	for _, node := range nodes {
+		nodeGroup, err := context.CloudProvider.NodeGroupForNode(node)
+		if err != nil || nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
+			continue
+		}
+		nodeGroupIds[node.Name] = nodeGroup.Id()
+		nodeGroupNodeNames[nodeGroup.Id()] = append(nodeGroupNodeNames[nodeGroup.Id()], node.Name)
+	}
+
+	place := func(pod *apiv1.Pod, nodeGroupId string) {
+		for _, node := range nodes {
+			if nodeGroupId != "" && nodeGroupIds[node.Name] != nodeGroupId {
+				continue
+			}
+			nodeInfo, found := context.ClusterSnapshot.GetNodeInfo(node.Name)
+			if !found {
+				continue
+			}
+			if err := context.PredicateChecker.CheckPredicates(pod, nil, nodeInfo); err == nil {
+				podCopy := pod.DeepCopy()
+				podCopy.Spec.NodeName = node.Name
+				if nodeGroupId != "" {
+					podCopy.Spec.Affinity = nodeNamesAffinity(nodeGroupNodeNames[nodeGroupId])
+				}
+				if err := context.ClusterSnapshot.AddPod(podCopy, node.Name); err != nil {
+					klog.Errorf("Failed to add headroom pod %s to cluster snapshot: %v", podCopy.Name, err)
+					break
+				}
+				placed = append(placed, podCopy)
+				return
+			}
+		}
+		missing = append(missing, pod)
+	}
+
+	// Node group headroom is more constrained, so it's placed first.
+	for _, nodeGroup := range context.CloudProvider.NodeGroups() {
+		for _, pod := range headroom.perNodeGroup[nodeGroup.Id()] {
+			place(pod, nodeGroup.Id())
+		}
+	}
+	for _, pod := range headroom.clusterwide {
+		place(pod, "")
+	}
+	return placed, missing
+}
+
+// nodeNamesAffinity returns an affinity that requires a pod to run on one of the given nodes.
+func nodeNamesAffinity(nodeNames []string) *apiv1.Affinity {
+	terms := make([]apiv1.NodeSelectorTerm, 0, len(nodeNames))
+	for _, name := range nodeNames {
+		// Node field selectors accept a single value, so each node gets its own term.
+		terms = append(terms, apiv1.NodeSelectorTerm{
+			MatchFields: []apiv1.NodeSelectorRequirement{{
+				Key:      schedulerapi.NodeFieldSelectorKeyNodeName,
+				Operator: apiv1.NodeSelectorOpIn,
+				Values:   []string{name},
+			}},
+		})
+	}
+	return &apiv1.Affinity{
+		NodeAffinity: &apiv1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{NodeSelectorTerms: terms},
+		},
+	}
+}
+
+// updateHeadroomStatus records in the cluster state registry how much of the headroom is missing.
+func updateHeadroomStatus(clusterStateRegistry *clusterstate.ClusterStateRegistry, headroom headroomPods, missing []*apiv1.Pod) {
+	missingCount := make(map[string]int)
+	for _, pod := range missing {
+		missingCount[pod.Annotations[drain.HeadroomPodKey]]++
+	}
+	var clusterwide *clusterstate.HeadroomStatus
+	if len(headroom.clusterwide) > 0 {
+		clusterwide = &clusterstate.HeadroomStatus{Pods: len(headroom.clusterwide), Missing: missingCount[""]}
+	}
+	perNodeGroup := make(map[string]clusterstate.HeadroomStatus, len(headroom.perNodeGroup))
+	for id, pods := range headroom.perNodeGroup {
+		perNodeGroup[id] = clusterstate.HeadroomStatus{Pods: len(pods), Missing: missingCount[id]}
+	}
+	clusterStateRegistry.UpdateHeadroom(clusterwide, perNodeGroup)
+}
+
+// filterOutHeadroomPods returns pods that are not headroom pods.
+func filterOutHeadroomPods(pods []*apiv1.Pod) []*apiv1.Pod {
+	result := make([]*apiv1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if !drain.IsHeadroomPod(pod) {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+// filterOutHeadroomPodsFromScaleUpStatus removes headroom pods from the scale up status, so that
+// status processors don't report them as if they existed.
+func filterOutHeadroomPodsFromScaleUpStatus(scaleUpStatus *status.ScaleUpStatus) {
+	scaleUpStatus.PodsTriggeredScaleUp = filterOutHeadroomPods(scaleUpStatus.PodsTriggeredScaleUp)
+	scaleUpStatus.PodsAwaitEvaluation = filterOutHeadroomPods(scaleUpStatus.PodsAwaitEvaluation)
+	remainUnschedulable := make([]status.NoScaleUpInfo, 0, len(scaleUpStatus.PodsRemainUnschedulable))
+	for _, info := range scaleUpStatus.PodsRemainUnschedulable {
+		if !drain.IsHeadroomPod(info.Pod) {
+			remainUnschedulable = append(remainUnschedulable, info)
+		}
+	}
+	scaleUpStatus.PodsRemainUnschedulable = remainUnschedulable
+}
+
+// podsForNodeGroup filters out headroom pods that belong to other node groups.
+func podsForNodeGroup(pods []*apiv1.Pod, nodeGroupId string) []*apiv1.Pod {
+	result := make([]*apiv1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if owner := pod.Annotations[drain.HeadroomPodKey]; owner == "" || owner == nodeGroupId {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+func divideRoundingUp(a, b int64) int64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	return (a + b - 1) / b
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildHeadroomTestProvider() (*testprovider.TestCloudProvider, map[string]*schedulercache.NodeInfo) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+
+	nodeInfos := make(map[string]*schedulercache.NodeInfo)
+	for id, cpu := range map[string]int64{"ng1": 1000, "ng2": 2000} {
+		template := BuildTestNode("template-"+id, cpu, 1000*MiB)
+		// Daemon set running on every node.
+		nodeInfos[id] = schedulercache.NewNodeInfo(BuildTestPod("ds-"+id, 200, 200*MiB))
+		nodeInfos[id].SetNode(template)
+	}
+	return provider, nodeInfos
+}
+
+func requests(pod *apiv1.Pod) (int64, int64) {
+	resources := pod.Spec.Containers[0].Resources.Requests
+	return resources.Cpu().MilliValue(), resources.Memory().Value()
+}
+
+func TestBuildHeadroomPods(t *testing.T) {
+	provider, nodeInfos := buildHeadroomTestProvider()
+	provider.GetNodeGroup("ng1").(*testprovider.TestNodeGroup).SetOptions(&config.NodeGroupAutoscalingOptions{
+		Headroom: config.Headroom{MilliCPU: 1000, Nodes: 1},
+	})
+	options := config.AutoscalingOptions{
+		Headroom: config.Headroom{Memory: 1000 * MiB},
+	}
+	context := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, provider)
+
+	headroom := buildHeadroomPods(&context, nodeInfos)
+
+	// 800m CPU is free on ng1 template, so 1000m is split into 2 pods, followed by a spare node pod.
+	ng1Pods := headroom.perNodeGroup["ng1"]
+	assert.Equal(t, 3, len(ng1Pods))
+	for i, expectedCPU := range []int64{500, 500, 800} {
+		cpu, _ := requests(ng1Pods[i])
+		assert.Equal(t, expectedCPU, cpu)
+		assert.Equal(t, "ng1", ng1Pods[i].Annotations[drain.HeadroomPodKey])
+	}
+	_, memory := requests(ng1Pods[2])
+	assert.Equal(t, int64(800*MiB), memory)
+	_, found := headroom.perNodeGroup["ng2"]
+	assert.False(t, found)
+
+	// Cluster-wide headroom is split into pods fitting the smallest template.
+	assert.Equal(t, 2, len(headroom.clusterwide))
+	for _, pod := range headroom.clusterwide {
+		cpu, memory := requests(pod)
+		assert.Equal(t, int64(0), cpu)
+		assert.Equal(t, int64(500*MiB), memory)
+		assert.True(t, drain.IsHeadroomPod(pod))
+	}
+}
+
+func TestPlaceHeadroomPods(t *testing.T) {
+	provider, nodeInfos := buildHeadroomTestProvider()
+	provider.GetNodeGroup("ng1").(*testprovider.TestNodeGroup).SetOptions(&config.NodeGroupAutoscalingOptions{
+		Headroom: config.Headroom{MilliCPU: 500},
+	})
+	options := config.AutoscalingOptions{
+		Headroom: config.Headroom{MilliCPU: 500},
+	}
+	context := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, provider)
+
+	n1 := BuildTestNode("n1", 1000, 1000*MiB)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 2000, 1000*MiB)
+	SetNodeReadyState(n2, true, time.Time{})
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng2", n2)
+
+	p1 := BuildTestPod("p1", 700, 0)
+	p1.Spec.NodeName = "n1"
+
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2}, []*apiv1.Pod{p1})
+	headroom := buildHeadroomPods(&context, nodeInfos)
+	placed, missing := placeHeadroomPods(&context, headroom, []*apiv1.Node{n1, n2})
+
+	// ng1 headroom doesn't fit on n1 and can't use n2 from another node group.
+	assert.Equal(t, 1, len(missing))
+	assert.Equal(t, "ng1", missing[0].Annotations[drain.HeadroomPodKey])
+	assert.Equal(t, "", missing[0].Spec.NodeName)
+	// Cluster-wide headroom can use any node.
+	assert.Equal(t, 1, len(placed))
+	assert.Equal(t, "", placed[0].Annotations[drain.HeadroomPodKey])
+	assert.Equal(t, "n2", placed[0].Spec.NodeName)
+}
+
+func TestScaleDownKeepsHeadroomInNodeGroup(t *testing.T) {
+	provider := testprovider.NewTestCloudProvider(nil, func(nodeGroup string, node string) error {
+		t.Errorf("Unexpected deletion of node %s", node)
+		return nil
+	})
+	provider.AddNodeGroup("ng1", 0, 10, 1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+	options := config.AutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.6,
+		ScaleDownUnneededTime:         time.Minute,
+		MaxGracefulTerminationSec:     60,
+	}
+	context := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, provider)
+
+	// n1 is underutilized, but runs ng1 headroom. The only free space is on n2 from ng2.
+	n1 := BuildTestNode("n1", 1000, 1000*MiB)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 1000, 1000*MiB)
+	SetNodeReadyState(n2, true, time.Time{})
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng2", n2)
+	nodes := []*apiv1.Node{n1, n2}
+
+	headroom := headroomPods{perNodeGroup: map[string][]*apiv1.Pod{
+		"ng1": {buildHeadroomPod("ng1", 0, 500, 0, nil)},
+	}}
+	initializeClusterSnapshotOrDie(t, &context, nodes, nil)
+	placed, missing := placeHeadroomPods(&context, headroom, nodes)
+	assert.Empty(t, missing)
+	if assert.Equal(t, 1, len(placed)) {
+		assert.Equal(t, "n1", placed[0].Spec.NodeName)
+	}
+
+	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+	now := time.Now()
+	err := scaleDown.UpdateUnneededNodes(nodes, []*apiv1.Node{n1}, placed, now.Add(-5*time.Minute), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, scaleDown.unneededNodes)
+
+	scaleDownStatus, err := scaleDown.TryToScaleDown(nodes, placed, nil, nil, now)
+	waitForDeleteToFinish(t, scaleDown)
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleDownNoUnneeded, scaleDownStatus.Result)
+}
+
+func TestPodsForNodeGroup(t *testing.T) {
+	p1 := BuildTestPod("p1", 100, 0)
+	clusterwide := buildHeadroomPod("", 0, 100, 0, nil)
+	ng1 := buildHeadroomPod("ng1", 0, 100, 0, nil)
+	ng2 := buildHeadroomPod("ng2", 0, 100, 0, nil)
+
+	assert.Equal(t, []*apiv1.Pod{p1, clusterwide, ng1}, podsForNodeGroup([]*apiv1.Pod{p1, clusterwide, ng1, ng2}, "ng1"))
+	assert.Equal(t, []*apiv1.Pod{p1}, filterOutHeadroomPods([]*apiv1.Pod{p1, clusterwide, ng1, ng2}))
+
+	scaleUpStatus := &status.ScaleUpStatus{
+		PodsTriggeredScaleUp:    []*apiv1.Pod{p1, ng1},
+		PodsRemainUnschedulable: []status.NoScaleUpInfo{{Pod: ng2}},
+		PodsAwaitEvaluation:     []*apiv1.Pod{clusterwide},
+	}
+	filterOutHeadroomPodsFromScaleUpStatus(scaleUpStatus)
+	assert.Equal(t, []*apiv1.Pod{p1}, scaleUpStatus.PodsTriggeredScaleUp)
+	assert.Empty(t, scaleUpStatus.PodsRemainUnschedulable)
+	assert.Empty(t, scaleUpStatus.PodsAwaitEvaluation)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -32,12 +33,12 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/utils/deletetaint"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
-	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
 
 	apiv1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1beta1"
 	kube_errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	kube_client "k8s.io/client-go/kubernetes"
 	kube_record "k8s.io/client-go/tools/record"
 
@@ -45,6 +46,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
 	"k8s.io/klog"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
 )
 
 const (
@@ -64,8 +66,28 @@ const (
 	// PodEvictionHeadroom is the extra time we wait to catch situations when the pod is ignoring SIGTERM and
 	// is killed with SIGKILL after MaxGracefulTerminationTime
 	PodEvictionHeadroom = 30 * time.Second
+	// ReplacementPodsCheckInterval is the time between checks whether the replacements of evicted pods are running.
+	ReplacementPodsCheckInterval = 5 * time.Second
 )
 
+// UnremovableNodeEventInterval is the minimal time between two events about the same node
+// being unremovable for the same reason.
+const UnremovableNodeEventInterval = 10 * time.Minute
+
+// disruptionCostBucketSize is the width of the ranges of node disruption costs considered equal when
+// ordering scale-down candidates, so that removal savings decide between them. It is the cost of
+// evicting a new pod without the disruption cost annotation.
+const disruptionCostBucketSize = 1.0
+
+// unremovableReasonsWithoutEvent are the normal, transient states of nodes that aren't worth an event.
+var unremovableReasonsWithoutEvent = map[simulator.UnremovableReason]bool{
+	simulator.NotUnderutilized:      true,
+	simulator.NotUnneededLongEnough: true,
+	simulator.NotUnreadyLongEnough:  true,
+	simulator.RecentlyUnremovable:   true,
+	simulator.CurrentlyBeingDeleted: true,
+}
+
 // NodeDeleteStatus tells whether a node is being deleted right now.
 type NodeDeleteStatus struct {
 	sync.Mutex
@@ -305,30 +327,130 @@ type ScaleDown struct {
 	unneededNodes        map[string]time.Time
 	unneededNodesList    []*apiv1.Node
 	unremovableNodes     map[string]time.Time
-	podLocationHints     map[string]string
-	nodeUtilizationMap   map[string]simulator.UtilizationInfo
-	usageTracker         *simulator.UsageTracker
-	nodeDeleteStatus     *NodeDeleteStatus
+	// unremovableNodeReasons holds the reason why each node couldn't be removed in the current loop.
+	unremovableNodeReasons map[string]*simulator.UnremovableNode
+	// unremovableNodeEvents holds the last time an event was recorded for a node and reason.
+	unremovableNodeEvents map[string]time.Time
+	podLocationHints      map[string]string
+	nodeUtilizationMap    map[string]simulator.UtilizationInfo
+	usageTracker          *simulator.UsageTracker
+	nodeDeleteStatus      *NodeDeleteStatus
+	// listerRegistry is the lister registry of the context when ScaleDown is built. Node deletions
+	// outlive the loop that started them, and the listers of the context may be replaced during a
+	// loop to record it.
+	listerRegistry kube_util.ListerRegistry
 }
 
 // NewScaleDown builds new ScaleDown object.
 func NewScaleDown(context *context.AutoscalingContext, clusterStateRegistry *clusterstate.ClusterStateRegistry) *ScaleDown {
 	return &ScaleDown{
-		context:              context,
-		clusterStateRegistry: clusterStateRegistry,
-		unneededNodes:        make(map[string]time.Time),
-		unremovableNodes:     make(map[string]time.Time),
-		podLocationHints:     make(map[string]string),
-		nodeUtilizationMap:   make(map[string]simulator.UtilizationInfo),
-		usageTracker:         simulator.NewUsageTracker(),
-		unneededNodesList:    make([]*apiv1.Node, 0),
-		nodeDeleteStatus:     &NodeDeleteStatus{nodeDeleteResults: make(map[string]error)},
+		context:                context,
+		clusterStateRegistry:   clusterStateRegistry,
+		unneededNodes:          make(map[string]time.Time),
+		unremovableNodes:       make(map[string]time.Time),
+		unremovableNodeReasons: make(map[string]*simulator.UnremovableNode),
+		unremovableNodeEvents:  make(map[string]time.Time),
+		podLocationHints:       make(map[string]string),
+		nodeUtilizationMap:     make(map[string]simulator.UtilizationInfo),
+		usageTracker:           simulator.NewUsageTracker(),
+		unneededNodesList:      make([]*apiv1.Node, 0),
+		nodeDeleteStatus:       &NodeDeleteStatus{nodeDeleteResults: make(map[string]error)},
+		listerRegistry:         context.ListerRegistry,
 	}
 }
 
 // CleanUp cleans up the internal ScaleDown state.
 func (sd *ScaleDown) CleanUp(timestamp time.Time) {
 	sd.usageTracker.CleanUp(timestamp.Add(-sd.context.ScaleDownUnneededTime))
+	sd.unremovableNodeReasons = make(map[string]*simulator.UnremovableNode)
+}
+
+// addUnremovableNodeReason records why the node can't be removed in the current loop.
+func (sd *ScaleDown) addUnremovableNodeReason(node *apiv1.Node, reason simulator.UnremovableReason) {
+	sd.addUnremovableNode(&simulator.UnremovableNode{Node: node, Reason: reason})
+}
+
+// addUnremovableNode records an unremovable node. A later reason for the same node replaces the earlier one.
+func (sd *ScaleDown) addUnremovableNode(unremovableNode *simulator.UnremovableNode) {
+	sd.unremovableNodeReasons[unremovableNode.Node.Name] = unremovableNode
+}
+
+// getUnremovableNodes returns the nodes that couldn't be removed in the current loop, sorted by name.
+func (sd *ScaleDown) getUnremovableNodes() []*simulator.UnremovableNode {
+	result := make([]*simulator.UnremovableNode, 0, len(sd.unremovableNodeReasons))
+	for _, unremovableNode := range sd.unremovableNodeReasons {
+		result = append(result, unremovableNode)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Node.Name < result[j].Node.Name })
+	return result
+}
+
+// reportUnremovableNodes updates the unremovable nodes metric and records an event on each unremovable
+// node, at most once per UnremovableNodeEventInterval for a given node and reason.
+func (sd *ScaleDown) reportUnremovableNodes(timestamp time.Time) {
+	for _, unremovableNode := range sd.getUnremovableNodes() {
+		if unremovableReasonsWithoutEvent[unremovableNode.Reason] {
+			continue
+		}
+		key := unremovableNode.Node.Name + "/" + string(unremovableNode.Reason)
+		if last, found := sd.unremovableNodeEvents[key]; found && timestamp.Sub(last) < UnremovableNodeEventInterval {
+			continue
+		}
+		sd.unremovableNodeEvents[key] = timestamp
+		sd.context.Recorder.Eventf(unremovableNode.Node, apiv1.EventTypeNormal, "ScaleDownSkipped",
+			"node can't be scaled down: %s", unremovableNodeMessage(unremovableNode))
+	}
+	for key, last := range sd.unremovableNodeEvents {
+		if timestamp.Sub(last) >= UnremovableNodeEventInterval {
+			delete(sd.unremovableNodeEvents, key)
+		}
+	}
+	metrics.UpdateUnremovableNodesCount(sd.countUnremovableNodesByReason())
+}
+
+// countUnremovableNodesByReason returns the number of nodes that couldn't be removed in the current
+// loop for each reason.
+func (sd *ScaleDown) countUnremovableNodesByReason() map[string]int {
+	countByReason := make(map[string]int)
+	for _, unremovableNode := range sd.unremovableNodeReasons {
+		countByReason[string(unremovableNode.Reason)]++
+	}
+	return countByReason
+}
+
+// unremovableNodeMessage describes why the node can't be removed.
+func unremovableNodeMessage(unremovableNode *simulator.UnremovableNode) string {
+	if unremovableNode.BlockingPod != nil {
+		pod := unremovableNode.BlockingPod.Pod
+		return fmt.Sprintf("%s: pod %s/%s", unremovableNode.Reason, pod.Namespace, pod.Name)
+	}
+	return string(unremovableNode.Reason)
+}
+
+// SoftTaintUnneededNodes taints unneeded nodes with the PreferNoSchedule DeletionCandidate taint, up to
+// MaxBulkSoftTaintCount nodes, and removes the taint from nodes that are no longer unneeded.
+func (sd *ScaleDown) SoftTaintUnneededNodes(allNodes []*apiv1.Node) {
+	tainted := 0
+	for _, node := range allNodes {
+		_, unneeded := sd.unneededNodes[node.Name]
+		hasTaint := deletetaint.HasDeletionCandidateTaint(node)
+		if unneeded && !hasTaint {
+			if tainted >= sd.context.MaxBulkSoftTaintCount {
+				klog.V(3).Infof("Soft taint limit of %d nodes reached, not tainting %s", sd.context.MaxBulkSoftTaintCount, node.Name)
+				continue
+			}
+			if err := deletetaint.MarkDeletionCandidate(node, sd.context.ClientSet); err != nil {
+				klog.Warningf("Failed to soft taint unneeded node %s: %v", node.Name, err)
+				continue
+			}
+			tainted++
+		}
+		if !unneeded && hasTaint {
+			if _, err := deletetaint.CleanDeletionCandidate(node, sd.context.ClientSet); err != nil {
+				klog.Warningf("Failed to remove soft taint from node %s: %v", node.Name, err)
+			}
+		}
+	}
 }
 
 // GetCandidatesForScaleDown gets candidates for scale down.
@@ -355,8 +477,8 @@ func (sd *ScaleDown) UpdateUnneededNodes(
 
 	currentlyUnneededNodes := make([]*apiv1.Node, 0)
 	// Only scheduled non expendable pods and pods waiting for lower priority pods preemption can prevent node delete.
+	// The cluster snapshot is expected to hold exactly those pods.
 	nonExpendablePods := FilterOutExpendablePods(pods, sd.context.ExpendablePodsPriorityCutoff)
-	nodeNameToNodeInfo := scheduler_util.CreateNodeNameToInfoMap(nonExpendablePods, nodes)
 	utilizationMap := make(map[string]simulator.UtilizationInfo)
 
 	sd.updateUnremovableNodes(nodes)
@@ -365,6 +487,7 @@ func (sd *ScaleDown) UpdateUnneededNodes(
 	for _, node := range nodesToCheck {
 		if unremovableTimestamp, found := sd.unremovableNodes[node.Name]; found {
 			if unremovableTimestamp.After(timestamp) {
+				sd.addUnremovableNodeReason(node, simulator.RecentlyUnremovable)
 				continue
 			}
 			delete(sd.unremovableNodes, node.Name)
@@ -385,18 +508,21 @@ func (sd *ScaleDown) UpdateUnneededNodes(
 		// and they have not been deleted.
 		if isNodeBeingDeleted(node, timestamp) {
 			klog.V(1).Infof("Skipping %s from delete considerations - the node is currently being deleted", node.Name)
+			sd.addUnremovableNodeReason(node, simulator.CurrentlyBeingDeleted)
 			continue
 		}
 
 		// Skip nodes marked with no scale down annotation
 		if hasNoScaleDownAnnotation(node) {
 			klog.V(1).Infof("Skipping %s from delete consideration - the node is marked as no scale down", node.Name)
+			sd.addUnremovableNodeReason(node, simulator.ScaleDownDisabledAnnotation)
 			continue
 		}
 
-		nodeInfo, found := nodeNameToNodeInfo[node.Name]
+		nodeInfo, found := sd.context.ClusterSnapshot.GetNodeInfo(node.Name)
 		if !found {
 			klog.Errorf("Node info for %s not found", node.Name)
+			sd.addUnremovableNodeReason(node, simulator.UnexpectedError)
 			continue
 		}
 		utilInfo, err := simulator.CalculateUtilization(node, nodeInfo, sd.context.IgnoreDaemonSetsUtilization, sd.context.IgnoreMirrorPodsUtilization)
@@ -407,8 +533,9 @@ func (sd *ScaleDown) UpdateUnneededNodes(
 		klog.V(4).Infof("Node %s - utilization %f", node.Name, utilInfo.Utilization)
 		utilizationMap[node.Name] = utilInfo
 
-		if utilInfo.Utilization >= sd.context.ScaleDownUtilizationThreshold {
+		if utilInfo.Utilization >= nodeGroupOptionsForNode(sd.context, node).ScaleDownUtilizationThreshold {
 			klog.V(4).Infof("Node %s is not suitable for removal - utilization too big (%f)", node.Name, utilInfo.Utilization)
+			sd.addUnremovableNodeReason(node, simulator.NotUnderutilized)
 			continue
 		}
 		currentlyUnneededNodes = append(currentlyUnneededNodes, node)
@@ -428,12 +555,16 @@ func (sd *ScaleDown) UpdateUnneededNodes(
 		}
 	}
 
+	// Nodes that save the most money per moved pod are checked first.
+	ranking := sd.getRemovalSavings(currentlyUnneededNonEmptyNodes, nonExpendablePods, timestamp)
+	sortScaleDownCandidates(currentlyUnneededNonEmptyNodes, nil, nil, nil, ranking)
+
 	// Phase2 - check which nodes can be probably removed using fast drain.
 	currentCandidates, currentNonCandidates := sd.chooseCandidates(currentlyUnneededNonEmptyNodes)
 
 	// Look for nodes to remove in the current candidates
 	nodesToRemove, unremovable, newHints, simulatorErr := simulator.FindNodesToRemove(
-		currentCandidates, nodes, nonExpendablePods, nil, sd.context.PredicateChecker,
+		currentCandidates, nodes, sd.context.ClusterSnapshot, nil, sd.context.PredicateChecker,
 		len(currentCandidates), true, sd.podLocationHints, sd.usageTracker, timestamp, pdbs)
 	if simulatorErr != nil {
 		return sd.markSimulationError(simulatorErr, timestamp)
@@ -455,7 +586,7 @@ func (sd *ScaleDown) UpdateUnneededNodes(
 		// Look for additional nodes to remove among the rest of nodes.
 		klog.V(3).Infof("Finding additional %v candidates for scale down.", additionalCandidatesCount)
 		additionalNodesToRemove, additionalUnremovable, additionalNewHints, simulatorErr :=
-			simulator.FindNodesToRemove(currentNonCandidates[:additionalCandidatesPoolSize], nodes, nonExpendablePods, nil,
+			simulator.FindNodesToRemove(currentNonCandidates[:additionalCandidatesPoolSize], nodes, sd.context.ClusterSnapshot, nil,
 				sd.context.PredicateChecker, additionalCandidatesCount, true,
 				sd.podLocationHints, sd.usageTracker, timestamp, pdbs)
 		if simulatorErr != nil {
@@ -487,8 +618,9 @@ func (sd *ScaleDown) UpdateUnneededNodes(
 	// Add nodes to unremovable map
 	if len(unremovable) > 0 {
 		unremovableTimeout := timestamp.Add(sd.context.AutoscalingOptions.UnremovableNodeRecheckTimeout)
-		for _, node := range unremovable {
-			sd.unremovableNodes[node.Name] = unremovableTimeout
+		for _, unremovableNode := range unremovable {
+			sd.unremovableNodes[unremovableNode.Node.Name] = unremovableTimeout
+			sd.addUnremovableNode(unremovableNode)
 		}
 		klog.V(1).Infof("%v nodes found to be unremovable in simulation, will re-check them at %v", len(unremovable), unremovableTimeout)
 	}
@@ -500,6 +632,7 @@ func (sd *ScaleDown) UpdateUnneededNodes(
 	sd.nodeUtilizationMap = utilizationMap
 	sd.clusterStateRegistry.UpdateScaleDownCandidates(sd.unneededNodesList, timestamp)
 	metrics.UpdateUnneededNodesCount(len(sd.unneededNodesList))
+	sd.reportUnremovableNodes(timestamp)
 	return nil
 }
 
@@ -559,6 +692,92 @@ func (sd *ScaleDown) chooseCandidates(nodes []*apiv1.Node) ([]*apiv1.Node, []*ap
 	return currentCandidates, currentNonCandidates
 }
 
+// filterOutCostlyDisruptions removes the candidates whose pods cost more than ScaleDownMaxDisruptionCost
+// to disrupt and returns them as skipped nodes.
+func (sd *ScaleDown) filterOutCostlyDisruptions(candidates []*apiv1.Node,
+	disruptionCosts map[string]float64) ([]*apiv1.Node, []*status.ScaleDownSkippedNode) {
+	maxCost := sd.context.ScaleDownMaxDisruptionCost
+	if maxCost <= 0 {
+		return candidates, nil
+	}
+	result := make([]*apiv1.Node, 0, len(candidates))
+	var skipped []*status.ScaleDownSkippedNode
+	for _, node := range candidates {
+		cost := disruptionCosts[node.Name]
+		if cost <= maxCost {
+			result = append(result, node)
+			continue
+		}
+		reason := fmt.Sprintf("pod disruption cost %.2f exceeds the maximum of %.2f", cost, maxCost)
+		klog.V(1).Infof("Skipping %s - %s", node.Name, reason)
+		sd.addUnremovableNodeReason(node, simulator.DisruptionCostTooHigh)
+		skipped = append(skipped, &status.ScaleDownSkippedNode{Node: node, Reason: reason, DisruptionCost: cost})
+	}
+	return result, skipped
+}
+
+// getRemovalSavings returns the money saved per moved pod by removing each priced candidate, most
+// savings first, if the cloud provider has a pricing model.
+func (sd *ScaleDown) getRemovalSavings(candidates []*apiv1.Node, pods []*apiv1.Pod,
+	timestamp time.Time) []simulator.NodeRemovalSavings {
+	pricingModel, err := sd.context.CloudProvider.Pricing()
+	if err != nil {
+		if err != cloudprovider.ErrNotImplemented {
+			klog.Warningf("Failed to get pricing model, scale-down candidates are not ordered by cost: %v", err)
+		}
+		return nil
+	}
+	_, ranking := simulator.SortNodesByRemovalSavings(candidates, pods, pricingModel, timestamp)
+	return ranking
+}
+
+// sortScaleDownCandidates orders candidates in which they are tried for removal. The order is
+// decided by, in this precedence:
+//  1. balance: nodes of the groups larger than their similar groups first, if balancer is not nil,
+//  2. disruption cost bucket: nodes with cheaper to disrupt pods first, disruption costs that differ
+//     by less than disruptionCostBucketSize may fall into the same bucket,
+//  3. savings: nodes saving more money per moved pod first, nodes without savings, i.e. unpriced
+//     nodes, last,
+//  4. disruption cost: nodes with cheaper to disrupt pods first.
+//
+// The relative order of candidates is kept otherwise.
+func sortScaleDownCandidates(candidates []*apiv1.Node, candidateNodeGroups map[string]cloudprovider.NodeGroup,
+	balancer *scaleDownBalancer, disruptionCosts map[string]float64, ranking []simulator.NodeRemovalSavings) {
+	savings := make(map[string]float64, len(ranking))
+	for _, nodeSavings := range ranking {
+		savings[nodeSavings.NodeName] = nodeSavings.SavingsPerPod
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		first, second := candidates[i].Name, candidates[j].Name
+		if balancer != nil {
+			firstExcess := balancer.excess(candidateNodeGroups[first].Id())
+			secondExcess := balancer.excess(candidateNodeGroups[second].Id())
+			if firstExcess != secondExcess {
+				return firstExcess > secondExcess
+			}
+		}
+		firstBucket := disruptionCostBucket(disruptionCosts[first])
+		secondBucket := disruptionCostBucket(disruptionCosts[second])
+		if firstBucket != secondBucket {
+			return firstBucket < secondBucket
+		}
+		firstSavings, firstPriced := savings[first]
+		secondSavings, secondPriced := savings[second]
+		if firstPriced != secondPriced {
+			return firstPriced
+		}
+		if firstSavings != secondSavings {
+			return firstSavings > secondSavings
+		}
+		return disruptionCosts[first] < disruptionCosts[second]
+	})
+}
+
+// disruptionCostBucket returns the bucket of a node disruption cost used by sortScaleDownCandidates.
+func disruptionCostBucket(cost float64) int {
+	return int(math.Floor(cost / disruptionCostBucketSize))
+}
+
 func (sd *ScaleDown) mapNodesToStatusScaleDownNodes(nodes []*apiv1.Node, nodeGroups map[string]cloudprovider.NodeGroup, evictedPodLists map[string][]*apiv1.Pod) []*status.ScaleDownNode {
 	var result []*status.ScaleDownNode
 	for _, node := range nodes {
@@ -574,8 +793,13 @@ func (sd *ScaleDown) mapNodesToStatusScaleDownNodes(nodes []*apiv1.Node, nodeGro
 
 // TryToScaleDown tries to scale down the cluster. It returns a result inside a ScaleDownStatus indicating if any node was
 // removed and error if such occurred.
-func (sd *ScaleDown) TryToScaleDown(allNodes []*apiv1.Node, pods []*apiv1.Pod, pdbs []*policyv1.PodDisruptionBudget, currentTime time.Time) (*status.ScaleDownStatus, errors.AutoscalerError) {
+func (sd *ScaleDown) TryToScaleDown(allNodes []*apiv1.Node, pods []*apiv1.Pod, pdbs []*policyv1.PodDisruptionBudget,
+	nodeInfosForGroups map[string]*schedulercache.NodeInfo, currentTime time.Time) (*status.ScaleDownStatus, errors.AutoscalerError) {
 	scaleDownStatus := &status.ScaleDownStatus{NodeDeleteResults: sd.nodeDeleteStatus.DrainNodeDeleteResults()}
+	defer func() {
+		scaleDownStatus.UnremovableNodes = sd.getUnremovableNodes()
+		sd.reportUnremovableNodes(currentTime)
+	}()
 	nodeDeletionDuration := time.Duration(0)
 	findNodesToRemoveDuration := time.Duration(0)
 	defer updateScaleDownMetrics(time.Now(), &findNodesToRemoveDuration, &nodeDeletionDuration)
@@ -583,6 +807,7 @@ func (sd *ScaleDown) TryToScaleDown(allNodes []*apiv1.Node, pods []*apiv1.Pod, p
 	candidates := make([]*apiv1.Node, 0)
 	readinessMap := make(map[string]bool)
 	candidateNodeGroups := make(map[string]cloudprovider.NodeGroup)
+	inBlackout := 0
 
 	resourceLimiter, errCP := sd.context.CloudProvider.GetResourceLimiter()
 	if errCP != nil {
@@ -594,6 +819,13 @@ func (sd *ScaleDown) TryToScaleDown(allNodes []*apiv1.Node, pods []*apiv1.Pod, p
 
 	nodeGroupSize := getNodeGroupSizeMap(sd.context.CloudProvider)
 	resourcesWithLimits := resourceLimiter.GetResources()
+
+	// Similar node groups are kept balanced on scale-down as they are on scale-up.
+	var balancer *scaleDownBalancer
+	if sd.context.BalanceSimilarNodeGroups {
+		balancer = newScaleDownBalancer(sd.context.CloudProvider.NodeGroups(), nodeInfosForGroups, nodeGroupSize, sd.context.ScaleDownBalanceMaxSkew)
+	}
+
 	for _, node := range nodesWithoutMaster {
 		if val, found := sd.unneededNodes[node.Name]; found {
 
@@ -602,52 +834,70 @@ func (sd *ScaleDown) TryToScaleDown(allNodes []*apiv1.Node, pods []*apiv1.Pod, p
 			// Check if node is marked with no scale down annotation.
 			if hasNoScaleDownAnnotation(node) {
 				klog.V(4).Infof("Skipping %s - scale down disabled annotation found", node.Name)
+				sd.addUnremovableNodeReason(node, simulator.ScaleDownDisabledAnnotation)
 				continue
 			}
 
 			ready, _, _ := kube_util.GetReadinessState(node)
 			readinessMap[node.Name] = ready
 
+			nodeGroupOptions := nodeGroupOptionsForNode(sd.context, node)
+
 			// Check how long the node was underutilized.
-			if ready && !val.Add(sd.context.ScaleDownUnneededTime).Before(currentTime) {
+			if ready && !val.Add(nodeGroupOptions.ScaleDownUnneededTime).Before(currentTime) {
+				sd.addUnremovableNodeReason(node, simulator.NotUnneededLongEnough)
 				continue
 			}
 
 			// Unready nodes may be deleted after a different time than underutilized nodes.
-			if !ready && !val.Add(sd.context.ScaleDownUnreadyTime).Before(currentTime) {
+			if !ready && !val.Add(nodeGroupOptions.ScaleDownUnreadyTime).Before(currentTime) {
+				sd.addUnremovableNodeReason(node, simulator.NotUnreadyLongEnough)
 				continue
 			}
 
 			nodeGroup, err := sd.context.CloudProvider.NodeGroupForNode(node)
 			if err != nil {
 				klog.Errorf("Error while checking node group for %s: %v", node.Name, err)
+				sd.addUnremovableNodeReason(node, simulator.UnexpectedError)
 				continue
 			}
 			if nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
 				klog.V(4).Infof("Skipping %s - no node group config", node.Name)
+				sd.addUnremovableNodeReason(node, simulator.NotAutoscaled)
 				continue
 			}
 
 			size, found := nodeGroupSize[nodeGroup.Id()]
 			if !found {
 				klog.Errorf("Error while checking node group size %s: group size not found in cache", nodeGroup.Id())
+				sd.addUnremovableNodeReason(node, simulator.UnexpectedError)
 				continue
 			}
 
 			if size <= nodeGroup.MinSize() {
 				klog.V(1).Infof("Skipping %s - node group min size reached", node.Name)
+				sd.addUnremovableNodeReason(node, simulator.NodeGroupMinSizeReached)
 				continue
 			}
 
 			scaleDownResourcesDelta, err := computeScaleDownResourcesDelta(node, nodeGroup, resourcesWithLimits)
 			if err != nil {
 				klog.Errorf("Error getting node resources: %v", err)
+				sd.addUnremovableNodeReason(node, simulator.UnexpectedError)
 				continue
 			}
 
 			checkResult := scaleDownResourcesLeft.checkScaleDownDeltaWithinLimits(scaleDownResourcesDelta)
 			if checkResult.exceeded {
 				klog.V(4).Infof("Skipping %s - minimal limit exceeded for %v", node.Name, checkResult.exceededResources)
+				sd.addUnremovableNodeReason(node, simulator.MinimalResourceLimitExceeded)
+				continue
+			}
+
+			if allowed, _ := nodeGroupScaleDownAllowedAt(nodeGroupOptions, currentTime); !allowed {
+				klog.V(1).Infof("Skipping %s - node group %s is outside of its scale down windows", node.Name, nodeGroup.Id())
+				inBlackout++
+				sd.addUnremovableNodeReason(node, simulator.OutsideScaleDownWindow)
 				continue
 			}
 
@@ -655,16 +905,47 @@ func (sd *ScaleDown) TryToScaleDown(allNodes []*apiv1.Node, pods []*apiv1.Pod, p
 			candidateNodeGroups[node.Name] = nodeGroup
 		}
 	}
+
+	// Only scheduled non expendable pods are taken into account and have to be moved.
+	nonExpendablePods := FilterOutExpendablePods(pods, sd.context.ExpendablePodsPriorityCutoff)
+
+	// Nodes whose pods are too costly to disrupt are skipped.
+	candidates, disruptionCosts := simulator.SortNodesByDisruptionCost(candidates, nonExpendablePods, currentTime)
+	candidates, scaleDownStatus.SkippedNodes = sd.filterOutCostlyDisruptions(candidates, disruptionCosts)
+
 	if len(candidates) == 0 {
 		klog.V(1).Infof("No candidates for scale down")
 		scaleDownStatus.Result = status.ScaleDownNoUnneeded
+		if len(scaleDownStatus.SkippedNodes) > 0 {
+			scaleDownStatus.Result = status.ScaleDownNoNodeDeleted
+		}
+		if inBlackout > 0 {
+			scaleDownStatus.Result = status.ScaleDownInBlackout
+		}
 		return scaleDownStatus, nil
 	}
 
+	scaleDownStatus.CandidatesRanking = sd.getRemovalSavings(candidates, nonExpendablePods, currentTime)
+	sortScaleDownCandidates(candidates, candidateNodeGroups, balancer, disruptionCosts, scaleDownStatus.CandidatesRanking)
+
 	// Trying to delete empty nodes in bulk. If there are no empty nodes then CA will
 	// try to delete not-so-empty nodes, possibly killing some pods and allowing them
 	// to recreate on other nodes.
 	emptyNodes := getEmptyNodes(candidates, pods, sd.context.MaxEmptyBulkDelete, scaleDownResourcesLeft, sd.context.CloudProvider)
+	if balancer != nil {
+		emptyNodes = balancer.limitNodes(emptyNodes, candidateNodeGroups)
+	}
+	if len(emptyNodes) > 0 && sd.context.DryRun {
+		for _, node := range emptyNodes {
+			klog.V(0).Infof("Scale-down (dry run): would remove empty node %s", node.Name)
+			sd.context.LogRecorder.Eventf(apiv1.EventTypeNormal, "SimulatedScaleDownEmpty", "Scale-down (dry run): would remove empty node %s", node.Name)
+			registerSimulatedScaleDown(node, readinessMap, metrics.Empty)
+		}
+		scaleDownStatus.ScaledDownNodes = sd.mapNodesToStatusScaleDownNodes(emptyNodes, candidateNodeGroups, make(map[string][]*apiv1.Pod))
+		scaleDownStatus.Result = status.ScaleDownNodeDeleted
+		scaleDownStatus.Simulated = true
+		return scaleDownStatus, nil
+	}
 	if len(emptyNodes) > 0 {
 		nodeDeletionStart := time.Now()
 		confirmation := make(chan errors.AutoscalerError, len(emptyNodes))
@@ -681,11 +962,13 @@ func (sd *ScaleDown) TryToScaleDown(allNodes []*apiv1.Node, pods []*apiv1.Pod, p
 	}
 
 	findNodesToRemoveStart := time.Now()
-	// Only scheduled non expendable pods are taken into account and have to be moved.
-	nonExpendablePods := FilterOutExpendablePods(pods, sd.context.ExpendablePodsPriorityCutoff)
-	// We look for only 1 node so new hints may be incomplete.
-	nodesToRemove, _, _, err := simulator.FindNodesToRemove(candidates, nodesWithoutMaster, nonExpendablePods, sd.context.ClientSet,
-		sd.context.PredicateChecker, 1, false,
+	maxDrainParallelism := sd.context.MaxDrainParallelism
+	if maxDrainParallelism < 1 {
+		maxDrainParallelism = 1
+	}
+	// We look only for a limited number of nodes so new hints may be incomplete.
+	nodesToRemove, unremovable, _, err := simulator.FindNodesToRemoveTogether(candidates, nodesWithoutMaster, sd.context.ClusterSnapshot, sd.context.ClientSet,
+		sd.context.PredicateChecker, maxDrainParallelism, false,
 		sd.podLocationHints, sd.usageTracker, time.Now(), pdbs)
 	findNodesToRemoveDuration = time.Now().Sub(findNodesToRemoveStart)
 
@@ -693,51 +976,136 @@ func (sd *ScaleDown) TryToScaleDown(allNodes []*apiv1.Node, pods []*apiv1.Pod, p
 		scaleDownStatus.Result = status.ScaleDownError
 		return scaleDownStatus, err.AddPrefix("Find node to remove failed: ")
 	}
+	for _, unremovableNode := range unremovable {
+		sd.addUnremovableNode(unremovableNode)
+	}
+	nodesToRemove = limitNodesToRemove(nodesToRemove, candidateNodeGroups, nodeGroupSize, scaleDownResourcesLeft, resourcesWithLimits, balancer)
 	if len(nodesToRemove) == 0 {
 		klog.V(1).Infof("No node to remove")
 		scaleDownStatus.Result = status.ScaleDownNoNodeDeleted
 		return scaleDownStatus, nil
 	}
-	toRemove := nodesToRemove[0]
-	utilization := sd.nodeUtilizationMap[toRemove.Node.Name]
-	podNames := make([]string, 0, len(toRemove.PodsToReschedule))
-	for _, pod := range toRemove.PodsToReschedule {
-		podNames = append(podNames, pod.Namespace+"/"+pod.Name)
+
+	// Headroom pods exist only in simulations, there is nothing to evict.
+	for i := range nodesToRemove {
+		nodesToRemove[i].PodsToReschedule = filterOutHeadroomPods(nodesToRemove[i].PodsToReschedule)
+	}
+
+	nodes := make([]*apiv1.Node, 0, len(nodesToRemove))
+	evictedPodLists := make(map[string][]*apiv1.Pod, len(nodesToRemove))
+	for _, toRemove := range nodesToRemove {
+		nodes = append(nodes, toRemove.Node)
+		evictedPodLists[toRemove.Node.Name] = toRemove.PodsToReschedule
+		utilization := sd.nodeUtilizationMap[toRemove.Node.Name]
+		podNames := make([]string, 0, len(toRemove.PodsToReschedule))
+		for _, pod := range toRemove.PodsToReschedule {
+			podNames = append(podNames, pod.Namespace+"/"+pod.Name)
+		}
+		if sd.context.DryRun {
+			klog.V(0).Infof("Scale-down (dry run): would remove node %s, utilization: %v, pods to reschedule: %s", toRemove.Node.Name, utilization,
+				strings.Join(podNames, ","))
+			sd.context.LogRecorder.Eventf(apiv1.EventTypeNormal, "SimulatedScaleDown", "Scale-down (dry run): would remove node %s, utilization: %v, pods to reschedule: %s",
+				toRemove.Node.Name, utilization, strings.Join(podNames, ","))
+			registerSimulatedScaleDown(toRemove.Node, readinessMap, metrics.Underutilized)
+			continue
+		}
+		klog.V(0).Infof("Scale-down: removing node %s, utilization: %v, pods to reschedule: %s", toRemove.Node.Name, utilization,
+			strings.Join(podNames, ","))
+		sd.context.LogRecorder.Eventf(apiv1.EventTypeNormal, "ScaleDown", "Scale-down: removing node %s, utilization: %v, pods to reschedule: %s",
+			toRemove.Node.Name, utilization, strings.Join(podNames, ","))
+	}
+	scaleDownStatus.ScaledDownNodes = sd.mapNodesToStatusScaleDownNodes(nodes, candidateNodeGroups, evictedPodLists)
+	scaleDownStatus.Result = status.ScaleDownNodeDeleteStarted
+	if sd.context.DryRun {
+		scaleDownStatus.Simulated = true
+		return scaleDownStatus, nil
 	}
-	klog.V(0).Infof("Scale-down: removing node %s, utilization: %v, pods to reschedule: %s", toRemove.Node.Name, utilization,
-		strings.Join(podNames, ","))
-	sd.context.LogRecorder.Eventf(apiv1.EventTypeNormal, "ScaleDown", "Scale-down: removing node %s, utilization: %v, pods to reschedule: %s",
-		toRemove.Node.Name, utilization, strings.Join(podNames, ","))
 
-	// Nothing super-bad should happen if the node is removed from tracker prematurely.
-	simulator.RemoveNodeFromTracker(sd.usageTracker, toRemove.Node.Name, sd.unneededNodes)
+	// Nothing super-bad should happen if the nodes are removed from tracker prematurely.
+	for _, toRemove := range nodesToRemove {
+		simulator.RemoveNodeFromTracker(sd.usageTracker, toRemove.Node.Name, sd.unneededNodes)
+	}
 	nodeDeletionStart := time.Now()
 
 	// Starting deletion.
 	nodeDeletionDuration = time.Now().Sub(nodeDeletionStart)
 	sd.nodeDeleteStatus.SetDeleteInProgress(true)
 
+	var deletions sync.WaitGroup
+	for _, toRemove := range nodesToRemove {
+		deletions.Add(1)
+		go func(toRemove simulator.NodeToBeRemoved) {
+			defer deletions.Done()
+			var err error
+			defer func() { sd.nodeDeleteStatus.AddNodeDeleteResult(toRemove.Node.Name, err) }()
+			err = sd.deleteNode(toRemove.Node, toRemove.PodsToReschedule)
+			if err != nil {
+				klog.Errorf("Failed to delete %s: %v", toRemove.Node.Name, err)
+				return
+			}
+			nodeGroup := candidateNodeGroups[toRemove.Node.Name]
+			if readinessMap[toRemove.Node.Name] {
+				metrics.RegisterScaleDown(1, gpu.GetGpuTypeForMetrics(toRemove.Node, nodeGroup), metrics.Underutilized)
+			} else {
+				metrics.RegisterScaleDown(1, gpu.GetGpuTypeForMetrics(toRemove.Node, nodeGroup), metrics.Unready)
+			}
+		}(toRemove)
+	}
 	go func() {
-		// Finishing the delete process once this goroutine is over.
-		var err error
-		defer func() { sd.nodeDeleteStatus.AddNodeDeleteResult(toRemove.Node.Name, err) }()
-		defer sd.nodeDeleteStatus.SetDeleteInProgress(false)
-		err = sd.deleteNode(toRemove.Node, toRemove.PodsToReschedule)
+		// Finishing the delete process once all node deletions are over.
+		deletions.Wait()
+		sd.nodeDeleteStatus.SetDeleteInProgress(false)
+	}()
+
+	return scaleDownStatus, nil
+}
+
+// limitNodesToRemove drops nodes whose removal, together with the nodes preceding them on the list,
+// would shrink their node group below its min size, exceed the minimal cluster resource limits or,
+// if balancer is not nil, unbalance similar node groups.
+func limitNodesToRemove(nodesToRemove []simulator.NodeToBeRemoved, candidateNodeGroups map[string]cloudprovider.NodeGroup,
+	nodeGroupSize map[string]int, resourcesLimits scaleDownResourcesLimits, resourcesWithLimits []string,
+	balancer *scaleDownBalancer) []simulator.NodeToBeRemoved {
+
+	result := make([]simulator.NodeToBeRemoved, 0, len(nodesToRemove))
+	resourcesLimitsCopy := copyScaleDownResourcesLimits(resourcesLimits) // we do not want to modify input parameter
+	if balancer != nil {
+		balancer = balancer.copy() // we do not want to modify input parameter
+	}
+	removedFromGroup := make(map[string]int)
+	for _, toRemove := range nodesToRemove {
+		nodeGroup := candidateNodeGroups[toRemove.Node.Name]
+		if nodeGroupSize[nodeGroup.Id()]-removedFromGroup[nodeGroup.Id()] <= nodeGroup.MinSize() {
+			klog.V(1).Infof("Skipping %s - node group min size would be reached", toRemove.Node.Name)
+			continue
+		}
+		resourcesDelta, err := computeScaleDownResourcesDelta(toRemove.Node, nodeGroup, resourcesWithLimits)
 		if err != nil {
-			klog.Errorf("Failed to delete %s: %v", toRemove.Node.Name, err)
-			return
+			klog.Errorf("Error getting node resources: %v", err)
+			continue
 		}
-		nodeGroup := candidateNodeGroups[toRemove.Node.Name]
-		if readinessMap[toRemove.Node.Name] {
-			metrics.RegisterScaleDown(1, gpu.GetGpuTypeForMetrics(toRemove.Node, nodeGroup), metrics.Underutilized)
-		} else {
-			metrics.RegisterScaleDown(1, gpu.GetGpuTypeForMetrics(toRemove.Node, nodeGroup), metrics.Unready)
+		checkResult := resourcesLimitsCopy.tryDecrementLimitsByDelta(resourcesDelta)
+		if checkResult.exceeded {
+			klog.V(4).Infof("Skipping %s - minimal limit exceeded for %v", toRemove.Node.Name, checkResult.exceededResources)
+			continue
 		}
-	}()
+		if balancer != nil && !balancer.tryRemove(nodeGroup.Id()) {
+			klog.V(1).Infof("Skipping %s - similar node groups would become unbalanced", toRemove.Node.Name)
+			continue
+		}
+		removedFromGroup[nodeGroup.Id()]++
+		result = append(result, toRemove)
+	}
+	return result
+}
 
-	scaleDownStatus.ScaledDownNodes = sd.mapNodesToStatusScaleDownNodes([]*apiv1.Node{toRemove.Node}, candidateNodeGroups, map[string][]*apiv1.Pod{toRemove.Node.Name: toRemove.PodsToReschedule})
-	scaleDownStatus.Result = status.ScaleDownNodeDeleteStarted
-	return scaleDownStatus, nil
+// registerSimulatedScaleDown records a node removal that was only planned in dry-run mode. Unready
+// nodes are always reported as such, regardless of the reason passed.
+func registerSimulatedScaleDown(node *apiv1.Node, readinessMap map[string]bool, reason metrics.NodeScaleDownReason) {
+	if !readinessMap[node.Name] {
+		reason = metrics.Unready
+	}
+	metrics.RegisterSimulatedScaleDown(1, reason)
 }
 
 // updateScaleDownMetrics registers duration of different parts of scale down.
@@ -879,6 +1247,7 @@ func (sd *ScaleDown) waitForEmptyNodesDeleted(emptyNodes []*apiv1.Node, confirma
 func (sd *ScaleDown) deleteNode(node *apiv1.Node, pods []*apiv1.Pod) errors.AutoscalerError {
 	deleteSuccessful := false
 	drainSuccessful := false
+	replacementsRunning := false
 
 	if err := deletetaint.MarkToBeDeleted(node, sd.context.ClientSet); err != nil {
 		sd.context.Recorder.Eventf(node, apiv1.EventTypeWarning, "ScaleDownFailed", "failed to mark the node as toBeDeleted/unschedulable: %v", err)
@@ -891,6 +1260,8 @@ func (sd *ScaleDown) deleteNode(node *apiv1.Node, pods []*apiv1.Pod) errors.Auto
 			deletetaint.CleanToBeDeleted(node, sd.context.ClientSet)
 			if !drainSuccessful {
 				sd.context.Recorder.Eventf(node, apiv1.EventTypeWarning, "ScaleDownFailed", "failed to drain the node, aborting ScaleDown")
+			} else if !replacementsRunning {
+				sd.context.Recorder.Eventf(node, apiv1.EventTypeWarning, "ScaleDownFailed", "replacements of evicted pods not running, aborting ScaleDown")
 			} else {
 				sd.context.Recorder.Eventf(node, apiv1.EventTypeWarning, "ScaleDownFailed", "failed to delete the node")
 			}
@@ -900,11 +1271,19 @@ func (sd *ScaleDown) deleteNode(node *apiv1.Node, pods []*apiv1.Pod) errors.Auto
 	sd.context.Recorder.Eventf(node, apiv1.EventTypeNormal, "ScaleDown", "marked the node as toBeDeleted/unschedulable")
 
 	// attempt drain
+	drainStart := time.Now()
 	if err := drainNode(node, pods, sd.context.ClientSet, sd.context.Recorder, sd.context.MaxGracefulTerminationSec, MaxPodEvictionTime, EvictionRetryTime); err != nil {
 		return err
 	}
 	drainSuccessful = true
 
+	if sd.context.ReplacementPodsTimeout > 0 {
+		if err := waitForReplacementPods(node, pods, sd.listerRegistry.ScheduledPodLister(), drainStart, sd.context.ReplacementPodsTimeout, ReplacementPodsCheckInterval); err != nil {
+			return err
+		}
+	}
+	replacementsRunning = true
+
 	// attempt delete from cloud provider
 	err := deleteNodeFromCloudProvider(node, sd.context.CloudProvider, sd.context.Recorder, sd.clusterStateRegistry)
 	if err != nil {
@@ -1011,6 +1390,78 @@ func drainNode(node *apiv1.Node, pods []*apiv1.Pod, client kube_client.Interface
 		errors.TransientError, "Failed to drain node %s/%s: pods remaining after timeout", node.Namespace, node.Name)
 }
 
+// Waits until every controller that owned some of the evicted pods has as many pods created after
+// drainStart running on other nodes as it had pods evicted. Pods without a controller are not replaced
+// and are not waited for. Fails right away if the scheduled pods can't be listed.
+func waitForReplacementPods(node *apiv1.Node, evictedPods []*apiv1.Pod, scheduledPodLister kube_util.PodLister,
+	drainStart time.Time, timeout time.Duration, checkInterval time.Duration) errors.AutoscalerError {
+	// Creation timestamps have a one second precision.
+	createdAfter := drainStart.Truncate(time.Second)
+	missing := make(map[types.UID]int)
+	for _, pod := range evictedPods {
+		if controllerRef := metav1.GetControllerOf(pod); controllerRef != nil {
+			missing[controllerRef.UID]++
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		pods, err := scheduledPodLister.List()
+		if err != nil {
+			return errors.NewAutoscalerError(
+				errors.ApiCallError, "Failed to list pods to check replacements of pods evicted from %s: %v", node.Name, err)
+		}
+		running := make(map[types.UID]int)
+		for _, pod := range pods {
+			controllerRef := metav1.GetControllerOf(pod)
+			if controllerRef == nil || missing[controllerRef.UID] == 0 {
+				continue
+			}
+			if pod.Spec.NodeName != node.Name && pod.Status.Phase == apiv1.PodRunning &&
+				!pod.CreationTimestamp.Time.Before(createdAfter) {
+				running[controllerRef.UID]++
+			}
+		}
+		allRunning := true
+		for uid, count := range missing {
+			if running[uid] < count {
+				allRunning = false
+				break
+			}
+		}
+		if allRunning {
+			klog.V(1).Infof("All replacements of pods evicted from %s are running", node.Name)
+			return nil
+		}
+		left := deadline.Sub(time.Now())
+		if left <= 0 {
+			return errors.NewAutoscalerError(
+				errors.TransientError, "Failed to delete node %s/%s: replacements of evicted pods not running after timeout", node.Namespace, node.Name)
+		}
+		if left > checkInterval {
+			left = checkInterval
+		}
+		time.Sleep(left)
+	}
+}
+
+// cleanDeletionCandidates cleans DeletionCandidate taints.
+func cleanDeletionCandidates(nodes []*apiv1.Node, client kube_client.Interface, recorder kube_record.EventRecorder) {
+	for _, node := range nodes {
+		cleaned, err := deletetaint.CleanDeletionCandidate(node, client)
+		if err != nil {
+			klog.Warningf("Error while releasing soft taints on node %v: %v", node.Name, err)
+			recorder.Eventf(node, apiv1.EventTypeWarning, "ClusterAutoscalerCleanup",
+				"failed to clean deletionCandidateTaint: %v", err)
+		} else if cleaned {
+			klog.V(1).Infof("Successfully released deletionCandidateTaint on node %v", node.Name)
+		}
+	}
+}
+
 // cleanToBeDeleted cleans ToBeDeleted taints.
 func cleanToBeDeleted(nodes []*apiv1.Node, client kube_client.Interface, recorder kube_record.EventRecorder) {
 	for _, node := range nodes {
@@ -0,0 +1,184 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+
+	apiv1 "k8s.io/api/core/v1"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"k8s.io/klog"
+)
+
+// scaleDownBalancer keeps similar node groups, e.g. the same machine type in
+// different zones, balanced on scale-down. Nodes are preferably removed from
+// the largest group of a set of similar groups and no removal may leave the
+// difference between the largest and the smallest group of a set above maxSkew,
+// unless the difference doesn't grow.
+type scaleDownBalancer struct {
+	// similarGroups maps a node group id to the ids of the similar groups,
+	// including itself. Groups without similar groups are not present.
+	similarGroups map[string][]string
+	// sizes are the node group sizes, reduced by the removals accepted so far.
+	sizes   map[string]int
+	maxSkew int
+}
+
+// newScaleDownBalancer groups nodeGroups into sets of similar groups, comparing
+// their template NodeInfos with nodegroupset.IsNodeInfoSimilar.
+func newScaleDownBalancer(nodeGroups []cloudprovider.NodeGroup, nodeInfosForGroups map[string]*schedulercache.NodeInfo,
+	nodeGroupSize map[string]int, maxSkew int) *scaleDownBalancer {
+
+	b := &scaleDownBalancer{
+		similarGroups: make(map[string][]string),
+		sizes:         make(map[string]int, len(nodeGroupSize)),
+		maxSkew:       maxSkew,
+	}
+	for id, size := range nodeGroupSize {
+		b.sizes[id] = size
+	}
+
+	for _, ng := range nodeGroups {
+		id := ng.Id()
+		nodeInfo, found := nodeInfosForGroups[id]
+		if !found {
+			continue
+		}
+		if _, found := b.sizes[id]; !found {
+			continue
+		}
+		similar := []string{id}
+		for _, other := range nodeGroups {
+			otherId := other.Id()
+			if otherId == id {
+				continue
+			}
+			otherNodeInfo, found := nodeInfosForGroups[otherId]
+			if !found {
+				continue
+			}
+			if _, found := b.sizes[otherId]; !found {
+				continue
+			}
+			if nodegroupset.IsNodeInfoSimilar(nodeInfo, otherNodeInfo) {
+				similar = append(similar, otherId)
+			}
+		}
+		if len(similar) > 1 {
+			b.similarGroups[id] = similar
+		}
+	}
+	return b
+}
+
+// copy returns a balancer with the same similar groups and the current sizes.
+func (b *scaleDownBalancer) copy() *scaleDownBalancer {
+	sizes := make(map[string]int, len(b.sizes))
+	for id, size := range b.sizes {
+		sizes[id] = size
+	}
+	return &scaleDownBalancer{similarGroups: b.similarGroups, sizes: sizes, maxSkew: b.maxSkew}
+}
+
+// skew returns the difference between the largest and the smallest of the
+// given groups, with the size of nodeGroupId reduced by delta.
+func (b *scaleDownBalancer) skew(groups []string, nodeGroupId string, delta int) int {
+	min, max := 0, 0
+	for i, id := range groups {
+		size := b.sizes[id]
+		if id == nodeGroupId {
+			size -= delta
+		}
+		if i == 0 || size < min {
+			min = size
+		}
+		if i == 0 || size > max {
+			max = size
+		}
+	}
+	return max - min
+}
+
+// canRemove tells if a node can be removed from the given group without making
+// its set of similar groups more imbalanced than allowed.
+func (b *scaleDownBalancer) canRemove(nodeGroupId string) bool {
+	groups, found := b.similarGroups[nodeGroupId]
+	if !found {
+		return true
+	}
+	after := b.skew(groups, nodeGroupId, 1)
+	return after <= b.maxSkew || after <= b.skew(groups, nodeGroupId, 0)
+}
+
+// tryRemove records the removal of a node from the given group if canRemove allows it.
+func (b *scaleDownBalancer) tryRemove(nodeGroupId string) bool {
+	if !b.canRemove(nodeGroupId) {
+		return false
+	}
+	b.sizes[nodeGroupId]--
+	return true
+}
+
+// excess returns by how many nodes the given group is larger than the smallest
+// of its similar groups, 0 for groups without similar groups.
+func (b *scaleDownBalancer) excess(nodeGroupId string) int {
+	groups, found := b.similarGroups[nodeGroupId]
+	if !found {
+		return 0
+	}
+	excess := 0
+	for _, id := range groups {
+		if d := b.sizes[nodeGroupId] - b.sizes[id]; d > excess {
+			excess = d
+		}
+	}
+	return excess
+}
+
+// limitNodes returns the nodes that can be removed together without unbalancing
+// their sets of similar groups. Nodes are taken from the group that is the
+// largest at each step, in the order of the list otherwise.
+func (b *scaleDownBalancer) limitNodes(nodes []*apiv1.Node, candidateNodeGroups map[string]cloudprovider.NodeGroup) []*apiv1.Node {
+	balancer := b.copy()
+	remaining := append([]*apiv1.Node{}, nodes...)
+	result := make([]*apiv1.Node, 0, len(nodes))
+	for len(remaining) > 0 {
+		best := -1
+		for i, node := range remaining {
+			id := candidateNodeGroups[node.Name].Id()
+			if !balancer.canRemove(id) {
+				continue
+			}
+			if best < 0 || balancer.excess(id) > balancer.excess(candidateNodeGroups[remaining[best].Name].Id()) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		node := remaining[best]
+		balancer.tryRemove(candidateNodeGroups[node.Name].Id())
+		result = append(result, node)
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+	for _, node := range remaining {
+		klog.V(1).Infof("Skipping %s - similar node groups would become unbalanced", node.Name)
+	}
+	return result
+}
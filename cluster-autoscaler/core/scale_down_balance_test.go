@@ -0,0 +1,124 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+
+	apiv1 "k8s.io/api/core/v1"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestBalancer(t *testing.T, maxSkew int, sizes map[string]int, ids ...string) (*scaleDownBalancer, map[string]cloudprovider.NodeGroup) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	nodeInfos := make(map[string]*schedulercache.NodeInfo)
+	for _, id := range ids {
+		provider.AddNodeGroup(id, 0, 10, sizes[id])
+		node := BuildTestNode(id+"-template", 1000, 1000)
+		if id == "other" {
+			node.Labels["custom"] = "true"
+		}
+		nodeInfo := schedulercache.NewNodeInfo()
+		assert.NoError(t, nodeInfo.SetNode(node))
+		nodeInfos[id] = nodeInfo
+	}
+	nodeGroups := make(map[string]cloudprovider.NodeGroup)
+	for _, ng := range provider.NodeGroups() {
+		nodeGroups[ng.Id()] = ng
+	}
+	return newScaleDownBalancer(provider.NodeGroups(), nodeInfos, sizes, maxSkew), nodeGroups
+}
+
+func TestScaleDownBalancerSimilarGroups(t *testing.T) {
+	balancer, _ := buildTestBalancer(t, 1, map[string]int{"ng1": 3, "ng2": 2, "other": 1}, "ng1", "ng2", "other")
+
+	assert.ElementsMatch(t, []string{"ng1", "ng2"}, balancer.similarGroups["ng1"])
+	assert.ElementsMatch(t, []string{"ng1", "ng2"}, balancer.similarGroups["ng2"])
+	_, found := balancer.similarGroups["other"]
+	assert.False(t, found)
+}
+
+func TestScaleDownBalancerCanRemove(t *testing.T) {
+	balancer, _ := buildTestBalancer(t, 1, map[string]int{"ng1": 3, "ng2": 2, "ng3": 2}, "ng1", "ng2", "ng3")
+
+	assert.True(t, balancer.canRemove("ng1"))
+	assert.False(t, balancer.canRemove("ng2"))
+	assert.True(t, balancer.tryRemove("ng1"))
+	assert.True(t, balancer.tryRemove("ng2"))
+	assert.False(t, balancer.tryRemove("ng2"))
+	assert.Equal(t, 2, balancer.sizes["ng1"])
+	assert.Equal(t, 1, balancer.sizes["ng2"])
+
+	// Groups already more imbalanced than allowed can still shrink, as long
+	// as the difference doesn't grow.
+	balancer, _ = buildTestBalancer(t, 1, map[string]int{"ng1": 5, "ng2": 3, "ng3": 1}, "ng1", "ng2", "ng3")
+	assert.True(t, balancer.canRemove("ng1"))
+	assert.True(t, balancer.canRemove("ng2"))
+	assert.False(t, balancer.canRemove("ng3"))
+
+	// Groups without similar groups are not limited.
+	balancer, _ = buildTestBalancer(t, 1, map[string]int{"ng1": 5, "other": 1}, "ng1", "other")
+	assert.True(t, balancer.canRemove("other"))
+}
+
+func TestScaleDownBalancerSortAndLimitNodes(t *testing.T) {
+	balancer, nodeGroups := buildTestBalancer(t, 1, map[string]int{"ng1": 2, "ng2": 4}, "ng1", "ng2")
+
+	candidateNodeGroups := make(map[string]cloudprovider.NodeGroup)
+	var candidates []*apiv1.Node
+	for _, n := range []struct{ name, group string }{
+		{"n1", "ng1"}, {"n2", "ng1"}, {"n3", "ng2"}, {"n4", "ng2"}, {"n5", "ng2"},
+	} {
+		candidates = append(candidates, BuildTestNode(n.name, 1000, 1000))
+		candidateNodeGroups[n.name] = nodeGroups[n.group]
+	}
+
+	sortScaleDownCandidates(candidates, candidateNodeGroups, balancer, nil, nil)
+	names := make([]string, 0, len(candidates))
+	for _, node := range candidates {
+		names = append(names, node.Name)
+	}
+	assert.Equal(t, []string{"n3", "n4", "n5", "n1", "n2"}, names)
+
+	limited := balancer.limitNodes(candidates, candidateNodeGroups)
+	names = names[:0]
+	for _, node := range limited {
+		names = append(names, node.Name)
+	}
+	// ng2 is shrunk to the size of ng1 first, then the groups shrink together.
+	assert.Equal(t, []string{"n3", "n4", "n5", "n1", "n2"}, names)
+
+	// The balancer itself is not modified.
+	assert.Equal(t, 2, balancer.sizes["ng1"])
+	assert.Equal(t, 4, balancer.sizes["ng2"])
+
+	limited = balancer.limitNodes(candidates[:3], candidateNodeGroups)
+	names = names[:0]
+	for _, node := range limited {
+		names = append(names, node.Name)
+	}
+	assert.Equal(t, []string{"n3", "n4", "n5"}, names)
+
+	limited = balancer.limitNodes(candidates[3:], candidateNodeGroups)
+	assert.Equal(t, 0, len(limited))
+}
@@ -28,24 +28,29 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
 	"k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
+	kube_record "k8s.io/client-go/tools/record"
 
 	"strconv"
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/deletetaint"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
 	"k8s.io/klog"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
 )
 
 func TestFindUnneededNodes(t *testing.T) {
@@ -129,6 +134,7 @@ func TestFindUnneededNodes(t *testing.T) {
 
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
 	sd := NewScaleDown(&context, clusterStateRegistry)
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2, n3, n4, n5, n7, n8, n9}, []*apiv1.Pod{p1, p2, p3, p4, p5, p6})
 	sd.UpdateUnneededNodes([]*apiv1.Node{n1, n2, n3, n4, n5, n7, n8, n9}, []*apiv1.Node{n1, n2, n3, n4, n5, n6, n7, n8, n9},
 		[]*apiv1.Pod{p1, p2, p3, p4, p5, p6}, time.Now(), nil)
 
@@ -144,6 +150,7 @@ func TestFindUnneededNodes(t *testing.T) {
 
 	sd.unremovableNodes = make(map[string]time.Time)
 	sd.unneededNodes["n1"] = time.Now()
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2, n3, n4}, []*apiv1.Pod{p1, p2, p3, p4})
 	sd.UpdateUnneededNodes([]*apiv1.Node{n1, n2, n3, n4}, []*apiv1.Node{n1, n2, n3, n4}, []*apiv1.Pod{p1, p2, p3, p4}, time.Now(), nil)
 	sd.unremovableNodes = make(map[string]time.Time)
 
@@ -154,16 +161,19 @@ func TestFindUnneededNodes(t *testing.T) {
 	assert.Equal(t, 4, len(sd.nodeUtilizationMap))
 
 	sd.unremovableNodes = make(map[string]time.Time)
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2, n3, n4}, []*apiv1.Pod{p1, p2, p3, p4})
 	sd.UpdateUnneededNodes([]*apiv1.Node{n1, n2, n3, n4}, []*apiv1.Node{n1, n3, n4}, []*apiv1.Pod{p1, p2, p3, p4}, time.Now(), nil)
 	assert.Equal(t, 0, len(sd.unneededNodes))
 
 	// Node n1 is unneeded, but should be skipped because it has just recently been found to be unremovable
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1}, []*apiv1.Pod{})
 	sd.UpdateUnneededNodes([]*apiv1.Node{n1}, []*apiv1.Node{n1}, []*apiv1.Pod{}, time.Now(), nil)
 	assert.Equal(t, 0, len(sd.unneededNodes))
 	// Verify that no other nodes are in unremovable map.
 	assert.Equal(t, 1, len(sd.unremovableNodes))
 
 	// But it should be checked after timeout
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1}, []*apiv1.Pod{})
 	sd.UpdateUnneededNodes([]*apiv1.Node{n1}, []*apiv1.Node{n1}, []*apiv1.Pod{}, time.Now().Add(context.UnremovableNodeRecheckTimeout+time.Second), nil)
 	assert.Equal(t, 1, len(sd.unneededNodes))
 	// Verify that nodes that are no longer unremovable are removed.
@@ -241,6 +251,7 @@ func TestPodsWithPrioritiesFindUnneededNodes(t *testing.T) {
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
 	sd := NewScaleDown(&context, clusterStateRegistry)
 
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2, n3, n4}, []*apiv1.Pod{p1, p2, p3, p4, p5, p6, p7})
 	sd.UpdateUnneededNodes([]*apiv1.Node{n1, n2, n3, n4}, []*apiv1.Node{n1, n2, n3, n4},
 		[]*apiv1.Pod{p1, p2, p3, p4, p5, p6, p7}, time.Now(), nil)
 	assert.Equal(t, 2, len(sd.unneededNodes))
@@ -291,6 +302,7 @@ func TestFindUnneededMaxCandidates(t *testing.T) {
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
 	sd := NewScaleDown(&context, clusterStateRegistry)
 
+	initializeClusterSnapshotOrDie(t, &context, nodes, pods)
 	sd.UpdateUnneededNodes(nodes, nodes, pods, time.Now(), nil)
 	assert.Equal(t, numCandidates, len(sd.unneededNodes))
 	// Simulate one of the unneeded nodes got deleted
@@ -312,6 +324,7 @@ func TestFindUnneededMaxCandidates(t *testing.T) {
 		}
 	}
 
+	initializeClusterSnapshotOrDie(t, &context, nodes, pods)
 	sd.UpdateUnneededNodes(nodes, nodes, pods, time.Now(), nil)
 	// Check that the deleted node was replaced
 	assert.Equal(t, numCandidates, len(sd.unneededNodes))
@@ -357,6 +370,7 @@ func TestFindUnneededEmptyNodes(t *testing.T) {
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
 	sd := NewScaleDown(&context, clusterStateRegistry)
 
+	initializeClusterSnapshotOrDie(t, &context, nodes, pods)
 	sd.UpdateUnneededNodes(nodes, nodes, pods, time.Now(), nil)
 	for _, node := range sd.unneededNodesList {
 		t.Log(node.Name)
@@ -401,10 +415,54 @@ func TestFindUnneededNodePool(t *testing.T) {
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
 	sd := NewScaleDown(&context, clusterStateRegistry)
 
+	initializeClusterSnapshotOrDie(t, &context, nodes, pods)
 	sd.UpdateUnneededNodes(nodes, nodes, pods, time.Now(), nil)
 	assert.NotEmpty(t, sd.unneededNodes)
 }
 
+func TestFindUnneededNodesWithNodeGroupOptions(t *testing.T) {
+	ownerRef := GenerateOwnerReferences("rs", "ReplicaSet", "extensions/v1beta1", "")
+
+	p1 := BuildTestPod("p1", 600, 0)
+	p1.Spec.NodeName = "n1"
+	p1.OwnerReferences = ownerRef
+	p2 := BuildTestPod("p2", 600, 0)
+	p2.Spec.NodeName = "n2"
+	p2.OwnerReferences = ownerRef
+
+	n1 := BuildTestNode("n1", 1000, 10)
+	n2 := BuildTestNode("n2", 1000, 10)
+	n3 := BuildTestNode("n3", 10000, 10)
+	SetNodeReadyState(n1, true, time.Time{})
+	SetNodeReadyState(n2, true, time.Time{})
+	SetNodeReadyState(n3, true, time.Time{})
+
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 2)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng2", n2)
+	provider.AddNode("ng1", n3)
+	provider.GetNodeGroup("ng2").(*testprovider.TestNodeGroup).SetOptions(&config.NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.7,
+	})
+
+	options := config.AutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.5,
+	}
+	context := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, provider)
+
+	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	sd := NewScaleDown(&context, clusterStateRegistry)
+
+	nodes := []*apiv1.Node{n1, n2, n3}
+	initializeClusterSnapshotOrDie(t, &context, nodes, []*apiv1.Pod{p1, p2})
+	sd.UpdateUnneededNodes(nodes, []*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2}, time.Now(), nil)
+	assert.Equal(t, 1, len(sd.unneededNodes))
+	_, found := sd.unneededNodes["n2"]
+	assert.True(t, found)
+}
+
 func TestDeleteNode(t *testing.T) {
 	// common parameters
 	nothingReturned := "Nothing returned"
@@ -686,6 +744,129 @@ func TestDrainNodeWithRetries(t *testing.T) {
 	assert.Equal(t, p3.Name, deleted[2])
 }
 
+func TestWaitForReplacementPods(t *testing.T) {
+	drainStart := time.Now()
+	n1 := BuildTestNode("n1", 1000, 1000)
+	ownerRefs := GenerateOwnerReferences("rs", "ReplicaSet", "extensions/v1beta1", "rs-uid")
+
+	evicted := BuildTestPod("evicted", 100, 0)
+	evicted.OwnerReferences = ownerRefs
+	evicted.Spec.NodeName = "n1"
+	standalone := BuildTestPod("standalone", 100, 0)
+	standalone.Spec.NodeName = "n1"
+
+	buildReplica := func(name string, created time.Time, phase apiv1.PodPhase) *apiv1.Pod {
+		pod := BuildTestPod(name, 100, 0)
+		pod.OwnerReferences = ownerRefs
+		pod.CreationTimestamp = metav1.Time{Time: created}
+		pod.Spec.NodeName = "n2"
+		pod.Status.Phase = phase
+		return pod
+	}
+	olderReplica := buildReplica("older", drainStart.Add(-time.Hour), apiv1.PodRunning)
+
+	testScenarios := []struct {
+		name         string
+		evictedPods  []*apiv1.Pod
+		existingPods []*apiv1.Pod
+		listError    error
+		expectError  bool
+	}{
+		{
+			name:         "replacement running",
+			evictedPods:  []*apiv1.Pod{evicted, standalone},
+			existingPods: []*apiv1.Pod{olderReplica, buildReplica("replacement", drainStart, apiv1.PodRunning)},
+		},
+		{
+			name:         "replacement pending",
+			evictedPods:  []*apiv1.Pod{evicted},
+			existingPods: []*apiv1.Pod{olderReplica, buildReplica("replacement", drainStart, apiv1.PodPending)},
+			expectError:  true,
+		},
+		{
+			name:         "no replacement",
+			evictedPods:  []*apiv1.Pod{evicted},
+			existingPods: []*apiv1.Pod{olderReplica},
+			expectError:  true,
+		},
+		{
+			name:         "list error",
+			evictedPods:  []*apiv1.Pod{evicted},
+			existingPods: []*apiv1.Pod{},
+			listError:    fmt.Errorf("list failed"),
+			expectError:  true,
+		},
+		{
+			name:        "pods without controller",
+			evictedPods: []*apiv1.Pod{standalone},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			scheduledPodMock := &podListerMock{}
+			scheduledPodMock.On("List").Return(scenario.existingPods, scenario.listError)
+			err := waitForReplacementPods(n1, scenario.evictedPods, scheduledPodMock, drainStart, 50*time.Millisecond, 10*time.Millisecond)
+			if scenario.listError != nil {
+				// List errors are not retried until the timeout.
+				scheduledPodMock.AssertNumberOfCalls(t, "List", 1)
+			}
+			if scenario.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDeleteNodeReplacementPodsTimeout(t *testing.T) {
+	updatedNodes := make(chan string, 10)
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Time{})
+	p1 := BuildTestPod("p1", 100, 0)
+	p1.OwnerReferences = GenerateOwnerReferences("rs", "ReplicaSet", "extensions/v1beta1", "rs-uid")
+	p1.Spec.NodeName = "n1"
+
+	provider := testprovider.NewTestCloudProvider(nil, func(nodeGroup string, node string) error {
+		t.Errorf("Unexpected deletion of node %s", node)
+		return nil
+	})
+	provider.AddNodeGroup("ng1", 1, 100, 100)
+	provider.AddNode("ng1", n1)
+
+	fakeClient := &fake.Clientset{}
+	fakeClient.Fake.AddReactor("get", "nodes", func(action core.Action) (bool, runtime.Object, error) {
+		return true, n1, nil
+	})
+	fakeClient.Fake.AddReactor("update", "nodes", func(action core.Action) (bool, runtime.Object, error) {
+		obj := action.(core.UpdateAction).GetObject().(*apiv1.Node)
+		updatedNodes <- fmt.Sprintf("%s-%d", obj.Name, len(obj.Spec.Taints))
+		return true, obj, nil
+	})
+	fakeClient.Fake.AddReactor("create", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	fakeClient.Fake.AddReactor("get", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewNotFound(apiv1.Resource("pod"), "whatever")
+	})
+	// The evicted pod is never replaced.
+	scheduledPodMock := &podListerMock{}
+	scheduledPodMock.On("List").Return([]*apiv1.Pod{}, nil)
+
+	options := config.AutoscalingOptions{ReplacementPodsTimeout: 50 * time.Millisecond}
+	context := NewScaleTestAutoscalingContext(options, fakeClient, provider)
+	context.ListerRegistry = kube_util.NewListerRegistry(nil, nil, scheduledPodMock, nil, nil, nil)
+	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	sd := NewScaleDown(&context, clusterStateRegistry)
+
+	err := sd.deleteNode(n1, []*apiv1.Pod{p1})
+	assert.Error(t, err)
+	// The node is tainted for the drain and untainted after the timeout.
+	assert.Equal(t, "n1-1", getStringFromChan(updatedNodes))
+	assert.Equal(t, "n1-0", getStringFromChan(updatedNodes))
+}
+
 func TestScaleDown(t *testing.T) {
 	deletedPods := make(chan string, 10)
 	updatedNodes := make(chan string, 10)
@@ -763,9 +944,10 @@ func TestScaleDown(t *testing.T) {
 
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
 	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2, p3})
 	scaleDown.UpdateUnneededNodes([]*apiv1.Node{n1, n2},
 		[]*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2, p3}, time.Now().Add(-5*time.Minute), nil)
-	scaleDownStatus, err := scaleDown.TryToScaleDown([]*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2, p3}, nil, time.Now())
+	scaleDownStatus, err := scaleDown.TryToScaleDown([]*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2, p3}, nil, nil, time.Now())
 	waitForDeleteToFinish(t, scaleDown)
 	assert.NoError(t, err)
 	assert.Equal(t, status.ScaleDownNodeDeleteStarted, scaleDownStatus.Result)
@@ -773,6 +955,360 @@ func TestScaleDown(t *testing.T) {
 	assert.Equal(t, n1.Name, getStringFromChan(updatedNodes))
 }
 
+func TestScaleDownSkipCostlyDisruption(t *testing.T) {
+	fakeClient := &fake.Clientset{}
+
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 1000, 1000)
+	SetNodeReadyState(n2, true, time.Time{})
+
+	p1 := BuildTestPod("p1", 100, 0)
+	p1.OwnerReferences = GenerateOwnerReferences("rs", "ReplicaSet", "extensions/v1beta1", "")
+	p1.Annotations = map[string]string{drain.PodDisruptionCostKey: "100"}
+	p1.Spec.NodeName = "n1"
+	p2 := BuildTestPod("p2", 800, 0)
+	p2.Spec.NodeName = "n2"
+
+	fakeClient.Fake.AddReactor("list", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &apiv1.PodList{Items: []apiv1.Pod{*p1, *p2}}, nil
+	})
+
+	provider := testprovider.NewTestCloudProvider(nil, func(nodeGroup string, node string) error {
+		t.Errorf("Unexpected deletion of node %s", node)
+		return nil
+	})
+	provider.AddNodeGroup("ng1", 1, 10, 2)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng1", n2)
+
+	options := config.AutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.5,
+		ScaleDownUnneededTime:         time.Minute,
+		MaxGracefulTerminationSec:     60,
+		ScaleDownMaxDisruptionCost:    10,
+	}
+	context := NewScaleTestAutoscalingContext(options, fakeClient, provider)
+
+	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2})
+	scaleDown.UpdateUnneededNodes([]*apiv1.Node{n1, n2},
+		[]*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2}, time.Now().Add(-5*time.Minute), nil)
+	scaleDownStatus, err := scaleDown.TryToScaleDown([]*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2}, nil, nil, time.Now())
+	waitForDeleteToFinish(t, scaleDown)
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleDownNoNodeDeleted, scaleDownStatus.Result)
+	if assert.Equal(t, 1, len(scaleDownStatus.SkippedNodes)) {
+		assert.Equal(t, n1.Name, scaleDownStatus.SkippedNodes[0].Node.Name)
+		assert.True(t, scaleDownStatus.SkippedNodes[0].DisruptionCost >= 100)
+	}
+}
+
+func TestScaleDownUnremovableNodeReasons(t *testing.T) {
+	fakeClient := &fake.Clientset{}
+
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n2.Annotations = map[string]string{
+		ScaleDownDisabledKey: "true",
+	}
+	SetNodeReadyState(n2, true, time.Time{})
+	n3 := BuildTestNode("n3", 1000, 1000)
+	SetNodeReadyState(n3, true, time.Time{})
+
+	// p1 isn't replicated, so it blocks the drain of n1.
+	p1 := BuildTestPod("p1", 100, 0)
+	p1.Spec.NodeName = "n1"
+	p3 := BuildTestPod("p3", 800, 0)
+	p3.Spec.NodeName = "n3"
+
+	fakeClient.Fake.AddReactor("list", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &apiv1.PodList{Items: []apiv1.Pod{*p1, *p3}}, nil
+	})
+
+	provider := testprovider.NewTestCloudProvider(nil, func(nodeGroup string, node string) error {
+		t.Errorf("Unexpected deletion of node %s", node)
+		return nil
+	})
+	provider.AddNodeGroup("ng1", 1, 10, 3)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng1", n2)
+	provider.AddNode("ng1", n3)
+
+	options := config.AutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.5,
+		ScaleDownUnneededTime:         time.Minute,
+		MaxGracefulTerminationSec:     60,
+		UnremovableNodeRecheckTimeout: 5 * time.Minute,
+	}
+	context := NewScaleTestAutoscalingContext(options, fakeClient, provider)
+	fakeRecorder := context.Recorder.(*kube_record.FakeRecorder)
+
+	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+	nodes := []*apiv1.Node{n1, n2, n3}
+	pods := []*apiv1.Pod{p1, p3}
+	now := time.Now()
+
+	scaleDown.CleanUp(now)
+	initializeClusterSnapshotOrDie(t, &context, nodes, pods)
+	err := scaleDown.UpdateUnneededNodes(nodes, nodes, pods, now, nil)
+	assert.NoError(t, err)
+	scaleDownStatus, err := scaleDown.TryToScaleDown(nodes, pods, nil, nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleDownNoUnneeded, scaleDownStatus.Result)
+
+	unremovable := scaleDownStatus.UnremovableNodes
+	if assert.Equal(t, 3, len(unremovable)) {
+		assert.Equal(t, "n1", unremovable[0].Node.Name)
+		assert.Equal(t, simulator.NotReplicated, unremovable[0].Reason)
+		if assert.NotNil(t, unremovable[0].BlockingPod) {
+			assert.Equal(t, "p1", unremovable[0].BlockingPod.Pod.Name)
+			assert.Equal(t, drain.NotReplicated, unremovable[0].BlockingPod.Reason)
+		}
+		assert.Equal(t, "n2", unremovable[1].Node.Name)
+		assert.Equal(t, simulator.ScaleDownDisabledAnnotation, unremovable[1].Reason)
+		assert.Equal(t, "n3", unremovable[2].Node.Name)
+		assert.Equal(t, simulator.NotUnderutilized, unremovable[2].Reason)
+	}
+
+	// Events are recorded only for unexpected reasons, and only once per interval.
+	events := make([]string, 0)
+	for len(fakeRecorder.Events) > 0 {
+		events = append(events, <-fakeRecorder.Events)
+	}
+	assert.Equal(t, []string{
+		"Normal ScaleDownSkipped node can't be scaled down: NotReplicated: pod default/p1",
+		"Normal ScaleDownSkipped node can't be scaled down: ScaleDownDisabledAnnotation",
+	}, events)
+
+	// n1 isn't simulated again until UnremovableNodeRecheckTimeout passes.
+	later := now.Add(time.Minute)
+	scaleDown.CleanUp(later)
+	initializeClusterSnapshotOrDie(t, &context, nodes, pods)
+	err = scaleDown.UpdateUnneededNodes(nodes, nodes, pods, later, nil)
+	assert.NoError(t, err)
+	unremovable = scaleDown.getUnremovableNodes()
+	if assert.Equal(t, 3, len(unremovable)) {
+		assert.Equal(t, simulator.RecentlyUnremovable, unremovable[0].Reason)
+	}
+	assert.Equal(t, 0, len(fakeRecorder.Events))
+}
+
+func TestScaleDownUnremovableNodesCountByBlockingPodReason(t *testing.T) {
+	ownerRefs := GenerateOwnerReferences("rs", "ReplicaSet", "extensions/v1beta1", "")
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "pdb"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{PodDisruptionsAllowed: 0},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		setupPod func(pod *apiv1.Pod)
+		expected simulator.UnremovableReason
+	}{
+		{
+			name:     "not replicated",
+			setupPod: func(pod *apiv1.Pod) {},
+			expected: simulator.NotReplicated,
+		},
+		{
+			name: "local storage",
+			setupPod: func(pod *apiv1.Pod) {
+				pod.OwnerReferences = ownerRefs
+				pod.Spec.Volumes = []apiv1.Volume{{
+					Name:         "scratch",
+					VolumeSource: apiv1.VolumeSource{EmptyDir: &apiv1.EmptyDirVolumeSource{}},
+				}}
+			},
+			expected: simulator.LocalStorageRequested,
+		},
+		{
+			name: "not safe to evict",
+			setupPod: func(pod *apiv1.Pod) {
+				pod.OwnerReferences = ownerRefs
+				pod.Annotations = map[string]string{drain.PodSafeToEvictKey: "false"}
+			},
+			expected: simulator.NotSafeToEvictAnnotation,
+		},
+		{
+			name: "kube-system pod",
+			setupPod: func(pod *apiv1.Pod) {
+				pod.OwnerReferences = ownerRefs
+				pod.Namespace = "kube-system"
+			},
+			expected: simulator.UnmovableKubeSystemPod,
+		},
+		{
+			name: "pod disruption budget",
+			setupPod: func(pod *apiv1.Pod) {
+				pod.OwnerReferences = ownerRefs
+				pod.Labels = map[string]string{"app": "pdb"}
+			},
+			expected: simulator.NotEnoughPdb,
+		},
+		{
+			name: "controller not found",
+			setupPod: func(pod *apiv1.Pod) {
+				pod.OwnerReferences = ownerRefs
+			},
+			expected: simulator.ControllerNotFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := &fake.Clientset{}
+			fakeClient.Fake.AddReactor("get", "replicasets", func(action core.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.NewNotFound(apiv1.Resource("replicaset"), "rs")
+			})
+
+			n1 := BuildTestNode("n1", 1000, 1000)
+			SetNodeReadyState(n1, true, time.Time{})
+			n2 := BuildTestNode("n2", 1000, 1000)
+			SetNodeReadyState(n2, true, time.Time{})
+			p1 := BuildTestPod("p1", 100, 0)
+			p1.Spec.NodeName = "n1"
+			tc.setupPod(p1)
+
+			provider := testprovider.NewTestCloudProvider(nil, func(nodeGroup string, node string) error {
+				t.Errorf("Unexpected deletion of node %s", node)
+				return nil
+			})
+			provider.AddNodeGroup("ng1", 1, 10, 2)
+			provider.AddNode("ng1", n1)
+			provider.AddNode("ng1", n2)
+
+			options := config.AutoscalingOptions{
+				ScaleDownUtilizationThreshold: 0.5,
+				ScaleDownUnneededTime:         time.Minute,
+				MaxGracefulTerminationSec:     60,
+			}
+			context := NewScaleTestAutoscalingContext(options, fakeClient, provider)
+			clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+			scaleDown := NewScaleDown(&context, clusterStateRegistry)
+			nodes := []*apiv1.Node{n1, n2}
+			pods := []*apiv1.Pod{p1}
+			pdbs := []*policyv1.PodDisruptionBudget{pdb}
+			now := time.Now()
+
+			// The controller is only checked by the detailed drain simulation when scaling down.
+			scaleDown.CleanUp(now)
+			initializeClusterSnapshotOrDie(t, &context, nodes, pods)
+			err := scaleDown.UpdateUnneededNodes(nodes, []*apiv1.Node{n1}, pods, now.Add(-5*time.Minute), pdbs)
+			assert.NoError(t, err)
+			scaleDownStatus, err := scaleDown.TryToScaleDown(nodes, pods, pdbs, nil, now)
+			waitForDeleteToFinish(t, scaleDown)
+			assert.NoError(t, err)
+
+			assert.Equal(t, map[string]int{string(tc.expected): 1}, scaleDown.countUnremovableNodesByReason())
+			if assert.Equal(t, 1, len(scaleDownStatus.UnremovableNodes)) {
+				unremovable := scaleDownStatus.UnremovableNodes[0]
+				assert.Equal(t, "n1", unremovable.Node.Name)
+				assert.Equal(t, tc.expected, unremovable.Reason)
+				if assert.NotNil(t, unremovable.BlockingPod) {
+					assert.Equal(t, "p1", unremovable.BlockingPod.Pod.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestScaleDownParallelDrain(t *testing.T) {
+	updatedNodes := make(chan string, 10)
+	deletedNodes := make(chan string, 10)
+	fakeClient := &fake.Clientset{}
+
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 1000, 1000)
+	SetNodeReadyState(n2, true, time.Time{})
+	n3 := BuildTestNode("n3", 1000, 1000)
+	n3.Labels["target"] = "true"
+	SetNodeReadyState(n3, true, time.Time{})
+
+	// Pods from n1 and n2 can only be moved to n3, which has room for both of them.
+	ownerRefs := GenerateOwnerReferences("rs", "ReplicaSet", "extensions/v1beta1", "")
+	p1 := BuildTestPod("p1", 200, 0)
+	p1.OwnerReferences = ownerRefs
+	p1.Spec.NodeSelector = map[string]string{"target": "true"}
+	p1.Spec.NodeName = "n1"
+	p2 := BuildTestPod("p2", 200, 0)
+	p2.OwnerReferences = ownerRefs
+	p2.Spec.NodeSelector = map[string]string{"target": "true"}
+	p2.Spec.NodeName = "n2"
+	p3 := BuildTestPod("p3", 600, 0)
+	p3.Spec.NodeName = "n3"
+
+	fakeClient.Fake.AddReactor("list", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &apiv1.PodList{Items: []apiv1.Pod{*p1, *p2, *p3}}, nil
+	})
+	fakeClient.Fake.AddReactor("get", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewNotFound(apiv1.Resource("pod"), "whatever")
+	})
+	fakeClient.Fake.AddReactor("get", "nodes", func(action core.Action) (bool, runtime.Object, error) {
+		getAction := action.(core.GetAction)
+		switch getAction.GetName() {
+		case n1.Name:
+			return true, n1, nil
+		case n2.Name:
+			return true, n2, nil
+		case n3.Name:
+			return true, n3, nil
+		}
+		return true, nil, fmt.Errorf("Wrong node: %v", getAction.GetName())
+	})
+	fakeClient.Fake.AddReactor("delete", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	fakeClient.Fake.AddReactor("update", "nodes", func(action core.Action) (bool, runtime.Object, error) {
+		update := action.(core.UpdateAction)
+		obj := update.GetObject().(*apiv1.Node)
+		updatedNodes <- obj.Name
+		return true, obj, nil
+	})
+
+	provider := testprovider.NewTestCloudProvider(nil, func(nodeGroup string, node string) error {
+		deletedNodes <- node
+		return nil
+	})
+	provider.AddNodeGroup("ng1", 1, 10, 3)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng1", n2)
+	provider.AddNode("ng1", n3)
+	assert.NotNil(t, provider)
+
+	options := config.AutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.5,
+		ScaleDownUnneededTime:         time.Minute,
+		MaxGracefulTerminationSec:     60,
+		MaxDrainParallelism:           2,
+	}
+	context := NewScaleTestAutoscalingContext(options, fakeClient, provider)
+	// Draining multiple nodes at once emits more events than the default test recorder can buffer.
+	context.Recorder = kube_record.NewFakeRecorder(20)
+
+	nodes := []*apiv1.Node{n1, n2, n3}
+	pods := []*apiv1.Pod{p1, p2, p3}
+	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+	initializeClusterSnapshotOrDie(t, &context, nodes, pods)
+	scaleDown.UpdateUnneededNodes(nodes, nodes, pods, time.Now().Add(-5*time.Minute), nil)
+	scaleDownStatus, err := scaleDown.TryToScaleDown(nodes, pods, nil, nil, time.Now())
+	waitForDeleteToFinish(t, scaleDown)
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleDownNodeDeleteStarted, scaleDownStatus.Result)
+	assert.Equal(t, 2, len(scaleDownStatus.ScaledDownNodes))
+	deleted := []string{getStringFromChan(deletedNodes), getStringFromChan(deletedNodes)}
+	assertEqualSet(t, []string{n1.Name, n2.Name}, deleted)
+	assert.Equal(t, "Nothing returned", getStringFromChanImmediately(deletedNodes))
+	tainted := []string{getStringFromChan(updatedNodes), getStringFromChan(updatedNodes)}
+	assertEqualSet(t, []string{n1.Name, n2.Name}, tainted)
+}
+
 func waitForDeleteToFinish(t *testing.T, sd *ScaleDown) {
 	for start := time.Now(); time.Since(start) < 20*time.Second; time.Sleep(100 * time.Millisecond) {
 		if !sd.nodeDeleteStatus.IsDeleteInProgress() {
@@ -841,6 +1377,86 @@ func TestScaleDownEmptySingleNodeGroup(t *testing.T) {
 	simpleScaleDownEmpty(t, config)
 }
 
+func TestScaleDownEmptyBalanceSimilarNodeGroups(t *testing.T) {
+	options := defaultScaleDownOptions
+	options.BalanceSimilarNodeGroups = true
+	options.ScaleDownBalanceMaxSkew = 1
+	options.MaxEmptyBulkDelete = 2
+	config := &scaleTestConfig{
+		nodes: []nodeConfig{
+			{"n1", 1000, 1000, 0, true, "ng1"},
+			{"n2", 1000, 1000, 0, true, "ng1"},
+			{"n3", 1000, 1000, 0, true, "ng2"},
+			{"n4", 1000, 1000, 0, true, "ng2"},
+			{"n5", 1000, 1000, 0, true, "ng2"},
+		},
+		options: options,
+		// Nodes are removed from the larger group first.
+		expectedScaleDowns: []string{"n3", "n4"},
+	}
+	simpleScaleDownEmpty(t, config)
+}
+
+func TestScaleDownEmptyMostExpensiveFirst(t *testing.T) {
+	options := defaultScaleDownOptions
+	options.MaxEmptyBulkDelete = 1
+	config := &scaleTestConfig{
+		nodes: []nodeConfig{
+			{"n1", 1000, 1000, 0, true, "ng1"},
+			{"n2", 1000, 1000, 0, true, "ng1"},
+			{"n3", 1000, 1000, 0, true, "ng1"},
+		},
+		nodePrices:         map[string]float64{"n1": 1.0, "n2": 3.0, "n3": 2.0},
+		options:            options,
+		expectedScaleDowns: []string{"n2"},
+	}
+	scaleDownStatus := simpleScaleDownEmpty(t, config)
+
+	ranking := make([]string, 0, len(scaleDownStatus.CandidatesRanking))
+	for _, savings := range scaleDownStatus.CandidatesRanking {
+		ranking = append(ranking, savings.NodeName)
+	}
+	assert.Equal(t, []string{"n2", "n3", "n1"}, ranking)
+}
+
+func TestSortScaleDownCandidates(t *testing.T) {
+	balancer, nodeGroups := buildTestBalancer(t, 1, map[string]int{"ng1": 2, "ng2": 4}, "ng1", "ng2")
+
+	candidateNodeGroups := make(map[string]cloudprovider.NodeGroup)
+	var candidates []*apiv1.Node
+	for _, n := range []struct{ name, group string }{
+		{"n1", "ng1"}, {"n2", "ng1"}, {"n3", "ng1"}, {"n4", "ng1"}, {"n5", "ng1"}, {"n6", "ng2"},
+	} {
+		candidates = append(candidates, BuildTestNode(n.name, 1000, 1000))
+		candidateNodeGroups[n.name] = nodeGroups[n.group]
+	}
+	disruptionCosts := map[string]float64{"n1": 0.9, "n2": 0.5, "n3": 0.2, "n4": 1.5, "n5": 0.8, "n6": 3.0}
+	ranking := []simulator.NodeRemovalSavings{
+		{NodeName: "n4", SavingsPerPod: 10.0},
+		{NodeName: "n3", SavingsPerPod: 3.0},
+		{NodeName: "n1", SavingsPerPod: 1.0},
+		{NodeName: "n2", SavingsPerPod: 1.0},
+		{NodeName: "n6", SavingsPerPod: 0.1},
+	}
+
+	sortScaleDownCandidates(candidates, candidateNodeGroups, balancer, disruptionCosts, ranking)
+	names := make([]string, 0, len(candidates))
+	for _, node := range candidates {
+		names = append(names, node.Name)
+	}
+	// n6 keeps the groups balanced despite its cost. n4 saves the most but is in a higher disruption
+	// cost bucket. Equal savings are decided by the disruption cost, unpriced n5 follows priced nodes.
+	assert.Equal(t, []string{"n6", "n3", "n2", "n1", "n5", "n4"}, names)
+
+	// Without a balancer the disruption cost bucket comes first.
+	sortScaleDownCandidates(candidates, candidateNodeGroups, nil, disruptionCosts, ranking)
+	names = names[:0]
+	for _, node := range candidates {
+		names = append(names, node.Name)
+	}
+	assert.Equal(t, []string{"n3", "n2", "n1", "n5", "n4", "n6"}, names)
+}
+
 func TestScaleDownEmptyMinCoresLimitHit(t *testing.T) {
 	options := defaultScaleDownOptions
 	options.MinCoresTotal = 2
@@ -911,7 +1527,7 @@ func TestScaleDownEmptyMinGroupSizeLimitHit(t *testing.T) {
 	}
 	simpleScaleDownEmpty(t, config)
 }
-func simpleScaleDownEmpty(t *testing.T, config *scaleTestConfig) {
+func simpleScaleDownEmpty(t *testing.T, config *scaleTestConfig) *status.ScaleDownStatus {
 	updatedNodes := make(chan string, 10)
 	deletedNodes := make(chan string, 10)
 	fakeClient := &fake.Clientset{}
@@ -965,16 +1581,27 @@ func simpleScaleDownEmpty(t *testing.T, config *scaleTestConfig) {
 
 	resourceLimiter := context.NewResourceLimiterFromAutoscalingOptions(config.options)
 	provider.SetResourceLimiter(resourceLimiter)
+	if config.nodePrices != nil {
+		provider.SetPricingModel(&testPricingModel{nodePrices: config.nodePrices})
+	}
 
 	assert.NotNil(t, provider)
 
 	context := NewScaleTestAutoscalingContext(config.options, fakeClient, provider)
 
+	nodeInfosForGroups := make(map[string]*schedulercache.NodeInfo)
+	for name, nodesInGroup := range groups {
+		nodeInfo := schedulercache.NewNodeInfo()
+		assert.NoError(t, nodeInfo.SetNode(nodesInGroup[0]))
+		nodeInfosForGroups[name] = nodeInfo
+	}
+
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
 	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+	initializeClusterSnapshotOrDie(t, &context, nodes, []*apiv1.Pod{})
 	scaleDown.UpdateUnneededNodes(nodes,
 		nodes, []*apiv1.Pod{}, time.Now().Add(-5*time.Minute), nil)
-	scaleDownStatus, err := scaleDown.TryToScaleDown(nodes, []*apiv1.Pod{}, nil, time.Now())
+	scaleDownStatus, err := scaleDown.TryToScaleDown(nodes, []*apiv1.Pod{}, nil, nodeInfosForGroups, time.Now())
 	waitForDeleteToFinish(t, scaleDown)
 	// This helps to verify that TryToScaleDown doesn't attempt to remove anything
 	// after delete in progress status is gone.
@@ -1001,6 +1628,112 @@ func simpleScaleDownEmpty(t *testing.T, config *scaleTestConfig) {
 	}
 
 	assertEqualSet(t, config.expectedScaleDowns, deleted)
+	return scaleDownStatus
+}
+
+func TestScaleDownEmptyDryRun(t *testing.T) {
+	updatedNodes := make(chan string, 10)
+	deletedNodes := make(chan string, 10)
+	fakeClient := &fake.Clientset{}
+
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 1000, 1000)
+	SetNodeReadyState(n2, true, time.Time{})
+	nodes := []*apiv1.Node{n1, n2}
+
+	fakeClient.Fake.AddReactor("list", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &apiv1.PodList{Items: []apiv1.Pod{}}, nil
+	})
+	fakeClient.Fake.AddReactor("update", "nodes", func(action core.Action) (bool, runtime.Object, error) {
+		update := action.(core.UpdateAction)
+		obj := update.GetObject().(*apiv1.Node)
+		updatedNodes <- obj.Name
+		return true, obj, nil
+	})
+
+	provider := testprovider.NewTestCloudProvider(nil, func(nodeGroup string, node string) error {
+		deletedNodes <- node
+		return nil
+	})
+	provider.AddNodeGroup("ng1", 1, 10, 2)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng1", n2)
+
+	options := defaultScaleDownOptions
+	options.DryRun = true
+	provider.SetResourceLimiter(context.NewResourceLimiterFromAutoscalingOptions(options))
+	context := NewScaleTestAutoscalingContext(options, fakeClient, provider)
+
+	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+	initializeClusterSnapshotOrDie(t, &context, nodes, []*apiv1.Pod{})
+	scaleDown.UpdateUnneededNodes(nodes, nodes, []*apiv1.Pod{}, time.Now().Add(-5*time.Minute), nil)
+	scaleDownStatus, err := scaleDown.TryToScaleDown(nodes, []*apiv1.Pod{}, nil, nil, time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleDownNodeDeleted, scaleDownStatus.Result)
+	assert.True(t, scaleDownStatus.Simulated)
+	assert.Equal(t, 1, len(scaleDownStatus.ScaledDownNodes))
+	assert.False(t, scaleDown.nodeDeleteStatus.IsDeleteInProgress())
+	assert.Equal(t, "Nothing returned", getStringFromChanImmediately(deletedNodes))
+	assert.Equal(t, "Nothing returned", getStringFromChanImmediately(updatedNodes))
+}
+
+func TestScaleDownEmptyInBlackout(t *testing.T) {
+	deletedNodes := make(chan string, 10)
+	fakeClient := &fake.Clientset{}
+
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 1000, 1000)
+	SetNodeReadyState(n2, true, time.Time{})
+	nodes := []*apiv1.Node{n1, n2}
+
+	fakeClient.Fake.AddReactor("list", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &apiv1.PodList{Items: []apiv1.Pod{}}, nil
+	})
+
+	provider := testprovider.NewTestCloudProvider(nil, func(nodeGroup string, node string) error {
+		deletedNodes <- node
+		return nil
+	})
+	provider.AddNodeGroup("ng1", 1, 10, 2)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng1", n2)
+	// Scale down is allowed only on weekday nights.
+	provider.GetNodeGroup("ng1").(*testprovider.TestNodeGroup).SetOptions(&config.NodeGroupAutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.5,
+		ScaleDownAllowedWindows:       "0 22 * * 1-5 10h",
+	})
+
+	options := defaultScaleDownOptions
+	provider.SetResourceLimiter(context.NewResourceLimiterFromAutoscalingOptions(options))
+	context := NewScaleTestAutoscalingContext(options, fakeClient, provider)
+
+	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+
+	// Monday noon.
+	now := time.Date(2019, time.January, 7, 12, 0, 0, 0, time.UTC)
+	initializeClusterSnapshotOrDie(t, &context, nodes, []*apiv1.Pod{})
+	scaleDown.UpdateUnneededNodes(nodes, nodes, []*apiv1.Pod{}, now.Add(-5*time.Minute), nil)
+	assert.Equal(t, 2, len(scaleDown.unneededNodes))
+	scaleDownStatus, err := scaleDown.TryToScaleDown(nodes, []*apiv1.Pod{}, nil, nil, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleDownInBlackout, scaleDownStatus.Result)
+	assert.Equal(t, "Nothing returned", getStringFromChanImmediately(deletedNodes))
+
+	// Monday night.
+	now = time.Date(2019, time.January, 7, 23, 0, 0, 0, time.UTC)
+	initializeClusterSnapshotOrDie(t, &context, nodes, []*apiv1.Pod{})
+	scaleDown.UpdateUnneededNodes(nodes, nodes, []*apiv1.Pod{}, now.Add(-5*time.Minute), nil)
+	scaleDownStatus, err = scaleDown.TryToScaleDown(nodes, []*apiv1.Pod{}, nil, nil, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleDownNodeDeleted, scaleDownStatus.Result)
+	assert.NotEqual(t, "Nothing returned", getStringFromChan(deletedNodes))
 }
 
 func TestNoScaleDownUnready(t *testing.T) {
@@ -1048,9 +1781,10 @@ func TestNoScaleDownUnready(t *testing.T) {
 	// N1 is unready so it requires a bigger unneeded time.
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
 	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2}, []*apiv1.Pod{p2})
 	scaleDown.UpdateUnneededNodes([]*apiv1.Node{n1, n2},
 		[]*apiv1.Node{n1, n2}, []*apiv1.Pod{p2}, time.Now().Add(-5*time.Minute), nil)
-	scaleDownStatus, err := scaleDown.TryToScaleDown([]*apiv1.Node{n1, n2}, []*apiv1.Pod{p2}, nil, time.Now())
+	scaleDownStatus, err := scaleDown.TryToScaleDown([]*apiv1.Node{n1, n2}, []*apiv1.Pod{p2}, nil, nil, time.Now())
 	waitForDeleteToFinish(t, scaleDown)
 
 	assert.NoError(t, err)
@@ -1070,9 +1804,10 @@ func TestNoScaleDownUnready(t *testing.T) {
 	// N1 has been unready for 2 hours, ok to delete.
 	context.CloudProvider = provider
 	scaleDown = NewScaleDown(&context, clusterStateRegistry)
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2}, []*apiv1.Pod{p2})
 	scaleDown.UpdateUnneededNodes([]*apiv1.Node{n1, n2}, []*apiv1.Node{n1, n2},
 		[]*apiv1.Pod{p2}, time.Now().Add(-2*time.Hour), nil)
-	scaleDownStatus, err = scaleDown.TryToScaleDown([]*apiv1.Node{n1, n2}, []*apiv1.Pod{p2}, nil, time.Now())
+	scaleDownStatus, err = scaleDown.TryToScaleDown([]*apiv1.Node{n1, n2}, []*apiv1.Pod{p2}, nil, nil, time.Now())
 	waitForDeleteToFinish(t, scaleDown)
 
 	assert.NoError(t, err)
@@ -1147,9 +1882,10 @@ func TestScaleDownNoMove(t *testing.T) {
 
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
 	scaleDown := NewScaleDown(&context, clusterStateRegistry)
+	initializeClusterSnapshotOrDie(t, &context, []*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2})
 	scaleDown.UpdateUnneededNodes([]*apiv1.Node{n1, n2}, []*apiv1.Node{n1, n2},
 		[]*apiv1.Pod{p1, p2}, time.Now().Add(5*time.Minute), nil)
-	scaleDownStatus, err := scaleDown.TryToScaleDown([]*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2}, nil, time.Now())
+	scaleDownStatus, err := scaleDown.TryToScaleDown([]*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2}, nil, nil, time.Now())
 	waitForDeleteToFinish(t, scaleDown)
 
 	assert.NoError(t, err)
@@ -1174,6 +1910,55 @@ func getStringFromChanImmediately(c chan string) string {
 	}
 }
 
+func TestSoftTaintUnneededNodes(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 1000, 1000)
+	SetNodeReadyState(n2, true, time.Time{})
+	n3 := BuildTestNode("n3", 1000, 1000)
+	SetNodeReadyState(n3, true, time.Time{})
+	n3.Spec.Taints = []apiv1.Taint{{Key: deletetaint.DeletionCandidateTaint, Value: "1", Effect: apiv1.TaintEffectPreferNoSchedule}}
+
+	// n1 and n2 are empty, n3 is highly utilized.
+	p3 := BuildTestPod("p3", 800, 0)
+	p3.Spec.NodeName = "n3"
+
+	fakeClient := fake.NewSimpleClientset(n1, n2, n3)
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 3)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng1", n2)
+	provider.AddNode("ng1", n3)
+
+	options := config.AutoscalingOptions{
+		ScaleDownUtilizationThreshold: 0.5,
+		MaxBulkSoftTaintCount:         1,
+	}
+	context := NewScaleTestAutoscalingContext(options, fakeClient, provider)
+	clusterStateRegistry := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	sd := NewScaleDown(&context, clusterStateRegistry)
+
+	nodes := []*apiv1.Node{n1, n2, n3}
+	initializeClusterSnapshotOrDie(t, &context, nodes, []*apiv1.Pod{p3})
+	sd.UpdateUnneededNodes(nodes, nodes, []*apiv1.Pod{p3}, time.Now(), nil)
+	assert.Equal(t, 2, len(sd.unneededNodes))
+	sd.SoftTaintUnneededNodes(nodes)
+
+	tainted := 0
+	for _, node := range nodes {
+		updated, err := fakeClient.CoreV1().Nodes().Get(node.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		if deletetaint.HasDeletionCandidateTaint(updated) {
+			tainted++
+		}
+		if node.Name == "n3" {
+			assert.False(t, deletetaint.HasDeletionCandidateTaint(updated))
+		}
+	}
+	// Only one of the unneeded nodes is tainted because of the limit.
+	assert.Equal(t, 1, tainted)
+}
+
 func TestCleanToBeDeleted(t *testing.T) {
 	n1 := BuildTestNode("n1", 1000, 10)
 	n2 := BuildTestNode("n2", 1000, 10)
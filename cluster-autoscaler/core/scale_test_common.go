@@ -17,7 +17,9 @@ limitations under the License.
 package core
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
@@ -72,9 +74,25 @@ type scaleTestConfig struct {
 	scaleUpOptionToChoose  groupSizeChange   // this will be selected by assertingStrategy.BestOption
 	expectedFinalScaleUp   groupSizeChange   // we expect this to be delivered via scale-up event
 	expectedScaleDowns     []string
+	nodePrices             map[string]float64 // prices of the nodes by name, if the cloud provider has a pricing model
 	options                config.AutoscalingOptions
 }
 
+type testPricingModel struct {
+	nodePrices map[string]float64
+}
+
+func (tpm *testPricingModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	if price, found := tpm.nodePrices[node.Name]; found {
+		return price, nil
+	}
+	return 0.0, fmt.Errorf("price for node %v not found", node.Name)
+}
+
+func (tpm *testPricingModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	return 0.0, fmt.Errorf("price for pod %v not found", pod.Name)
+}
+
 // NewScaleTestAutoscalingContext creates a new test autoscaling context for scaling tests.
 func NewScaleTestAutoscalingContext(options config.AutoscalingOptions, fakeClient kube_client.Interface, provider cloudprovider.CloudProvider) context.AutoscalingContext {
 	fakeRecorder := kube_record.NewFakeRecorder(5)
@@ -91,11 +109,21 @@ func NewScaleTestAutoscalingContext(options config.AutoscalingOptions, fakeClien
 		},
 		CloudProvider:    provider,
 		PredicateChecker: simulator.NewTestPredicateChecker(),
+		ClusterSnapshot:  simulator.NewDeltaClusterSnapshot(),
 		ExpanderStrategy: random.NewStrategy(),
 		EstimatorBuilder: estimatorBuilder,
 	}
 }
 
+// initializeClusterSnapshotOrDie fills the cluster snapshot of the context with the given nodes and their
+// non expendable pods, the same way RunOnce does it at the beginning of every loop.
+func initializeClusterSnapshotOrDie(t *testing.T, context *context.AutoscalingContext, nodes []*apiv1.Node, pods []*apiv1.Pod) {
+	pods = FilterOutExpendablePods(pods, context.ExpendablePodsPriorityCutoff)
+	if err := simulator.InitializeClusterSnapshot(context.ClusterSnapshot, nodes, pods); err != nil {
+		t.Fatalf("Failed to initialize cluster snapshot: %v", err)
+	}
+}
+
 type mockAutoprovisioningNodeGroupManager struct {
 	t *testing.T
 }
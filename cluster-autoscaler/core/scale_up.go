@@ -399,7 +399,7 @@ func ScaleUp(context *context.AutoscalingContext, processors *ca_processors.Auto
 		}
 
 		if len(option.Pods) > 0 {
-			estimator := context.EstimatorBuilder(context.PredicateChecker)
+			estimator := context.EstimatorBuilder(context.PredicateChecker, context.ClusterSnapshot)
 			option.NodeCount = estimator.Estimate(option.Pods, nodeInfo, upcomingNodes)
 			if option.NodeCount > 0 {
 				expansionOptions = append(expansionOptions, option)
@@ -437,7 +437,9 @@ func ScaleUp(context *context.AutoscalingContext, processors *ca_processors.Auto
 			}
 		}
 
-		if !bestOption.NodeGroup.Exist() {
+		if !bestOption.NodeGroup.Exist() && context.DryRun {
+			klog.V(1).Infof("Dry run: not creating node group %s", bestOption.NodeGroup.Id())
+		} else if !bestOption.NodeGroup.Exist() {
 			oldId := bestOption.NodeGroup.Id()
 			createNodeGroupResult, err := processors.NodeGroupManager.CreateNodeGroup(context, bestOption.NodeGroup)
 			if err != nil {
@@ -539,7 +541,8 @@ func ScaleUp(context *context.AutoscalingContext, processors *ca_processors.Auto
 				ScaleUpInfos:            scaleUpInfos,
 				PodsRemainUnschedulable: getRemainingPods(podsRemainUnschedulable, skippedNodeGroups),
 				PodsTriggeredScaleUp:    bestOption.Pods,
-				PodsAwaitEvaluation:     getPodsAwaitingEvaluation(unschedulablePods, podsRemainUnschedulable, bestOption.Pods)},
+				PodsAwaitEvaluation:     getPodsAwaitingEvaluation(unschedulablePods, podsRemainUnschedulable, bestOption.Pods),
+				Simulated:               context.DryRun},
 			nil
 	}
 
@@ -569,7 +572,7 @@ func getPodsPredicatePassingCheckFunctions(
 
 		podsPassing := make([]*apiv1.Pod, 0)
 		podsNotPassing := make(map[*apiv1.Pod]status.Reasons)
-		schedulableOnNode := CheckPodsSchedulableOnNode(context, unschedulablePods, nodeGroupId, nodeInfo)
+		schedulableOnNode := CheckPodsSchedulableOnNode(context, podsForNodeGroup(unschedulablePods, nodeGroupId), nodeGroupId, nodeInfo)
 		for pod, err := range schedulableOnNode {
 			if err == nil {
 				podsPassing = append(podsPassing, pod)
@@ -682,10 +685,17 @@ groupsloop:
 }
 
 func executeScaleUp(context *context.AutoscalingContext, clusterStateRegistry *clusterstate.ClusterStateRegistry, info nodegroupset.ScaleUpInfo, gpuType string) errors.AutoscalerError {
+	increase := info.NewSize - info.CurrentSize
+	if context.DryRun {
+		klog.V(0).Infof("Scale-up (dry run): would set group %s size to %d", info.Group.Id(), info.NewSize)
+		context.LogRecorder.Eventf(apiv1.EventTypeNormal, "SimulatedScaleUp",
+			"Scale-up (dry run): would set group %s size to %d", info.Group.Id(), info.NewSize)
+		metrics.RegisterSimulatedScaleUp(increase)
+		return nil
+	}
 	klog.V(0).Infof("Scale-up: setting group %s size to %d", info.Group.Id(), info.NewSize)
 	context.LogRecorder.Eventf(apiv1.EventTypeNormal, "ScaledUpGroup",
 		"Scale-up: setting group %s size to %d", info.Group.Id(), info.NewSize)
-	increase := info.NewSize - info.CurrentSize
 	if err := info.Group.IncreaseSize(increase); err != nil {
 		context.LogRecorder.Eventf(apiv1.EventTypeWarning, "FailedToScaleUpGroup", "Scale-up failed for group %s: %v", info.Group.Id(), err)
 		clusterStateRegistry.RegisterFailedScaleUp(info.Group, metrics.APIError)
@@ -697,7 +707,7 @@ func executeScaleUp(context *context.AutoscalingContext, clusterStateRegistry *c
 			NodeGroup:       info.Group,
 			Increase:        increase,
 			Time:            time.Now(),
-			ExpectedAddTime: time.Now().Add(context.MaxNodeProvisionTime),
+			ExpectedAddTime: time.Now().Add(cloudprovider.GetNodeGroupOptions(info.Group, context.NodeGroupDefaults()).MaxNodeProvisionTime),
 		})
 	metrics.RegisterScaleUp(increase, gpuType)
 	context.LogRecorder.Eventf(apiv1.EventTypeNormal, "ScaledUpGroup",
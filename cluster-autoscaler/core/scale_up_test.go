@@ -599,6 +599,48 @@ func TestScaleUpNodeComingHasScale(t *testing.T) {
 	assert.Equal(t, "ng2-1", getStringFromChan(expandedGroups))
 }
 
+func TestScaleUpDryRun(t *testing.T) {
+	n1 := BuildTestNode("n1", 100, 1000)
+	SetNodeReadyState(n1, true, time.Now())
+	p1 := BuildTestPod("p1", 80, 0)
+	p1.Spec.NodeName = "n1"
+
+	fakeClient := &fake.Clientset{}
+	fakeClient.Fake.AddReactor("list", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &apiv1.PodList{Items: []apiv1.Pod{*p1}}, nil
+	})
+
+	expandedGroups := make(chan string, 10)
+	provider := testprovider.NewTestCloudProvider(func(nodeGroup string, increase int) error {
+		expandedGroups <- fmt.Sprintf("%s-%d", nodeGroup, increase)
+		return nil
+	}, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNode("ng1", n1)
+
+	options := defaultOptions
+	options.DryRun = true
+	context := NewScaleTestAutoscalingContext(options, fakeClient, provider)
+
+	nodes := []*apiv1.Node{n1}
+	nodeInfos, _ := GetNodeInfosForGroups(nodes, nil, provider, fakeClient, []*extensionsv1.DaemonSet{}, context.PredicateChecker)
+	clusterState := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, newBackoff())
+	clusterState.UpdateNodes(nodes, nodeInfos, time.Now())
+
+	p2 := BuildTestPod("p-new", 50, 0)
+
+	processors := ca_processors.TestProcessors()
+	scaleUpStatus, err := ScaleUp(&context, processors, clusterState, []*apiv1.Pod{p2}, nodes, []*extensionsv1.DaemonSet{}, nodeInfos)
+
+	assert.NoError(t, err)
+	assert.True(t, scaleUpStatus.WasSuccessful())
+	assert.True(t, scaleUpStatus.Simulated)
+	assert.Equal(t, 1, len(scaleUpStatus.ScaleUpInfos))
+	assert.Equal(t, 2, scaleUpStatus.ScaleUpInfos[0].NewSize)
+	assert.Equal(t, "Nothing returned", getStringFromChanImmediately(expandedGroups))
+	assert.Equal(t, 0, clusterState.GetUpcomingNodes()["ng1"])
+}
+
 func TestScaleUpUnhealthy(t *testing.T) {
 	n1 := BuildTestNode("n1", 100, 1000)
 	SetNodeReadyState(n1, true, time.Now())
@@ -28,6 +28,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/recording"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
@@ -67,19 +68,24 @@ type StaticAutoscaler struct {
 	initialized             bool
 	// Caches nodeInfo computed for previously seen nodes
 	nodeInfoCache map[string]*schedulercache.NodeInfo
+	// Writes inputs of every loop if loop recording is enabled.
+	loopRecorder *recording.Recorder
+	// Recording of the current loop, until it is written.
+	loopRecording *recording.LoopRecording
 }
 
 // NewStaticAutoscaler creates an instance of Autoscaler filled with provided parameters
 func NewStaticAutoscaler(
 	opts config.AutoscalingOptions,
 	predicateChecker *simulator.PredicateChecker,
+	clusterSnapshot simulator.ClusterSnapshot,
 	autoscalingKubeClients *context.AutoscalingKubeClients,
 	processors *ca_processors.AutoscalingProcessors,
 	cloudProvider cloudprovider.CloudProvider,
 	expanderStrategy expander.Strategy,
 	estimatorBuilder estimator.EstimatorBuilder,
 	backoff backoff.Backoff) *StaticAutoscaler {
-	autoscalingContext := context.NewAutoscalingContext(opts, predicateChecker, autoscalingKubeClients, cloudProvider, expanderStrategy, estimatorBuilder)
+	autoscalingContext := context.NewAutoscalingContext(opts, predicateChecker, clusterSnapshot, autoscalingKubeClients, cloudProvider, expanderStrategy, estimatorBuilder)
 
 	clusterStateConfig := clusterstate.ClusterStateRegistryConfig{
 		MaxTotalUnreadyPercentage: opts.MaxTotalUnreadyPercentage,
@@ -90,6 +96,15 @@ func NewStaticAutoscaler(
 
 	scaleDown := NewScaleDown(autoscalingContext, clusterStateRegistry)
 
+	var loopRecorder *recording.Recorder
+	if opts.RecordLoopsDir != "" {
+		var err error
+		loopRecorder, err = recording.NewRecorder(opts.RecordLoopsDir, opts.RecordLoopsMaxFiles)
+		if err != nil {
+			klog.Errorf("Failed to initialize loop recording, loops won't be recorded: %v", err)
+		}
+	}
+
 	return &StaticAutoscaler{
 		AutoscalingContext:      autoscalingContext,
 		startTime:               time.Now(),
@@ -100,10 +115,20 @@ func NewStaticAutoscaler(
 		processors:              processors,
 		clusterStateRegistry:    clusterStateRegistry,
 		nodeInfoCache:           make(map[string]*schedulercache.NodeInfo),
+		loopRecorder:            loopRecorder,
 	}
 }
 
-// cleanUpIfRequired removes ToBeDeleted taints added by a previous run of CA
+// SetStartTime sets the time the autoscaler was started at, which also delays the first scale down.
+// It's needed when loops are run for past timestamps, e.g. when replaying recordings.
+func (a *StaticAutoscaler) SetStartTime(startTime time.Time) {
+	a.startTime = startTime
+	a.lastScaleUpTime = startTime
+	a.lastScaleDownDeleteTime = startTime
+	a.lastScaleDownFailTime = startTime
+}
+
+// cleanUpIfRequired removes ToBeDeleted and DeletionCandidate taints added by a previous run of CA
 // the taints are removed only once per runtime
 func (a *StaticAutoscaler) cleanUpIfRequired() {
 	if a.initialized {
@@ -111,18 +136,63 @@ func (a *StaticAutoscaler) cleanUpIfRequired() {
 	}
 
 	// CA can die at any time. Removing taints that might have been left from the previous run.
-	if readyNodes, err := a.ReadyNodeLister().List(); err != nil {
+	// In dry-run mode the taints may belong to another autoscaler instance, so they are left alone.
+	if a.DryRun {
+		klog.V(1).Info("Dry run: not cleaning up taints")
+	} else if readyNodes, err := a.ReadyNodeLister().List(); err != nil {
 		klog.Errorf("Failed to list ready nodes, not cleaning up taints: %v", err)
 	} else {
 		cleanToBeDeleted(readyNodes, a.AutoscalingContext.ClientSet, a.Recorder)
+		cleanDeletionCandidates(readyNodes, a.AutoscalingContext.ClientSet, a.Recorder)
 	}
 	a.initialized = true
 }
 
+// recordLoop records Kubernetes inputs of the loop and makes the loop use exactly the recorded objects by
+// replacing listers until the returned function is called. Node groups are recorded, and the recording
+// written, by writeLoopRecording once the cloud provider is refreshed.
+func (a *StaticAutoscaler) recordLoop(currentTime time.Time) func() {
+	listerRegistry := a.ListerRegistry
+	loopRecording, err := recording.NewLoopRecording(currentTime, a.AutoscalingOptions, listerRegistry)
+	if err != nil {
+		klog.Errorf("Failed to record loop inputs: %v", err)
+		return func() {}
+	}
+	a.loopRecording = loopRecording
+	a.ListerRegistry = recording.NewStaticListerRegistry(loopRecording)
+	return func() {
+		// Only writes the recording if the loop ended before refreshing the cloud provider.
+		a.writeLoopRecording(false)
+		a.ListerRegistry = listerRegistry
+	}
+}
+
+// writeLoopRecording writes the recording of the current loop, if there is one not written yet.
+func (a *StaticAutoscaler) writeLoopRecording(recordNodeGroups bool) {
+	if a.loopRecording == nil {
+		return
+	}
+	loopRecording := a.loopRecording
+	a.loopRecording = nil
+	if recordNodeGroups {
+		if err := loopRecording.RecordNodeGroups(a.CloudProvider); err != nil {
+			klog.Errorf("Failed to record node groups: %v", err)
+			return
+		}
+	}
+	if err := a.loopRecorder.Write(loopRecording); err != nil {
+		klog.Errorf("Failed to write loop recording: %v", err)
+	}
+}
+
 // RunOnce iterates over node groups and scales them up/down if necessary
 func (a *StaticAutoscaler) RunOnce(currentTime time.Time) errors.AutoscalerError {
 	a.cleanUpIfRequired()
 
+	if a.loopRecorder != nil {
+		defer a.recordLoop(currentTime)()
+	}
+
 	unschedulablePodLister := a.UnschedulablePodLister()
 	scheduledPodLister := a.ScheduledPodLister()
 	pdbLister := a.PodDisruptionBudgetLister()
@@ -274,10 +344,17 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) errors.AutoscalerError
 	// Such pods don't require scale up but should be considered during scale down.
 	unschedulablePods, unschedulableWaitingForLowerPriorityPreemption := FilterOutExpendableAndSplit(unschedulablePodsWithoutTPUs, a.ExpendablePodsPriorityCutoff)
 
+	// The snapshot is built once per loop and shared by all scheduling simulations below.
+	nonExpendableScheduledPods := FilterOutExpendablePods(allScheduled, a.ExpendablePodsPriorityCutoff)
+	if err := simulator.InitializeClusterSnapshot(a.ClusterSnapshot, allNodes,
+		append(nonExpendableScheduledPods, unschedulableWaitingForLowerPriorityPreemption...)); err != nil {
+		klog.Errorf("Failed to initialize cluster snapshot: %v", err)
+		return errors.ToAutoscalerError(errors.InternalError, err)
+	}
+
 	klog.V(4).Infof("Filtering out schedulables")
 	filterOutSchedulableStart := time.Now()
-	unschedulablePodsToHelp := FilterOutSchedulable(unschedulablePods, readyNodes, allScheduled,
-		unschedulableWaitingForLowerPriorityPreemption, a.PredicateChecker, a.ExpendablePodsPriorityCutoff)
+	unschedulablePodsToHelp := FilterOutSchedulable(unschedulablePods, readyNodes, a.ClusterSnapshot, a.PredicateChecker)
 	metrics.UpdateDurationFromStart(metrics.FilterOutSchedulable, filterOutSchedulableStart)
 
 	if len(unschedulablePodsToHelp) != len(unschedulablePods) {
@@ -290,6 +367,17 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) errors.AutoscalerError
 	// finally, filter out pods that are too "young" to safely be considered for a scale-up (delay is configurable)
 	unschedulablePodsToHelp = a.filterOutYoungPods(unschedulablePodsToHelp, currentTime)
 
+	// Headroom is represented with synthetic pods. Those fitting on existing nodes are treated as scheduled,
+	// so that the nodes are not scaled down, the rest needs a scale up.
+	headroom := buildHeadroomPods(autoscalingContext, nodeInfosForGroups)
+	placedHeadroomPods, missingHeadroomPods := placeHeadroomPods(autoscalingContext, headroom, readyNodes)
+	updateHeadroomStatus(a.clusterStateRegistry, headroom, missingHeadroomPods)
+	if len(missingHeadroomPods) > 0 {
+		klog.V(1).Infof("%d headroom pods don't fit on existing nodes", len(missingHeadroomPods))
+		unschedulablePodsToHelp = append(unschedulablePodsToHelp, missingHeadroomPods...)
+	}
+	allScheduledWithHeadroom := append(allScheduled, placedHeadroomPods...)
+
 	if len(unschedulablePodsToHelp) == 0 {
 		scaleUpStatus.Result = status.ScaleUpNotNeeded
 		klog.V(1).Info("No unschedulable pods")
@@ -309,6 +397,9 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) errors.AutoscalerError
 		metrics.UpdateLastTime(metrics.ScaleUp, scaleUpStart)
 
 		scaleUpStatus, typedErr := ScaleUp(autoscalingContext, a.processors, a.clusterStateRegistry, unschedulablePodsToHelp, readyNodes, daemonsets, nodeInfosForGroups)
+		if scaleUpStatus != nil {
+			filterOutHeadroomPodsFromScaleUpStatus(scaleUpStatus)
+		}
 
 		metrics.UpdateDurationFromStart(metrics.ScaleUp, scaleUpStart)
 
@@ -342,14 +433,18 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) errors.AutoscalerError
 		klog.V(4).Infof("Calculating unneeded nodes")
 
 		scaleDown.CleanUp(currentTime)
-		potentiallyUnneeded := getPotentiallyUnneededNodes(autoscalingContext, allNodes)
+		potentiallyUnneeded, unremovable := getPotentiallyUnneededNodes(autoscalingContext, allNodes)
+		for _, unremovableNode := range unremovable {
+			scaleDown.addUnremovableNode(unremovableNode)
+		}
 
-		typedErr := scaleDown.UpdateUnneededNodes(allNodes, potentiallyUnneeded, append(allScheduled, unschedulableWaitingForLowerPriorityPreemption...), currentTime, pdbs)
+		typedErr := scaleDown.UpdateUnneededNodes(allNodes, potentiallyUnneeded, append(allScheduledWithHeadroom, unschedulableWaitingForLowerPriorityPreemption...), currentTime, pdbs)
 		if typedErr != nil {
 			scaleDownStatus.Result = status.ScaleDownError
 			klog.Errorf("Failed to scale down: %v", typedErr)
 			return typedErr
 		}
+		scaleDownStatus.UnremovableNodes = scaleDown.getUnremovableNodes()
 
 		metrics.UpdateDurationFromStart(metrics.FindUnneeded, unneededStart)
 
@@ -359,32 +454,43 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) errors.AutoscalerError
 			}
 		}
 
+		// Unneeded nodes are soft tainted, so that new pods preferably don't land on them.
+		if a.MaxBulkSoftTaintCount > 0 && !a.DryRun {
+			scaleDown.SoftTaintUnneededNodes(allNodes)
+		}
+
 		scaleDownInCooldown := scaleDownForbidden ||
 			a.lastScaleUpTime.Add(a.ScaleDownDelayAfterAdd).After(currentTime) ||
 			a.lastScaleDownFailTime.Add(a.ScaleDownDelayAfterFailure).After(currentTime) ||
 			a.lastScaleDownDeleteTime.Add(a.ScaleDownDelayAfterDelete).After(currentTime)
+		scaleDownAllowed := updateScaleDownBlackouts(autoscalingContext, a.clusterStateRegistry, currentTime)
 		// In dry run only utilization is updated
-		calculateUnneededOnly := scaleDownInCooldown || scaleDown.nodeDeleteStatus.IsDeleteInProgress()
+		calculateUnneededOnly := scaleDownInCooldown || scaleDown.nodeDeleteStatus.IsDeleteInProgress() || !scaleDownAllowed
 
 		klog.V(4).Infof("Scale down status: unneededOnly=%v lastScaleUpTime=%s "+
-			"lastScaleDownDeleteTime=%v lastScaleDownFailTime=%s scaleDownForbidden=%v isDeleteInProgress=%v",
+			"lastScaleDownDeleteTime=%v lastScaleDownFailTime=%s scaleDownForbidden=%v isDeleteInProgress=%v scaleDownAllowed=%v",
 			calculateUnneededOnly, a.lastScaleUpTime, a.lastScaleDownDeleteTime, a.lastScaleDownFailTime,
-			scaleDownForbidden, scaleDown.nodeDeleteStatus.IsDeleteInProgress())
+			scaleDownForbidden, scaleDown.nodeDeleteStatus.IsDeleteInProgress(), scaleDownAllowed)
 
 		if scaleDownInCooldown {
 			scaleDownStatus.Result = status.ScaleDownInCooldown
 		} else if scaleDown.nodeDeleteStatus.IsDeleteInProgress() {
 			scaleDownStatus.Result = status.ScaleDownInProgress
+		} else if !scaleDownAllowed {
+			klog.V(1).Infof("Scale down is outside of allowed scale down windows")
+			scaleDownStatus.Result = status.ScaleDownInBlackout
 		} else {
 			klog.V(4).Infof("Starting scale down")
 
 			// We want to delete unneeded Node Groups only if there was no recent scale up,
 			// and there is no current delete in progress and there was no recent errors.
-			a.processors.NodeGroupManager.RemoveUnneededNodeGroups(autoscalingContext)
+			if !a.DryRun {
+				a.processors.NodeGroupManager.RemoveUnneededNodeGroups(autoscalingContext)
+			}
 
 			scaleDownStart := time.Now()
 			metrics.UpdateLastTime(metrics.ScaleDown, scaleDownStart)
-			scaleDownStatus, typedErr := scaleDown.TryToScaleDown(allNodes, allScheduled, pdbs, currentTime)
+			scaleDownStatus, typedErr := scaleDown.TryToScaleDown(allNodes, allScheduledWithHeadroom, pdbs, nodeInfosForGroups, currentTime)
 			metrics.UpdateDurationFromStart(metrics.ScaleDown, scaleDownStart)
 
 			if scaleDownStatus.Result == status.ScaleDownNodeDeleted {
@@ -476,6 +582,7 @@ func (a *StaticAutoscaler) updateClusterState(allNodes []*apiv1.Node, nodeInfosF
 		klog.Errorf("Failed to refresh cloud provider config: %v", err)
 		return errors.ToAutoscalerError(errors.CloudProviderError, err)
 	}
+	a.writeLoopRecording(true)
 
 	err = a.clusterStateRegistry.UpdateNodes(allNodes, nodeInfosForGroups, currentTime)
 	if err != nil {
@@ -17,6 +17,8 @@ limitations under the License.
 package core
 
 import (
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 	"time"
@@ -26,6 +28,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/recording"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
@@ -681,3 +684,50 @@ func TestStaticAutoscalerRunOncePodsWithPriorities(t *testing.T) {
 		podDisruptionBudgetListerMock, daemonSetListerMock, onScaleUpMock, onScaleDownMock)
 
 }
+
+func TestStaticAutoscalerRecordLoop(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Now())
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNode("ng1", n1)
+
+	dir, err := ioutil.TempDir("", "loop-recordings")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	loopRecorder, err := recording.NewRecorder(dir, 0)
+	assert.NoError(t, err)
+
+	context := NewScaleTestAutoscalingContext(config.AutoscalingOptions{}, &fake.Clientset{}, provider)
+	listerRegistry := recording.NewStaticListerRegistry(&recording.LoopRecording{
+		AllNodes:   []*apiv1.Node{n1},
+		ReadyNodes: []*apiv1.Node{n1},
+	})
+	context.ListerRegistry = listerRegistry
+	autoscaler := &StaticAutoscaler{
+		AutoscalingContext: &context,
+		loopRecorder:       loopRecorder,
+	}
+
+	finishRecording := autoscaler.recordLoop(time.Now())
+	assert.NotEqual(t, listerRegistry, autoscaler.ListerRegistry)
+	paths, err := recording.ListRecordings(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+
+	// Node groups are recorded as seen after the cloud provider is refreshed.
+	provider.AddNodeGroup("ng2", 0, 10, 0)
+	autoscaler.writeLoopRecording(true)
+	finishRecording()
+	assert.Equal(t, listerRegistry, autoscaler.ListerRegistry)
+
+	paths, err = recording.ListRecordings(dir)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(paths)) {
+		loopRecording, err := recording.ReadRecording(paths[0])
+		assert.NoError(t, err)
+		if assert.Equal(t, 2, len(loopRecording.NodeGroups)) {
+			assert.Equal(t, "ng2", loopRecording.NodeGroups[1].Id)
+		}
+	}
+}
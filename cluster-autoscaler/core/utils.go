@@ -25,6 +25,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
@@ -35,6 +36,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/utils/glogx"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
 
 	apiv1 "k8s.io/api/core/v1"
@@ -108,13 +110,18 @@ func (podMap podSchedulableMap) set(pod *apiv1.Pod, err *simulator.PredicateErro
 }
 
 // FilterOutSchedulable checks whether pods from <unschedulableCandidates> marked as unschedulable
-// by Scheduler actually can't be scheduled on any node and filter out the ones that can.
-// It takes into account pods that are bound to node and will be scheduled after lower priority pod preemption.
-func FilterOutSchedulable(unschedulableCandidates []*apiv1.Pod, nodes []*apiv1.Node, allScheduled []*apiv1.Pod, podsWaitingForLowerPriorityPreemption []*apiv1.Pod,
-	predicateChecker *simulator.PredicateChecker, expendablePodsPriorityCutoff int) []*apiv1.Pod {
+// by Scheduler actually can't be scheduled on any of the given nodes and filter out the ones that can.
+// Node state is read from clusterSnapshot, which is expected to already contain scheduled pods as well as
+// pods that are bound to node and will be scheduled after lower priority pod preemption.
+func FilterOutSchedulable(unschedulableCandidates []*apiv1.Pod, nodes []*apiv1.Node, clusterSnapshot simulator.ClusterSnapshot,
+	predicateChecker *simulator.PredicateChecker) []*apiv1.Pod {
 	unschedulablePods := []*apiv1.Pod{}
-	nonExpendableScheduled := FilterOutExpendablePods(allScheduled, expendablePodsPriorityCutoff)
-	nodeNameToNodeInfo := scheduler_util.CreateNodeNameToInfoMap(append(nonExpendableScheduled, podsWaitingForLowerPriorityPreemption...), nodes)
+	nodeNameToNodeInfo := make(map[string]*schedulercache.NodeInfo, len(nodes))
+	for _, node := range nodes {
+		if nodeInfo, found := clusterSnapshot.GetNodeInfo(node.Name); found {
+			nodeNameToNodeInfo[node.Name] = nodeInfo
+		}
+	}
 	podSchedulable := make(podSchedulableMap)
 	loggingQuota := glogx.PodsLoggingQuota()
 
@@ -215,6 +222,7 @@ func CheckPodsSchedulableOnNode(context *context.AutoscalingContext, pods []*api
 }
 
 // GetNodeInfosForGroups finds NodeInfos for all node groups used to manage the given nodes. It also returns a node group to sample node mapping.
+// The returned NodeInfos are shared with nodeInfoCache and must be treated as read-only.
 // TODO(mwielgus): This returns map keyed by url, while most code (including scheduler) uses node.Name for a key.
 //
 // TODO(mwielgus): Review error policy - sometimes we may continue with partial errors.
@@ -259,9 +267,7 @@ func GetNodeInfosForGroups(nodes []*apiv1.Node, nodeInfoCache map[string]*schedu
 			return map[string]*schedulercache.NodeInfo{}, typedErr
 		}
 		if added && nodeInfoCache != nil {
-			if nodeInfoCopy, err := deepCopyNodeInfo(result[id]); err == nil {
-				nodeInfoCache[id] = nodeInfoCopy
-			}
+			nodeInfoCache[id] = result[id]
 		}
 	}
 	for _, nodeGroup := range cloudProvider.NodeGroups() {
@@ -274,10 +280,8 @@ func GetNodeInfosForGroups(nodes []*apiv1.Node, nodeInfoCache map[string]*schedu
 		// No good template, check cache of previously running nodes.
 		if nodeInfoCache != nil {
 			if nodeInfo, found := nodeInfoCache[id]; found {
-				if nodeInfoCopy, err := deepCopyNodeInfo(nodeInfo); err == nil {
-					result[id] = nodeInfoCopy
-					continue
-				}
+				result[id] = nodeInfo
+				continue
 			}
 		}
 
@@ -360,20 +364,6 @@ func FilterOutNodesFromNotAutoscaledGroups(nodes []*apiv1.Node, cloudProvider cl
 	return result, nil
 }
 
-func deepCopyNodeInfo(nodeInfo *schedulercache.NodeInfo) (*schedulercache.NodeInfo, errors.AutoscalerError) {
-	newPods := make([]*apiv1.Pod, 0)
-	for _, pod := range nodeInfo.Pods() {
-		newPods = append(newPods, pod.DeepCopy())
-	}
-
-	// Build a new node info.
-	newNodeInfo := schedulercache.NewNodeInfo(newPods...)
-	if err := newNodeInfo.SetNode(nodeInfo.Node().DeepCopy()); err != nil {
-		return nil, errors.ToAutoscalerError(errors.InternalError, err)
-	}
-	return newNodeInfo, nil
-}
-
 func sanitizeNodeInfo(nodeInfo *schedulercache.NodeInfo, nodeGroupName string) (*schedulercache.NodeInfo, errors.AutoscalerError) {
 	// Sanitize node name.
 	sanitizedNode, err := sanitizeTemplateNode(nodeInfo.Node(), nodeGroupName)
@@ -417,8 +407,8 @@ func sanitizeTemplateNode(node *apiv1.Node, nodeGroup string) (*apiv1.Node, erro
 		switch taint.Key {
 		case ReschedulerTaintKey:
 			klog.V(4).Infof("Removing rescheduler taint when creating template from node %s", node.Name)
-		case deletetaint.ToBeDeletedTaint:
-			klog.V(4).Infof("Removing autoscaler taint when creating template from node %s", node.Name)
+		case deletetaint.ToBeDeletedTaint, deletetaint.DeletionCandidateTaint:
+			klog.V(4).Infof("Removing autoscaler taint %s when creating template from node %s", taint.Key, node.Name)
 		default:
 			newTaints = append(newTaints, taint)
 		}
@@ -432,7 +422,9 @@ func removeOldUnregisteredNodes(unregisteredNodes []clusterstate.UnregisteredNod
 	currentTime time.Time, logRecorder *utils.LogEventRecorder) (bool, error) {
 	removedAny := false
 	for _, unregisteredNode := range unregisteredNodes {
-		if unregisteredNode.UnregisteredSince.Add(context.MaxNodeProvisionTime).Before(currentTime) {
+		// Nodes that failed to be created won't register, so they are removed right away.
+		if unregisteredNode.ErrorInfo != nil ||
+			unregisteredNode.UnregisteredSince.Add(nodeGroupOptionsForNode(context, unregisteredNode.Node).MaxNodeProvisionTime).Before(currentTime) {
 			klog.V(0).Infof("Removing unregistered node %v", unregisteredNode.Node.Name)
 			nodeGroup, err := context.CloudProvider.NodeGroupForNode(unregisteredNode.Node)
 			if err != nil {
@@ -452,6 +444,12 @@ func removeOldUnregisteredNodes(unregisteredNodes []clusterstate.UnregisteredNod
 				klog.Warningf("Failed to remove node %s: node group min size reached, skipping unregistered node removal", unregisteredNode.Node.Name)
 				continue
 			}
+			if context.DryRun {
+				klog.V(0).Infof("Dry run: would remove unregistered node %v", unregisteredNode.Node.Name)
+				logRecorder.Eventf(apiv1.EventTypeNormal, "SimulatedDeleteUnregistered",
+					"Would remove unregistered node %v (dry run)", unregisteredNode.Node.Name)
+				continue
+			}
 			err = nodeGroup.DeleteNodes([]*apiv1.Node{unregisteredNode.Node})
 			if err != nil {
 				klog.Warningf("Failed to remove node %s: %v", unregisteredNode.Node.Name, err)
@@ -467,6 +465,64 @@ func removeOldUnregisteredNodes(unregisteredNodes []clusterstate.UnregisteredNod
 	return removedAny, nil
 }
 
+// nodeGroupOptionsForNode returns autoscaling options of the node group the given node belongs to.
+// Global defaults are returned if the node group cannot be determined.
+func nodeGroupOptionsForNode(context *context.AutoscalingContext, node *apiv1.Node) config.NodeGroupAutoscalingOptions {
+	defaults := context.NodeGroupDefaults()
+	nodeGroup, err := context.CloudProvider.NodeGroupForNode(node)
+	if err != nil || nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
+		return defaults
+	}
+	return cloudprovider.GetNodeGroupOptions(nodeGroup, defaults)
+}
+
+// scaleDownAllowedAt checks whether scale down is allowed at the given time according to the given
+// scale down windows. If it's not allowed, the start of the next allowed window is returned as well
+// (zero if unknown).
+func scaleDownAllowedAt(windows *schedule.Schedule, now time.Time) (bool, time.Time) {
+	if windows.Contains(now) {
+		return true, now
+	}
+	return false, windows.Next(now)
+}
+
+// nodeGroupScaleDownAllowedAt is scaleDownAllowedAt for the scale down windows of a node group. Cloud
+// providers parse the windows when they read node group options. Windows of options without a parsed
+// schedule are parsed here, scale down is never allowed if they are invalid.
+func nodeGroupScaleDownAllowedAt(options config.NodeGroupAutoscalingOptions, now time.Time) (bool, time.Time) {
+	windows := options.ScaleDownAllowedSchedule
+	if windows == nil {
+		var err error
+		if windows, err = schedule.Parse(options.ScaleDownAllowedWindows); err != nil {
+			klog.Warningf("Invalid scale down windows %q, scale down is not allowed: %v", options.ScaleDownAllowedWindows, err)
+			return false, time.Time{}
+		}
+	}
+	return scaleDownAllowedAt(windows, now)
+}
+
+// updateScaleDownBlackouts records in the cluster state registry which node groups, if any, are
+// outside of their scale down windows. Returns false if scale down is not allowed in the whole cluster.
+func updateScaleDownBlackouts(context *context.AutoscalingContext, clusterStateRegistry *clusterstate.ClusterStateRegistry, now time.Time) bool {
+	var clusterwide *clusterstate.ScaleDownBlackout
+	allowed := true
+	if context.ScaleDownAllowedSchedule != nil {
+		var next time.Time
+		if allowed, next = scaleDownAllowedAt(context.ScaleDownAllowedSchedule, now); !allowed {
+			clusterwide = &clusterstate.ScaleDownBlackout{NextAllowed: next}
+		}
+	}
+	perNodeGroup := make(map[string]clusterstate.ScaleDownBlackout)
+	for _, nodeGroup := range context.CloudProvider.NodeGroups() {
+		options := cloudprovider.GetNodeGroupOptions(nodeGroup, context.NodeGroupDefaults())
+		if ngAllowed, ngNext := nodeGroupScaleDownAllowedAt(options, now); !ngAllowed {
+			perNodeGroup[nodeGroup.Id()] = clusterstate.ScaleDownBlackout{NextAllowed: ngNext}
+		}
+	}
+	clusterStateRegistry.UpdateScaleDownBlackouts(clusterwide, perNodeGroup)
+	return allowed
+}
+
 // Sets the target size of node groups to the current number of nodes in them
 // if the difference was constant for a prolonged time. Returns true if managed
 // to fix something.
@@ -477,13 +533,20 @@ func fixNodeGroupSize(context *context.AutoscalingContext, clusterStateRegistry
 		if incorrectSize == nil {
 			continue
 		}
-		if incorrectSize.FirstObserved.Add(context.MaxNodeProvisionTime).Before(currentTime) {
+		maxNodeProvisionTime := cloudprovider.GetNodeGroupOptions(nodeGroup, context.NodeGroupDefaults()).MaxNodeProvisionTime
+		if incorrectSize.FirstObserved.Add(maxNodeProvisionTime).Before(currentTime) {
 			delta := incorrectSize.CurrentSize - incorrectSize.ExpectedSize
 			if delta < 0 {
 				klog.V(0).Infof("Decreasing size of %s, expected=%d current=%d delta=%d", nodeGroup.Id(),
 					incorrectSize.ExpectedSize,
 					incorrectSize.CurrentSize,
 					delta)
+				if context.DryRun {
+					klog.V(0).Infof("Dry run: not decreasing size of %s", nodeGroup.Id())
+					context.LogRecorder.Eventf(apiv1.EventTypeNormal, "SimulatedFixNodeGroupSize",
+						"Would decrease size of %s by %d (dry run)", nodeGroup.Id(), -delta)
+					continue
+				}
 				if err := nodeGroup.DecreaseTargetSize(delta); err != nil {
 					return fixed, fmt.Errorf("Failed to decrease %s: %v", nodeGroup.Id(), err)
 				}
@@ -497,8 +560,9 @@ func fixNodeGroupSize(context *context.AutoscalingContext, clusterStateRegistry
 // getPotentiallyUnneededNodes returns nodes that are:
 // - managed by the cluster autoscaler
 // - in groups with size > min size
-func getPotentiallyUnneededNodes(context *context.AutoscalingContext, nodes []*apiv1.Node) []*apiv1.Node {
+func getPotentiallyUnneededNodes(context *context.AutoscalingContext, nodes []*apiv1.Node) ([]*apiv1.Node, []*simulator.UnremovableNode) {
 	result := make([]*apiv1.Node, 0, len(nodes))
+	unremovable := make([]*simulator.UnremovableNode, 0)
 
 	nodeGroupSize := getNodeGroupSizeMap(context.CloudProvider)
 
@@ -506,24 +570,28 @@ func getPotentiallyUnneededNodes(context *context.AutoscalingContext, nodes []*a
 		nodeGroup, err := context.CloudProvider.NodeGroupForNode(node)
 		if err != nil {
 			klog.Warningf("Error while checking node group for %s: %v", node.Name, err)
+			unremovable = append(unremovable, &simulator.UnremovableNode{Node: node, Reason: simulator.UnexpectedError})
 			continue
 		}
 		if nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
 			klog.V(4).Infof("Skipping %s - no node group config", node.Name)
+			unremovable = append(unremovable, &simulator.UnremovableNode{Node: node, Reason: simulator.NotAutoscaled})
 			continue
 		}
 		size, found := nodeGroupSize[nodeGroup.Id()]
 		if !found {
 			klog.Errorf("Error while checking node group size %s: group size not found", nodeGroup.Id())
+			unremovable = append(unremovable, &simulator.UnremovableNode{Node: node, Reason: simulator.UnexpectedError})
 			continue
 		}
 		if size <= nodeGroup.MinSize() {
 			klog.V(1).Infof("Skipping %s - node group min size reached", node.Name)
+			unremovable = append(unremovable, &simulator.UnremovableNode{Node: node, Reason: simulator.NodeGroupMinSizeReached})
 			continue
 		}
 		result = append(result, node)
 	}
-	return result
+	return result, unremovable
 }
 
 func hasHardInterPodAffinity(affinity *apiv1.Affinity) bool {
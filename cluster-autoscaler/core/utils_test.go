@@ -28,6 +28,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/deletetaint"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 
@@ -172,25 +173,82 @@ func TestFilterOutSchedulable(t *testing.T) {
 	SetNodeReadyState(node, true, time.Time{})
 
 	predicateChecker := simulator.NewTestPredicateChecker()
+	nodes := []*apiv1.Node{node}
+	clusterSnapshot := simulator.NewDeltaClusterSnapshot()
 
-	res := FilterOutSchedulable(unschedulablePods, []*apiv1.Node{node}, []*apiv1.Pod{scheduledPod1, scheduledPod3}, []*apiv1.Pod{}, predicateChecker, 10)
+	err := simulator.InitializeClusterSnapshot(clusterSnapshot, nodes, FilterOutExpendablePods([]*apiv1.Pod{scheduledPod1, scheduledPod3}, 10))
+	assert.NoError(t, err)
+	res := FilterOutSchedulable(unschedulablePods, nodes, clusterSnapshot, predicateChecker)
 	assert.Equal(t, 2, len(res))
 	assert.Equal(t, p2_1, res[0])
 	assert.Equal(t, p2_2, res[1])
 
-	res2 := FilterOutSchedulable(unschedulablePods, []*apiv1.Node{node}, []*apiv1.Pod{scheduledPod1, scheduledPod2, scheduledPod3}, []*apiv1.Pod{}, predicateChecker, 10)
+	err = simulator.InitializeClusterSnapshot(clusterSnapshot, nodes, FilterOutExpendablePods([]*apiv1.Pod{scheduledPod1, scheduledPod2, scheduledPod3}, 10))
+	assert.NoError(t, err)
+	res2 := FilterOutSchedulable(unschedulablePods, nodes, clusterSnapshot, predicateChecker)
 	assert.Equal(t, 3, len(res2))
 	assert.Equal(t, p1, res2[0])
 	assert.Equal(t, p2_1, res2[1])
 	assert.Equal(t, p2_2, res2[2])
 
-	res3 := FilterOutSchedulable(unschedulablePods, []*apiv1.Node{node}, []*apiv1.Pod{scheduledPod1, scheduledPod3}, []*apiv1.Pod{podWaitingForPreemption}, predicateChecker, 10)
+	err = simulator.InitializeClusterSnapshot(clusterSnapshot, nodes, append(FilterOutExpendablePods([]*apiv1.Pod{scheduledPod1, scheduledPod3}, 10), podWaitingForPreemption))
+	assert.NoError(t, err)
+	res3 := FilterOutSchedulable(unschedulablePods, nodes, clusterSnapshot, predicateChecker)
 	assert.Equal(t, 3, len(res3))
 	assert.Equal(t, p1, res3[0])
 	assert.Equal(t, p2_1, res3[1])
 	assert.Equal(t, p2_2, res3[2])
 }
 
+func BenchmarkFilterOutSchedulable(b *testing.B) {
+	const (
+		nodeCount          = 1000
+		podsPerNode        = 10
+		unschedulableCount = 100
+	)
+	nodes := make([]*apiv1.Node, 0, nodeCount)
+	scheduled := make([]*apiv1.Pod, 0, nodeCount*podsPerNode)
+	for i := 0; i < nodeCount; i++ {
+		node := BuildTestNode(fmt.Sprintf("n%d", i), 2000, 2000000)
+		SetNodeReadyState(node, true, time.Time{})
+		nodes = append(nodes, node)
+		for j := 0; j < podsPerNode; j++ {
+			pod := BuildTestPod(fmt.Sprintf("p%d-%d", i, j), 200, 200000)
+			pod.Spec.NodeName = node.Name
+			scheduled = append(scheduled, pod)
+		}
+	}
+	// None of the pods fits, so every one of them is checked against all nodes.
+	unschedulable := make([]*apiv1.Pod, 0, unschedulableCount)
+	for i := 0; i < unschedulableCount; i++ {
+		unschedulable = append(unschedulable, BuildTestPod(fmt.Sprintf("u%d", i), 100, 200000))
+	}
+	predicateChecker := simulator.NewTestPredicateChecker()
+
+	// Before node infos were built from scratch in every call, which is what
+	// initializing a snapshot for every call amounts to.
+	b.Run("node infos per call", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			clusterSnapshot := simulator.NewDeltaClusterSnapshot()
+			if err := simulator.InitializeClusterSnapshot(clusterSnapshot, nodes, scheduled); err != nil {
+				b.Fatal(err)
+			}
+			FilterOutSchedulable(unschedulable, nodes, clusterSnapshot, predicateChecker)
+		}
+	})
+	// Now the snapshot is built once per loop and shared with the rest of the loop.
+	b.Run("shared cluster snapshot", func(b *testing.B) {
+		clusterSnapshot := simulator.NewDeltaClusterSnapshot()
+		if err := simulator.InitializeClusterSnapshot(clusterSnapshot, nodes, scheduled); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			FilterOutSchedulable(unschedulable, nodes, clusterSnapshot, predicateChecker)
+		}
+	})
+}
+
 func TestFilterOutExpendableAndSplit(t *testing.T) {
 	var priority1 int32 = 1
 	var priority100 int32 = 100
@@ -536,6 +594,49 @@ func TestRemoveOldUnregisteredNodes(t *testing.T) {
 	assert.Equal(t, "ng1/ng1-2", deletedNode)
 }
 
+func TestRemoveOldUnregisteredNodesDryRun(t *testing.T) {
+	deletedNodes := make(chan string, 10)
+
+	now := time.Now()
+
+	ng1_1 := BuildTestNode("ng1-1", 1000, 1000)
+	ng1_1.Spec.ProviderID = "ng1-1"
+	ng1_2 := BuildTestNode("ng1-2", 1000, 1000)
+	ng1_2.Spec.ProviderID = "ng1-2"
+	provider := testprovider.NewTestCloudProvider(nil, func(nodegroup string, node string) error {
+		deletedNodes <- fmt.Sprintf("%s/%s", nodegroup, node)
+		return nil
+	})
+	provider.AddNodeGroup("ng1", 1, 10, 2)
+	provider.AddNode("ng1", ng1_1)
+	provider.AddNode("ng1", ng1_2)
+
+	fakeClient := &fake.Clientset{}
+	fakeLogRecorder, _ := utils.NewStatusMapRecorder(fakeClient, "kube-system", kube_record.NewFakeRecorder(5), false)
+	clusterState := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{
+		MaxTotalUnreadyPercentage: 10,
+		OkTotalUnreadyCount:       1,
+	}, fakeLogRecorder, newBackoff())
+	err := clusterState.UpdateNodes([]*apiv1.Node{ng1_1}, nil, now.Add(-time.Hour))
+	assert.NoError(t, err)
+
+	context := &context.AutoscalingContext{
+		AutoscalingOptions: config.AutoscalingOptions{
+			MaxNodeProvisionTime: 45 * time.Minute,
+			DryRun:               true,
+		},
+		CloudProvider: provider,
+	}
+	unregisteredNodes := clusterState.GetUnregisteredNodes()
+	assert.Equal(t, 1, len(unregisteredNodes))
+
+	// ng1_2 is old enough, but nothing should be removed in dry run.
+	removed, err := removeOldUnregisteredNodes(unregisteredNodes, context, now, fakeLogRecorder)
+	assert.NoError(t, err)
+	assert.False(t, removed)
+	assert.Equal(t, "Nothing returned", getStringFromChanImmediately(deletedNodes))
+}
+
 func TestSanitizeNodeInfo(t *testing.T) {
 	pod := BuildTestPod("p1", 80, 0)
 	pod.Spec.NodeName = "n1"
@@ -582,6 +683,11 @@ func TestSanitizeTaints(t *testing.T) {
 		Value:  "1",
 		Effect: apiv1.TaintEffectNoSchedule,
 	})
+	taints = append(taints, apiv1.Taint{
+		Key:    deletetaint.DeletionCandidateTaint,
+		Value:  "1",
+		Effect: apiv1.TaintEffectPreferNoSchedule,
+	})
 	oldNode.Spec.Taints = taints
 	node, err := sanitizeTemplateNode(oldNode, "bzium")
 	assert.NoError(t, err)
@@ -631,6 +737,38 @@ func TestRemoveFixNodeTargetSize(t *testing.T) {
 	assert.Equal(t, "ng1/-2", change)
 }
 
+func TestNodeGroupScaleDownAllowedAt(t *testing.T) {
+	// Monday 12:00, outside of the 22:00-08:00 weekday window.
+	noon := time.Date(2019, 3, 4, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2019, 3, 4, 22, 0, 0, 0, time.UTC)
+
+	windows, err := schedule.Parse("0 22 * * 1-5 10h")
+	assert.NoError(t, err)
+	options := config.NodeGroupAutoscalingOptions{
+		ScaleDownAllowedWindows:  "0 22 * * 1-5 10h",
+		ScaleDownAllowedSchedule: windows,
+	}
+	allowed, next := nodeGroupScaleDownAllowedAt(options, noon)
+	assert.False(t, allowed)
+	assert.Equal(t, night, next)
+	allowed, _ = nodeGroupScaleDownAllowedAt(options, night)
+	assert.True(t, allowed)
+
+	// The parsed schedule is used, the windows are not parsed again.
+	options.ScaleDownAllowedWindows = "invalid"
+	allowed, _ = nodeGroupScaleDownAllowedAt(options, night)
+	assert.True(t, allowed)
+
+	// Options without a parsed schedule are parsed, invalid windows never allow scale down.
+	allowed, next = nodeGroupScaleDownAllowedAt(config.NodeGroupAutoscalingOptions{ScaleDownAllowedWindows: "0 22 * * 1-5 10h"}, noon)
+	assert.False(t, allowed)
+	assert.Equal(t, night, next)
+	allowed, _ = nodeGroupScaleDownAllowedAt(config.NodeGroupAutoscalingOptions{ScaleDownAllowedWindows: "invalid"}, night)
+	assert.False(t, allowed)
+	allowed, _ = nodeGroupScaleDownAllowedAt(config.NodeGroupAutoscalingOptions{}, noon)
+	assert.True(t, allowed)
+}
+
 func TestGetPotentiallyUnneededNodes(t *testing.T) {
 	ng1_1 := BuildTestNode("ng1-1", 1000, 1000)
 	ng1_2 := BuildTestNode("ng1-2", 1000, 1000)
@@ -647,11 +785,15 @@ func TestGetPotentiallyUnneededNodes(t *testing.T) {
 		CloudProvider: provider,
 	}
 
-	result := getPotentiallyUnneededNodes(context, []*apiv1.Node{ng1_1, ng1_2, ng2_1, noNg})
+	result, unremovable := getPotentiallyUnneededNodes(context, []*apiv1.Node{ng1_1, ng1_2, ng2_1, noNg})
 	assert.Equal(t, 2, len(result))
 	ok1 := result[0].Name == "ng1-1" && result[1].Name == "ng1-2"
 	ok2 := result[1].Name == "ng1-1" && result[0].Name == "ng1-2"
 	assert.True(t, ok1 || ok2)
+	assert.Equal(t, []*simulator.UnremovableNode{
+		{Node: ng2_1, Reason: simulator.NodeGroupMinSizeReached},
+		{Node: noNg, Reason: simulator.NotAutoscaled},
+	}, unremovable)
 }
 
 func TestConfigurePredicateCheckerForLoop(t *testing.T) {
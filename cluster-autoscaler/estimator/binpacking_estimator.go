@@ -17,11 +17,13 @@ limitations under the License.
 package estimator
 
 import (
+	"fmt"
 	"sort"
 
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/klog"
 	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
 )
 
@@ -34,12 +36,15 @@ type podInfo struct {
 // BinpackingNodeEstimator estimates the number of needed nodes to handle the given amount of pods.
 type BinpackingNodeEstimator struct {
 	predicateChecker *simulator.PredicateChecker
+	clusterSnapshot  simulator.ClusterSnapshot
 }
 
-// NewBinpackingNodeEstimator builds a new BinpackingNodeEstimator.
-func NewBinpackingNodeEstimator(predicateChecker *simulator.PredicateChecker) *BinpackingNodeEstimator {
+// NewBinpackingNodeEstimator builds a new BinpackingNodeEstimator. Nodes are simulated in a fork
+// of clusterSnapshot, which is reverted after every estimation.
+func NewBinpackingNodeEstimator(predicateChecker *simulator.PredicateChecker, clusterSnapshot simulator.ClusterSnapshot) *BinpackingNodeEstimator {
 	return &BinpackingNodeEstimator{
 		predicateChecker: predicateChecker,
+		clusterSnapshot:  clusterSnapshot,
 	}
 }
 
@@ -56,32 +61,62 @@ func (estimator *BinpackingNodeEstimator) Estimate(pods []*apiv1.Pod, nodeTempla
 	podInfos := calculatePodScore(pods, nodeTemplate)
 	sort.Slice(podInfos, func(i, j int) bool { return podInfos[i].score > podInfos[j].score })
 
-	// nodeWithPod function returns NodeInfo, which is a copy of nodeInfo argument with an additional pod scheduled on it.
-	nodeWithPod := func(nodeInfo *schedulercache.NodeInfo, pod *apiv1.Pod) *schedulercache.NodeInfo {
-		podsOnNode := nodeInfo.Pods()
-		podsOnNode = append(podsOnNode, pod)
-		newNodeInfo := schedulercache.NewNodeInfo(podsOnNode...)
-		newNodeInfo.SetNode(nodeInfo.Node())
-		return newNodeInfo
+	clusterSnapshot := estimator.clusterSnapshot
+	if err := clusterSnapshot.Fork(); err != nil {
+		klog.Errorf("Error while forking cluster snapshot: %v", err)
+		return 0
+	}
+	defer func() {
+		if err := clusterSnapshot.Revert(); err != nil {
+			klog.Errorf("Error while reverting cluster snapshot: %v", err)
+		}
+	}()
+	// Names of nodes added to the snapshot, in the order in which they are tried. Only these
+	// nodes are considered, the rest of the snapshot is the existing cluster.
+	newNodeNames := make([]string, 0, len(upcomingNodes))
+
+	// addNode adds a copy of the given NodeInfo to the snapshot under a unique name, as upcoming
+	// nodes and nodes created from the template may share node names.
+	addNode := func(nodeInfo *schedulercache.NodeInfo) (string, error) {
+		node := *nodeInfo.Node()
+		node.Name = fmt.Sprintf("%s-estimator-%d", node.Name, len(newNodeNames))
+		if err := clusterSnapshot.AddNodeWithPods(&node, nodeInfo.Pods()); err != nil {
+			return "", err
+		}
+		newNodeNames = append(newNodeNames, node.Name)
+		return node.Name, nil
 	}
 
-	newNodes := make([]*schedulercache.NodeInfo, 0)
-	newNodes = append(newNodes, upcomingNodes...)
+	for _, nodeInfo := range upcomingNodes {
+		if _, err := addNode(nodeInfo); err != nil {
+			klog.Errorf("Error while adding upcoming node to cluster snapshot: %v", err)
+		}
+	}
+	upcomingCount := len(newNodeNames)
 
 	for _, podInfo := range podInfos {
 		found := false
-		for i, nodeInfo := range newNodes {
+		for _, nodeName := range newNodeNames {
+			nodeInfo, _ := clusterSnapshot.GetNodeInfo(nodeName)
 			if err := estimator.predicateChecker.CheckPredicates(podInfo.pod, nil, nodeInfo); err == nil {
 				found = true
-				newNodes[i] = nodeWithPod(nodeInfo, podInfo.pod)
+				if err := clusterSnapshot.AddPod(podInfo.pod, nodeName); err != nil {
+					klog.Errorf("Error while adding pod %s/%s to cluster snapshot: %v", podInfo.pod.Namespace, podInfo.pod.Name, err)
+				}
 				break
 			}
 		}
 		if !found {
-			newNodes = append(newNodes, nodeWithPod(nodeTemplate, podInfo.pod))
+			nodeName, err := addNode(nodeTemplate)
+			if err == nil {
+				err = clusterSnapshot.AddPod(podInfo.pod, nodeName)
+			}
+			if err != nil {
+				klog.Errorf("Error while adding new node for pod %s/%s to cluster snapshot: %v", podInfo.pod.Namespace, podInfo.pod.Name, err)
+			}
 		}
 	}
-	return len(newNodes) - len(upcomingNodes)
+	return len(newNodeNames) - upcomingCount
 }
 
 // Calculates score for all pods and returns podInfo structure.
@@ -31,7 +31,9 @@ import (
 )
 
 func TestBinpackingEstimate(t *testing.T) {
-	estimator := NewBinpackingNodeEstimator(simulator.NewTestPredicateChecker())
+	clusterSnapshot := simulator.NewDeltaClusterSnapshot()
+	assert.NoError(t, clusterSnapshot.AddNode(BuildTestNode("existing", 1000, 1000)))
+	estimator := NewBinpackingNodeEstimator(simulator.NewTestPredicateChecker(), clusterSnapshot)
 
 	cpuPerPod := int64(350)
 	memoryPerPod := int64(1000 * units.MiB)
@@ -57,10 +59,13 @@ func TestBinpackingEstimate(t *testing.T) {
 	nodeInfo.SetNode(node)
 	estimate := estimator.Estimate(pods, nodeInfo, []*schedulercache.NodeInfo{})
 	assert.Equal(t, 5, estimate)
+	// Nodes are only simulated in a fork of the snapshot.
+	assert.Equal(t, 1, len(clusterSnapshot.NodeInfos()))
+	assert.Error(t, clusterSnapshot.Revert(), "snapshot should not be left forked")
 }
 
 func TestBinpackingEstimateComingNodes(t *testing.T) {
-	estimator := NewBinpackingNodeEstimator(simulator.NewTestPredicateChecker())
+	estimator := NewBinpackingNodeEstimator(simulator.NewTestPredicateChecker(), simulator.NewDeltaClusterSnapshot())
 
 	cpuPerPod := int64(350)
 	memoryPerPod := int64(1000 * units.MiB)
@@ -90,7 +95,7 @@ func TestBinpackingEstimateComingNodes(t *testing.T) {
 }
 
 func TestBinpackingEstimateWithPorts(t *testing.T) {
-	estimator := NewBinpackingNodeEstimator(simulator.NewTestPredicateChecker())
+	estimator := NewBinpackingNodeEstimator(simulator.NewTestPredicateChecker(), simulator.NewDeltaClusterSnapshot())
 
 	cpuPerPod := int64(200)
 	memoryPerPod := int64(1000 * units.MiB)
@@ -44,21 +44,22 @@ type Estimator interface {
 	Estimate([]*apiv1.Pod, *schedulercache.NodeInfo, []*schedulercache.NodeInfo) int
 }
 
-// EstimatorBuilder creates a new estimator object.
-type EstimatorBuilder func(*simulator.PredicateChecker) Estimator
+// EstimatorBuilder creates a new estimator object. Estimators may simulate nodes in a fork of the
+// given cluster snapshot, but leave the snapshot unchanged.
+type EstimatorBuilder func(*simulator.PredicateChecker, simulator.ClusterSnapshot) Estimator
 
 // NewEstimatorBuilder creates a new estimator object from flag.
 func NewEstimatorBuilder(name string) (EstimatorBuilder, error) {
 	switch name {
 	case BinpackingEstimatorName:
-		return func(predicateChecker *simulator.PredicateChecker) Estimator {
-			return NewBinpackingNodeEstimator(predicateChecker)
+		return func(predicateChecker *simulator.PredicateChecker, clusterSnapshot simulator.ClusterSnapshot) Estimator {
+			return NewBinpackingNodeEstimator(predicateChecker, clusterSnapshot)
 		}, nil
 	// Deprecated.
 	// TODO(aleksandra-malinowska): remove in 1.5.
 	case BasicEstimatorName:
 		klog.Warning(basicEstimatorDeprecationMessage)
-		return func(_ *simulator.PredicateChecker) Estimator {
+		return func(_ *simulator.PredicateChecker, _ simulator.ClusterSnapshot) Estimator {
 			return NewBasicNodeEstimator()
 		}, nil
 	}
@@ -24,7 +24,7 @@ import (
 
 var (
 	// AvailableExpanders is a list of available expander options
-	AvailableExpanders = []string{RandomExpanderName, MostPodsExpanderName, LeastWasteExpanderName, PriceBasedExpanderName}
+	AvailableExpanders = []string{RandomExpanderName, MostPodsExpanderName, LeastWasteExpanderName, PriceBasedExpanderName, PriorityBasedExpanderName, GRPCExpanderName}
 	// RandomExpanderName selects a node group at random
 	RandomExpanderName = "random"
 	// MostPodsExpanderName selects a node group that fits the most pods
@@ -34,6 +34,10 @@ var (
 	// PriceBasedExpanderName selects a node group that is the most cost-effective and consistent with
 	// the preferred node size for the cluster
 	PriceBasedExpanderName = "price"
+	// PriorityBasedExpanderName selects a node group with the highest priority configured in a ConfigMap
+	PriorityBasedExpanderName = "priority"
+	// GRPCExpanderName selects node groups chosen by an external gRPC server
+	GRPCExpanderName = "grpc"
 )
 
 // Option describes an option to expand the cluster.
@@ -48,3 +52,9 @@ type Option struct {
 type Strategy interface {
 	BestOption(options []Option, nodeInfo map[string]*schedulercache.NodeInfo) *Option
 }
+
+// Filter describes an interface for selecting the subset of options that are equally good according
+// to a strategy, so that another strategy can choose between them.
+type Filter interface {
+	BestOptions(options []Option, nodeInfo map[string]*schedulercache.NodeInfo) []Option
+}
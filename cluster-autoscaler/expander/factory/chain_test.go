@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/mostpods"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lastStrategy picks the last option, so that results are deterministic.
+type lastStrategy struct {
+	calls int
+}
+
+func (l *lastStrategy) BestOption(options []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) *expander.Option {
+	l.calls++
+	return &options[len(options)-1]
+}
+
+// fixedFilter keeps options with the given ids.
+type fixedFilter struct {
+	ids map[string]bool
+}
+
+func (f *fixedFilter) BestOptions(options []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) []expander.Option {
+	var result []expander.Option
+	for _, option := range options {
+		if f.ids[option.NodeGroup.Id()] {
+			result = append(result, option)
+		}
+	}
+	return result
+}
+
+func TestChainStrategy(t *testing.T) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	for _, id := range []string{"ng1", "ng2", "ng3", "ng4"} {
+		provider.AddNodeGroup(id, 0, 10, 0)
+	}
+	pod := BuildTestPod("p", 100, 100)
+	options := []expander.Option{
+		{NodeGroup: provider.GetNodeGroup("ng1"), Pods: []*apiv1.Pod{pod, pod}},
+		{NodeGroup: provider.GetNodeGroup("ng2"), Pods: []*apiv1.Pod{pod}},
+		{NodeGroup: provider.GetNodeGroup("ng3"), Pods: []*apiv1.Pod{pod, pod}},
+		{NodeGroup: provider.GetNodeGroup("ng4"), Pods: []*apiv1.Pod{pod, pod}},
+	}
+
+	// The first filter keeps ng2-ng4, most-pods keeps ng3 and ng4, the final strategy picks ng4.
+	last := &lastStrategy{}
+	chain := newChainStrategy([]expander.Filter{
+		&fixedFilter{ids: map[string]bool{"ng2": true, "ng3": true, "ng4": true}},
+		mostpods.NewStrategy().(expander.Filter),
+	}, last)
+	best := chain.BestOption(options, nil)
+	assert.Equal(t, "ng4", best.NodeGroup.Id())
+	assert.Equal(t, 1, last.calls)
+
+	// A single remaining option is picked without asking the following strategies.
+	chain = newChainStrategy([]expander.Filter{
+		mostpods.NewStrategy().(expander.Filter),
+		&fixedFilter{ids: map[string]bool{"ng1": true}},
+	}, last)
+	best = chain.BestOption(options, nil)
+	assert.Equal(t, "ng1", best.NodeGroup.Id())
+	assert.Equal(t, 1, last.calls)
+
+	// No option left.
+	chain = newChainStrategy([]expander.Filter{&fixedFilter{}}, last)
+	assert.Nil(t, chain.BestOption(options, nil))
+}
+
+func TestExpanderStrategyFromString(t *testing.T) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+
+	strategy, err := ExpanderStrategyFromString("most-pods", provider, nil, nil, config.AutoscalingOptions{}, nil)
+	assert.NoError(t, err)
+	_, isChain := strategy.(*chainStrategy)
+	assert.False(t, isChain)
+
+	strategy, err = ExpanderStrategyFromString("most-pods,least-waste,random", provider, nil, nil, config.AutoscalingOptions{}, nil)
+	assert.NoError(t, err)
+	chain, isChain := strategy.(*chainStrategy)
+	assert.True(t, isChain)
+	assert.Equal(t, 2, len(chain.filters))
+
+	strategy, err = ExpanderStrategyFromString(" most-pods , random", provider, nil, nil, config.AutoscalingOptions{}, nil)
+	assert.NoError(t, err)
+	_, isChain = strategy.(*chainStrategy)
+	assert.True(t, isChain)
+
+	_, err = ExpanderStrategyFromString("most-pods,unknown", provider, nil, nil, config.AutoscalingOptions{}, nil)
+	assert.Error(t, err)
+	_, err = ExpanderStrategyFromString("most-pods,least-waste,most-pods", provider, nil, nil, config.AutoscalingOptions{}, nil)
+	assert.Error(t, err)
+	_, err = ExpanderStrategyFromString("most-pods,,random", provider, nil, nil, config.AutoscalingOptions{}, nil)
+	assert.Error(t, err)
+	_, err = ExpanderStrategyFromString("most-pods,", provider, nil, nil, config.AutoscalingOptions{}, nil)
+	assert.Error(t, err)
+	_, err = ExpanderStrategyFromString("grpc", provider, nil, nil, config.AutoscalingOptions{}, nil)
+	assert.Error(t, err)
+
+	// The gRPC expander falls back to the expanders following it, so it ends the chain.
+	grpcOptions := config.AutoscalingOptions{GRPCExpanderURL: "127.0.0.1:1"}
+	strategy, err = ExpanderStrategyFromString("most-pods,grpc,least-waste", provider, nil, nil, grpcOptions, nil)
+	assert.NoError(t, err)
+	chain, isChain = strategy.(*chainStrategy)
+	if assert.True(t, isChain) {
+		assert.Equal(t, 1, len(chain.filters))
+		_, isChain = chain.fallback.(*chainStrategy)
+		assert.False(t, isChain)
+	}
+	_, err = ExpanderStrategyFromString("grpc,unknown", provider, nil, nil, grpcOptions, nil)
+	assert.Error(t, err)
+}
@@ -17,21 +17,104 @@ limitations under the License.
 package factory
 
 import (
+	"strings"
+
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/mostpods"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/price"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/priority"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/random"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/waste"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	kube_client "k8s.io/client-go/kubernetes"
 )
 
-// ExpanderStrategyFromString creates an expander.Strategy according to its name
+// ExpanderStrategyFromString creates an expander.Strategy according to its name. A comma-separated
+// list of names creates a strategy applying the expanders in order, each one choosing between the
+// options considered equally good by the previous ones. Informers started by the expanders run until
+// stopChannel is closed.
 func ExpanderStrategyFromString(expanderFlag string, cloudProvider cloudprovider.CloudProvider,
-	nodeLister kube_util.NodeLister) (expander.Strategy, errors.AutoscalerError) {
-	switch expanderFlag {
+	nodeLister kube_util.NodeLister, kubeClient kube_client.Interface, options config.AutoscalingOptions,
+	stopChannel <-chan struct{}) (expander.Strategy, errors.AutoscalerError) {
+	expanderNames := strings.Split(expanderFlag, ",")
+	seen := make(map[string]bool)
+	for i, expanderName := range expanderNames {
+		expanderName = strings.TrimSpace(expanderName)
+		if expanderName == "" {
+			return nil, errors.NewAutoscalerError(errors.InternalError, "Empty expander name in %q", expanderFlag)
+		}
+		expanderNames[i] = expanderName
+		if seen[expanderName] {
+			return nil, errors.NewAutoscalerError(errors.InternalError, "Expander %s listed more than once", expanderName)
+		}
+		seen[expanderName] = true
+	}
+	return expanderChainFromNames(expanderNames, cloudProvider, nodeLister, kubeClient, options, stopChannel)
+}
+
+func expanderChainFromNames(expanderNames []string, cloudProvider cloudprovider.CloudProvider,
+	nodeLister kube_util.NodeLister, kubeClient kube_client.Interface, options config.AutoscalingOptions,
+	stopChannel <-chan struct{}) (expander.Strategy, errors.AutoscalerError) {
+	strategies := make([]expander.Strategy, 0, len(expanderNames))
+	for i, expanderName := range expanderNames {
+		if expanderName == expander.GRPCExpanderName {
+			// The gRPC expander falls back to the rest of the chain, so it ends the chain.
+			var fallbackStrategy expander.Strategy = random.NewStrategy()
+			if i < len(expanderNames)-1 {
+				var err errors.AutoscalerError
+				fallbackStrategy, err = expanderChainFromNames(expanderNames[i+1:], cloudProvider, nodeLister, kubeClient, options, stopChannel)
+				if err != nil {
+					return nil, err
+				}
+			}
+			strategy, err := grpcExpanderStrategy(options, fallbackStrategy)
+			if err != nil {
+				return nil, err
+			}
+			strategies = append(strategies, strategy)
+			break
+		}
+		strategy, err := expanderStrategyFromName(expanderName, cloudProvider, nodeLister, kubeClient, options, stopChannel)
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, strategy)
+	}
+	if len(strategies) == 1 {
+		return strategies[0], nil
+	}
+
+	filters := make([]expander.Filter, 0, len(strategies)-1)
+	for i, strategy := range strategies[:len(strategies)-1] {
+		filter, ok := strategy.(expander.Filter)
+		if !ok {
+			return nil, errors.NewAutoscalerError(errors.InternalError, "Expander %s can't be followed by other expanders", expanderNames[i])
+		}
+		filters = append(filters, filter)
+	}
+	return newChainStrategy(filters, strategies[len(strategies)-1]), nil
+}
+
+func grpcExpanderStrategy(options config.AutoscalingOptions, fallbackStrategy expander.Strategy) (expander.Strategy, errors.AutoscalerError) {
+	if options.GRPCExpanderURL == "" {
+		return nil, errors.NewAutoscalerError(errors.InternalError, "Expander %s requires the gRPC expander url", expander.GRPCExpanderName)
+	}
+	strategy, err := grpcplugin.NewStrategy(options.GRPCExpanderURL, options.GRPCExpanderCert, options.GRPCExpanderTimeout, fallbackStrategy)
+	if err != nil {
+		return nil, errors.ToAutoscalerError(errors.InternalError, err)
+	}
+	return strategy, nil
+}
+
+func expanderStrategyFromName(expanderName string, cloudProvider cloudprovider.CloudProvider,
+	nodeLister kube_util.NodeLister, kubeClient kube_client.Interface, options config.AutoscalingOptions,
+	stopChannel <-chan struct{}) (expander.Strategy, errors.AutoscalerError) {
+	switch expanderName {
 	case expander.RandomExpanderName:
 		return random.NewStrategy(), nil
 	case expander.MostPodsExpanderName:
@@ -46,6 +129,9 @@ func ExpanderStrategyFromString(expanderFlag string, cloudProvider cloudprovider
 		return price.NewStrategy(pricing,
 			price.NewSimplePreferredNodeProvider(nodeLister),
 			price.SimpleNodeUnfitness), nil
+	case expander.PriorityBasedExpanderName:
+		lister := kube_util.NewConfigMapListerForNamespace(kubeClient, stopChannel, options.ConfigNamespace)
+		return priority.NewStrategy(lister), nil
 	}
-	return nil, errors.NewAutoscalerError(errors.InternalError, "Expander %s not supported", expanderFlag)
+	return nil, errors.NewAutoscalerError(errors.InternalError, "Expander %s not supported", expanderName)
 }
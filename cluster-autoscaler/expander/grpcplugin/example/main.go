@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command example is a reference gRPC expander server. It prefers node groups with enough reserved
+// capacity for the nodes to add, as given by the --reservations flag. Real servers would typically
+// read reservations from an external system instead.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin/protos"
+	"k8s.io/klog"
+)
+
+var (
+	address      = flag.String("address", ":7000", "Address to listen on.")
+	certFile     = flag.String("cert", "", "Path to the TLS certificate of the server. If empty, the server doesn't use TLS.")
+	keyFile      = flag.String("key", "", "Path to the TLS key of the server.")
+	reservations = flag.String("reservations", "", "Comma-separated list of <node group id>=<number of reserved nodes>.")
+)
+
+// reservationServer prefers node groups with reserved capacity.
+type reservationServer struct {
+	reservedNodes map[string]int32
+}
+
+// BestOptions returns the options for which the most reserved nodes remain after the scale up. If no
+// option fits in its reservation, all options are returned, leaving the choice to Cluster Autoscaler.
+func (s *reservationServer) BestOptions(ctx context.Context, request *protos.BestOptionsRequest) (*protos.BestOptionsResponse, error) {
+	var bestRemaining int32
+	var bestOptions []*protos.Option
+	for _, option := range request.Options {
+		remaining := s.reservedNodes[option.NodeGroupId] - option.NodeCount
+		klog.V(2).Infof("Node group %s: %d nodes requested, %d reserved nodes remaining", option.NodeGroupId, option.NodeCount, remaining)
+		if remaining < 0 {
+			continue
+		}
+		if bestOptions == nil || remaining > bestRemaining {
+			bestRemaining = remaining
+			bestOptions = []*protos.Option{option}
+		} else if remaining == bestRemaining {
+			bestOptions = append(bestOptions, option)
+		}
+	}
+	if len(bestOptions) == 0 {
+		return &protos.BestOptionsResponse{Options: request.Options}, nil
+	}
+	return &protos.BestOptionsResponse{Options: bestOptions}, nil
+}
+
+func parseReservations(value string) (map[string]int32, error) {
+	result := make(map[string]int32)
+	if value == "" {
+		return result, nil
+	}
+	for _, reservation := range strings.Split(value, ",") {
+		parts := strings.SplitN(reservation, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid reservation %q, expected <node group id>=<number of nodes>", reservation)
+		}
+		nodes, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number of nodes in reservation %q: %v", reservation, err)
+		}
+		result[parts[0]] = int32(nodes)
+	}
+	return result, nil
+}
+
+func main() {
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	reservedNodes, err := parseReservations(*reservations)
+	if err != nil {
+		klog.Fatalf("Failed to parse --reservations: %v", err)
+	}
+	var opts []grpc.ServerOption
+	if *certFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(*certFile, *keyFile)
+		if err != nil {
+			klog.Fatalf("Failed to load TLS credentials: %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		klog.Fatalf("Failed to listen on %s: %v", *address, err)
+	}
+	server := grpc.NewServer(opts...)
+	protos.RegisterExpanderServer(server, &reservationServer{reservedNodes: reservedNodes})
+	klog.Infof("Serving gRPC expander on %s", listener.Addr())
+	if err := server.Serve(listener); err != nil {
+		klog.Fatalf("Failed to serve: %v", err)
+	}
+}
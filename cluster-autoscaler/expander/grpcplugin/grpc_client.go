@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin/protos"
+	"k8s.io/klog"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+type grpcclientstrategy struct {
+	fallbackStrategy expander.Strategy
+	grpcClient       protos.ExpanderClient
+	timeout          time.Duration
+}
+
+// NewStrategy returns a strategy that sends expansion options to an external gRPC expander server
+// at the given url and picks between the options it returns with fallbackStrategy. If certPath is set, the
+// connection uses TLS and the server certificate is verified with the CA certificate from certPath.
+// Each request times out after the given timeout, in which case all options are considered.
+func NewStrategy(url string, certPath string, timeout time.Duration, fallbackStrategy expander.Strategy) (expander.Strategy, error) {
+	var dialOpt grpc.DialOption
+	if certPath == "" {
+		klog.Warning("No certificate of the gRPC expander given, connecting without TLS")
+		dialOpt = grpc.WithInsecure()
+	} else {
+		creds, err := credentials.NewClientTLSFromFile(certPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gRPC expander certificate %s: %v", certPath, err)
+		}
+		dialOpt = grpc.WithTransportCredentials(creds)
+	}
+	// The connection is established in the background, errors are reported by requests.
+	conn, err := grpc.Dial(url, dialOpt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC expander at %s: %v", url, err)
+	}
+	return newStrategy(protos.NewExpanderClient(conn), timeout, fallbackStrategy), nil
+}
+
+func newStrategy(client protos.ExpanderClient, timeout time.Duration, fallbackStrategy expander.Strategy) *grpcclientstrategy {
+	return &grpcclientstrategy{
+		fallbackStrategy: fallbackStrategy,
+		grpcClient:       client,
+		timeout:          timeout,
+	}
+}
+
+// BestOption selects between the options returned by the gRPC expander with the fallback strategy.
+func (g *grpcclientstrategy) BestOption(expansionOptions []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) *expander.Option {
+	bestOptions := g.BestOptions(expansionOptions, nodeInfo)
+	if len(bestOptions) == 0 {
+		return nil
+	}
+	return g.fallbackStrategy.BestOption(bestOptions, nodeInfo)
+}
+
+// BestOptions returns the options chosen by the gRPC expander. If the request fails or none of the
+// returned options is known, all options are returned.
+func (g *grpcclientstrategy) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) []expander.Option {
+	if len(expansionOptions) == 0 {
+		return nil
+	}
+	request := &protos.BestOptionsRequest{Options: make([]*protos.Option, 0, len(expansionOptions))}
+	optionsById := make(map[string]expander.Option, len(expansionOptions))
+	for _, option := range expansionOptions {
+		requestOption, err := optionToProto(option, nodeInfo[option.NodeGroup.Id()])
+		if err != nil {
+			klog.Warningf("Failed to serialize option %s for gRPC expander, considering all options: %v", option.NodeGroup.Id(), err)
+			return expansionOptions
+		}
+		request.Options = append(request.Options, requestOption)
+		optionsById[option.NodeGroup.Id()] = option
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	response, err := g.grpcClient.BestOptions(ctx, request)
+	if err != nil {
+		klog.Warningf("gRPC expander request failed, considering all options: %v", err)
+		return expansionOptions
+	}
+
+	var bestOptions []expander.Option
+	for _, responseOption := range response.Options {
+		if responseOption == nil {
+			continue
+		}
+		option, found := optionsById[responseOption.NodeGroupId]
+		if !found {
+			klog.Warningf("gRPC expander returned unknown node group %s", responseOption.NodeGroupId)
+			continue
+		}
+		bestOptions = append(bestOptions, option)
+	}
+	if len(bestOptions) == 0 {
+		klog.Warningf("gRPC expander returned no known options, considering all options")
+		return expansionOptions
+	}
+	return bestOptions
+}
+
+func optionToProto(option expander.Option, template *schedulercache.NodeInfo) (*protos.Option, error) {
+	pods, err := protos.MarshalPods(option.Pods)
+	if err != nil {
+		return nil, err
+	}
+	result := &protos.Option{
+		NodeGroupId: option.NodeGroup.Id(),
+		NodeCount:   int32(option.NodeCount),
+		Debug:       option.Debug,
+		Pods:        pods,
+	}
+	if template != nil && template.Node() != nil {
+		node, err := template.Node().Marshal()
+		if err != nil {
+			return nil, err
+		}
+		templatePods, err := protos.MarshalPods(template.Pods())
+		if err != nil {
+			return nil, err
+		}
+		result.TemplateNodeInfo = &protos.NodeInfo{
+			Node: node,
+			Pods: templatePods,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	apiv1 "k8s.io/api/core/v1"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin/protos"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubServer returns the configured node groups, or an error. Requests are handled on
+// server goroutines, so all fields are guarded by the mutex.
+type stubServer struct {
+	sync.Mutex
+	nodeGroupIds []string
+	err          error
+	delay        time.Duration
+	request      *protos.BestOptionsRequest
+}
+
+func (s *stubServer) BestOptions(ctx context.Context, request *protos.BestOptionsRequest) (*protos.BestOptionsResponse, error) {
+	s.Lock()
+	s.request = request
+	delay, err, nodeGroupIds := s.delay, s.err, s.nodeGroupIds
+	s.Unlock()
+
+	time.Sleep(delay)
+	if err != nil {
+		return nil, err
+	}
+	response := &protos.BestOptionsResponse{}
+	for _, id := range nodeGroupIds {
+		response.Options = append(response.Options, &protos.Option{NodeGroupId: id})
+	}
+	return response, nil
+}
+
+// lastRequest returns the last request received by the server.
+func (s *stubServer) lastRequest() *protos.BestOptionsRequest {
+	s.Lock()
+	defer s.Unlock()
+	return s.request
+}
+
+// firstStrategy always picks the first option, so that results are deterministic.
+type firstStrategy struct{}
+
+func (f *firstStrategy) BestOption(options []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) *expander.Option {
+	return &options[0]
+}
+
+func startStubServer(t *testing.T, stub *stubServer) (*grpcclientstrategy, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	protos.RegisterExpanderServer(server, stub)
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	assert.NoError(t, err)
+	strategy := newStrategy(protos.NewExpanderClient(conn), time.Second, &firstStrategy{})
+	return strategy, func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+func testOptions() ([]expander.Option, map[string]*schedulercache.NodeInfo) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 0, 10, 0)
+	provider.AddNodeGroup("ng2", 0, 10, 0)
+	provider.AddNodeGroup("ng3", 0, 10, 0)
+	pod := BuildTestPod("p1", 100, 100)
+	options := []expander.Option{
+		{NodeGroup: provider.GetNodeGroup("ng1"), NodeCount: 1, Pods: []*apiv1.Pod{pod}},
+		{NodeGroup: provider.GetNodeGroup("ng2"), NodeCount: 2, Pods: []*apiv1.Pod{pod}},
+		{NodeGroup: provider.GetNodeGroup("ng3"), NodeCount: 3, Pods: []*apiv1.Pod{pod}},
+	}
+	template := schedulercache.NewNodeInfo(BuildTestPod("ds", 50, 50))
+	template.SetNode(BuildTestNode("ng2-template", 1000, 1000))
+	return options, map[string]*schedulercache.NodeInfo{"ng2": template}
+}
+
+func TestGRPCExpander(t *testing.T) {
+	stub := &stubServer{nodeGroupIds: []string{"ng3", "ng2"}}
+	strategy, stop := startStubServer(t, stub)
+	defer stop()
+	options, nodeInfos := testOptions()
+
+	// Options are kept in the order returned by the server.
+	best := strategy.BestOption(options, nodeInfos)
+	assert.Equal(t, "ng3", best.NodeGroup.Id())
+	assert.Equal(t, 3, best.NodeCount)
+
+	// The request contains all options with pods and known templates.
+	request := stub.lastRequest()
+	assert.Equal(t, 3, len(request.Options))
+	requestOption := request.Options[1]
+	assert.Equal(t, "ng2", requestOption.NodeGroupId)
+	assert.Equal(t, int32(2), requestOption.NodeCount)
+	pods, err := protos.UnmarshalPods(requestOption.Pods)
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", pods[0].Name)
+	templateNode, err := protos.UnmarshalNode(requestOption.TemplateNodeInfo.Node)
+	assert.NoError(t, err)
+	assert.Equal(t, "ng2-template", templateNode.Name)
+	templatePods, err := protos.UnmarshalPods(requestOption.TemplateNodeInfo.Pods)
+	assert.NoError(t, err)
+	assert.Equal(t, "ds", templatePods[0].Name)
+	assert.Nil(t, request.Options[0].TemplateNodeInfo)
+
+	bestOptions := strategy.BestOptions(options, nodeInfos)
+	assert.Equal(t, 2, len(bestOptions))
+	assert.Equal(t, "ng2", bestOptions[1].NodeGroup.Id())
+}
+
+func TestGRPCExpanderFallback(t *testing.T) {
+	options, nodeInfos := testOptions()
+
+	for name, stub := range map[string]*stubServer{
+		"error":         {err: errors.New("server failure")},
+		"timeout":       {nodeGroupIds: []string{"ng3"}, delay: 2 * time.Second},
+		"unknown group": {nodeGroupIds: []string{"ng4"}},
+		"no options":    {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			strategy, stop := startStubServer(t, stub)
+			defer stop()
+			strategy.timeout = 100 * time.Millisecond
+
+			best := strategy.BestOption(options, nodeInfos)
+			assert.Equal(t, "ng1", best.NodeGroup.Id())
+			assert.Equal(t, 3, len(strategy.BestOptions(options, nodeInfos)))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//go:generate ./generate.sh
+
+// Package protos contains messages and the gRPC service of the expander plugin protocol, defined
+// in expander.proto, together with helpers serializing the Kubernetes objects they carry.
+package protos
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// MarshalPods serializes pods for Option.Pods and NodeInfo.Pods.
+func MarshalPods(pods []*v1.Pod) ([][]byte, error) {
+	result := make([][]byte, 0, len(pods))
+	for _, pod := range pods {
+		data, err := pod.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
+
+// UnmarshalPods deserializes pods from Option.Pods and NodeInfo.Pods.
+func UnmarshalPods(data [][]byte) ([]*v1.Pod, error) {
+	result := make([]*v1.Pod, 0, len(data))
+	for _, podData := range data {
+		pod := &v1.Pod{}
+		if err := pod.Unmarshal(podData); err != nil {
+			return nil, err
+		}
+		result = append(result, pod)
+	}
+	return result, nil
+}
+
+// UnmarshalNode deserializes a node from NodeInfo.Node.
+func UnmarshalNode(data []byte) (*v1.Node, error) {
+	node := &v1.Node{}
+	if err := node.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
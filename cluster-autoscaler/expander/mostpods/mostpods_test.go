@@ -43,3 +43,13 @@ func TestMostPods(t *testing.T) {
 
 	assert.True(t, assert.ObjectsAreEqual(*ret, eo1) || assert.ObjectsAreEqual(*ret, eo1b))
 }
+
+func TestMostPodsBestOptions(t *testing.T) {
+	eo0 := expander.Option{Debug: "EO0"}
+	eo1 := expander.Option{Debug: "EO1", Pods: []*apiv1.Pod{nil}}
+	eo1b := expander.Option{Debug: "EO1b", Pods: []*apiv1.Pod{nil}}
+	e := NewStrategy().(expander.Filter)
+
+	ret := e.BestOptions([]expander.Option{eo0, eo1, eo1b}, nil)
+	assert.Equal(t, []expander.Option{eo1, eo1b}, ret)
+}
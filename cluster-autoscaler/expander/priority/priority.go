@@ -0,0 +1,178 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	apiv1 "k8s.io/api/core/v1"
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/random"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+const (
+	// PriorityConfigMapName defines a name of the ConfigMap used to store priority expander configuration
+	PriorityConfigMapName = "cluster-autoscaler-priority-expander"
+	// ConfigMapKey defines the key used in the ConfigMap to configure priorities
+	ConfigMapKey = "priorities"
+)
+
+// priorities maps priorities to regular expressions matching node group ids.
+type priorities map[int][]*regexp.Regexp
+
+type priority struct {
+	fallbackStrategy expander.Strategy
+	configMapLister  v1lister.ConfigMapNamespaceLister
+
+	sync.Mutex
+	// Priorities parsed from the ConfigMap, reparsed only when the ConfigMap changes.
+	priorities      priorities
+	resourceVersion string
+}
+
+// NewStrategy returns a strategy that selects node groups with the highest priority, as configured
+// in the PriorityConfigMapName ConfigMap. Ties are broken at random.
+func NewStrategy(configMapLister v1lister.ConfigMapNamespaceLister) expander.Strategy {
+	return NewStrategyWithFallback(configMapLister, random.NewStrategy())
+}
+
+// NewStrategyWithFallback returns a priority strategy that breaks ties with the fallback strategy.
+func NewStrategyWithFallback(configMapLister v1lister.ConfigMapNamespaceLister, fallbackStrategy expander.Strategy) expander.Strategy {
+	return &priority{
+		fallbackStrategy: fallbackStrategy,
+		configMapLister:  configMapLister,
+	}
+}
+
+// BestOption selects the option whose node group matches the highest priority. If no option matches
+// any priority, or the configuration is missing or invalid, all options are passed to the fallback strategy.
+func (p *priority) BestOption(expansionOptions []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) *expander.Option {
+	bestOptions := p.BestOptions(expansionOptions, nodeInfo)
+	if len(bestOptions) == 0 {
+		return nil
+	}
+	return p.fallbackStrategy.BestOption(bestOptions, nodeInfo)
+}
+
+// BestOptions selects the options whose node groups match the highest priority. If no option matches
+// any priority, or the configuration is missing or invalid, all options are returned.
+func (p *priority) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) []expander.Option {
+	if len(expansionOptions) == 0 {
+		return nil
+	}
+	priorities, err := p.reloadPriorities()
+	if err != nil {
+		klog.Warningf("Priority expander: %v, ignoring priorities", err)
+		return expansionOptions
+	}
+
+	var bestPriority int
+	var bestOptions []expander.Option
+	for _, option := range expansionOptions {
+		optionPriority, found := priorities.priorityFor(option.NodeGroup.Id())
+		if !found {
+			continue
+		}
+		if bestOptions == nil || optionPriority > bestPriority {
+			bestPriority = optionPriority
+			bestOptions = []expander.Option{option}
+		} else if optionPriority == bestPriority {
+			bestOptions = append(bestOptions, option)
+		}
+	}
+	if len(bestOptions) == 0 {
+		klog.Warningf("Priority expander: no node group matches any priority, ignoring priorities")
+		return expansionOptions
+	}
+	klog.V(2).Infof("Priority expander: %d of %d options have the highest priority %d", len(bestOptions), len(expansionOptions), bestPriority)
+	return bestOptions
+}
+
+// reloadPriorities returns priorities from the ConfigMap, parsing it again if it changed.
+func (p *priority) reloadPriorities() (priorities, error) {
+	configMap, err := p.configMapLister.Get(PriorityConfigMapName)
+	if kube_errors.IsNotFound(err) {
+		return nil, fmt.Errorf("configmap %s not found", PriorityConfigMapName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap %s: %v", PriorityConfigMapName, err)
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if p.priorities != nil && p.resourceVersion == configMap.ResourceVersion {
+		return p.priorities, nil
+	}
+	parsed, err := parsePriorities(configMap)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(1).Infof("Priority expander: loaded priorities from configmap %s, version %q", PriorityConfigMapName, configMap.ResourceVersion)
+	p.priorities = parsed
+	p.resourceVersion = configMap.ResourceVersion
+	return parsed, nil
+}
+
+func parsePriorities(configMap *apiv1.ConfigMap) (priorities, error) {
+	data, found := configMap.Data[ConfigMapKey]
+	if !found {
+		return nil, fmt.Errorf("configmap %s has no %s key", PriorityConfigMapName, ConfigMapKey)
+	}
+	var config map[int][]string
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse configmap %s: %v", PriorityConfigMapName, err)
+	}
+	result := make(priorities)
+	for priority, expressions := range config {
+		for _, expression := range expressions {
+			// Patterns have to match the whole node group id.
+			regex, err := regexp.Compile("^(?:" + expression + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q for priority %d in configmap %s: %v",
+					expression, priority, PriorityConfigMapName, err)
+			}
+			result[priority] = append(result[priority], regex)
+		}
+	}
+	return result, nil
+}
+
+// priorityFor returns the highest priority with a regular expression matching the node group id.
+func (p priorities) priorityFor(nodeGroupId string) (int, bool) {
+	var result int
+	found := false
+	for priority, regexes := range p {
+		if found && priority <= result {
+			continue
+		}
+		for _, regex := range regexes {
+			if regex.MatchString(nodeGroupId) {
+				result = priority
+				found = true
+				break
+			}
+		}
+	}
+	return result, found
+}
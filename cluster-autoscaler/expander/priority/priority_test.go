@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testNamespace = "kube-system"
+	config        = `
+5:
+  - ".*spot.*"
+10:
+  - ".*on-demand.*"
+20:
+  - "reserved-.*"
+`
+)
+
+// firstStrategy always picks the first option, so that results are deterministic.
+type firstStrategy struct{}
+
+func (f *firstStrategy) BestOption(options []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) *expander.Option {
+	return &options[0]
+}
+
+func setUpTest(t *testing.T, priorities string) (*testprovider.TestCloudProvider, cache.Indexer, expander.Strategy) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("spot-1", 0, 10, 0)
+	provider.AddNodeGroup("on-demand-1", 0, 10, 0)
+	provider.AddNodeGroup("on-demand-2", 0, 10, 0)
+	provider.AddNodeGroup("reserved-1", 0, 10, 0)
+	provider.AddNodeGroup("other", 0, 10, 0)
+
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if priorities != "" {
+		setConfigMap(t, store, priorities, "1")
+	}
+	lister := v1lister.NewConfigMapLister(store).ConfigMaps(testNamespace)
+	return provider, store, NewStrategyWithFallback(lister, &firstStrategy{})
+}
+
+func setConfigMap(t *testing.T, store cache.Indexer, priorities, resourceVersion string) {
+	assert.NoError(t, store.Add(&apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNamespace,
+			Name:            PriorityConfigMapName,
+			ResourceVersion: resourceVersion,
+		},
+		Data: map[string]string{ConfigMapKey: priorities},
+	}))
+}
+
+func options(provider *testprovider.TestCloudProvider, ids ...string) []expander.Option {
+	result := make([]expander.Option, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, expander.Option{NodeGroup: provider.GetNodeGroup(id)})
+	}
+	return result
+}
+
+func TestPriorityPicksHighest(t *testing.T) {
+	provider, _, strategy := setUpTest(t, config)
+
+	best := strategy.BestOption(options(provider, "spot-1", "on-demand-1", "reserved-1"), nil)
+	assert.Equal(t, "reserved-1", best.NodeGroup.Id())
+
+	best = strategy.BestOption(options(provider, "spot-1", "on-demand-2", "on-demand-1"), nil)
+	assert.Equal(t, "on-demand-2", best.NodeGroup.Id())
+
+	// Options without priority are skipped.
+	best = strategy.BestOption(options(provider, "other", "spot-1"), nil)
+	assert.Equal(t, "spot-1", best.NodeGroup.Id())
+}
+
+func TestPriorityMatchesWholeId(t *testing.T) {
+	provider, _, strategy := setUpTest(t, `
+20:
+  - "reserved"
+10:
+  - "other"
+`)
+	best := strategy.BestOption(options(provider, "reserved-1", "other"), nil)
+	assert.Equal(t, "other", best.NodeGroup.Id())
+}
+
+func TestPriorityFallsBackWithoutConfig(t *testing.T) {
+	provider, _, strategy := setUpTest(t, "")
+	best := strategy.BestOption(options(provider, "spot-1", "reserved-1"), nil)
+	assert.Equal(t, "spot-1", best.NodeGroup.Id())
+
+	provider, _, strategy = setUpTest(t, "10: [\"(\"]")
+	best = strategy.BestOption(options(provider, "spot-1", "reserved-1"), nil)
+	assert.Equal(t, "spot-1", best.NodeGroup.Id())
+
+	provider, _, strategy = setUpTest(t, config)
+	best = strategy.BestOption(options(provider, "other"), nil)
+	assert.Equal(t, "other", best.NodeGroup.Id())
+}
+
+func TestPriorityReloadsConfig(t *testing.T) {
+	provider, store, strategy := setUpTest(t, config)
+	best := strategy.BestOption(options(provider, "spot-1", "reserved-1"), nil)
+	assert.Equal(t, "reserved-1", best.NodeGroup.Id())
+
+	setConfigMap(t, store, `
+30:
+  - "spot-.*"
+`, "2")
+	best = strategy.BestOption(options(provider, "spot-1", "reserved-1"), nil)
+	assert.Equal(t, "spot-1", best.NodeGroup.Id())
+}
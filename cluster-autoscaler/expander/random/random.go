@@ -33,6 +33,18 @@ func NewStrategy() expander.Strategy {
 
 // RandomExpansion Selects from the expansion options at random
 func (r *random) BestOption(expansionOptions []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) *expander.Option {
+	if len(expansionOptions) == 0 {
+		return nil
+	}
 	pos := rand.Int31n(int32(len(expansionOptions)))
 	return &expansionOptions[pos]
 }
+
+// BestOptions selects a single expansion option at random
+func (r *random) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulercache.NodeInfo) []expander.Option {
+	best := r.BestOption(expansionOptions, nodeInfo)
+	if best == nil {
+		return nil
+	}
+	return []expander.Option{*best}
+}
@@ -29,6 +29,7 @@ import (
 	"syscall"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apiserverconfig "k8s.io/apiserver/pkg/apis/config"
 	kube_flag "k8s.io/apiserver/pkg/util/flag"
@@ -39,10 +40,14 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroups"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+	"k8s.io/client-go/dynamic"
 	kube_client "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -81,6 +86,7 @@ var (
 	kubernetes             = flag.String("kubernetes", "", "Kubernetes master location. Leave blank for default")
 	kubeConfigFile         = flag.String("kubeconfig", "", "Path to kubeconfig file with authorization and master location information.")
 	cloudConfig            = flag.String("cloud-config", "", "The path to the cloud provider configuration file.  Empty string for no configuration file.")
+	instancePricesFile     = flag.String("instance-prices-file", "", "The path to a YAML file with hourly instance prices per region and instance type, overriding prices bundled with the cloud provider. Empty string to use bundled prices only.")
 	namespace              = flag.String("namespace", "kube-system", "Namespace in which cluster-autoscaler run.")
 	scaleDownEnabled       = flag.Bool("scale-down-enabled", true, "Should CA scale down the cluster")
 	scaleDownDelayAfterAdd = flag.Duration("scale-down-delay-after-add", 10*time.Minute,
@@ -93,6 +99,9 @@ var (
 		"How long a node should be unneeded before it is eligible for scale down")
 	scaleDownUnreadyTime = flag.Duration("scale-down-unready-time", 20*time.Minute,
 		"How long an unready node should be unneeded before it is eligible for scale down")
+	scaleDownAllowedWindows = flag.String("scale-down-allowed-windows", "",
+		"Semicolon separated list of windows in which scale down is allowed, each given as a cron expression describing when the window starts followed by its duration, e.g. '0 22 * * 1-5 10h'. "+
+			"Empty means scale down is always allowed.")
 	scaleDownUtilizationThreshold = flag.Float64("scale-down-utilization-threshold", 0.5,
 		"Node utilization level, defined as sum of requested resources divided by capacity, below which a node can be considered for scale down")
 	scaleDownNonEmptyCandidatesCount = flag.Int("scale-down-non-empty-candidates-count", 30,
@@ -111,6 +120,9 @@ var (
 			"for scale down when some candidates from previous iteration are no longer valid."+
 			"When calculating the pool size for additional candidates we take"+
 			"max(#nodes * scale-down-candidates-pool-ratio, scale-down-candidates-pool-min-count).")
+	scaleDownMaxDisruptionCost = flag.Float64("scale-down-max-disruption-cost", 0,
+		"Maximum sum of disruption costs of the pods that have to be moved for a node to be scaled down. "+
+			"Nodes above it are not scaled down. Set to 0 to turn this limit off.")
 	scanInterval      = flag.Duration("scan-interval", 10*time.Second, "How often cluster is reevaluated for scale up or down")
 	maxNodesTotal     = flag.Int("max-nodes-total", 0, "Maximum number of nodes in all node groups. Cluster autoscaler will not grow the cluster beyond this number.")
 	coresTotal        = flag.String("cores-total", minMaxFlagString(0, config.DefaultMaxClusterCores), "Minimum and maximum number of cores in cluster, in the format <min>:<max>. Cluster autoscaler will not scale the cluster beyond these numbers.")
@@ -119,7 +131,10 @@ var (
 	cloudProviderFlag = flag.String("cloud-provider", cloudBuilder.DefaultCloudProvider,
 		"Cloud provider type. Available values: ["+strings.Join(cloudBuilder.AvailableCloudProviders, ",")+"]")
 	maxEmptyBulkDeleteFlag     = flag.Int("max-empty-bulk-delete", 10, "Maximum number of empty nodes that can be deleted at the same time.")
+	maxBulkSoftTaintCount      = flag.Int("max-bulk-soft-taint-count", 0, "Maximum number of unneeded nodes that can be tainted with PreferNoSchedule in one loop. Set to 0 to turn soft tainting off.")
+	maxDrainParallelismFlag    = flag.Int("max-drain-parallelism", 1, "Maximum number of non empty nodes that can be drained and deleted at the same time.")
 	maxGracefulTerminationFlag = flag.Int("max-graceful-termination-sec", 10*60, "Maximum number of seconds CA waits for pod termination when trying to scale down a node.")
+	replacementPodsTimeout     = flag.Duration("replacement-pods-timeout", 0, "Maximum time CA waits after draining a node for the replacements of evicted pods to be running elsewhere before deleting the node. The node is made schedulable again on timeout. 0 means CA doesn't wait.")
 	maxTotalUnreadyPercentage  = flag.Float64("max-total-unready-percentage", 45, "Maximum percentage of unready nodes in the cluster.  After this is exceeded, CA halts operations")
 	okTotalUnreadyCount        = flag.Int("ok-total-unready-count", 3, "Number of allowed unready nodes, irrespective of max-total-unready-percentage")
 	maxNodeProvisionTime       = flag.Duration("max-node-provision-time", 15*time.Minute, "Maximum time CA waits for node to be provisioned")
@@ -132,13 +147,18 @@ var (
 			"A definition is expressed `<name of discoverer>:[<key>[=<value>]]`. "+
 			"The `aws` and `gce` cloud providers are currently supported. AWS matches by ASG tags, e.g. `asg:tag=tagKey,anotherTagKey`. "+
 			"GCE matches by IG name prefix, and requires you to specify min and max nodes per IG, e.g. `mig:namePrefix=pfx,min=0,max=10` "+
+			"OpenShift Machine API matches MachineSets and MachineDeployments by namespace and labels, e.g. `machineapi:namespace=openshift-machine-api,labelKey=labelValue`. "+
 			"Can be used multiple times.")
 
 	estimatorFlag = flag.String("estimator", estimator.BinpackingEstimatorName,
 		"Type of resource estimator to be used in scale up. Available values: ["+strings.Join(estimator.AvailableEstimators, ",")+"]")
 
 	expanderFlag = flag.String("expander", expander.RandomExpanderName,
-		"Type of node group expander to be used in scale up. Available values: ["+strings.Join(expander.AvailableExpanders, ",")+"]")
+		"Type of node group expander to be used in scale up. Available values: ["+strings.Join(expander.AvailableExpanders, ",")+"]. "+
+			"A comma-separated list of expanders applies them in order, each one choosing between the node groups considered equally good by the previous ones.")
+	grpcExpanderURL     = flag.String("grpc-expander-url", "", "URL of the gRPC expander server, used by the grpc expander.")
+	grpcExpanderCert    = flag.String("grpc-expander-cert", "", "Path to the CA certificate verifying the gRPC expander server. If empty, the connection to the server doesn't use TLS.")
+	grpcExpanderTimeout = flag.Duration("grpc-expander-timeout", 5*time.Second, "Timeout of requests to the gRPC expander server. Node groups are chosen without the server if it doesn't answer in time.")
 
 	ignoreDaemonSetsUtilization = flag.Bool("ignore-daemonsets-utilization", false,
 		"Should CA ignore DaemonSet pods when calculating resource utilization for scaling down")
@@ -146,16 +166,23 @@ var (
 		"Should CA ignore Mirror pods when calculating resource utilization for scaling down")
 
 	writeStatusConfigMapFlag         = flag.Bool("write-status-configmap", true, "Should CA write status information to a configmap")
+	writeStatusResourceFlag          = flag.Bool("write-status-resource", false, "Should CA write status information to the ClusterAutoscalerStatus custom resource. The custom resource definition has to be installed in the cluster.")
 	maxInactivityTimeFlag            = flag.Duration("max-inactivity", 10*time.Minute, "Maximum time from last recorded autoscaler activity before automatic restart")
 	maxFailingTimeFlag               = flag.Duration("max-failing-time", 15*time.Minute, "Maximum time from last recorded successful autoscaler run before automatic restart")
 	balanceSimilarNodeGroupsFlag     = flag.Bool("balance-similar-node-groups", false, "Detect similar node groups and balance the number of nodes between them")
+	scaleDownBalanceMaxSkew          = flag.Int("scale-down-balance-max-skew", 1, "Maximum difference in node count between similar node groups that scale down may cause when --balance-similar-node-groups is set")
 	nodeAutoprovisioningEnabled      = flag.Bool("node-autoprovisioning-enabled", false, "Should CA autoprovision node groups when needed")
 	maxAutoprovisionedNodeGroupCount = flag.Int("max-autoprovisioned-node-group-count", 15, "The maximum number of autoprovisioned groups in the cluster.")
 
 	unremovableNodeRecheckTimeout = flag.Duration("unremovable-node-recheck-timeout", 5*time.Minute, "The timeout before we check again a node that couldn't be removed before")
 	expendablePodsPriorityCutoff  = flag.Int("expendable-pods-priority-cutoff", -10, "Pods with priority below cutoff will be expendable. They can be killed without any consideration during scale down and they don't cause scale up. Pods with null priority (PodPriority disabled) are non expendable.")
+	headroomCPU                   = flag.String("headroom-cpu", "0", "Amount of CPU, as a Kubernetes quantity (e.g. 500m or 2), to keep free in the cluster for pods that don't exist yet.")
+	headroomMemory                = flag.String("headroom-memory", "0", "Amount of memory, as a Kubernetes quantity (e.g. 4Gi), to keep free in the cluster for pods that don't exist yet.")
 	regional                      = flag.Bool("regional", false, "Cluster is regional.")
 	newPodScaleUpDelay            = flag.Duration("new-pod-scale-up-delay", 0*time.Second, "Pods less than this old will not be considered for scale-up.")
+	dryRun                        = flag.Bool("dry-run", false, "Compute scale-up and scale-down decisions without acting on them. Simulated actions are reported in events, metrics and status only.")
+	recordLoopsDir                = flag.String("record-loops-dir", "", "If set, inputs of every loop are written to this directory, so that they can be replayed offline with the replay command.")
+	recordLoopsMaxFiles           = flag.Int("record-loops-max-files", 100, "Maximum number of loop recordings kept in record-loops-dir. Older recordings are removed. 0 means no limit.")
 )
 
 func createAutoscalingOptions() config.AutoscalingOptions {
@@ -176,18 +203,43 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		klog.Fatalf("Failed to parse flags: %v", err)
 	}
 
+	scaleDownAllowedSchedule, err := schedule.Parse(*scaleDownAllowedWindows)
+	if err != nil {
+		klog.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	headroomCPUQuantity, err := resource.ParseQuantity(*headroomCPU)
+	if err != nil {
+		klog.Fatalf("Failed to parse flags: %v", err)
+	}
+	headroomMemoryQuantity, err := resource.ParseQuantity(*headroomMemory)
+	if err != nil {
+		klog.Fatalf("Failed to parse flags: %v", err)
+	}
+	headroom := config.Headroom{
+		MilliCPU: headroomCPUQuantity.MilliValue(),
+		Memory:   headroomMemoryQuantity.Value(),
+	}
+
 	return config.AutoscalingOptions{
 		CloudConfig:                      *cloudConfig,
+		InstancePricesFile:               *instancePricesFile,
 		CloudProviderName:                *cloudProviderFlag,
 		NodeGroupAutoDiscovery:           *nodeGroupAutoDiscoveryFlag,
 		MaxTotalUnreadyPercentage:        *maxTotalUnreadyPercentage,
 		OkTotalUnreadyCount:              *okTotalUnreadyCount,
 		EstimatorName:                    *estimatorFlag,
 		ExpanderName:                     *expanderFlag,
+		GRPCExpanderURL:                  *grpcExpanderURL,
+		GRPCExpanderCert:                 *grpcExpanderCert,
+		GRPCExpanderTimeout:              *grpcExpanderTimeout,
 		IgnoreDaemonSetsUtilization:      *ignoreDaemonSetsUtilization,
 		IgnoreMirrorPodsUtilization:      *ignoreMirrorPodsUtilization,
 		MaxEmptyBulkDelete:               *maxEmptyBulkDeleteFlag,
+		MaxBulkSoftTaintCount:            *maxBulkSoftTaintCount,
+		MaxDrainParallelism:              *maxDrainParallelismFlag,
 		MaxGracefulTerminationSec:        *maxGracefulTerminationFlag,
+		ReplacementPodsTimeout:           *replacementPodsTimeout,
 		MaxNodeProvisionTime:             *maxNodeProvisionTime,
 		MaxNodesTotal:                    *maxNodesTotal,
 		MaxCoresTotal:                    maxCoresTotal,
@@ -202,21 +254,30 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		ScaleDownEnabled:                 *scaleDownEnabled,
 		ScaleDownUnneededTime:            *scaleDownUnneededTime,
 		ScaleDownUnreadyTime:             *scaleDownUnreadyTime,
+		ScaleDownAllowedWindows:          *scaleDownAllowedWindows,
+		ScaleDownAllowedSchedule:         scaleDownAllowedSchedule,
 		ScaleDownUtilizationThreshold:    *scaleDownUtilizationThreshold,
 		ScaleDownNonEmptyCandidatesCount: *scaleDownNonEmptyCandidatesCount,
 		ScaleDownCandidatesPoolRatio:     *scaleDownCandidatesPoolRatio,
 		ScaleDownCandidatesPoolMinCount:  *scaleDownCandidatesPoolMinCount,
+		ScaleDownMaxDisruptionCost:       *scaleDownMaxDisruptionCost,
 		WriteStatusConfigMap:             *writeStatusConfigMapFlag,
+		WriteStatusResource:              *writeStatusResourceFlag,
 		BalanceSimilarNodeGroups:         *balanceSimilarNodeGroupsFlag,
+		ScaleDownBalanceMaxSkew:          *scaleDownBalanceMaxSkew,
 		ConfigNamespace:                  *namespace,
 		ClusterName:                      *clusterName,
 		NodeAutoprovisioningEnabled:      *nodeAutoprovisioningEnabled,
 		MaxAutoprovisionedNodeGroupCount: *maxAutoprovisionedNodeGroupCount,
 		UnremovableNodeRecheckTimeout:    *unremovableNodeRecheckTimeout,
 		ExpendablePodsPriorityCutoff:     *expendablePodsPriorityCutoff,
+		Headroom:                         headroom,
 		Regional:                         *regional,
 		NewPodScaleUpDelay:               *newPodScaleUpDelay,
 		KubeConfigPath:                   *kubeConfigFile,
+		DryRun:                           *dryRun,
+		RecordLoopsDir:                   *recordLoopsDir,
+		RecordLoopsMaxFiles:              *recordLoopsMaxFiles,
 	}
 }
 
@@ -247,7 +308,7 @@ func createKubeClient(kubeConfig *rest.Config) kube_client.Interface {
 	return kube_client.NewForConfigOrDie(kubeConfig)
 }
 
-func registerSignalHandlers(autoscaler core.Autoscaler) {
+func registerSignalHandlers(autoscaler core.Autoscaler, stopChannel chan struct{}) {
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, os.Interrupt, os.Kill, syscall.SIGTERM, syscall.SIGQUIT)
 	klog.V(1).Info("Registered cleanup signal handler")
@@ -256,26 +317,37 @@ func registerSignalHandlers(autoscaler core.Autoscaler) {
 		<-sigs
 		klog.V(1).Info("Received signal, attempting cleanup")
 		autoscaler.ExitCleanUp()
+		close(stopChannel)
 		klog.V(1).Info("Cleaned up, exiting...")
 		klog.Flush()
 		os.Exit(0)
 	}()
 }
 
-func buildAutoscaler() (core.Autoscaler, error) {
+func buildAutoscaler(stopChannel <-chan struct{}) (core.Autoscaler, error) {
 	// Create basic config from flags.
 	autoscalingOptions := createAutoscalingOptions()
-	kubeClient := createKubeClient(getKubeConfig())
+	kubeConfig := getKubeConfig()
+	kubeClient := createKubeClient(kubeConfig)
 	processors := ca_processors.DefaultProcessors()
 	if autoscalingOptions.CloudProviderName == "gke" {
 		processors.NodeGroupSetProcessor = &nodegroupset.BalancingNodeGroupSetProcessor{
 			Comparator: nodegroupset.IsGkeNodeInfoSimilar}
 
 	}
+	// Only openshift-machine-api creates node groups through the generic autoprovisioning processors.
+	if autoscalingOptions.NodeAutoprovisioningEnabled && autoscalingOptions.CloudProviderName == "openshift-machine-api" {
+		processors.NodeGroupListProcessor = nodegroups.NewAutoprovisioningNodeGroupListProcessor()
+		processors.NodeGroupManager = nodegroups.NewAutoprovisioningNodeGroupManager()
+	}
+	if autoscalingOptions.WriteStatusResource {
+		processors.AutoscalingStatusProcessor = status.NewStatusResourceProcessor(dynamic.NewForConfigOrDie(kubeConfig))
+	}
 	opts := core.AutoscalerOptions{
 		AutoscalingOptions: autoscalingOptions,
 		KubeClient:         kubeClient,
 		Processors:         processors,
+		StopChannel:        stopChannel,
 	}
 
 	// This metric should be published only once.
@@ -288,13 +360,14 @@ func buildAutoscaler() (core.Autoscaler, error) {
 func run(healthCheck *metrics.HealthCheck) {
 	metrics.RegisterAll()
 
-	autoscaler, err := buildAutoscaler()
+	stopChannel := make(chan struct{})
+	autoscaler, err := buildAutoscaler(stopChannel)
 	if err != nil {
 		klog.Fatalf("Failed to create autoscaler: %v", err)
 	}
 
 	// Register signal handlers for graceful shutdown.
-	registerSignalHandlers(autoscaler)
+	registerSignalHandlers(autoscaler, stopChannel)
 
 	// Start updating health check endpoint.
 	healthCheck.StartMonitoring()
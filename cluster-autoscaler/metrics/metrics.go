@@ -59,6 +59,10 @@ const (
 	APIError FailedScaleUpReason = "apiCallError"
 	// Timeout was encountered when trying to scale-up
 	Timeout FailedScaleUpReason = "timeout"
+	// CloudProviderError caused instances added by scale-up to fail to be created
+	CloudProviderError FailedScaleUpReason = "cloudProviderError"
+	// OutOfResources caused instances added by scale-up to fail to be created
+	OutOfResources FailedScaleUpReason = "outOfResources"
 
 	// autoscaledGroup is managed by CA
 	autoscaledGroup NodeGroupType = "autoscaled"
@@ -198,6 +202,22 @@ var (
 		},
 	)
 
+	simulatedScaleUpCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "simulated_scaled_up_nodes_total",
+			Help:      "Number of nodes CA would have added if it was not running in dry-run mode.",
+		},
+	)
+
+	simulatedScaleDownCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "simulated_scaled_down_nodes_total",
+			Help:      "Number of nodes CA would have removed if it was not running in dry-run mode.",
+		}, []string{"reason"},
+	)
+
 	unneededNodesCount = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: caNamespace,
@@ -206,6 +226,14 @@ var (
 		},
 	)
 
+	unremovableNodesCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: caNamespace,
+			Name:      "unremovable_nodes_count",
+			Help:      "Number of nodes currently considered unremovable by CA, by the reason.",
+		}, []string{"reason"},
+	)
+
 	/**** Metrics related to NodeAutoprovisioning ****/
 	napEnabled = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -247,7 +275,10 @@ func RegisterAll() {
 	prometheus.MustRegister(scaleDownCount)
 	prometheus.MustRegister(gpuScaleDownCount)
 	prometheus.MustRegister(evictionsCount)
+	prometheus.MustRegister(simulatedScaleUpCount)
+	prometheus.MustRegister(simulatedScaleDownCount)
 	prometheus.MustRegister(unneededNodesCount)
+	prometheus.MustRegister(unremovableNodesCount)
 	prometheus.MustRegister(napEnabled)
 	prometheus.MustRegister(nodeGroupCreationCount)
 	prometheus.MustRegister(nodeGroupDeletionCount)
@@ -336,11 +367,29 @@ func RegisterEvictions(podsCount int) {
 	evictionsCount.Add(float64(podsCount))
 }
 
+// RegisterSimulatedScaleUp records number of nodes that would be added by scale up in dry-run mode
+func RegisterSimulatedScaleUp(nodesCount int) {
+	simulatedScaleUpCount.Add(float64(nodesCount))
+}
+
+// RegisterSimulatedScaleDown records number of nodes that would be removed by scale down in dry-run mode
+func RegisterSimulatedScaleDown(nodesCount int, reason NodeScaleDownReason) {
+	simulatedScaleDownCount.WithLabelValues(string(reason)).Add(float64(nodesCount))
+}
+
 // UpdateUnneededNodesCount records number of currently unneeded nodes
 func UpdateUnneededNodesCount(nodesCount int) {
 	unneededNodesCount.Set(float64(nodesCount))
 }
 
+// UpdateUnremovableNodesCount records number of currently unremovable nodes by the reason
+func UpdateUnremovableNodesCount(countByReason map[string]int) {
+	unremovableNodesCount.Reset()
+	for reason, count := range countByReason {
+		unremovableNodesCount.WithLabelValues(reason).Set(float64(count))
+	}
+}
+
 // UpdateNapEnabled records if NodeAutoprovisioning is enabled
 func UpdateNapEnabled(enabled bool) {
 	if enabled {
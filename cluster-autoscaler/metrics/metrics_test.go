@@ -0,0 +1,42 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func gaugeValue(t *testing.T, gauge prometheus.Gauge) float64 {
+	metric := &dto.Metric{}
+	assert.NoError(t, gauge.Write(metric))
+	return metric.GetGauge().GetValue()
+}
+
+func TestUpdateUnremovableNodesCount(t *testing.T) {
+	UpdateUnremovableNodesCount(map[string]int{"NotReplicated": 2, "LocalStorageRequested": 1})
+	assert.Equal(t, float64(2), gaugeValue(t, unremovableNodesCount.WithLabelValues("NotReplicated")))
+	assert.Equal(t, float64(1), gaugeValue(t, unremovableNodesCount.WithLabelValues("LocalStorageRequested")))
+
+	// Reasons missing in the next update are reset.
+	UpdateUnremovableNodesCount(map[string]int{"UnmovableKubeSystemPod": 1})
+	assert.Equal(t, float64(0), gaugeValue(t, unremovableNodesCount.WithLabelValues("NotReplicated")))
+	assert.Equal(t, float64(1), gaugeValue(t, unremovableNodesCount.WithLabelValues("UnmovableKubeSystemPod")))
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodegroups
+
+import (
+	"fmt"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/labels"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"k8s.io/klog"
+)
+
+// AutoprovisioningNodeGroupListProcessor adds theoretical node groups, one per machine type
+// available in the cloud provider, to the list of node groups considered in scale-up. The
+// node groups get the labels and taints that fit the unschedulable pods best.
+type AutoprovisioningNodeGroupListProcessor struct {
+}
+
+// NewAutoprovisioningNodeGroupListProcessor creates an instance of AutoprovisioningNodeGroupListProcessor.
+func NewAutoprovisioningNodeGroupListProcessor() NodeGroupListProcessor {
+	return &AutoprovisioningNodeGroupListProcessor{}
+}
+
+// Process extends the list of node groups with not yet existing autoprovisioned node groups,
+// unless autoprovisioning is disabled or MaxAutoprovisionedNodeGroupCount is already reached.
+func (p *AutoprovisioningNodeGroupListProcessor) Process(context *context.AutoscalingContext, nodeGroups []cloudprovider.NodeGroup, nodeInfos map[string]*schedulercache.NodeInfo,
+	unschedulablePods []*apiv1.Pod) ([]cloudprovider.NodeGroup, map[string]*schedulercache.NodeInfo, error) {
+
+	if !context.NodeAutoprovisioningEnabled || len(unschedulablePods) == 0 {
+		return nodeGroups, nodeInfos, nil
+	}
+
+	existing := make(map[string]bool)
+	autoprovisionedCount := 0
+	for _, nodeGroup := range nodeGroups {
+		existing[nodeGroup.Id()] = true
+		if nodeGroup.Autoprovisioned() {
+			autoprovisionedCount++
+		}
+	}
+	if autoprovisionedCount >= context.MaxAutoprovisionedNodeGroupCount {
+		klog.V(4).Infof("Max autoprovisioned node group count reached (%d), not considering new node groups", context.MaxAutoprovisionedNodeGroupCount)
+		return nodeGroups, nodeInfos, nil
+	}
+
+	machineTypes, err := context.CloudProvider.GetAvailableMachineTypes()
+	if err == cloudprovider.ErrNotImplemented {
+		klog.V(4).Infof("Cloud provider doesn't list machine types, not considering new node groups")
+		return nodeGroups, nodeInfos, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get available machine types: %v", err)
+	}
+
+	bestLabels := labels.BestLabelSet(unschedulablePods)
+	taints := tolerationTaints(unschedulablePods, bestLabels)
+	for _, machineType := range machineTypes {
+		nodeGroup, err := context.CloudProvider.NewNodeGroup(machineType, bestLabels, map[string]string{}, taints, map[string]resource.Quantity{})
+		if err != nil {
+			klog.Warningf("Failed to build autoprovisioned node group for machine type %s: %v", machineType, err)
+			continue
+		}
+		if existing[nodeGroup.Id()] {
+			continue
+		}
+		nodeInfo, err := nodeGroup.TemplateNodeInfo()
+		if err != nil {
+			klog.Warningf("Failed to build template for autoprovisioned node group %s: %v", nodeGroup.Id(), err)
+			continue
+		}
+		existing[nodeGroup.Id()] = true
+		nodeInfos[nodeGroup.Id()] = nodeInfo
+		nodeGroups = append(nodeGroups, nodeGroup)
+	}
+	return nodeGroups, nodeInfos, nil
+}
+
+// CleanUp cleans up the processor's internal structures.
+func (p *AutoprovisioningNodeGroupListProcessor) CleanUp() {
+}
+
+// tolerationTaints returns the taints that are tolerated by all the pods that fit nodes with
+// the given labels, so that a node group with these taints keeps other pods away. Only
+// tolerations that match a single taint exactly are considered.
+func tolerationTaints(pods []*apiv1.Pod, nodeLabels map[string]string) []apiv1.Taint {
+	var result []apiv1.Taint
+	first := true
+	for _, pod := range pods {
+		if !fitsLabels(pod, nodeLabels) {
+			continue
+		}
+		var tolerated []apiv1.Taint
+		for _, toleration := range pod.Spec.Tolerations {
+			if toleration.Key == "" || toleration.Effect == "" || toleration.Operator == apiv1.TolerationOpExists {
+				continue
+			}
+			taint := apiv1.Taint{Key: toleration.Key, Value: toleration.Value, Effect: toleration.Effect}
+			if first || containsTaint(result, taint) {
+				tolerated = append(tolerated, taint)
+			}
+		}
+		result = tolerated
+		first = false
+	}
+	return result
+}
+
+func fitsLabels(pod *apiv1.Pod, nodeLabels map[string]string) bool {
+	for k, v := range pod.Spec.NodeSelector {
+		if nodeLabels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func containsTaint(taints []apiv1.Taint, taint apiv1.Taint) bool {
+	for _, t := range taints {
+		if t.MatchTaint(&taint) && t.Value == taint.Value {
+			return true
+		}
+	}
+	return false
+}
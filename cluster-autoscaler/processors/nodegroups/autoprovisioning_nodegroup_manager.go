@@ -0,0 +1,101 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodegroups
+
+import (
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+
+	"k8s.io/klog"
+)
+
+// AutoprovisioningNodeGroupManager creates autoprovisioned node groups in the cloud provider
+// and deletes them again once they are empty.
+type AutoprovisioningNodeGroupManager struct {
+}
+
+// NewAutoprovisioningNodeGroupManager creates an instance of AutoprovisioningNodeGroupManager.
+func NewAutoprovisioningNodeGroupManager() NodeGroupManager {
+	return &AutoprovisioningNodeGroupManager{}
+}
+
+// CreateNodeGroup creates the given theoretical node group, unless autoprovisioning is disabled
+// or MaxAutoprovisionedNodeGroupCount is already reached.
+func (m *AutoprovisioningNodeGroupManager) CreateNodeGroup(context *context.AutoscalingContext, nodeGroup cloudprovider.NodeGroup) (CreateNodeGroupResult, errors.AutoscalerError) {
+	if !context.NodeAutoprovisioningEnabled {
+		return CreateNodeGroupResult{}, errors.NewAutoscalerError(errors.InternalError, "node autoprovisioning is disabled")
+	}
+
+	autoprovisionedCount := 0
+	for _, existing := range context.CloudProvider.NodeGroups() {
+		if existing.Autoprovisioned() {
+			autoprovisionedCount++
+		}
+	}
+	if autoprovisionedCount >= context.MaxAutoprovisionedNodeGroupCount {
+		return CreateNodeGroupResult{}, errors.NewAutoscalerError(errors.TransientError,
+			"max autoprovisioned node group count reached (%d)", context.MaxAutoprovisionedNodeGroupCount)
+	}
+
+	newNodeGroup, err := nodeGroup.Create()
+	if err != nil {
+		return CreateNodeGroupResult{}, errors.ToAutoscalerError(errors.CloudProviderError, err)
+	}
+	metrics.RegisterNodeGroupCreation()
+	klog.V(1).Infof("Created autoprovisioned node group %s", newNodeGroup.Id())
+	return CreateNodeGroupResult{MainCreatedNodeGroup: newNodeGroup}, nil
+}
+
+// RemoveUnneededNodeGroups deletes the autoprovisioned node groups that have no nodes and a
+// target size of 0.
+func (m *AutoprovisioningNodeGroupManager) RemoveUnneededNodeGroups(context *context.AutoscalingContext) error {
+	if !context.NodeAutoprovisioningEnabled {
+		return nil
+	}
+	for _, nodeGroup := range context.CloudProvider.NodeGroups() {
+		if !nodeGroup.Autoprovisioned() {
+			continue
+		}
+		targetSize, err := nodeGroup.TargetSize()
+		if err != nil {
+			return err
+		}
+		if targetSize > 0 {
+			continue
+		}
+		nodes, err := nodeGroup.Nodes()
+		if err != nil {
+			return err
+		}
+		if len(nodes) > 0 {
+			continue
+		}
+		if err := nodeGroup.Delete(); err != nil {
+			klog.Warningf("Failed to delete autoprovisioned node group %s: %v", nodeGroup.Id(), err)
+			continue
+		}
+		metrics.RegisterNodeGroupDeletion()
+		klog.V(1).Infof("Deleted empty autoprovisioned node group %s", nodeGroup.Id())
+	}
+	return nil
+}
+
+// CleanUp cleans up the manager's internal structures.
+func (m *AutoprovisioningNodeGroupManager) CleanUp() {
+}
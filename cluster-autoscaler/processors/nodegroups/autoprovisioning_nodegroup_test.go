@@ -0,0 +1,206 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodegroups
+
+import (
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildAutoprovisioningContext(enabled bool, maxCount int) (*context.AutoscalingContext, *testprovider.TestCloudProvider) {
+	t1 := BuildTestNode("t1", 1000, 1000)
+	ti1 := schedulercache.NewNodeInfo()
+	ti1.SetNode(t1)
+	t2 := BuildTestNode("t2", 4000, 4000)
+	ti2 := schedulercache.NewNodeInfo()
+	ti2.SetNode(t2)
+
+	provider := testprovider.NewTestAutoprovisioningCloudProvider(nil, nil,
+		func(string) error { return nil }, func(string) error { return nil },
+		[]string{"T1", "T2"}, map[string]*schedulercache.NodeInfo{"T1": ti1, "T2": ti2, "ng1": ti1})
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+
+	return &context.AutoscalingContext{
+		AutoscalingOptions: config.AutoscalingOptions{
+			NodeAutoprovisioningEnabled:      enabled,
+			MaxAutoprovisionedNodeGroupCount: maxCount,
+		},
+		CloudProvider: provider,
+	}, provider
+}
+
+func TestAutoprovisioningNodeGroupListProcessor(t *testing.T) {
+	p1 := BuildTestPod("p1", 100, 100)
+
+	for _, tc := range []struct {
+		name     string
+		enabled  bool
+		maxCount int
+		existing int
+		pods     []*apiv1.Pod
+		expected []string
+	}{
+		{
+			name:     "adds a node group per machine type",
+			enabled:  true,
+			maxCount: 10,
+			pods:     []*apiv1.Pod{p1},
+			expected: []string{"ng1", "autoprovisioned-T1", "autoprovisioned-T2"},
+		},
+		{
+			name:     "skips existing node groups",
+			enabled:  true,
+			maxCount: 10,
+			existing: 1,
+			pods:     []*apiv1.Pod{p1},
+			expected: []string{"ng1", "autoprovisioned-T1", "autoprovisioned-T2"},
+		},
+		{
+			name:     "disabled",
+			maxCount: 10,
+			pods:     []*apiv1.Pod{p1},
+			expected: []string{"ng1"},
+		},
+		{
+			name:     "max autoprovisioned node group count reached",
+			enabled:  true,
+			maxCount: 1,
+			existing: 1,
+			pods:     []*apiv1.Pod{p1},
+			expected: []string{"ng1", "autoprovisioned-T1"},
+		},
+		{
+			name:     "no unschedulable pods",
+			enabled:  true,
+			maxCount: 10,
+			expected: []string{"ng1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			context, provider := buildAutoprovisioningContext(tc.enabled, tc.maxCount)
+			if tc.existing > 0 {
+				provider.AddAutoprovisionedNodeGroup("autoprovisioned-T1", 0, 10, 0, "T1")
+			}
+			nodeGroups := provider.NodeGroups()
+			nodeInfos := map[string]*schedulercache.NodeInfo{}
+
+			processor := NewAutoprovisioningNodeGroupListProcessor()
+			nodeGroups, nodeInfos, err := processor.Process(context, nodeGroups, nodeInfos, tc.pods)
+			assert.NoError(t, err)
+
+			var ids []string
+			for _, nodeGroup := range nodeGroups {
+				ids = append(ids, nodeGroup.Id())
+				if !nodeGroup.Exist() {
+					assert.NotNil(t, nodeInfos[nodeGroup.Id()])
+				}
+			}
+			assert.ElementsMatch(t, tc.expected, ids)
+		})
+	}
+}
+
+// noMachineTypesCloudProvider doesn't implement listing machine types.
+type noMachineTypesCloudProvider struct {
+	*testprovider.TestCloudProvider
+}
+
+func (p *noMachineTypesCloudProvider) GetAvailableMachineTypes() ([]string, error) {
+	return nil, cloudprovider.ErrNotImplemented
+}
+
+func TestAutoprovisioningNodeGroupListProcessorNoMachineTypes(t *testing.T) {
+	context, provider := buildAutoprovisioningContext(true, 10)
+	context.CloudProvider = &noMachineTypesCloudProvider{provider}
+	nodeGroups := provider.NodeGroups()
+	nodeInfos := map[string]*schedulercache.NodeInfo{}
+
+	processor := NewAutoprovisioningNodeGroupListProcessor()
+	resultNodeGroups, resultNodeInfos, err := processor.Process(context, nodeGroups, nodeInfos,
+		[]*apiv1.Pod{BuildTestPod("p1", 100, 100)})
+	assert.NoError(t, err)
+	assert.Equal(t, nodeGroups, resultNodeGroups)
+	assert.Equal(t, nodeInfos, resultNodeInfos)
+}
+
+func TestTolerationTaints(t *testing.T) {
+	dedicated := apiv1.Toleration{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "foo", Effect: apiv1.TaintEffectNoSchedule}
+	gpu := apiv1.Toleration{Key: "gpu", Operator: apiv1.TolerationOpEqual, Value: "true", Effect: apiv1.TaintEffectNoSchedule}
+	exists := apiv1.Toleration{Key: "any", Operator: apiv1.TolerationOpExists}
+
+	p1 := BuildTestPod("p1", 100, 100)
+	p1.Spec.Tolerations = []apiv1.Toleration{dedicated, gpu, exists}
+	p2 := BuildTestPod("p2", 100, 100)
+	p2.Spec.Tolerations = []apiv1.Toleration{dedicated}
+	p3 := BuildTestPod("p3", 100, 100)
+	p3.Spec.NodeSelector = map[string]string{"other": "true"}
+
+	assert.Equal(t, []apiv1.Taint{
+		{Key: "dedicated", Value: "foo", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "gpu", Value: "true", Effect: apiv1.TaintEffectNoSchedule},
+	}, tolerationTaints([]*apiv1.Pod{p1}, map[string]string{}))
+	assert.Equal(t, []apiv1.Taint{
+		{Key: "dedicated", Value: "foo", Effect: apiv1.TaintEffectNoSchedule},
+	}, tolerationTaints([]*apiv1.Pod{p1, p2, p3}, map[string]string{}))
+	assert.Empty(t, tolerationTaints([]*apiv1.Pod{p1, p3}, map[string]string{"other": "true"}))
+}
+
+func TestAutoprovisioningNodeGroupManagerCreateNodeGroup(t *testing.T) {
+	context, provider := buildAutoprovisioningContext(true, 1)
+	manager := NewAutoprovisioningNodeGroupManager()
+
+	nodeGroup, err := provider.NewNodeGroup("T1", nil, nil, nil, nil)
+	assert.NoError(t, err)
+	result, typedErr := manager.CreateNodeGroup(context, nodeGroup)
+	assert.NoError(t, typedErr)
+	assert.True(t, result.MainCreatedNodeGroup.Exist())
+	assert.NotNil(t, provider.GetNodeGroup("autoprovisioned-T1"))
+
+	// MaxAutoprovisionedNodeGroupCount is reached.
+	nodeGroup, err = provider.NewNodeGroup("T2", nil, nil, nil, nil)
+	assert.NoError(t, err)
+	_, typedErr = manager.CreateNodeGroup(context, nodeGroup)
+	assert.Error(t, typedErr)
+	assert.Nil(t, provider.GetNodeGroup("autoprovisioned-T2"))
+}
+
+func TestAutoprovisioningNodeGroupManagerRemoveUnneededNodeGroups(t *testing.T) {
+	context, provider := buildAutoprovisioningContext(true, 10)
+	provider.AddAutoprovisionedNodeGroup("empty", 0, 10, 0, "T1")
+	provider.AddAutoprovisionedNodeGroup("scaling-up", 0, 10, 1, "T1")
+	provider.AddAutoprovisionedNodeGroup("with-nodes", 0, 10, 0, "T1")
+	provider.AddNode("with-nodes", BuildTestNode("n1", 1000, 1000))
+	provider.AddNodeGroup("not-autoprovisioned", 0, 10, 0)
+
+	manager := NewAutoprovisioningNodeGroupManager()
+	assert.NoError(t, manager.RemoveUnneededNodeGroups(context))
+
+	var ids []string
+	for _, nodeGroup := range provider.NodeGroups() {
+		ids = append(ids, nodeGroup.Id())
+	}
+	assert.ElementsMatch(t, []string{"ng1", "scaling-up", "with-nodes", "not-autoprovisioned"}, ids)
+}
@@ -36,7 +36,12 @@ func (p *EventingScaleUpStatusProcessor) Process(context *context.AutoscalingCon
 		context.Recorder.Event(noScaleUpInfo.Pod, apiv1.EventTypeNormal, "NotTriggerScaleUp",
 			fmt.Sprintf("pod didn't trigger scale-up (it wouldn't fit if a new node is added): %s", ReasonsMessage(noScaleUpInfo)))
 	}
-	if len(status.ScaleUpInfos) > 0 {
+	if len(status.ScaleUpInfos) > 0 && status.Simulated {
+		for _, pod := range status.PodsTriggeredScaleUp {
+			context.Recorder.Eventf(pod, apiv1.EventTypeNormal, "TriggeredSimulatedScaleUp",
+				"pod would have triggered scale-up (dry run): %v", status.ScaleUpInfos)
+		}
+	} else if len(status.ScaleUpInfos) > 0 {
 		for _, pod := range status.PodsTriggeredScaleUp {
 			context.Recorder.Eventf(pod, apiv1.EventTypeNormal, "TriggeredScaleUp",
 				"pod triggered scale-up: %v", status.ScaleUpInfos)
@@ -55,6 +55,7 @@ func TestEventingScaleUpStatusProcessor(t *testing.T) {
 		caseName            string
 		state               *ScaleUpStatus
 		expectedTriggered   int
+		expectedSimulated   int
 		expectedNoTriggered int
 	}{
 		{
@@ -81,6 +82,19 @@ func TestEventingScaleUpStatusProcessor(t *testing.T) {
 			expectedTriggered:   1,
 			expectedNoTriggered: 2,
 		},
+		{
+			caseName: "Simulated scale up",
+			state: &ScaleUpStatus{
+				ScaleUpInfos:         []nodegroupset.ScaleUpInfo{{}},
+				PodsTriggeredScaleUp: []*apiv1.Pod{p3},
+				PodsRemainUnschedulable: []NoScaleUpInfo{
+					{p1, reasons, reasons},
+				},
+				Simulated: true,
+			},
+			expectedSimulated:   1,
+			expectedNoTriggered: 1,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -92,12 +106,15 @@ func TestEventingScaleUpStatusProcessor(t *testing.T) {
 		}
 		p.Process(context, tc.state)
 		triggered := 0
+		simulated := 0
 		noTriggered := 0
 		for eventsLeft := true; eventsLeft; {
 			select {
 			case event := <-fakeRecorder.Events:
 				if strings.Contains(event, "TriggeredScaleUp") {
 					triggered += 1
+				} else if strings.Contains(event, "TriggeredSimulatedScaleUp") {
+					simulated += 1
 				} else if strings.Contains(event, "NotTriggerScaleUp") {
 					noTriggered += 1
 				} else {
@@ -108,6 +125,7 @@ func TestEventingScaleUpStatusProcessor(t *testing.T) {
 			}
 		}
 		assert.Equal(t, tc.expectedTriggered, triggered, "Test case '%v' failed.", tc.caseName)
+		assert.Equal(t, tc.expectedSimulated, simulated, "Test case '%v' failed.", tc.caseName)
 		assert.Equal(t, tc.expectedNoTriggered, noTriggered, "Test case '%v' failed.", tc.caseName)
 	}
 }
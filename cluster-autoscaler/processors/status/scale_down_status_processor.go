@@ -28,6 +28,23 @@ type ScaleDownStatus struct {
 	Result            ScaleDownResult
 	ScaledDownNodes   []*ScaleDownNode
 	NodeDeleteResults map[string]error
+	// Simulated is true if the scale-down was only planned because CA runs in dry-run mode.
+	Simulated bool
+	// CandidatesRanking lists the priced scale-down candidates by the money saved per moved pod,
+	// most first. It is empty if the cloud provider has no pricing model.
+	CandidatesRanking []simulator.NodeRemovalSavings
+	// SkippedNodes lists the unneeded nodes that were not scaled down on purpose, with the reason.
+	SkippedNodes []*ScaleDownSkippedNode
+	// UnremovableNodes lists the nodes that couldn't be removed in this loop, with the reason.
+	UnremovableNodes []*simulator.UnremovableNode
+}
+
+// ScaleDownSkippedNode represents an unneeded node that wasn't scaled down.
+type ScaleDownSkippedNode struct {
+	Node   *apiv1.Node
+	Reason string
+	// DisruptionCost is the sum of disruption costs of the pods that would have to be moved.
+	DisruptionCost float64
 }
 
 // ScaleDownNode represents the state of a node that's being scaled down.
@@ -59,6 +76,9 @@ const (
 	ScaleDownInCooldown
 	// ScaleDownInProgress - the scale down wasn't attempted, because a previous scale-down was still in progress.
 	ScaleDownInProgress
+	// ScaleDownInBlackout - the scale down wasn't attempted, or no node could be removed, because the current time
+	// is outside of the windows in which scale down is allowed.
+	ScaleDownInBlackout
 )
 
 // ScaleDownStatusProcessor processes the status of the cluster after a scale-down.
@@ -31,6 +31,8 @@ type ScaleUpStatus struct {
 	PodsTriggeredScaleUp    []*apiv1.Pod
 	PodsRemainUnschedulable []NoScaleUpInfo
 	PodsAwaitEvaluation     []*apiv1.Pod
+	// Simulated is true if the scale-up was only planned because CA runs in dry-run mode.
+	Simulated bool
 }
 
 // NoScaleUpInfo contains information about a pod that didn't trigger scale-up.
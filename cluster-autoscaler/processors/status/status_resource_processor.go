@@ -0,0 +1,47 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/client-go/dynamic"
+)
+
+// StatusResourceProcessor is an AutoscalingStatusProcessor writing the status of the cluster to the
+// ClusterAutoscalerStatus custom resource.
+type StatusResourceProcessor struct {
+	client dynamic.Interface
+}
+
+// NewStatusResourceProcessor creates a StatusResourceProcessor writing the status with the given client.
+func NewStatusResourceProcessor(client dynamic.Interface) *StatusResourceProcessor {
+	return &StatusResourceProcessor{client: client}
+}
+
+// Process writes the status of the cluster after an autoscaling iteration.
+func (p *StatusResourceProcessor) Process(context *context.AutoscalingContext, csr *clusterstate.ClusterStateRegistry, now time.Time) error {
+	status := csr.GetStatus(now)
+	return utils.WriteStatusResource(p.client, context.ConfigNamespace, status, now)
+}
+
+// CleanUp cleans up the processor's internal structures.
+func (p *StatusResourceProcessor) CleanUp() {
+}
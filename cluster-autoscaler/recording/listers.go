@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	"sync"
+
+	apiv1 "k8s.io/api/core/v1"
+	extensionsv1 "k8s.io/api/extensions/v1beta1"
+	policyv1 "k8s.io/api/policy/v1beta1"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+)
+
+// StaticListerRegistry is a ListerRegistry returning objects stored in a LoopRecording. The recording
+// can be replaced with SetRecording, listers obtained from the registry earlier follow the change.
+type StaticListerRegistry struct {
+	sync.Mutex
+	recording *LoopRecording
+}
+
+// NewStaticListerRegistry builds a StaticListerRegistry for the given recording.
+func NewStaticListerRegistry(recording *LoopRecording) *StaticListerRegistry {
+	return &StaticListerRegistry{recording: recording}
+}
+
+// SetRecording replaces the recording returned by listers.
+func (r *StaticListerRegistry) SetRecording(recording *LoopRecording) {
+	r.Lock()
+	defer r.Unlock()
+	r.recording = recording
+}
+
+func (r *StaticListerRegistry) current() *LoopRecording {
+	r.Lock()
+	defer r.Unlock()
+	return r.recording
+}
+
+// AllNodeLister returns a lister of all recorded nodes.
+func (r *StaticListerRegistry) AllNodeLister() kube_util.NodeLister {
+	return &staticNodeLister{registry: r, ready: false}
+}
+
+// ReadyNodeLister returns a lister of recorded ready nodes.
+func (r *StaticListerRegistry) ReadyNodeLister() kube_util.NodeLister {
+	return &staticNodeLister{registry: r, ready: true}
+}
+
+// ScheduledPodLister returns a lister of recorded scheduled pods.
+func (r *StaticListerRegistry) ScheduledPodLister() kube_util.PodLister {
+	return &staticPodLister{registry: r, scheduled: true}
+}
+
+// UnschedulablePodLister returns a lister of recorded unschedulable pods.
+func (r *StaticListerRegistry) UnschedulablePodLister() kube_util.PodLister {
+	return &staticPodLister{registry: r, scheduled: false}
+}
+
+// PodDisruptionBudgetLister returns a lister of recorded pod disruption budgets.
+func (r *StaticListerRegistry) PodDisruptionBudgetLister() kube_util.PodDisruptionBudgetLister {
+	return &staticPodDisruptionBudgetLister{registry: r}
+}
+
+// DaemonSetLister returns a lister of recorded daemon sets.
+func (r *StaticListerRegistry) DaemonSetLister() kube_util.DaemonSetLister {
+	return &staticDaemonSetLister{registry: r}
+}
+
+type staticNodeLister struct {
+	registry *StaticListerRegistry
+	ready    bool
+}
+
+func (l *staticNodeLister) List() ([]*apiv1.Node, error) {
+	if l.ready {
+		return l.registry.current().ReadyNodes, nil
+	}
+	return l.registry.current().AllNodes, nil
+}
+
+type staticPodLister struct {
+	registry  *StaticListerRegistry
+	scheduled bool
+}
+
+func (l *staticPodLister) List() ([]*apiv1.Pod, error) {
+	if l.scheduled {
+		return l.registry.current().ScheduledPods, nil
+	}
+	return l.registry.current().UnschedulablePods, nil
+}
+
+type staticPodDisruptionBudgetLister struct {
+	registry *StaticListerRegistry
+}
+
+func (l *staticPodDisruptionBudgetLister) List() ([]*policyv1.PodDisruptionBudget, error) {
+	return l.registry.current().PodDisruptionBudgets, nil
+}
+
+type staticDaemonSetLister struct {
+	registry *StaticListerRegistry
+}
+
+func (l *staticDaemonSetLister) List() ([]*extensionsv1.DaemonSet, error) {
+	return l.registry.current().DaemonSets, nil
+}
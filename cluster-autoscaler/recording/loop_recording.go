@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	extensionsv1 "k8s.io/api/extensions/v1beta1"
+	policyv1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/klog"
+)
+
+// LoopRecording contains all inputs of a single autoscaler loop: the objects returned by listers
+// and the state of node groups at the beginning of the loop.
+type LoopRecording struct {
+	// Timestamp is the current time passed to the loop.
+	Timestamp time.Time
+	// Options are the autoscaling options the loop ran with.
+	Options              config.AutoscalingOptions
+	AllNodes             []*apiv1.Node
+	ReadyNodes           []*apiv1.Node
+	ScheduledPods        []*apiv1.Pod
+	UnschedulablePods    []*apiv1.Pod
+	PodDisruptionBudgets []*policyv1.PodDisruptionBudget
+	DaemonSets           []*extensionsv1.DaemonSet
+	NodeGroups           []NodeGroupRecording
+}
+
+// NodeGroupRecording contains the state of a single node group.
+type NodeGroupRecording struct {
+	Id         string
+	MinSize    int
+	MaxSize    int
+	TargetSize int
+	// Nodes contains names of Kubernetes nodes belonging to the node group.
+	Nodes []string
+	// Options are set only if the node group overrides the default options.
+	Options *config.NodeGroupAutoscalingOptions `json:",omitempty"`
+	// TemplateNode and TemplatePods describe the node group template. They are recorded only
+	// for node groups without ready nodes, as only then the template is used by the loop.
+	TemplateNode *apiv1.Node  `json:",omitempty"`
+	TemplatePods []*apiv1.Pod `json:",omitempty"`
+}
+
+// NewLoopRecording lists all Kubernetes inputs of the loop from the given listers. Node groups are
+// recorded separately with RecordNodeGroups, once the cloud provider is refreshed.
+func NewLoopRecording(currentTime time.Time, options config.AutoscalingOptions, listerRegistry kube_util.ListerRegistry) (*LoopRecording, error) {
+	var err error
+	recording := &LoopRecording{
+		Timestamp: currentTime,
+		Options:   options,
+	}
+	if recording.AllNodes, err = listerRegistry.AllNodeLister().List(); err != nil {
+		return nil, err
+	}
+	if recording.ReadyNodes, err = listerRegistry.ReadyNodeLister().List(); err != nil {
+		return nil, err
+	}
+	if recording.ScheduledPods, err = listerRegistry.ScheduledPodLister().List(); err != nil {
+		return nil, err
+	}
+	if recording.UnschedulablePods, err = listerRegistry.UnschedulablePodLister().List(); err != nil {
+		return nil, err
+	}
+	if recording.PodDisruptionBudgets, err = listerRegistry.PodDisruptionBudgetLister().List(); err != nil {
+		return nil, err
+	}
+	if recording.DaemonSets, err = listerRegistry.DaemonSetLister().List(); err != nil {
+		return nil, err
+	}
+	return recording, nil
+}
+
+// RecordNodeGroups records the state of all node groups of the given cloud provider. It should be
+// called after the cloud provider is refreshed, so that it matches what the loop sees.
+func (recording *LoopRecording) RecordNodeGroups(cloudProvider cloudprovider.CloudProvider) error {
+	recording.NodeGroups = nil
+
+	nodesInGroup := make(map[string][]string)
+	for _, node := range recording.AllNodes {
+		nodeGroup, err := cloudProvider.NodeGroupForNode(node)
+		if err != nil {
+			klog.Warningf("Failed to get node group for %s: %v", node.Name, err)
+			continue
+		}
+		if nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() || nodeGroup.Id() == "" {
+			continue
+		}
+		nodesInGroup[nodeGroup.Id()] = append(nodesInGroup[nodeGroup.Id()], node.Name)
+	}
+	groupsWithReadyNodes := make(map[string]bool)
+	for _, node := range recording.ReadyNodes {
+		nodeGroup, err := cloudProvider.NodeGroupForNode(node)
+		if err == nil && nodeGroup != nil && !reflect.ValueOf(nodeGroup).IsNil() {
+			groupsWithReadyNodes[nodeGroup.Id()] = true
+		}
+	}
+
+	for _, nodeGroup := range cloudProvider.NodeGroups() {
+		targetSize, err := nodeGroup.TargetSize()
+		if err != nil {
+			return err
+		}
+		nodeGroupRecording := NodeGroupRecording{
+			Id:         nodeGroup.Id(),
+			MinSize:    nodeGroup.MinSize(),
+			MaxSize:    nodeGroup.MaxSize(),
+			TargetSize: targetSize,
+			Nodes:      nodesInGroup[nodeGroup.Id()],
+		}
+		nodeGroupOptions, err := nodeGroup.GetOptions(recording.Options.NodeGroupDefaults())
+		if err == nil {
+			nodeGroupRecording.Options = nodeGroupOptions
+		} else if err != cloudprovider.ErrNotImplemented {
+			klog.Warningf("Failed to record options of node group %s: %v", nodeGroup.Id(), err)
+		}
+		if !groupsWithReadyNodes[nodeGroup.Id()] {
+			template, err := nodeGroup.TemplateNodeInfo()
+			if err == nil {
+				nodeGroupRecording.TemplateNode = template.Node()
+				nodeGroupRecording.TemplatePods = template.Pods()
+			} else if err != cloudprovider.ErrNotImplemented {
+				klog.Warningf("Failed to record template of node group %s: %v", nodeGroup.Id(), err)
+			}
+		}
+		recording.NodeGroups = append(recording.NodeGroups, nodeGroupRecording)
+	}
+	sort.Slice(recording.NodeGroups, func(i, j int) bool { return recording.NodeGroups[i].Id < recording.NodeGroups[j].Id })
+	return nil
+}
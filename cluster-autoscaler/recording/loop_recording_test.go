@@ -0,0 +1,177 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoopRecordingRoundTrip(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 1000, 1000)
+	p1 := BuildTestPod("p1", 100, 100)
+	p1.Spec.NodeName = "n1"
+	p2 := BuildTestPod("p2", 100, 100)
+
+	template := schedulercache.NewNodeInfo(BuildTestPod("ds", 100, 100))
+	template.SetNode(BuildTestNode("template", 2000, 2000))
+	provider := testprovider.NewTestAutoprovisioningCloudProvider(nil, nil, nil, nil, nil,
+		map[string]*schedulercache.NodeInfo{"ng1": template, "ng2": template})
+	provider.AddNodeGroup("ng1", 1, 10, 2)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng1", n2)
+	provider.AddNodeGroup("ng2", 0, 5, 0)
+	provider.GetNodeGroup("ng2").(*testprovider.TestNodeGroup).SetOptions(&config.NodeGroupAutoscalingOptions{
+		ScaleDownUnneededTime: time.Minute,
+	})
+
+	listerRegistry := NewStaticListerRegistry(&LoopRecording{
+		AllNodes:          []*apiv1.Node{n1, n2},
+		ReadyNodes:        []*apiv1.Node{n1},
+		ScheduledPods:     []*apiv1.Pod{p1},
+		UnschedulablePods: []*apiv1.Pod{p2},
+	})
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	options := config.AutoscalingOptions{MaxNodesTotal: 10}
+
+	loopRecording, err := NewLoopRecording(now, options, listerRegistry)
+	assert.NoError(t, err)
+	assert.NoError(t, loopRecording.RecordNodeGroups(provider))
+	assert.Equal(t, 2, len(loopRecording.NodeGroups))
+
+	dir, err := ioutil.TempDir("", "loop-recordings")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	recorder, err := NewRecorder(filepath.Join(dir, "recordings"), 2)
+	assert.NoError(t, err)
+	for i := 2; i >= 0; i-- {
+		loopRecording.Timestamp = now.Add(-time.Duration(i) * time.Minute)
+		assert.NoError(t, recorder.Write(loopRecording))
+	}
+
+	// Only the two most recent recordings are kept.
+	files, err := ListRecordings(filepath.Join(dir, "recordings"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(files))
+	read, err := ReadRecording(files[1])
+	assert.NoError(t, err)
+	assert.True(t, now.Equal(read.Timestamp))
+	assert.Equal(t, 10, read.Options.MaxNodesTotal)
+
+	// Replay listers return the recorded objects.
+	replayRegistry := NewStaticListerRegistry(read)
+	nodes, _ := replayRegistry.AllNodeLister().List()
+	assert.Equal(t, 2, len(nodes))
+	nodes, _ = replayRegistry.ReadyNodeLister().List()
+	assert.Equal(t, 1, len(nodes))
+	assert.Equal(t, "n1", nodes[0].Name)
+	pods, _ := replayRegistry.ScheduledPodLister().List()
+	assert.Equal(t, "p1", pods[0].Name)
+	pods, _ = replayRegistry.UnschedulablePodLister().List()
+	assert.Equal(t, "p2", pods[0].Name)
+
+	// Replay cloud provider has the recorded node groups.
+	replayProvider := NewReplayCloudProvider(nil, nil)
+	replayProvider.SetRecording(read)
+	ng1, err := replayProvider.NodeGroupForNode(n2)
+	assert.NoError(t, err)
+	assert.Equal(t, "ng1", ng1.Id())
+	size, _ := ng1.TargetSize()
+	assert.Equal(t, 2, size)
+	// ng1 has a ready node, so its template is not recorded.
+	_, err = ng1.TemplateNodeInfo()
+	assert.Equal(t, cloudprovider.ErrNotImplemented, err)
+
+	ng2 := replayProvider.GetNodeGroup("ng2")
+	assert.Equal(t, 5, ng2.MaxSize())
+	ng2Template, err := ng2.TemplateNodeInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "template", ng2Template.Node().Name)
+	assert.Equal(t, 1, len(ng2Template.Pods()))
+	ng2Options, err := ng2.GetOptions(options.NodeGroupDefaults())
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, ng2Options.ScaleDownUnneededTime)
+
+	// Node group assignments follow the current recording.
+	read.NodeGroups = read.NodeGroups[1:]
+	replayProvider.SetRecording(read)
+	ng1, err = replayProvider.NodeGroupForNode(n2)
+	assert.NoError(t, err)
+	assert.Nil(t, ng1)
+}
+
+// nodeGroupLookupProvider fails node group lookups of the nodes in failing and returns nil
+// *TestNodeGroup node groups for the nodes in typedNil.
+type nodeGroupLookupProvider struct {
+	*testprovider.TestCloudProvider
+	failing  map[string]bool
+	typedNil map[string]bool
+}
+
+func (p *nodeGroupLookupProvider) NodeGroupForNode(node *apiv1.Node) (cloudprovider.NodeGroup, error) {
+	if p.failing[node.Name] {
+		return nil, fmt.Errorf("lookup of %s failed", node.Name)
+	}
+	if p.typedNil[node.Name] {
+		return (*testprovider.TestNodeGroup)(nil), nil
+	}
+	return p.TestCloudProvider.NodeGroupForNode(node)
+}
+
+func TestLoopRecordingSkipsNodesWithoutNodeGroup(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(n1, true, time.Time{})
+	n2 := BuildTestNode("n2", 1000, 1000)
+	SetNodeReadyState(n2, true, time.Time{})
+	n3 := BuildTestNode("n3", 1000, 1000)
+	SetNodeReadyState(n3, true, time.Time{})
+
+	provider := &nodeGroupLookupProvider{
+		TestCloudProvider: testprovider.NewTestCloudProvider(nil, nil),
+		failing:           map[string]bool{"n2": true},
+		typedNil:          map[string]bool{"n3": true},
+	}
+	provider.AddNodeGroup("ng1", 1, 10, 3)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng1", n2)
+	provider.AddNode("ng1", n3)
+
+	listerRegistry := NewStaticListerRegistry(&LoopRecording{
+		AllNodes:   []*apiv1.Node{n1, n2, n3},
+		ReadyNodes: []*apiv1.Node{n1, n2, n3},
+	})
+	loopRecording, err := NewLoopRecording(time.Now(), config.AutoscalingOptions{}, listerRegistry)
+	assert.NoError(t, err)
+	assert.NoError(t, loopRecording.RecordNodeGroups(provider))
+	assert.Equal(t, 1, len(loopRecording.NodeGroups))
+	assert.Equal(t, []string{"n1"}, loopRecording.NodeGroups[0].Nodes)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+const (
+	recordingFilePrefix = "loop-"
+	recordingFileSuffix = ".json"
+	// Timestamp format used in file names, sorts the same lexicographically and chronologically.
+	recordingTimeFormat = "20060102-150405.000000000"
+)
+
+// Recorder writes LoopRecordings to files in a directory, keeping at most a given number of
+// the most recent ones.
+type Recorder struct {
+	dir      string
+	maxFiles int
+}
+
+// NewRecorder builds a Recorder writing to the given directory, which is created if needed.
+// If maxFiles is positive, older recordings are removed so that at most maxFiles are kept.
+func NewRecorder(dir string, maxFiles int) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir, maxFiles: maxFiles}, nil
+}
+
+// Write stores the recording in a new file and removes recordings exceeding the limit.
+func (r *Recorder) Write(recording *LoopRecording) error {
+	data, err := json.Marshal(recording)
+	if err != nil {
+		return err
+	}
+	name := recordingFilePrefix + recording.Timestamp.UTC().Format(recordingTimeFormat) + recordingFileSuffix
+	if err := ioutil.WriteFile(filepath.Join(r.dir, name), data, 0644); err != nil {
+		return err
+	}
+	if r.maxFiles <= 0 {
+		return nil
+	}
+	files, err := ListRecordings(r.dir)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(files)-r.maxFiles; i++ {
+		if err := os.Remove(files[i]); err != nil {
+			klog.Warningf("Failed to remove old loop recording %s: %v", files[i], err)
+		}
+	}
+	return nil
+}
+
+// ListRecordings returns paths of recordings in the given directory, oldest first.
+func ListRecordings(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), recordingFilePrefix) || !strings.HasSuffix(entry.Name(), recordingFileSuffix) {
+			continue
+		}
+		result = append(result, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// ReadRecording reads a recording from the given file.
+func ReadRecording(path string) (*LoopRecording, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	recording := &LoopRecording{}
+	if err := json.Unmarshal(data, recording); err != nil {
+		return nil, fmt.Errorf("failed to parse loop recording %s: %v", path, err)
+	}
+	return recording, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+// ReplayCloudProvider is a TestCloudProvider whose node groups reflect those stored in recordings.
+type ReplayCloudProvider struct {
+	*testprovider.TestCloudProvider
+	onScaleUp   testprovider.OnScaleUpFunc
+	onScaleDown testprovider.OnScaleDownFunc
+}
+
+// NewReplayCloudProvider builds an empty ReplayCloudProvider. The callbacks are passed to the
+// underlying TestCloudProvider.
+func NewReplayCloudProvider(onScaleUp testprovider.OnScaleUpFunc, onScaleDown testprovider.OnScaleDownFunc) *ReplayCloudProvider {
+	return &ReplayCloudProvider{
+		TestCloudProvider: testprovider.NewTestCloudProvider(onScaleUp, onScaleDown),
+		onScaleUp:         onScaleUp,
+		onScaleDown:       onScaleDown,
+	}
+}
+
+// SetRecording replaces node groups with the ones stored in the recording.
+func (p *ReplayCloudProvider) SetRecording(recording *LoopRecording) {
+	// Node to node group assignments change between recordings, so the provider is rebuilt.
+	p.TestCloudProvider = testprovider.NewTestCloudProvider(p.onScaleUp, p.onScaleDown)
+	p.SetResourceLimiter(context.NewResourceLimiterFromAutoscalingOptions(recording.Options))
+	for _, nodeGroupRecording := range recording.NodeGroups {
+		nodeGroup := &replayNodeGroup{
+			TestNodeGroup: p.BuildNodeGroup(nodeGroupRecording.Id, nodeGroupRecording.MinSize, nodeGroupRecording.MaxSize,
+				nodeGroupRecording.TargetSize, false, ""),
+		}
+		if nodeGroupRecording.Options != nil {
+			nodeGroup.SetOptions(nodeGroupRecording.Options)
+		}
+		if nodeGroupRecording.TemplateNode != nil {
+			nodeGroup.template = schedulercache.NewNodeInfo(nodeGroupRecording.TemplatePods...)
+			nodeGroup.template.SetNode(nodeGroupRecording.TemplateNode)
+		}
+		p.InsertNodeGroup(nodeGroup)
+		for _, nodeName := range nodeGroupRecording.Nodes {
+			p.AddNode(nodeGroupRecording.Id, &apiv1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}})
+		}
+	}
+}
+
+// replayNodeGroup is a TestNodeGroup with an optional template.
+type replayNodeGroup struct {
+	*testprovider.TestNodeGroup
+	template *schedulercache.NodeInfo
+}
+
+// TemplateNodeInfo returns the recorded template, or ErrNotImplemented if there is none.
+func (ng *replayNodeGroup) TemplateNodeInfo() (*schedulercache.NodeInfo, error) {
+	if ng.template == nil {
+		return nil, cloudprovider.ErrNotImplemented
+	}
+	return ng.template, nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command replay runs loop recordings written by Cluster Autoscaler with --record-loops-dir through
+// StaticAutoscaler and prints the decisions made in every loop. The autoscaler runs in dry-run mode
+// on top of a test cloud provider and a fake clientset, so nothing outside of the process is affected.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterstate_utils "k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/recording"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
+	"k8s.io/client-go/kubernetes/fake"
+	kube_testing "k8s.io/client-go/testing"
+	"k8s.io/klog"
+)
+
+var (
+	recordings = flag.String("recordings", "", "Loop recording file, or directory with loop recordings to replay in order.")
+	seed       = flag.Int64("seed", 1, "Seed for the random number generator, so that replays are repeatable.")
+)
+
+var scaleUpResults = map[status.ScaleUpResult]string{
+	status.ScaleUpSuccessful:         "Successful",
+	status.ScaleUpError:              "Error",
+	status.ScaleUpNoOptionsAvailable: "NoOptionsAvailable",
+	status.ScaleUpNotNeeded:          "NotNeeded",
+	status.ScaleUpNotTried:           "NotTried",
+	status.ScaleUpInCooldown:         "InCooldown",
+}
+
+var scaleDownResults = map[status.ScaleDownResult]string{
+	status.ScaleDownError:             "Error",
+	status.ScaleDownNoUnneeded:        "NoUnneeded",
+	status.ScaleDownNoNodeDeleted:     "NoNodeDeleted",
+	status.ScaleDownNodeDeleted:       "NodeDeleted",
+	status.ScaleDownNodeDeleteStarted: "NodeDeleteStarted",
+	status.ScaleDownNotTried:          "NotTried",
+	status.ScaleDownInCooldown:        "InCooldown",
+	status.ScaleDownInProgress:        "InProgress",
+	status.ScaleDownInBlackout:        "InBlackout",
+}
+
+// printingScaleUpStatusProcessor prints scale-up decisions.
+type printingScaleUpStatusProcessor struct{}
+
+func (p *printingScaleUpStatusProcessor) Process(context *context.AutoscalingContext, scaleUpStatus *status.ScaleUpStatus) {
+	fmt.Printf("  scale-up: %s\n", scaleUpResults[scaleUpStatus.Result])
+	for _, info := range scaleUpStatus.ScaleUpInfos {
+		fmt.Printf("    group %s: %d -> %d\n", info.Group.Id(), info.CurrentSize, info.NewSize)
+	}
+	if len(scaleUpStatus.PodsTriggeredScaleUp) > 0 {
+		fmt.Printf("    triggered by: %s\n", podNames(scaleUpStatus.PodsTriggeredScaleUp))
+	}
+	if len(scaleUpStatus.PodsRemainUnschedulable) > 0 {
+		pods := make([]*apiv1.Pod, 0, len(scaleUpStatus.PodsRemainUnschedulable))
+		for _, info := range scaleUpStatus.PodsRemainUnschedulable {
+			pods = append(pods, info.Pod)
+		}
+		fmt.Printf("    remain unschedulable: %s\n", podNames(pods))
+	}
+}
+
+func (p *printingScaleUpStatusProcessor) CleanUp() {}
+
+// printingScaleDownStatusProcessor prints scale-down decisions.
+type printingScaleDownStatusProcessor struct{}
+
+func (p *printingScaleDownStatusProcessor) Process(context *context.AutoscalingContext, scaleDownStatus *status.ScaleDownStatus) {
+	fmt.Printf("  scale-down: %s\n", scaleDownResults[scaleDownStatus.Result])
+	for _, node := range scaleDownStatus.ScaledDownNodes {
+		nodeGroupId := ""
+		if node.NodeGroup != nil {
+			nodeGroupId = node.NodeGroup.Id()
+		}
+		fmt.Printf("    node %s (group %s), evicted: %s\n", node.Node.Name, nodeGroupId, podNames(node.EvictedPods))
+	}
+}
+
+func (p *printingScaleDownStatusProcessor) CleanUp() {}
+
+func podNames(pods []*apiv1.Pod) string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func recordingFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	return recording.ListRecordings(path)
+}
+
+// newFakeClient builds a fake clientset listing pods from the current recording, as the autoscaler
+// lists pods running on a node through the API when building node templates.
+func newFakeClient(listerRegistry *recording.StaticListerRegistry) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "pods", func(action kube_testing.Action) (bool, runtime.Object, error) {
+		selector := action.(kube_testing.ListAction).GetListRestrictions().Fields
+		pods, err := listerRegistry.ScheduledPodLister().List()
+		if err != nil {
+			return true, nil, err
+		}
+		result := &apiv1.PodList{}
+		for _, pod := range pods {
+			if selector == nil || selector.Matches(fields.Set{"spec.nodeName": pod.Spec.NodeName}) {
+				result.Items = append(result.Items, *pod)
+			}
+		}
+		return true, result, nil
+	})
+	return client
+}
+
+// withoutPriorityExpander removes the priority expander from a list of expanders. Priorities are not
+// recorded and without them the priority expander keeps all options, so removing it doesn't change
+// decisions. It also can't watch its config map through the fake clientset.
+func withoutPriorityExpander(expanderNames string) string {
+	names := strings.Split(expanderNames, ",")
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != expander.PriorityBasedExpanderName {
+			result = append(result, name)
+		}
+	}
+	if len(result) == len(names) {
+		return expanderNames
+	}
+	klog.Warningf("Priority expander configuration is not recorded, priorities are ignored")
+	if len(result) == 0 {
+		return expander.RandomExpanderName
+	}
+	return strings.Join(result, ",")
+}
+
+func main() {
+	klog.InitFlags(nil)
+	flag.Parse()
+	if *recordings == "" {
+		klog.Fatalf("--recordings is required")
+	}
+	rand.Seed(*seed)
+
+	files, err := recordingFiles(*recordings)
+	if err != nil {
+		klog.Fatalf("Failed to list recordings: %v", err)
+	}
+	if len(files) == 0 {
+		klog.Fatalf("No recordings found in %s", *recordings)
+	}
+	first, err := recording.ReadRecording(files[0])
+	if err != nil {
+		klog.Fatalf("Failed to read recording: %v", err)
+	}
+
+	options := first.Options
+	if options.ScaleDownAllowedSchedule, err = schedule.Parse(options.ScaleDownAllowedWindows); err != nil {
+		klog.Fatalf("Invalid scale down windows in recording: %v", err)
+	}
+	options.DryRun = true
+	options.WriteStatusConfigMap = false
+	options.RecordLoopsDir = ""
+
+	listerRegistry := recording.NewStaticListerRegistry(first)
+	cloudProvider := recording.NewReplayCloudProvider(nil, nil)
+	cloudProvider.SetRecording(first)
+	kubeClient := newFakeClient(listerRegistry)
+	kubeEventRecorder := kube_util.CreateEventRecorder(kubeClient)
+	logRecorder, err := clusterstate_utils.NewStatusMapRecorder(kubeClient, options.ConfigNamespace, kubeEventRecorder, false)
+	if err != nil {
+		klog.Fatalf("Failed to create log recorder: %v", err)
+	}
+	options.ExpanderName = withoutPriorityExpander(options.ExpanderName)
+	processors := ca_processors.DefaultProcessors()
+	processors.ScaleUpStatusProcessor = &printingScaleUpStatusProcessor{}
+	processors.ScaleDownStatusProcessor = &printingScaleDownStatusProcessor{}
+
+	autoscaler, typedErr := core.NewAutoscaler(core.AutoscalerOptions{
+		AutoscalingOptions: options,
+		KubeClient:         kubeClient,
+		AutoscalingKubeClients: &context.AutoscalingKubeClients{
+			ListerRegistry: listerRegistry,
+			ClientSet:      kubeClient,
+			Recorder:       kubeEventRecorder,
+			LogRecorder:    logRecorder,
+		},
+		CloudProvider: cloudProvider,
+		Processors:    processors,
+	})
+	if typedErr != nil {
+		klog.Fatalf("Failed to create autoscaler: %v", typedErr)
+	}
+	if staticAutoscaler, ok := autoscaler.(*core.StaticAutoscaler); ok {
+		staticAutoscaler.SetStartTime(first.Timestamp)
+	}
+
+	for _, file := range files {
+		loopRecording, err := recording.ReadRecording(file)
+		if err != nil {
+			klog.Fatalf("Failed to read recording: %v", err)
+		}
+		listerRegistry.SetRecording(loopRecording)
+		cloudProvider.SetRecording(loopRecording)
+
+		fmt.Printf("loop %s (%s)\n", loopRecording.Timestamp.UTC().Format("2006-01-02T15:04:05.000Z07:00"), file)
+		if typedErr := autoscaler.RunOnce(loopRecording.Timestamp); typedErr != nil {
+			fmt.Printf("  error: %v\n", typedErr)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+
+	apiv1 "k8s.io/api/core/v1"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+// BasicClusterSnapshot is a simple ClusterSnapshot implementation that copies all NodeInfos on Fork.
+// It is mostly useful as a reference for other implementations.
+type BasicClusterSnapshot struct {
+	// stack of states, the last one is the current state.
+	stack []map[string]*schedulercache.NodeInfo
+}
+
+// NewBasicClusterSnapshot creates an empty BasicClusterSnapshot.
+func NewBasicClusterSnapshot() *BasicClusterSnapshot {
+	snapshot := &BasicClusterSnapshot{}
+	snapshot.Clear()
+	return snapshot
+}
+
+func (snapshot *BasicClusterSnapshot) current() map[string]*schedulercache.NodeInfo {
+	return snapshot.stack[len(snapshot.stack)-1]
+}
+
+// AddNode adds a node without pods to the snapshot.
+func (snapshot *BasicClusterSnapshot) AddNode(node *apiv1.Node) error {
+	return snapshot.AddNodeWithPods(node, nil)
+}
+
+// AddNodes adds nodes without pods to the snapshot.
+func (snapshot *BasicClusterSnapshot) AddNodes(nodes []*apiv1.Node) error {
+	for _, node := range nodes {
+		if err := snapshot.AddNode(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddNodeWithPods adds a node together with pods running on it to the snapshot.
+func (snapshot *BasicClusterSnapshot) AddNodeWithPods(node *apiv1.Node, pods []*apiv1.Pod) error {
+	if _, found := snapshot.current()[node.Name]; found {
+		return fmt.Errorf("node %s already in snapshot", node.Name)
+	}
+	nodeInfo, err := nodeInfoWithPods(node, pods)
+	if err != nil {
+		return err
+	}
+	snapshot.current()[node.Name] = nodeInfo
+	return nil
+}
+
+// RemoveNode removes a node, together with its pods, from the snapshot.
+func (snapshot *BasicClusterSnapshot) RemoveNode(nodeName string) error {
+	if _, found := snapshot.current()[nodeName]; !found {
+		return errNodeNotFound
+	}
+	delete(snapshot.current(), nodeName)
+	return nil
+}
+
+// AddPod adds a pod to the given node.
+func (snapshot *BasicClusterSnapshot) AddPod(pod *apiv1.Pod, nodeName string) error {
+	nodeInfo, found := snapshot.current()[nodeName]
+	if !found {
+		return errNodeNotFound
+	}
+	nodeInfo.AddPod(pod)
+	return nil
+}
+
+// RemovePod removes a pod from the given node.
+func (snapshot *BasicClusterSnapshot) RemovePod(namespace string, podName string, nodeName string) error {
+	nodeInfo, found := snapshot.current()[nodeName]
+	if !found {
+		return errNodeNotFound
+	}
+	return removePodFromNodeInfo(nodeInfo, namespace, podName)
+}
+
+// GetNodeInfo returns the NodeInfo of the given node.
+func (snapshot *BasicClusterSnapshot) GetNodeInfo(nodeName string) (*schedulercache.NodeInfo, bool) {
+	nodeInfo, found := snapshot.current()[nodeName]
+	return nodeInfo, found
+}
+
+// NodeInfos returns NodeInfos of all nodes in the snapshot.
+func (snapshot *BasicClusterSnapshot) NodeInfos() map[string]*schedulercache.NodeInfo {
+	return snapshot.current()
+}
+
+// Fork creates a fork of the snapshot state by copying all NodeInfos.
+func (snapshot *BasicClusterSnapshot) Fork() error {
+	forked := make(map[string]*schedulercache.NodeInfo, len(snapshot.current()))
+	for name, nodeInfo := range snapshot.current() {
+		forked[name] = nodeInfo.Clone()
+	}
+	snapshot.stack = append(snapshot.stack, forked)
+	return nil
+}
+
+// Revert reverts the snapshot state to the moment of the most recent Fork.
+func (snapshot *BasicClusterSnapshot) Revert() error {
+	if len(snapshot.stack) < 2 {
+		return errNotForked
+	}
+	snapshot.stack = snapshot.stack[:len(snapshot.stack)-1]
+	return nil
+}
+
+// Commit applies modifications done after the most recent Fork to the state before forking.
+func (snapshot *BasicClusterSnapshot) Commit() error {
+	if len(snapshot.stack) < 2 {
+		return errNotForked
+	}
+	snapshot.stack[len(snapshot.stack)-2] = snapshot.current()
+	snapshot.stack = snapshot.stack[:len(snapshot.stack)-1]
+	return nil
+}
+
+// Clear resets the snapshot to an empty, unforked state.
+func (snapshot *BasicClusterSnapshot) Clear() {
+	snapshot.stack = []map[string]*schedulercache.NodeInfo{make(map[string]*schedulercache.NodeInfo)}
+}
@@ -32,6 +32,8 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	client "k8s.io/client-go/kubernetes"
 	"k8s.io/kubernetes/pkg/scheduler/algorithm"
 	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
@@ -58,6 +60,67 @@ type NodeToBeRemoved struct {
 	PodsToReschedule []*apiv1.Pod
 }
 
+// UnremovableNode represents a node that can't be removed by CA.
+type UnremovableNode struct {
+	Node   *apiv1.Node
+	Reason UnremovableReason
+	// BlockingPod is the pod that prevents the node from being drained, if any. Reason is then
+	// the reason why the pod can't be moved.
+	BlockingPod *drain.BlockingPod
+}
+
+// UnremovableReason represents a reason why a node can't be removed by CA.
+type UnremovableReason string
+
+const (
+	// ScaleDownDisabledAnnotation - node can't be removed because it has a "scale down disabled" annotation.
+	ScaleDownDisabledAnnotation UnremovableReason = "ScaleDownDisabledAnnotation"
+	// NotAutoscaled - node can't be removed because it doesn't belong to an autoscaled node group.
+	NotAutoscaled UnremovableReason = "NotAutoscaled"
+	// NotUnneededLongEnough - node can't be removed because it wasn't unneeded for long enough.
+	NotUnneededLongEnough UnremovableReason = "NotUnneededLongEnough"
+	// NotUnreadyLongEnough - node can't be removed because it wasn't unready for long enough.
+	NotUnreadyLongEnough UnremovableReason = "NotUnreadyLongEnough"
+	// NodeGroupMinSizeReached - node can't be removed because its node group is at its minimal size already.
+	NodeGroupMinSizeReached UnremovableReason = "NodeGroupMinSizeReached"
+	// MinimalResourceLimitExceeded - node can't be removed because it would violate cluster-wide minimal resource limits.
+	MinimalResourceLimitExceeded UnremovableReason = "MinimalResourceLimitExceeded"
+	// OutsideScaleDownWindow - node can't be removed because its node group is outside of its scale down windows.
+	OutsideScaleDownWindow UnremovableReason = "OutsideScaleDownWindow"
+	// DisruptionCostTooHigh - node can't be removed because moving its pods costs more than allowed.
+	DisruptionCostTooHigh UnremovableReason = "DisruptionCostTooHigh"
+	// CurrentlyBeingDeleted - node can't be removed because it's already being deleted.
+	CurrentlyBeingDeleted UnremovableReason = "CurrentlyBeingDeleted"
+	// NotUnderutilized - node can't be removed because it's not underutilized.
+	NotUnderutilized UnremovableReason = "NotUnderutilized"
+	// RecentlyUnremovable - node can't be removed because it was recently found to be unremovable.
+	RecentlyUnremovable UnremovableReason = "RecentlyUnremovable"
+	// NoPlaceToMovePods - node can't be removed because there's no place to move its pods to.
+	NoPlaceToMovePods UnremovableReason = "NoPlaceToMovePods"
+	// ReschedulingTarget - node can't be removed because pods from other removed nodes are moved to it.
+	ReschedulingTarget UnremovableReason = "ReschedulingTarget"
+	// UnexpectedError - node can't be removed because of an unexpected error.
+	UnexpectedError UnremovableReason = "UnexpectedError"
+)
+
+// Reasons for nodes running a pod that can't be moved. They match the drain.BlockingPodReason of the pod.
+const (
+	// ControllerNotFound - node can't be removed because the controller of a pod can't be found.
+	ControllerNotFound = UnremovableReason(drain.ControllerNotFound)
+	// MinReplicasReached - node can't be removed because the controller of a pod already has the minimum number of replicas.
+	MinReplicasReached = UnremovableReason(drain.MinReplicasReached)
+	// NotReplicated - node can't be removed because a pod is not replicated.
+	NotReplicated = UnremovableReason(drain.NotReplicated)
+	// LocalStorageRequested - node can't be removed because a pod uses local storage.
+	LocalStorageRequested = UnremovableReason(drain.LocalStorageRequested)
+	// NotSafeToEvictAnnotation - node can't be removed because a pod has the "not safe to evict" annotation.
+	NotSafeToEvictAnnotation = UnremovableReason(drain.NotSafeToEvictAnnotation)
+	// UnmovableKubeSystemPod - node can't be removed because of a non-daemonset, non-mirrored, non-pdb-assigned kube-system pod.
+	UnmovableKubeSystemPod = UnremovableReason(drain.UnmovableKubeSystemPod)
+	// NotEnoughPdb - node can't be removed because a pod doesn't have enough PDB left.
+	NotEnoughPdb = UnremovableReason(drain.NotEnoughPdb)
+)
+
 // UtilizationInfo contains utilization information for a node.
 type UtilizationInfo struct {
 	CpuUtil float64
@@ -67,52 +130,128 @@ type UtilizationInfo struct {
 }
 
 // FindNodesToRemove finds nodes that can be removed. Returns also an information about good
-// rescheduling location for each of the pods.
-func FindNodesToRemove(candidates []*apiv1.Node, allNodes []*apiv1.Node, pods []*apiv1.Pod,
+// rescheduling location for each of the pods. Each of the returned nodes is checked independently
+// of the others, so they are not guaranteed to be removable together. Pods are moved to
+// destinationNodes in a fork of clusterSnapshot, which is reverted before returning.
+func FindNodesToRemove(candidates []*apiv1.Node, destinationNodes []*apiv1.Node, clusterSnapshot ClusterSnapshot,
 	client client.Interface, predicateChecker *PredicateChecker, maxCount int,
 	fastCheck bool, oldHints map[string]string, usageTracker *UsageTracker,
 	timestamp time.Time,
 	podDisruptionBudgets []*policyv1.PodDisruptionBudget,
-) (nodesToRemove []NodeToBeRemoved, unremovableNodes []*apiv1.Node, podReschedulingHints map[string]string, finalError errors.AutoscalerError) {
+) (nodesToRemove []NodeToBeRemoved, unremovableNodes []*UnremovableNode, podReschedulingHints map[string]string, finalError errors.AutoscalerError) {
+	return findNodesToRemove(candidates, destinationNodes, clusterSnapshot, client, predicateChecker, maxCount, fastCheck, oldHints,
+		usageTracker, timestamp, podDisruptionBudgets, false)
+}
 
-	nodeNameToNodeInfo := scheduler_util.CreateNodeNameToInfoMap(pods, allNodes)
+// FindNodesToRemoveTogether works like FindNodesToRemove, but the returned nodes can be removed
+// together: pods from each of them are rescheduled only onto nodes that stay in the cluster, taking
+// into account pods already moved from previously chosen nodes, and pod disruption budgets are
+// shared between all chosen nodes.
+func FindNodesToRemoveTogether(candidates []*apiv1.Node, destinationNodes []*apiv1.Node, clusterSnapshot ClusterSnapshot,
+	client client.Interface, predicateChecker *PredicateChecker, maxCount int,
+	fastCheck bool, oldHints map[string]string, usageTracker *UsageTracker,
+	timestamp time.Time,
+	podDisruptionBudgets []*policyv1.PodDisruptionBudget,
+) (nodesToRemove []NodeToBeRemoved, unremovableNodes []*UnremovableNode, podReschedulingHints map[string]string, finalError errors.AutoscalerError) {
+	return findNodesToRemove(candidates, destinationNodes, clusterSnapshot, client, predicateChecker, maxCount, fastCheck, oldHints,
+		usageTracker, timestamp, podDisruptionBudgets, true)
+}
+
+func findNodesToRemove(candidates []*apiv1.Node, destinationNodes []*apiv1.Node, clusterSnapshot ClusterSnapshot,
+	client client.Interface, predicateChecker *PredicateChecker, maxCount int,
+	fastCheck bool, oldHints map[string]string, usageTracker *UsageTracker,
+	timestamp time.Time,
+	podDisruptionBudgets []*policyv1.PodDisruptionBudget,
+	together bool,
+) ([]NodeToBeRemoved, []*UnremovableNode, map[string]string, errors.AutoscalerError) {
+
+	// The snapshot is shared with the rest of the loop, so all changes are done in a fork.
+	if err := clusterSnapshot.Fork(); err != nil {
+		return []NodeToBeRemoved{}, []*UnremovableNode{}, nil, errors.ToAutoscalerError(errors.InternalError, err)
+	}
+	defer func() {
+		if err := clusterSnapshot.Revert(); err != nil {
+			klog.Errorf("Failed to revert cluster snapshot: %v", err)
+		}
+	}()
 	result := make([]NodeToBeRemoved, 0)
-	unremovable := make([]*apiv1.Node, 0)
+	unremovable := make([]*UnremovableNode, 0)
 
 	evaluationType := "Detailed evaluation"
 	if fastCheck {
 		evaluationType = "Fast evaluation"
 	}
 	newHints := make(map[string]string, len(oldHints))
+	remainingPdbs := podDisruptionBudgets
+	if together {
+		remainingPdbs = copyPdbs(podDisruptionBudgets)
+	}
+	// Nodes that are supposed to receive pods from nodes already chosen for removal.
+	receivingNodes := make(map[string]bool)
 
 candidateloop:
 	for _, node := range candidates {
 		klog.V(2).Infof("%s: %s for removal", evaluationType, node.Name)
 
+		if receivingNodes[node.Name] {
+			klog.V(2).Infof("%s: node %s is a rescheduling target for pods from other removed nodes", evaluationType, node.Name)
+			unremovable = append(unremovable, &UnremovableNode{Node: node, Reason: ReschedulingTarget})
+			continue candidateloop
+		}
+
 		var podsToRemove []*apiv1.Pod
+		var blockingPod *drain.BlockingPod
 		var err error
 
-		if nodeInfo, found := nodeNameToNodeInfo[node.Name]; found {
+		if nodeInfo, found := clusterSnapshot.GetNodeInfo(node.Name); found {
 			if fastCheck {
-				podsToRemove, err = FastGetPodsToMove(nodeInfo, *skipNodesWithSystemPods, *skipNodesWithLocalStorage,
-					podDisruptionBudgets)
+				podsToRemove, blockingPod, err = FastGetPodsToMove(nodeInfo, *skipNodesWithSystemPods, *skipNodesWithLocalStorage,
+					remainingPdbs)
 			} else {
-				podsToRemove, err = DetailedGetPodsForMove(nodeInfo, *skipNodesWithSystemPods, *skipNodesWithLocalStorage, client, int32(*minReplicaCount),
-					podDisruptionBudgets)
+				podsToRemove, blockingPod, err = DetailedGetPodsForMove(nodeInfo, *skipNodesWithSystemPods, *skipNodesWithLocalStorage, client, int32(*minReplicaCount),
+					remainingPdbs)
 			}
 			if err != nil {
 				klog.V(2).Infof("%s: node %s cannot be removed: %v", evaluationType, node.Name, err)
-				unremovable = append(unremovable, node)
+				if blockingPod != nil {
+					unremovable = append(unremovable, &UnremovableNode{Node: node, Reason: UnremovableReason(blockingPod.Reason), BlockingPod: blockingPod})
+				} else {
+					unremovable = append(unremovable, &UnremovableNode{Node: node, Reason: UnexpectedError})
+				}
 				continue candidateloop
 			}
 		} else {
 			klog.V(2).Infof("%s: nodeInfo for %s not found", evaluationType, node.Name)
-			unremovable = append(unremovable, node)
+			unremovable = append(unremovable, &UnremovableNode{Node: node, Reason: UnexpectedError})
 			continue candidateloop
 		}
-		findProblems := findPlaceFor(node.Name, podsToRemove, allNodes, nodeNameToNodeInfo, predicateChecker, oldHints, newHints,
+		// Rescheduled pods are only kept in the simulated cluster state if nodes are removed together.
+		if err := clusterSnapshot.Fork(); err != nil {
+			return []NodeToBeRemoved{}, []*UnremovableNode{}, nil, errors.ToAutoscalerError(errors.InternalError, err)
+		}
+		findProblems := findPlaceFor(node.Name, podsToRemove, destinationNodes, clusterSnapshot, predicateChecker, oldHints, newHints,
 			usageTracker, timestamp)
 
+		if findProblems == nil && together {
+			// The node is going away together with the rest of the result, so it can no
+			// longer host pods moved from other candidates.
+			err = clusterSnapshot.RemoveNode(node.Name)
+			if err == nil {
+				err = clusterSnapshot.Commit()
+			}
+			if err != nil {
+				clusterSnapshot.Revert()
+				return []NodeToBeRemoved{}, []*UnremovableNode{}, nil, errors.ToAutoscalerError(errors.InternalError, err)
+			}
+			if err := consumePdbs(podsToRemove, remainingPdbs); err != nil {
+				return []NodeToBeRemoved{}, []*UnremovableNode{}, nil, errors.ToAutoscalerError(errors.InternalError, err)
+			}
+			for _, pod := range podsToRemove {
+				receivingNodes[newHints[podKey(pod)]] = true
+			}
+		} else if err := clusterSnapshot.Revert(); err != nil {
+			return []NodeToBeRemoved{}, []*UnremovableNode{}, nil, errors.ToAutoscalerError(errors.InternalError, err)
+		}
 		if findProblems == nil {
 			result = append(result, NodeToBeRemoved{
 				Node:             node,
@@ -124,7 +263,7 @@ candidateloop:
 			}
 		} else {
 			klog.V(2).Infof("%s: node %s is not suitable for removal: %v", evaluationType, node.Name, findProblems)
-			unremovable = append(unremovable, node)
+			unremovable = append(unremovable, &UnremovableNode{Node: node, Reason: NoPlaceToMovePods})
 		}
 	}
 	return result, unremovable, newHints, nil
@@ -137,7 +276,7 @@ func FindEmptyNodesToRemove(candidates []*apiv1.Node, pods []*apiv1.Pod) []*apiv
 	for _, node := range candidates {
 		if nodeInfo, found := nodeNameToNodeInfo[node.Name]; found {
 			// Should block on all pods.
-			podsToRemove, err := FastGetPodsToMove(nodeInfo, true, true, nil)
+			podsToRemove, _, err := FastGetPodsToMove(nodeInfo, true, true, nil)
 			if err == nil && len(podsToRemove) == 0 {
 				result = append(result, node)
 			}
@@ -191,48 +330,34 @@ func calculateUtilizationOfResource(node *apiv1.Node, nodeInfo *schedulercache.N
 	return float64(podsRequest.MilliValue()) / float64(nodeAllocatable.MilliValue()), nil
 }
 
-// TODO: We don't need to pass list of nodes here as they are already available in nodeInfos.
-func findPlaceFor(removedNode string, pods []*apiv1.Pod, nodes []*apiv1.Node, nodeInfos map[string]*schedulercache.NodeInfo,
+// findPlaceFor checks whether all pods from removedNode can be rescheduled on the remaining nodes.
+// Pods that found a place are added to the snapshot so that further checks take them into account.
+// The snapshot is left modified even if some pods couldn't be placed, so callers are expected to
+// fork it before the call. Nodes missing from the snapshot are not considered as targets.
+// TODO: We don't need to pass list of nodes here as they are already available in the snapshot.
+func findPlaceFor(removedNode string, pods []*apiv1.Pod, nodes []*apiv1.Node, clusterSnapshot ClusterSnapshot,
 	predicateChecker *PredicateChecker, oldHints map[string]string, newHints map[string]string, usageTracker *UsageTracker,
 	timestamp time.Time) error {
 
-	newNodeInfos := make(map[string]*schedulercache.NodeInfo)
-	for k, v := range nodeInfos {
-		newNodeInfos[k] = v
-	}
-
-	podKey := func(pod *apiv1.Pod) string {
-		return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
-	}
-
 	loggingQuota := glogx.PodsLoggingQuota()
 
 	tryNodeForPod := func(nodename string, pod *apiv1.Pod, predicateMeta algorithm.PredicateMetadata) bool {
-		nodeInfo, found := newNodeInfos[nodename]
-		if found {
-			if nodeInfo.Node() == nil {
-				// NodeInfo is generated based on pods. It is possible that node is removed from
-				// an api server faster than the pod that were running on them. In such a case
-				// we have to skip this nodeInfo. It should go away pretty soon.
-				klog.Warningf("No node in nodeInfo %s -> %v", nodename, nodeInfo)
-				return false
-			}
-			err := predicateChecker.CheckPredicates(pod, predicateMeta, nodeInfo)
-			if err != nil {
-				glogx.V(4).UpTo(loggingQuota).Infof("Evaluation %s for %s/%s -> %v", nodename, pod.Namespace, pod.Name, err.VerboseError())
-			} else {
-				// TODO(mwielgus): Optimize it.
-				klog.V(4).Infof("Pod %s/%s can be moved to %s", pod.Namespace, pod.Name, nodename)
-				podsOnNode := nodeInfo.Pods()
-				podsOnNode = append(podsOnNode, pod)
-				newNodeInfo := schedulercache.NewNodeInfo(podsOnNode...)
-				newNodeInfo.SetNode(nodeInfo.Node())
-				newNodeInfos[nodename] = newNodeInfo
-				newHints[podKey(pod)] = nodename
-				return true
-			}
+		nodeInfo, found := clusterSnapshot.GetNodeInfo(nodename)
+		if !found {
+			return false
+		}
+		err := predicateChecker.CheckPredicates(pod, predicateMeta, nodeInfo)
+		if err != nil {
+			glogx.V(4).UpTo(loggingQuota).Infof("Evaluation %s for %s/%s -> %v", nodename, pod.Namespace, pod.Name, err.VerboseError())
+			return false
+		}
+		if err := clusterSnapshot.AddPod(pod, nodename); err != nil {
+			klog.Errorf("Failed to add pod %s/%s to node %s in cluster snapshot: %v", pod.Namespace, pod.Name, nodename, err)
+			return false
 		}
-		return false
+		klog.V(4).Infof("Pod %s/%s can be moved to %s", pod.Namespace, pod.Name, nodename)
+		newHints[podKey(pod)] = nodename
+		return true
 	}
 
 	// TODO: come up with a better semi-random semi-utilization sorted
@@ -247,7 +372,11 @@ func findPlaceFor(removedNode string, pods []*apiv1.Pod, nodes []*apiv1.Node, no
 
 		foundPlace := false
 		targetNode := ""
-		predicateMeta := predicateChecker.GetPredicateMetadata(pod, newNodeInfos)
+		var predicateMeta algorithm.PredicateMetadata
+		// Building the full NodeInfo map is only worth it if the metadata is going to be computed.
+		if predicateChecker.enableAffinityPredicate {
+			predicateMeta = predicateChecker.GetPredicateMetadata(pod, clusterSnapshot.NodeInfos())
+		}
 		loggingQuota.Reset()
 
 		klog.V(5).Infof("Looking for place for %s/%s", pod.Namespace, pod.Name)
@@ -281,6 +410,36 @@ func findPlaceFor(removedNode string, pods []*apiv1.Pod, nodes []*apiv1.Node, no
 	return nil
 }
 
+func podKey(pod *apiv1.Pod) string {
+	return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+}
+
+// copyPdbs returns deep copies of the given pod disruption budgets, so that the allowed
+// disruptions can be consumed during simulation without modifying the originals.
+func copyPdbs(pdbs []*policyv1.PodDisruptionBudget) []*policyv1.PodDisruptionBudget {
+	result := make([]*policyv1.PodDisruptionBudget, 0, len(pdbs))
+	for _, pdb := range pdbs {
+		result = append(result, pdb.DeepCopy())
+	}
+	return result
+}
+
+// consumePdbs decreases the allowed disruptions of pdbs by the number of matching pods.
+func consumePdbs(pods []*apiv1.Pod, pdbs []*policyv1.PodDisruptionBudget) error {
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods {
+			if pod.Namespace == pdb.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+				pdb.Status.PodDisruptionsAllowed--
+			}
+		}
+	}
+	return nil
+}
+
 func shuffleNodes(nodes []*apiv1.Node) []*apiv1.Node {
 	result := make([]*apiv1.Node, len(nodes))
 	for i := range nodes {
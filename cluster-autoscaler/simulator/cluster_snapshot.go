@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+
+	apiv1 "k8s.io/api/core/v1"
+	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+// ClusterSnapshot is an abstraction of the cluster state used in scheduling simulations. It allows adding
+// and removing nodes and pods, and forking the state so that a set of changes can be either committed or
+// reverted as a whole.
+type ClusterSnapshot interface {
+	// AddNode adds a node without pods to the snapshot.
+	AddNode(node *apiv1.Node) error
+	// AddNodes adds nodes without pods to the snapshot.
+	AddNodes(nodes []*apiv1.Node) error
+	// AddNodeWithPods adds a node together with pods running on it to the snapshot.
+	AddNodeWithPods(node *apiv1.Node, pods []*apiv1.Pod) error
+	// RemoveNode removes a node, together with its pods, from the snapshot.
+	RemoveNode(nodeName string) error
+	// AddPod adds a pod to the given node.
+	AddPod(pod *apiv1.Pod, nodeName string) error
+	// RemovePod removes a pod from the given node.
+	RemovePod(namespace string, podName string, nodeName string) error
+	// GetNodeInfo returns the NodeInfo of the given node. The returned NodeInfo must not be modified.
+	GetNodeInfo(nodeName string) (*schedulercache.NodeInfo, bool)
+	// NodeInfos returns NodeInfos of all nodes in the snapshot, keyed by node name. Neither the map nor
+	// the NodeInfos may be modified.
+	NodeInfos() map[string]*schedulercache.NodeInfo
+
+	// Fork creates a fork of the snapshot state. All modifications can later be reverted to the moment
+	// of forking with Revert, or applied to the state before forking with Commit. Forks can be nested.
+	Fork() error
+	// Revert reverts the snapshot state to the moment of the most recent Fork.
+	Revert() error
+	// Commit applies modifications done after the most recent Fork to the state before forking.
+	Commit() error
+	// Clear resets the snapshot to an empty, unforked state.
+	Clear()
+}
+
+var (
+	errNodeNotFound = fmt.Errorf("node not found")
+	errNotForked    = fmt.Errorf("snapshot not forked")
+)
+
+// InitializeClusterSnapshot clears the snapshot and fills it with the given nodes and pods scheduled
+// or nominated to run on them. Pods that don't belong to any of the nodes are ignored.
+func InitializeClusterSnapshot(snapshot ClusterSnapshot, nodes []*apiv1.Node, pods []*apiv1.Pod) error {
+	snapshot.Clear()
+	podsOnNode := make(map[string][]*apiv1.Pod, len(nodes))
+	for _, pod := range pods {
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" {
+			nodeName = pod.Annotations[scheduler_util.NominatedNodeAnnotationKey]
+		}
+		if nodeName != "" {
+			podsOnNode[nodeName] = append(podsOnNode[nodeName], pod)
+		}
+	}
+	for _, node := range nodes {
+		if err := snapshot.AddNodeWithPods(node, podsOnNode[node.Name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nodeInfoWithPods returns a new NodeInfo for the given node and pods.
+func nodeInfoWithPods(node *apiv1.Node, pods []*apiv1.Pod) (*schedulercache.NodeInfo, error) {
+	nodeInfo := schedulercache.NewNodeInfo(pods...)
+	if err := nodeInfo.SetNode(node); err != nil {
+		return nil, err
+	}
+	return nodeInfo, nil
+}
+
+// removePodFromNodeInfo removes a pod from the NodeInfo in place.
+func removePodFromNodeInfo(nodeInfo *schedulercache.NodeInfo, namespace string, podName string) error {
+	pods := nodeInfo.Pods()
+	for i, pod := range pods {
+		if pod.Namespace != namespace || pod.Name != podName {
+			continue
+		}
+		if pod.UID != "" {
+			return nodeInfo.RemovePod(pod)
+		}
+		// NodeInfo identifies pods by UID, pods without it have to be removed by rebuilding the NodeInfo.
+		remaining := make([]*apiv1.Pod, 0, len(pods)-1)
+		remaining = append(remaining, pods[:i]...)
+		remaining = append(remaining, pods[i+1:]...)
+		rebuilt, err := nodeInfoWithPods(nodeInfo.Node(), remaining)
+		if err != nil {
+			return err
+		}
+		*nodeInfo = *rebuilt
+		return nil
+	}
+	return fmt.Errorf("pod %s/%s not found on node %s", namespace, podName, nodeInfo.Node().Name)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+const (
+	benchmarkPodsPerNode  = 30
+	benchmarkPodsToPlace  = 10
+	benchmarkPodMilliCPU  = 10
+	benchmarkNodeMilliCPU = 10000
+)
+
+var benchmarkNodeCounts = []int{100, 1000, 5000}
+
+func createBenchmarkNodes(n int) ([]*apiv1.Node, []*apiv1.Pod) {
+	nodes := make([]*apiv1.Node, 0, n)
+	pods := make([]*apiv1.Pod, 0, n*benchmarkPodsPerNode)
+	for i := 0; i < n; i++ {
+		node := BuildTestNode(fmt.Sprintf("n%d", i), benchmarkNodeMilliCPU, 1000000000)
+		nodes = append(nodes, node)
+		for j := 0; j < benchmarkPodsPerNode; j++ {
+			pod := BuildTestPod(fmt.Sprintf("p%d-%d", i, j), benchmarkPodMilliCPU, 1000000)
+			pod.Spec.NodeName = node.Name
+			pods = append(pods, pod)
+		}
+	}
+	return nodes, pods
+}
+
+func createBenchmarkPods() []*apiv1.Pod {
+	pods := make([]*apiv1.Pod, 0, benchmarkPodsToPlace)
+	for i := 0; i < benchmarkPodsToPlace; i++ {
+		pods = append(pods, BuildTestPod(fmt.Sprintf("new%d", i), benchmarkPodMilliCPU, 1000000))
+	}
+	return pods
+}
+
+// BenchmarkNodeInfoMapSimulation measures the approach used before ClusterSnapshot was introduced:
+// the NodeInfo map is copied for every simulation and each placed pod rebuilds the NodeInfo.
+func BenchmarkNodeInfoMapSimulation(b *testing.B) {
+	for _, count := range benchmarkNodeCounts {
+		nodes, pods := createBenchmarkNodes(count)
+		newPods := createBenchmarkPods()
+		nodeInfos := scheduler_util.CreateNodeNameToInfoMap(pods, nodes)
+		b.Run(fmt.Sprintf("%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				forked := make(map[string]*schedulercache.NodeInfo, len(nodeInfos))
+				for k, v := range nodeInfos {
+					forked[k] = v
+				}
+				for j, pod := range newPods {
+					nodeInfo := forked[nodes[(i+j)%count].Name]
+					newNodeInfo := schedulercache.NewNodeInfo(append(nodeInfo.Pods(), pod)...)
+					newNodeInfo.SetNode(nodeInfo.Node())
+					forked[nodeInfo.Node().Name] = newNodeInfo
+				}
+			}
+		})
+	}
+}
+
+func benchmarkClusterSnapshotSimulation(b *testing.B, newSnapshot func() ClusterSnapshot) {
+	for _, count := range benchmarkNodeCounts {
+		nodes, pods := createBenchmarkNodes(count)
+		newPods := createBenchmarkPods()
+		snapshot := newSnapshot()
+		if err := InitializeClusterSnapshot(snapshot, nodes, pods); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := snapshot.Fork(); err != nil {
+					b.Fatal(err)
+				}
+				for j, pod := range newPods {
+					if err := snapshot.AddPod(pod, nodes[(i+j)%count].Name); err != nil {
+						b.Fatal(err)
+					}
+				}
+				if err := snapshot.Revert(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBasicClusterSnapshotSimulation(b *testing.B) {
+	benchmarkClusterSnapshotSimulation(b, func() ClusterSnapshot { return NewBasicClusterSnapshot() })
+}
+
+func BenchmarkDeltaClusterSnapshotSimulation(b *testing.B) {
+	benchmarkClusterSnapshotSimulation(b, func() ClusterSnapshot { return NewDeltaClusterSnapshot() })
+}
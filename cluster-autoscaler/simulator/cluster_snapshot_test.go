@@ -0,0 +1,176 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"sort"
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var snapshots = map[string]func() ClusterSnapshot{
+	"basic": func() ClusterSnapshot { return NewBasicClusterSnapshot() },
+	"delta": func() ClusterSnapshot { return NewDeltaClusterSnapshot() },
+}
+
+func nodeNames(snapshot ClusterSnapshot) []string {
+	result := make([]string, 0)
+	for name := range snapshot.NodeInfos() {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func podNames(t *testing.T, snapshot ClusterSnapshot, nodeName string) []string {
+	nodeInfo, found := snapshot.GetNodeInfo(nodeName)
+	assert.True(t, found)
+	result := make([]string, 0)
+	for _, pod := range nodeInfo.Pods() {
+		result = append(result, pod.Name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestInitializeClusterSnapshot(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	p1 := BuildTestPod("p1", 100, 100)
+	p1.Spec.NodeName = "n1"
+	p2 := BuildTestPod("p2", 100, 100)
+	p2.Spec.NodeName = "n3"
+	p3 := BuildTestPod("p3", 100, 100)
+
+	for name, newSnapshot := range snapshots {
+		snapshot := newSnapshot()
+		assert.NoError(t, snapshot.AddNode(BuildTestNode("old", 1000, 1000)), name)
+
+		assert.NoError(t, InitializeClusterSnapshot(snapshot, []*apiv1.Node{n1, n2}, []*apiv1.Pod{p1, p2, p3}), name)
+		assert.Equal(t, []string{"n1", "n2"}, nodeNames(snapshot), name)
+		assert.Equal(t, []string{"p1"}, podNames(t, snapshot, "n1"), name)
+		assert.Equal(t, []string{}, podNames(t, snapshot, "n2"), name)
+	}
+}
+
+func TestClusterSnapshotNodesAndPods(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	p1 := BuildTestPod("p1", 100, 100)
+	p2 := BuildTestPod("p2", 100, 100)
+	p2.UID = "p2-uid"
+
+	for name, newSnapshot := range snapshots {
+		snapshot := newSnapshot()
+		assert.NoError(t, snapshot.AddNodes([]*apiv1.Node{n1, n2}), name)
+		assert.Error(t, snapshot.AddNode(n1), name)
+
+		assert.NoError(t, snapshot.AddPod(p1, "n1"), name)
+		assert.NoError(t, snapshot.AddPod(p2, "n1"), name)
+		assert.Error(t, snapshot.AddPod(p1, "n3"), name)
+		assert.Equal(t, []string{"p1", "p2"}, podNames(t, snapshot, "n1"), name)
+		nodeInfo, _ := snapshot.GetNodeInfo("n1")
+		assert.Equal(t, int64(200), nodeInfo.RequestedResource().MilliCPU, name)
+
+		// Pods are removed both with and without UID.
+		assert.NoError(t, snapshot.RemovePod("default", "p1", "n1"), name)
+		assert.NoError(t, snapshot.RemovePod("default", "p2", "n1"), name)
+		assert.Error(t, snapshot.RemovePod("default", "p2", "n1"), name)
+		assert.Equal(t, []string{}, podNames(t, snapshot, "n1"), name)
+		nodeInfo, _ = snapshot.GetNodeInfo("n1")
+		assert.Equal(t, int64(0), nodeInfo.RequestedResource().MilliCPU, name)
+
+		assert.NoError(t, snapshot.RemoveNode("n1"), name)
+		assert.Error(t, snapshot.RemoveNode("n1"), name)
+		assert.Equal(t, []string{"n2"}, nodeNames(snapshot), name)
+	}
+}
+
+func TestClusterSnapshotForkRevert(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+	p1 := BuildTestPod("p1", 100, 100)
+	p2 := BuildTestPod("p2", 100, 100)
+
+	for name, newSnapshot := range snapshots {
+		snapshot := newSnapshot()
+		assert.Error(t, snapshot.Revert(), name)
+		assert.NoError(t, snapshot.AddNodeWithPods(n1, []*apiv1.Pod{p1}), name)
+		assert.NoError(t, snapshot.AddNode(n2), name)
+
+		assert.NoError(t, snapshot.Fork(), name)
+		assert.NoError(t, snapshot.AddPod(p2, "n1"), name)
+		assert.NoError(t, snapshot.RemoveNode("n2"), name)
+		assert.NoError(t, snapshot.AddNode(n3), name)
+		assert.Equal(t, []string{"n1", "n3"}, nodeNames(snapshot), name)
+		assert.Equal(t, []string{"p1", "p2"}, podNames(t, snapshot, "n1"), name)
+
+		assert.NoError(t, snapshot.Revert(), name)
+		assert.Equal(t, []string{"n1", "n2"}, nodeNames(snapshot), name)
+		assert.Equal(t, []string{"p1"}, podNames(t, snapshot, "n1"), name)
+		nodeInfo, _ := snapshot.GetNodeInfo("n1")
+		assert.Equal(t, int64(100), nodeInfo.RequestedResource().MilliCPU, name)
+	}
+}
+
+func TestClusterSnapshotForkCommit(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+	p1 := BuildTestPod("p1", 100, 100)
+	p2 := BuildTestPod("p2", 100, 100)
+	p3 := BuildTestPod("p3", 100, 100)
+
+	for name, newSnapshot := range snapshots {
+		snapshot := newSnapshot()
+		assert.Error(t, snapshot.Commit(), name)
+		assert.NoError(t, snapshot.AddNodeWithPods(n1, []*apiv1.Pod{p1}), name)
+		assert.NoError(t, snapshot.AddNode(n2), name)
+
+		assert.NoError(t, snapshot.Fork(), name)
+		assert.NoError(t, snapshot.AddPod(p2, "n1"), name)
+		assert.NoError(t, snapshot.RemoveNode("n2"), name)
+		assert.NoError(t, snapshot.AddNode(n3), name)
+
+		// Nested fork is reverted without affecting the outer one.
+		assert.NoError(t, snapshot.Fork(), name)
+		assert.NoError(t, snapshot.AddPod(p3, "n3"), name)
+		assert.NoError(t, snapshot.RemoveNode("n1"), name)
+		assert.NoError(t, snapshot.AddNode(n2), name)
+		assert.Equal(t, []string{"n2", "n3"}, nodeNames(snapshot), name)
+		assert.NoError(t, snapshot.Revert(), name)
+
+		// Nested fork is committed to the outer one.
+		assert.NoError(t, snapshot.Fork(), name)
+		assert.NoError(t, snapshot.AddPod(p3, "n3"), name)
+		assert.NoError(t, snapshot.Commit(), name)
+
+		assert.NoError(t, snapshot.Commit(), name)
+		assert.Error(t, snapshot.Commit(), name)
+		assert.Equal(t, []string{"n1", "n3"}, nodeNames(snapshot), name)
+		assert.Equal(t, []string{"p1", "p2"}, podNames(t, snapshot, "n1"), name)
+		assert.Equal(t, []string{"p3"}, podNames(t, snapshot, "n3"), name)
+
+		snapshot.Clear()
+		assert.Equal(t, []string{}, nodeNames(snapshot), name)
+	}
+}
@@ -23,6 +23,8 @@ import (
 
 	apiv1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 	"k8s.io/kubernetes/pkg/kubelet/types"
 	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
@@ -81,16 +83,13 @@ func TestFindPlaceAllOk(t *testing.T) {
 	new1 := BuildTestPod("p2", 600, 500000)
 	new2 := BuildTestPod("p3", 500, 500000)
 
-	nodeInfos := map[string]*schedulercache.NodeInfo{
-		"n1": schedulercache.NewNodeInfo(pod1),
-		"n2": schedulercache.NewNodeInfo(),
-	}
 	node1 := BuildTestNode("n1", 1000, 2000000)
 	SetNodeReadyState(node1, true, time.Time{})
 	node2 := BuildTestNode("n2", 1000, 2000000)
 	SetNodeReadyState(node2, true, time.Time{})
-	nodeInfos["n1"].SetNode(node1)
-	nodeInfos["n2"].SetNode(node2)
+	clusterSnapshot := NewDeltaClusterSnapshot()
+	assert.NoError(t, clusterSnapshot.AddNodeWithPods(node1, []*apiv1.Pod{pod1}))
+	assert.NoError(t, clusterSnapshot.AddNode(node2))
 
 	oldHints := make(map[string]string)
 	newHints := make(map[string]string)
@@ -100,7 +99,7 @@ func TestFindPlaceAllOk(t *testing.T) {
 		"x",
 		[]*apiv1.Pod{new1, new2},
 		[]*apiv1.Node{node1, node2},
-		nodeInfos, NewTestPredicateChecker(),
+		clusterSnapshot, NewTestPredicateChecker(),
 		oldHints, newHints, tracker, time.Now())
 
 	assert.Len(t, newHints, 2)
@@ -115,11 +114,6 @@ func TestFindPlaceAllBas(t *testing.T) {
 	new2 := BuildTestPod("p3", 500, 500000)
 	new3 := BuildTestPod("p4", 700, 500000)
 
-	nodeInfos := map[string]*schedulercache.NodeInfo{
-		"n1":   schedulercache.NewNodeInfo(pod1),
-		"n2":   schedulercache.NewNodeInfo(),
-		"nbad": schedulercache.NewNodeInfo(),
-	}
 	nodebad := BuildTestNode("nbad", 1000, 2000000)
 	node1 := BuildTestNode("n1", 1000, 2000000)
 	SetNodeReadyState(node1, true, time.Time{})
@@ -127,9 +121,10 @@ func TestFindPlaceAllBas(t *testing.T) {
 	node2 := BuildTestNode("n2", 1000, 2000000)
 	SetNodeReadyState(node2, true, time.Time{})
 
-	nodeInfos["n1"].SetNode(node1)
-	nodeInfos["n2"].SetNode(node2)
-	nodeInfos["nbad"].SetNode(nodebad)
+	clusterSnapshot := NewDeltaClusterSnapshot()
+	assert.NoError(t, clusterSnapshot.AddNodeWithPods(node1, []*apiv1.Pod{pod1}))
+	assert.NoError(t, clusterSnapshot.AddNode(node2))
+	assert.NoError(t, clusterSnapshot.AddNode(nodebad))
 
 	oldHints := make(map[string]string)
 	newHints := make(map[string]string)
@@ -139,7 +134,7 @@ func TestFindPlaceAllBas(t *testing.T) {
 		"nbad",
 		[]*apiv1.Pod{new1, new2, new3},
 		[]*apiv1.Node{nodebad, node1, node2},
-		nodeInfos, NewTestPredicateChecker(),
+		clusterSnapshot, NewTestPredicateChecker(),
 		oldHints, newHints, tracker, time.Now())
 
 	assert.Error(t, err)
@@ -151,24 +146,21 @@ func TestFindPlaceAllBas(t *testing.T) {
 func TestFindNone(t *testing.T) {
 	pod1 := BuildTestPod("p1", 300, 500000)
 
-	nodeInfos := map[string]*schedulercache.NodeInfo{
-		"n1": schedulercache.NewNodeInfo(pod1),
-		"n2": schedulercache.NewNodeInfo(),
-	}
 	node1 := BuildTestNode("n1", 1000, 2000000)
 	SetNodeReadyState(node1, true, time.Time{})
 
 	node2 := BuildTestNode("n2", 1000, 2000000)
 	SetNodeReadyState(node2, true, time.Time{})
 
-	nodeInfos["n1"].SetNode(node1)
-	nodeInfos["n2"].SetNode(node2)
+	clusterSnapshot := NewDeltaClusterSnapshot()
+	assert.NoError(t, clusterSnapshot.AddNodeWithPods(node1, []*apiv1.Pod{pod1}))
+	assert.NoError(t, clusterSnapshot.AddNode(node2))
 
 	err := findPlaceFor(
 		"x",
 		[]*apiv1.Pod{},
 		[]*apiv1.Node{node1, node2},
-		nodeInfos, NewTestPredicateChecker(),
+		clusterSnapshot, NewTestPredicateChecker(),
 		make(map[string]string),
 		make(map[string]string),
 		NewUsageTracker(),
@@ -220,7 +212,7 @@ type findNodesToRemoveTestConfig struct {
 	candidates  []*apiv1.Node
 	allNodes    []*apiv1.Node
 	toRemove    []NodeToBeRemoved
-	unremovable []*apiv1.Node
+	unremovable []*UnremovableNode
 }
 
 func TestFindNodesToRemove(t *testing.T) {
@@ -262,6 +254,10 @@ func TestFindNodesToRemove(t *testing.T) {
 		PodsToReschedule: []*apiv1.Pod{pod1, pod2},
 	}
 
+	drainableNodeNoPlace := &UnremovableNode{Node: drainableNode, Reason: NoPlaceToMovePods}
+	nonDrainableNodeBlocked := &UnremovableNode{Node: nonDrainableNode, Reason: NotReplicated,
+		BlockingPod: &drain.BlockingPod{Pod: pod3, Reason: drain.NotReplicated}}
+
 	pods := []*apiv1.Pod{pod1, pod2, pod3, pod4}
 	predicateChecker := NewTestPredicateChecker()
 	tracker := NewUsageTracker()
@@ -273,7 +269,7 @@ func TestFindNodesToRemove(t *testing.T) {
 			candidates:  []*apiv1.Node{emptyNode},
 			allNodes:    []*apiv1.Node{emptyNode},
 			toRemove:    []NodeToBeRemoved{emptyNodeToRemove},
-			unremovable: []*apiv1.Node{},
+			unremovable: []*UnremovableNode{},
 		},
 		// just a drainable node, but nowhere for pods to go to
 		{
@@ -281,7 +277,7 @@ func TestFindNodesToRemove(t *testing.T) {
 			candidates:  []*apiv1.Node{drainableNode},
 			allNodes:    []*apiv1.Node{drainableNode},
 			toRemove:    []NodeToBeRemoved{},
-			unremovable: []*apiv1.Node{drainableNode},
+			unremovable: []*UnremovableNode{drainableNodeNoPlace},
 		},
 		// drainable node, and a mostly empty node that can take its pods
 		{
@@ -289,7 +285,7 @@ func TestFindNodesToRemove(t *testing.T) {
 			candidates:  []*apiv1.Node{drainableNode, nonDrainableNode},
 			allNodes:    []*apiv1.Node{drainableNode, nonDrainableNode},
 			toRemove:    []NodeToBeRemoved{drainableNodeToRemove},
-			unremovable: []*apiv1.Node{nonDrainableNode},
+			unremovable: []*UnremovableNode{nonDrainableNodeBlocked},
 		},
 		// drainable node, and a full node that cannot fit anymore pods
 		{
@@ -297,7 +293,7 @@ func TestFindNodesToRemove(t *testing.T) {
 			candidates:  []*apiv1.Node{drainableNode},
 			allNodes:    []*apiv1.Node{drainableNode, fullNode},
 			toRemove:    []NodeToBeRemoved{},
-			unremovable: []*apiv1.Node{drainableNode},
+			unremovable: []*UnremovableNode{drainableNodeNoPlace},
 		},
 		// 4 nodes, 1 empty, 1 drainable
 		{
@@ -305,13 +301,15 @@ func TestFindNodesToRemove(t *testing.T) {
 			candidates:  []*apiv1.Node{emptyNode, drainableNode},
 			allNodes:    []*apiv1.Node{emptyNode, drainableNode, fullNode, nonDrainableNode},
 			toRemove:    []NodeToBeRemoved{emptyNodeToRemove, drainableNodeToRemove},
-			unremovable: []*apiv1.Node{},
+			unremovable: []*UnremovableNode{},
 		},
 	}
 
 	for _, test := range tests {
+		clusterSnapshot := NewDeltaClusterSnapshot()
+		assert.NoError(t, InitializeClusterSnapshot(clusterSnapshot, test.allNodes, pods))
 		toRemove, unremovable, _, err := FindNodesToRemove(
-			test.candidates, test.allNodes, pods, nil,
+			test.candidates, test.allNodes, clusterSnapshot, nil,
 			predicateChecker, len(test.allNodes), true, map[string]string{},
 			tracker, time.Now(), []*policyv1.PodDisruptionBudget{})
 		assert.NoError(t, err)
@@ -321,3 +319,80 @@ func TestFindNodesToRemove(t *testing.T) {
 	}
 
 }
+
+func TestFindNodesToRemoveTogether(t *testing.T) {
+	ownerRefs := GenerateOwnerReferences("rs", "ReplicaSet", "extensions/v1beta1", "")
+
+	// two small pods
+	smallNode := BuildTestNode("n1", 1000, 2000000)
+	// two large pods, filling up the node
+	largeNode := BuildTestNode("n2", 1000, 2000000)
+	// can take the pods from one of the nodes above, but not from both
+	targetNode := BuildTestNode("n3", 1000, 2000000)
+	SetNodeReadyState(smallNode, true, time.Time{})
+	SetNodeReadyState(largeNode, true, time.Time{})
+	SetNodeReadyState(targetNode, true, time.Time{})
+
+	pod1 := BuildTestPod("p1", 100, 100000)
+	pod1.OwnerReferences = ownerRefs
+	pod1.Spec.NodeName = "n1"
+	pod2 := BuildTestPod("p2", 100, 100000)
+	pod2.OwnerReferences = ownerRefs
+	pod2.Spec.NodeName = "n1"
+	pod3 := BuildTestPod("p3", 500, 100000)
+	pod3.OwnerReferences = ownerRefs
+	pod3.Spec.NodeName = "n2"
+	pod4 := BuildTestPod("p4", 500, 100000)
+	pod4.OwnerReferences = ownerRefs
+	pod4.Spec.NodeName = "n2"
+
+	pods := []*apiv1.Pod{pod1, pod2, pod3, pod4}
+	allNodes := []*apiv1.Node{smallNode, largeNode, targetNode}
+	// only a destination node in the last check
+	bigNode := BuildTestNode("n4", 10000, 20000000)
+	SetNodeReadyState(bigNode, true, time.Time{})
+	predicateChecker := NewTestPredicateChecker()
+
+	clusterSnapshot := NewDeltaClusterSnapshot()
+	assert.NoError(t, InitializeClusterSnapshot(clusterSnapshot, append(allNodes, bigNode), pods))
+
+	// Each of the nodes can be removed on its own, but only the first one once they are removed together.
+	toRemove, unremovable, _, err := FindNodesToRemove([]*apiv1.Node{smallNode, largeNode}, allNodes, clusterSnapshot, nil,
+		predicateChecker, len(allNodes), true, map[string]string{},
+		NewUsageTracker(), time.Now(), []*policyv1.PodDisruptionBudget{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(toRemove))
+	assert.Equal(t, 0, len(unremovable))
+	toRemove, unremovable, _, err = FindNodesToRemoveTogether([]*apiv1.Node{smallNode, largeNode}, allNodes, clusterSnapshot, nil,
+		predicateChecker, len(allNodes), true, map[string]string{},
+		NewUsageTracker(), time.Now(), []*policyv1.PodDisruptionBudget{})
+	assert.NoError(t, err)
+	assert.Equal(t, []NodeToBeRemoved{{Node: smallNode, PodsToReschedule: []*apiv1.Pod{pod1, pod2}}}, toRemove)
+	assert.Equal(t, []*UnremovableNode{{Node: largeNode, Reason: NoPlaceToMovePods}}, unremovable)
+
+	// The simulation doesn't change the snapshot.
+	assert.Equal(t, 4, len(clusterSnapshot.NodeInfos()))
+	targetNodeInfo, found := clusterSnapshot.GetNodeInfo("n3")
+	assert.True(t, found)
+	assert.Equal(t, 0, len(targetNodeInfo.Pods()))
+
+	// A pod disruption budget allowing a single disruption is shared between all removed nodes.
+	pod1.Labels = map[string]string{"app": "pdb"}
+	pod3.Labels = map[string]string{"app": "pdb"}
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: pod1.Namespace},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "pdb"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{PodDisruptionsAllowed: 1},
+	}
+	toRemove, unremovable, _, err = FindNodesToRemoveTogether([]*apiv1.Node{smallNode, largeNode}, append(allNodes, bigNode), clusterSnapshot, nil,
+		predicateChecker, len(allNodes), true, map[string]string{},
+		NewUsageTracker(), time.Now(), []*policyv1.PodDisruptionBudget{pdb})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(toRemove))
+	assert.Equal(t, smallNode, toRemove[0].Node)
+	assert.Equal(t, []*UnremovableNode{{Node: largeNode, Reason: NotEnoughPdb,
+		BlockingPod: &drain.BlockingPod{Pod: pod3, Reason: drain.NotEnoughPdb}}}, unremovable)
+	assert.Equal(t, int32(1), pdb.Status.PodDisruptionsAllowed)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"sort"
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// removalSavingsPeriod is the period for which the savings of removing a node are computed.
+	removalSavingsPeriod = time.Hour
+)
+
+// NodeRemovalSavings contains the money saved by removing a node, per pod that has to be moved
+// elsewhere.
+type NodeRemovalSavings struct {
+	NodeName string
+	// NodePrice is the price of running the node for an hour.
+	NodePrice float64
+	// PodsToMove is the number of pods running on the node, excluding DaemonSet, mirror and headroom
+	// pods.
+	PodsToMove int
+	// SavingsPerPod is NodePrice divided by PodsToMove, or NodePrice for nodes without pods to move.
+	SavingsPerPod float64
+}
+
+// SortNodesByRemovalSavings returns candidates ordered by the money saved per pod moved when they
+// are removed, most savings first, together with the savings of each candidate in the same order.
+// Candidates whose price is unknown follow, in their original order, and have no savings entry.
+func SortNodesByRemovalSavings(candidates []*apiv1.Node, pods []*apiv1.Pod, pricingModel cloudprovider.PricingModel,
+	now time.Time) ([]*apiv1.Node, []NodeRemovalSavings) {
+
+	nodeNameToNodeInfo := scheduler_util.CreateNodeNameToInfoMap(pods, candidates)
+	priced := make([]*apiv1.Node, 0, len(candidates))
+	unpriced := make([]*apiv1.Node, 0)
+	savings := make(map[string]NodeRemovalSavings, len(candidates))
+
+	for _, node := range candidates {
+		price, err := pricingModel.NodePrice(node, now, now.Add(removalSavingsPeriod))
+		if err != nil {
+			klog.V(4).Infof("Failed to get price of node %s: %v", node.Name, err)
+			unpriced = append(unpriced, node)
+			continue
+		}
+		podsToMove := 0
+		if nodeInfo, found := nodeNameToNodeInfo[node.Name]; found {
+			for _, pod := range nodeInfo.Pods() {
+				if isPodToMove(pod) {
+					podsToMove++
+				}
+			}
+		}
+		nodeSavings := NodeRemovalSavings{
+			NodeName:      node.Name,
+			NodePrice:     price,
+			PodsToMove:    podsToMove,
+			SavingsPerPod: price,
+		}
+		if podsToMove > 0 {
+			nodeSavings.SavingsPerPod = price / float64(podsToMove)
+		}
+		savings[node.Name] = nodeSavings
+		priced = append(priced, node)
+	}
+
+	sort.SliceStable(priced, func(i, j int) bool {
+		return savings[priced[i].Name].SavingsPerPod > savings[priced[j].Name].SavingsPerPod
+	})
+
+	ranking := make([]NodeRemovalSavings, 0, len(priced))
+	for _, node := range priced {
+		ranking = append(ranking, savings[node.Name])
+	}
+	return append(priced, unpriced...), ranking
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+
+	apiv1 "k8s.io/api/core/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPricingModel struct {
+	nodePrices map[string]float64
+}
+
+func (tpm *testPricingModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	if price, found := tpm.nodePrices[node.Name]; found {
+		return price, nil
+	}
+	return 0.0, fmt.Errorf("price for node %v not found", node.Name)
+}
+
+func (tpm *testPricingModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	return 0.0, fmt.Errorf("price for pod %v not found", pod.Name)
+}
+
+func TestSortNodesByRemovalSavings(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+	n4 := BuildTestNode("n4", 1000, 1000)
+
+	// n1 costs 5x n2, but 4 pods have to be moved to remove it.
+	var pods []*apiv1.Pod
+	for i := 0; i < 4; i++ {
+		pod := BuildTestPod(fmt.Sprintf("p1-%d", i), 100, 0)
+		pod.Spec.NodeName = "n1"
+		pods = append(pods, pod)
+	}
+	p2 := BuildTestPod("p2", 100, 0)
+	p2.Spec.NodeName = "n2"
+	// DaemonSet pods are not moved.
+	ds := BuildTestPod("ds", 100, 0)
+	ds.Spec.NodeName = "n3"
+	ds.OwnerReferences = GenerateOwnerReferences("ds", "DaemonSet", "extensions/v1beta1", "")
+	// Neither are headroom pods.
+	headroom := BuildTestPod("headroom", 100, 0)
+	headroom.Spec.NodeName = "n3"
+	headroom.Annotations = map[string]string{drain.HeadroomPodKey: ""}
+	pods = append(pods, p2, ds, headroom)
+
+	pricingModel := &testPricingModel{nodePrices: map[string]float64{"n1": 5.0, "n2": 1.0, "n3": 1.5}}
+	sorted, ranking := SortNodesByRemovalSavings([]*apiv1.Node{n4, n1, n2, n3}, pods, pricingModel, time.Now())
+
+	names := make([]string, 0, len(sorted))
+	for _, node := range sorted {
+		names = append(names, node.Name)
+	}
+	assert.Equal(t, []string{"n3", "n1", "n2", "n4"}, names)
+	assert.Equal(t, []NodeRemovalSavings{
+		{NodeName: "n3", NodePrice: 1.5, PodsToMove: 0, SavingsPerPod: 1.5},
+		{NodeName: "n1", NodePrice: 5.0, PodsToMove: 4, SavingsPerPod: 1.25},
+		{NodeName: "n2", NodePrice: 1.0, PodsToMove: 1, SavingsPerPod: 1.0},
+	}, ranking)
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+
+	apiv1 "k8s.io/api/core/v1"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+// DeltaClusterSnapshot is a ClusterSnapshot implementation that keeps only changes done since the most
+// recent Fork. NodeInfos are copied on write, so forking and reverting is cheap regardless of the cluster
+// size and the cost of a change doesn't depend on the number of nodes.
+//
+// Each fork is a layer on top of the previous state:
+//   - added contains nodes that are not present in the base layer,
+//   - modified contains copies of nodes from the base layer that were changed in this layer,
+//   - deleted contains nodes from the base layer that were removed in this layer.
+type DeltaClusterSnapshot struct {
+	data *deltaSnapshotLayer
+}
+
+type deltaSnapshotLayer struct {
+	base     *deltaSnapshotLayer
+	added    map[string]*schedulercache.NodeInfo
+	modified map[string]*schedulercache.NodeInfo
+	deleted  map[string]bool
+	// nodeInfoMap caches the result of nodeInfos until the layer is modified.
+	nodeInfoMap map[string]*schedulercache.NodeInfo
+}
+
+// NewDeltaClusterSnapshot creates an empty DeltaClusterSnapshot.
+func NewDeltaClusterSnapshot() *DeltaClusterSnapshot {
+	snapshot := &DeltaClusterSnapshot{}
+	snapshot.Clear()
+	return snapshot
+}
+
+func newDeltaSnapshotLayer(base *deltaSnapshotLayer) *deltaSnapshotLayer {
+	return &deltaSnapshotLayer{
+		base:     base,
+		added:    make(map[string]*schedulercache.NodeInfo),
+		modified: make(map[string]*schedulercache.NodeInfo),
+		deleted:  make(map[string]bool),
+	}
+}
+
+func (layer *deltaSnapshotLayer) getNodeInfo(nodeName string) (*schedulercache.NodeInfo, bool) {
+	if nodeInfo, found := layer.added[nodeName]; found {
+		return nodeInfo, true
+	}
+	if nodeInfo, found := layer.modified[nodeName]; found {
+		return nodeInfo, true
+	}
+	if layer.deleted[nodeName] || layer.base == nil {
+		return nil, false
+	}
+	return layer.base.getNodeInfo(nodeName)
+}
+
+func (layer *deltaSnapshotLayer) nodeInfos() map[string]*schedulercache.NodeInfo {
+	if layer.nodeInfoMap != nil {
+		return layer.nodeInfoMap
+	}
+	var result map[string]*schedulercache.NodeInfo
+	if layer.base == nil {
+		result = make(map[string]*schedulercache.NodeInfo, len(layer.added))
+	} else {
+		baseNodeInfos := layer.base.nodeInfos()
+		result = make(map[string]*schedulercache.NodeInfo, len(baseNodeInfos)+len(layer.added))
+		for name, nodeInfo := range baseNodeInfos {
+			if !layer.deleted[name] {
+				result[name] = nodeInfo
+			}
+		}
+	}
+	for name, nodeInfo := range layer.modified {
+		result[name] = nodeInfo
+	}
+	for name, nodeInfo := range layer.added {
+		result[name] = nodeInfo
+	}
+	layer.nodeInfoMap = result
+	return result
+}
+
+func (layer *deltaSnapshotLayer) addNodeInfo(nodeInfo *schedulercache.NodeInfo) error {
+	nodeName := nodeInfo.Node().Name
+	if _, found := layer.getNodeInfo(nodeName); found {
+		return fmt.Errorf("node %s already in snapshot", nodeName)
+	}
+	if layer.deleted[nodeName] {
+		// The node exists in the base layer, so it's a modification.
+		delete(layer.deleted, nodeName)
+		layer.modified[nodeName] = nodeInfo
+	} else {
+		layer.added[nodeName] = nodeInfo
+	}
+	layer.nodeInfoMap = nil
+	return nil
+}
+
+func (layer *deltaSnapshotLayer) updateNodeInfo(nodeInfo *schedulercache.NodeInfo) {
+	nodeName := nodeInfo.Node().Name
+	if _, found := layer.added[nodeName]; found {
+		layer.added[nodeName] = nodeInfo
+	} else {
+		layer.modified[nodeName] = nodeInfo
+	}
+	layer.nodeInfoMap = nil
+}
+
+func (layer *deltaSnapshotLayer) removeNode(nodeName string) error {
+	if _, found := layer.added[nodeName]; found {
+		delete(layer.added, nodeName)
+	} else if _, found := layer.getNodeInfo(nodeName); found {
+		delete(layer.modified, nodeName)
+		layer.deleted[nodeName] = true
+	} else {
+		return errNodeNotFound
+	}
+	layer.nodeInfoMap = nil
+	return nil
+}
+
+// nodeInfoToModify returns a NodeInfo owned by this layer that can be modified in place, copying
+// it from the base layer if needed.
+func (layer *deltaSnapshotLayer) nodeInfoToModify(nodeName string) (*schedulercache.NodeInfo, bool) {
+	nodeInfo, found := layer.added[nodeName]
+	if !found {
+		nodeInfo, found = layer.modified[nodeName]
+	}
+	if !found {
+		baseNodeInfo, baseFound := layer.getNodeInfo(nodeName)
+		if !baseFound {
+			return nil, false
+		}
+		nodeInfo = baseNodeInfo.Clone()
+		layer.modified[nodeName] = nodeInfo
+	}
+	layer.nodeInfoMap = nil
+	return nodeInfo, true
+}
+
+func (layer *deltaSnapshotLayer) commit() error {
+	base := layer.base
+	for nodeName := range layer.deleted {
+		if err := base.removeNode(nodeName); err != nil {
+			return err
+		}
+	}
+	for _, nodeInfo := range layer.modified {
+		base.updateNodeInfo(nodeInfo)
+	}
+	for _, nodeInfo := range layer.added {
+		if err := base.addNodeInfo(nodeInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddNode adds a node without pods to the snapshot.
+func (snapshot *DeltaClusterSnapshot) AddNode(node *apiv1.Node) error {
+	return snapshot.AddNodeWithPods(node, nil)
+}
+
+// AddNodes adds nodes without pods to the snapshot.
+func (snapshot *DeltaClusterSnapshot) AddNodes(nodes []*apiv1.Node) error {
+	for _, node := range nodes {
+		if err := snapshot.AddNode(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddNodeWithPods adds a node together with pods running on it to the snapshot.
+func (snapshot *DeltaClusterSnapshot) AddNodeWithPods(node *apiv1.Node, pods []*apiv1.Pod) error {
+	nodeInfo, err := nodeInfoWithPods(node, pods)
+	if err != nil {
+		return err
+	}
+	return snapshot.data.addNodeInfo(nodeInfo)
+}
+
+// RemoveNode removes a node, together with its pods, from the snapshot.
+func (snapshot *DeltaClusterSnapshot) RemoveNode(nodeName string) error {
+	return snapshot.data.removeNode(nodeName)
+}
+
+// AddPod adds a pod to the given node.
+func (snapshot *DeltaClusterSnapshot) AddPod(pod *apiv1.Pod, nodeName string) error {
+	nodeInfo, found := snapshot.data.nodeInfoToModify(nodeName)
+	if !found {
+		return errNodeNotFound
+	}
+	nodeInfo.AddPod(pod)
+	return nil
+}
+
+// RemovePod removes a pod from the given node.
+func (snapshot *DeltaClusterSnapshot) RemovePod(namespace string, podName string, nodeName string) error {
+	nodeInfo, found := snapshot.data.nodeInfoToModify(nodeName)
+	if !found {
+		return errNodeNotFound
+	}
+	return removePodFromNodeInfo(nodeInfo, namespace, podName)
+}
+
+// GetNodeInfo returns the NodeInfo of the given node.
+func (snapshot *DeltaClusterSnapshot) GetNodeInfo(nodeName string) (*schedulercache.NodeInfo, bool) {
+	return snapshot.data.getNodeInfo(nodeName)
+}
+
+// NodeInfos returns NodeInfos of all nodes in the snapshot. The map is cached until the snapshot
+// is modified.
+func (snapshot *DeltaClusterSnapshot) NodeInfos() map[string]*schedulercache.NodeInfo {
+	return snapshot.data.nodeInfos()
+}
+
+// Fork creates a fork of the snapshot state.
+func (snapshot *DeltaClusterSnapshot) Fork() error {
+	snapshot.data = newDeltaSnapshotLayer(snapshot.data)
+	return nil
+}
+
+// Revert reverts the snapshot state to the moment of the most recent Fork.
+func (snapshot *DeltaClusterSnapshot) Revert() error {
+	if snapshot.data.base == nil {
+		return errNotForked
+	}
+	snapshot.data = snapshot.data.base
+	return nil
+}
+
+// Commit applies modifications done after the most recent Fork to the state before forking.
+func (snapshot *DeltaClusterSnapshot) Commit() error {
+	if snapshot.data.base == nil {
+		return errNotForked
+	}
+	if err := snapshot.data.commit(); err != nil {
+		return err
+	}
+	snapshot.data = snapshot.data.base
+	return nil
+}
+
+// Clear resets the snapshot to an empty, unforked state.
+func (snapshot *DeltaClusterSnapshot) Clear() {
+	snapshot.data = newDeltaSnapshotLayer(nil)
+}
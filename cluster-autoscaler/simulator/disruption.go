@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+)
+
+const (
+	// defaultPodDisruptionCost is the disruption cost of pods without the drain.PodDisruptionCostKey annotation.
+	defaultPodDisruptionCost = 1.0
+	// disruptionCostPriorityStep is the pod priority that doubles the disruption cost of a pod.
+	disruptionCostPriorityStep = 1000
+	// disruptionCostMaxAge is the pod age at which the disruption cost of a pod is doubled. Older
+	// pods don't cost more.
+	disruptionCostMaxAge = 24 * time.Hour
+)
+
+// PodDisruptionCost returns the cost of evicting a pod. It is the value of the drain.PodDisruptionCostKey
+// annotation, 1 by default, increased by a factor of (1 + priority / 1000) for pods with a positive
+// priority and by a factor of (1 + age / 24h), up to 2, so that long running pods cost more.
+func PodDisruptionCost(pod *apiv1.Pod, now time.Time) float64 {
+	cost := defaultPodDisruptionCost
+	if value, found := pod.Annotations[drain.PodDisruptionCostKey]; found {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			klog.Warningf("Invalid %s annotation %q on pod %s/%s, using %v", drain.PodDisruptionCostKey, value,
+				pod.Namespace, pod.Name, defaultPodDisruptionCost)
+		} else {
+			cost = parsed
+		}
+	}
+
+	if pod.Spec.Priority != nil && *pod.Spec.Priority > 0 {
+		cost *= 1 + float64(*pod.Spec.Priority)/disruptionCostPriorityStep
+	}
+
+	startTime := pod.CreationTimestamp.Time
+	if pod.Status.StartTime != nil {
+		startTime = pod.Status.StartTime.Time
+	}
+	if !startTime.IsZero() && now.After(startTime) {
+		age := now.Sub(startTime)
+		if age > disruptionCostMaxAge {
+			age = disruptionCostMaxAge
+		}
+		cost *= 1 + float64(age)/float64(disruptionCostMaxAge)
+	}
+	return cost
+}
+
+// NodeDisruptionCost returns the sum of the disruption costs of the pods that have to be moved
+// to remove the node, that is all pods except DaemonSet, mirror and headroom pods.
+func NodeDisruptionCost(nodeInfo *schedulercache.NodeInfo, now time.Time) float64 {
+	if nodeInfo == nil {
+		return 0
+	}
+	cost := 0.0
+	for _, pod := range nodeInfo.Pods() {
+		if isPodToMove(pod) {
+			cost += PodDisruptionCost(pod, now)
+		}
+	}
+	return cost
+}
+
+// SortNodesByDisruptionCost returns candidates ordered by NodeDisruptionCost, cheapest first, together
+// with the disruption cost of each candidate.
+func SortNodesByDisruptionCost(candidates []*apiv1.Node, pods []*apiv1.Pod, now time.Time) ([]*apiv1.Node, map[string]float64) {
+	nodeNameToNodeInfo := scheduler_util.CreateNodeNameToInfoMap(pods, candidates)
+	costs := make(map[string]float64, len(candidates))
+	for _, node := range candidates {
+		costs[node.Name] = NodeDisruptionCost(nodeNameToNodeInfo[node.Name], now)
+	}
+
+	result := make([]*apiv1.Node, len(candidates))
+	copy(result, candidates)
+	sort.SliceStable(result, func(i, j int) bool {
+		return costs[result[i].Name] < costs[result[j].Name]
+	})
+	return result, costs
+}
+
+// isPodToMove tells if a pod has to be moved elsewhere when its node is removed. Headroom pods are
+// synthetic and are never moved.
+func isPodToMove(pod *apiv1.Pod) bool {
+	return !isDaemonSet(pod) && !drain.IsMirrorPod(pod) && !drain.IsHeadroomPod(pod)
+}
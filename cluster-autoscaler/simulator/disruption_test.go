@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	schedulercache "k8s.io/kubernetes/pkg/scheduler/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodDisruptionCost(t *testing.T) {
+	now := time.Now()
+	var priority int32 = 1000
+
+	plain := BuildTestPod("plain", 100, 0)
+	annotated := BuildTestPod("annotated", 100, 0)
+	annotated.Annotations = map[string]string{drain.PodDisruptionCostKey: "2.5"}
+	invalid := BuildTestPod("invalid", 100, 0)
+	invalid.Annotations = map[string]string{drain.PodDisruptionCostKey: "-1"}
+	important := BuildTestPod("important", 100, 0)
+	important.Spec.Priority = &priority
+	young := BuildTestPod("young", 100, 0)
+	young.Status.StartTime = &metav1.Time{Time: now.Add(-6 * time.Hour)}
+	old := BuildTestPod("old", 100, 0)
+	old.CreationTimestamp = metav1.Time{Time: now.Add(-72 * time.Hour)}
+
+	assert.Equal(t, 1.0, PodDisruptionCost(plain, now))
+	assert.Equal(t, 2.5, PodDisruptionCost(annotated, now))
+	assert.Equal(t, 1.0, PodDisruptionCost(invalid, now))
+	assert.Equal(t, 2.0, PodDisruptionCost(important, now))
+	assert.Equal(t, 1.25, PodDisruptionCost(young, now))
+	assert.Equal(t, 2.0, PodDisruptionCost(old, now))
+}
+
+func TestNodeDisruptionCost(t *testing.T) {
+	now := time.Now()
+	p1 := BuildTestPod("p1", 100, 0)
+	p1.Annotations = map[string]string{drain.PodDisruptionCostKey: "3"}
+	p2 := BuildTestPod("p2", 100, 0)
+	// DaemonSet pods are not moved, so they don't add to the cost.
+	ds := BuildTestPod("ds", 100, 0)
+	ds.OwnerReferences = GenerateOwnerReferences("ds", "DaemonSet", "extensions/v1beta1", "")
+	// Neither do headroom pods.
+	headroom := BuildTestPod("headroom", 100, 0)
+	headroom.Annotations = map[string]string{drain.HeadroomPodKey: "ng1"}
+
+	assert.Equal(t, 4.0, NodeDisruptionCost(schedulercache.NewNodeInfo(p1, p2, ds, headroom), now))
+	assert.Equal(t, 0.0, NodeDisruptionCost(nil, now))
+}
+
+func TestSortNodesByDisruptionCost(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+
+	p1 := BuildTestPod("p1", 100, 0)
+	p1.Annotations = map[string]string{drain.PodDisruptionCostKey: "5"}
+	p1.Spec.NodeName = "n1"
+	p2 := BuildTestPod("p2", 100, 0)
+	p2.Spec.NodeName = "n2"
+
+	sorted, costs := SortNodesByDisruptionCost([]*apiv1.Node{n1, n2, n3}, []*apiv1.Pod{p1, p2}, time.Now())
+
+	names := make([]string, 0, len(sorted))
+	for _, node := range sorted {
+		names = append(names, node.Name)
+	}
+	assert.Equal(t, []string{"n3", "n2", "n1"}, names)
+	assert.Equal(t, map[string]float64{"n1": 5.0, "n2": 1.0, "n3": 0.0}, costs)
+}
@@ -30,13 +30,13 @@ import (
 )
 
 // FastGetPodsToMove returns a list of pods that should be moved elsewhere if the node
-// is drained. Raises error if there is an unreplicated pod.
+// is drained. Raises error and returns the blocking pod if there is an unreplicated pod.
 // Based on kubectl drain code. It makes an assumption that RC, DS, Jobs and RS were deleted
 // along with their pods (no abandoned pods with dangling created-by annotation). Useful for fast
 // checks.
 func FastGetPodsToMove(nodeInfo *schedulercache.NodeInfo, skipNodesWithSystemPods bool, skipNodesWithLocalStorage bool,
-	pdbs []*policyv1.PodDisruptionBudget) ([]*apiv1.Pod, error) {
-	pods, err := drain.GetPodsForDeletionOnNodeDrain(
+	pdbs []*policyv1.PodDisruptionBudget) ([]*apiv1.Pod, *drain.BlockingPod, error) {
+	pods, blockingPod, err := drain.GetPodsForDeletionOnNodeDrain(
 		nodeInfo.Pods(),
 		pdbs,
 		false,
@@ -48,23 +48,23 @@ func FastGetPodsToMove(nodeInfo *schedulercache.NodeInfo, skipNodesWithSystemPod
 		time.Now())
 
 	if err != nil {
-		return pods, err
+		return pods, blockingPod, err
 	}
-	if err := checkPdbs(pods, pdbs); err != nil {
-		return []*apiv1.Pod{}, err
+	if blockingPod, err := checkPdbs(pods, pdbs); err != nil {
+		return []*apiv1.Pod{}, blockingPod, err
 	}
 
-	return pods, nil
+	return pods, nil, nil
 }
 
 // DetailedGetPodsForMove returns a list of pods that should be moved elsewhere if the node
-// is drained. Raises error if there is an unreplicated pod.
+// is drained. Raises error and returns the blocking pod if there is an unreplicated pod.
 // Based on kubectl drain code. It checks whether RC, DS, Jobs and RS that created these pods
 // still exist.
 func DetailedGetPodsForMove(nodeInfo *schedulercache.NodeInfo, skipNodesWithSystemPods bool,
 	skipNodesWithLocalStorage bool, client client.Interface, minReplicaCount int32,
-	pdbs []*policyv1.PodDisruptionBudget) ([]*apiv1.Pod, error) {
-	pods, err := drain.GetPodsForDeletionOnNodeDrain(
+	pdbs []*policyv1.PodDisruptionBudget) ([]*apiv1.Pod, *drain.BlockingPod, error) {
+	pods, blockingPod, err := drain.GetPodsForDeletionOnNodeDrain(
 		nodeInfo.Pods(),
 		pdbs,
 		false,
@@ -75,29 +75,30 @@ func DetailedGetPodsForMove(nodeInfo *schedulercache.NodeInfo, skipNodesWithSyst
 		minReplicaCount,
 		time.Now())
 	if err != nil {
-		return pods, err
+		return pods, blockingPod, err
 	}
-	if err := checkPdbs(pods, pdbs); err != nil {
-		return []*apiv1.Pod{}, err
+	if blockingPod, err := checkPdbs(pods, pdbs); err != nil {
+		return []*apiv1.Pod{}, blockingPod, err
 	}
 
-	return pods, nil
+	return pods, nil, nil
 }
 
-func checkPdbs(pods []*apiv1.Pod, pdbs []*policyv1.PodDisruptionBudget) error {
+func checkPdbs(pods []*apiv1.Pod, pdbs []*policyv1.PodDisruptionBudget) (*drain.BlockingPod, error) {
 	// TODO: make it more efficient.
 	for _, pdb := range pdbs {
 		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, pod := range pods {
 			if pod.Namespace == pdb.Namespace && selector.Matches(labels.Set(pod.Labels)) {
 				if pdb.Status.PodDisruptionsAllowed < 1 {
-					return fmt.Errorf("not enough pod disruption budget to move %s/%s", pod.Namespace, pod.Name)
+					return &drain.BlockingPod{Pod: pod, Reason: drain.NotEnoughPdb},
+						fmt.Errorf("not enough pod disruption budget to move %s/%s", pod.Namespace, pod.Name)
 				}
 			}
 		}
 	}
-	return nil
+	return nil, nil
 }
@@ -45,7 +45,7 @@ func GetRequiredPodsForNode(nodename string, client kube_client.Interface) ([]*a
 		allPods = append(allPods, &podListResult.Items[i])
 	}
 
-	podsToRemoveList, err := drain.GetPodsForDeletionOnNodeDrain(
+	podsToRemoveList, _, err := drain.GetPodsForDeletionOnNodeDrain(
 		allPods,
 		[]*policyv1.PodDisruptionBudget{}, // PDBs are irrelevant when considering new node.
 		true,                              // Force all removals.
@@ -28,6 +28,9 @@ type Backoff interface {
 	Backoff(nodeGroup cloudprovider.NodeGroup, currentTime time.Time) time.Time
 	// IsBackedOff returns true if execution is backed off for the given node group.
 	IsBackedOff(nodeGroup cloudprovider.NodeGroup, currentTime time.Time) bool
+	// BackoffUntil returns the time till execution is backed off for the given node group and
+	// whether it's backed off at all.
+	BackoffUntil(nodeGroup cloudprovider.NodeGroup, currentTime time.Time) (time.Time, bool)
 	// RemoveBackoff removes backoff data for the given node group.
 	RemoveBackoff(nodeGroup cloudprovider.NodeGroup)
 	// RemoveStaleBackoffData removes stale backoff data.
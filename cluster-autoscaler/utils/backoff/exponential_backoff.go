@@ -93,6 +93,16 @@ func (b *exponentialBackoff) IsBackedOff(nodeGroup cloudprovider.NodeGroup, curr
 	return found && backoffInfo.backoffUntil.After(currentTime)
 }
 
+// BackoffUntil returns the time till execution is backed off for the given node group and whether it's
+// backed off at all.
+func (b *exponentialBackoff) BackoffUntil(nodeGroup cloudprovider.NodeGroup, currentTime time.Time) (time.Time, bool) {
+	backoffInfo, found := b.backoffInfo[b.nodeGroupKey(nodeGroup)]
+	if !found || !backoffInfo.backoffUntil.After(currentTime) {
+		return time.Time{}, false
+	}
+	return backoffInfo.backoffUntil, true
+}
+
 // RemoveBackoff removes backoff data for the given node group.
 func (b *exponentialBackoff) RemoveBackoff(nodeGroup cloudprovider.NodeGroup) {
 	delete(b.backoffInfo, b.nodeGroupKey(nodeGroup))
@@ -60,6 +60,21 @@ func TestMaxBackoff(t *testing.T) {
 	assert.False(t, backoff.IsBackedOff(nodeGroup1, startTime.Add(6*time.Minute)))
 }
 
+func TestBackoffUntil(t *testing.T) {
+	backoff := NewIdBasedExponentialBackoff(1*time.Minute, 3*time.Minute, 3*time.Hour)
+	startTime := time.Now()
+	_, backedOff := backoff.BackoffUntil(nodeGroup1, startTime)
+	assert.False(t, backedOff)
+	backoff.Backoff(nodeGroup1, startTime)
+	until, backedOff := backoff.BackoffUntil(nodeGroup1, startTime)
+	assert.True(t, backedOff)
+	assert.Equal(t, startTime.Add(time.Minute), until)
+	_, backedOff = backoff.BackoffUntil(nodeGroup2, startTime)
+	assert.False(t, backedOff)
+	_, backedOff = backoff.BackoffUntil(nodeGroup1, startTime.Add(time.Minute))
+	assert.False(t, backedOff)
+}
+
 func TestRemoveBackoff(t *testing.T) {
 	backoff := NewIdBasedExponentialBackoff(1*time.Minute, 3*time.Minute, 3*time.Hour)
 	startTime := time.Now()
@@ -32,6 +32,8 @@ import (
 const (
 	// ToBeDeletedTaint is a taint used to make the node unschedulable.
 	ToBeDeletedTaint = "ToBeDeletedByClusterAutoscaler"
+	// DeletionCandidateTaint is a taint used to mark unneeded nodes as preferably unschedulable.
+	DeletionCandidateTaint = "DeletionCandidateOfClusterAutoscaler"
 
 	maxRetryDeadline      = 5 * time.Second
 	conflictRetryInterval = 750 * time.Millisecond
@@ -39,6 +41,15 @@ const (
 
 // MarkToBeDeleted sets a taint that makes the node unschedulable.
 func MarkToBeDeleted(node *apiv1.Node, client kube_client.Interface) error {
+	return markWithTaint(node, client, ToBeDeletedTaint, apiv1.TaintEffectNoSchedule)
+}
+
+// MarkDeletionCandidate sets a soft taint that makes the node preferably unschedulable.
+func MarkDeletionCandidate(node *apiv1.Node, client kube_client.Interface) error {
+	return markWithTaint(node, client, DeletionCandidateTaint, apiv1.TaintEffectPreferNoSchedule)
+}
+
+func markWithTaint(node *apiv1.Node, client kube_client.Interface, taintKey string, effect apiv1.TaintEffect) error {
 	retryDeadline := time.Now().Add(maxRetryDeadline)
 	for {
 		// Get the newest version of the node.
@@ -47,7 +58,7 @@ func MarkToBeDeleted(node *apiv1.Node, client kube_client.Interface) error {
 			return fmt.Errorf("failed to get node %v: %v", node.Name, err)
 		}
 
-		added, err := addToBeDeletedTaint(freshNode)
+		added, err := addTaint(freshNode, taintKey, effect)
 		if added == false {
 			return err
 		}
@@ -61,30 +72,43 @@ func MarkToBeDeleted(node *apiv1.Node, client kube_client.Interface) error {
 			klog.Warningf("Error while adding taints on node %v: %v", node.Name, err)
 			return err
 		}
-		klog.V(1).Infof("Successfully added toBeDeletedTaint on node %v", node.Name)
+		klog.V(1).Infof("Successfully added %v on node %v", taintKey, node.Name)
 		return nil
 	}
 }
 
 func addToBeDeletedTaint(node *apiv1.Node) (bool, error) {
+	return addTaint(node, ToBeDeletedTaint, apiv1.TaintEffectNoSchedule)
+}
+
+func addTaint(node *apiv1.Node, taintKey string, effect apiv1.TaintEffect) (bool, error) {
 	for _, taint := range node.Spec.Taints {
-		if taint.Key == ToBeDeletedTaint {
-			klog.V(2).Infof("ToBeDeletedTaint already present on node %v, taint: %v", node.Name, taint)
+		if taint.Key == taintKey {
+			klog.V(2).Infof("%v already present on node %v, taint: %v", taintKey, node.Name, taint)
 			return false, nil
 		}
 	}
 	node.Spec.Taints = append(node.Spec.Taints, apiv1.Taint{
-		Key:    ToBeDeletedTaint,
+		Key:    taintKey,
 		Value:  fmt.Sprint(time.Now().Unix()),
-		Effect: apiv1.TaintEffectNoSchedule,
+		Effect: effect,
 	})
 	return true, nil
 }
 
 // HasToBeDeletedTaint returns true if ToBeDeleted taint is applied on the node.
 func HasToBeDeletedTaint(node *apiv1.Node) bool {
+	return hasTaint(node, ToBeDeletedTaint)
+}
+
+// HasDeletionCandidateTaint returns true if DeletionCandidate taint is applied on the node.
+func HasDeletionCandidateTaint(node *apiv1.Node) bool {
+	return hasTaint(node, DeletionCandidateTaint)
+}
+
+func hasTaint(node *apiv1.Node, taintKey string) bool {
 	for _, taint := range node.Spec.Taints {
-		if taint.Key == ToBeDeletedTaint {
+		if taint.Key == taintKey {
 			return true
 		}
 	}
@@ -93,8 +117,17 @@ func HasToBeDeletedTaint(node *apiv1.Node) bool {
 
 // GetToBeDeletedTime returns the date when the node was marked by CA as for delete.
 func GetToBeDeletedTime(node *apiv1.Node) (*time.Time, error) {
+	return getTaintTime(node, ToBeDeletedTaint)
+}
+
+// GetDeletionCandidateTime returns the date when the node was marked by CA as a deletion candidate.
+func GetDeletionCandidateTime(node *apiv1.Node) (*time.Time, error) {
+	return getTaintTime(node, DeletionCandidateTaint)
+}
+
+func getTaintTime(node *apiv1.Node, taintKey string) (*time.Time, error) {
 	for _, taint := range node.Spec.Taints {
-		if taint.Key == ToBeDeletedTaint {
+		if taint.Key == taintKey {
 			resultTimestamp, err := strconv.ParseInt(taint.Value, 10, 64)
 			if err != nil {
 				return nil, err
@@ -108,6 +141,15 @@ func GetToBeDeletedTime(node *apiv1.Node) (*time.Time, error) {
 
 // CleanToBeDeleted cleans ToBeDeleted taint.
 func CleanToBeDeleted(node *apiv1.Node, client kube_client.Interface) (bool, error) {
+	return cleanTaint(node, client, ToBeDeletedTaint)
+}
+
+// CleanDeletionCandidate cleans DeletionCandidate taint.
+func CleanDeletionCandidate(node *apiv1.Node, client kube_client.Interface) (bool, error) {
+	return cleanTaint(node, client, DeletionCandidateTaint)
+}
+
+func cleanTaint(node *apiv1.Node, client kube_client.Interface, taintKey string) (bool, error) {
 	retryDeadline := time.Now().Add(maxRetryDeadline)
 	for {
 		freshNode, err := client.CoreV1().Nodes().Get(node.Name, metav1.GetOptions{})
@@ -116,7 +158,7 @@ func CleanToBeDeleted(node *apiv1.Node, client kube_client.Interface) (bool, err
 		}
 		newTaints := make([]apiv1.Taint, 0)
 		for _, taint := range freshNode.Spec.Taints {
-			if taint.Key == ToBeDeletedTaint {
+			if taint.Key == taintKey {
 				klog.V(1).Infof("Releasing taint %+v on node %v", taint, node.Name)
 			} else {
 				newTaints = append(newTaints, taint)
@@ -136,7 +178,7 @@ func CleanToBeDeleted(node *apiv1.Node, client kube_client.Interface) (bool, err
 				klog.Warningf("Error while releasing taints on node %v: %v", node.Name, err)
 				return false, err
 			}
-			klog.V(1).Infof("Successfully released toBeDeletedTaint on node %v", node.Name)
+			klog.V(1).Infof("Successfully released %v on node %v", taintKey, node.Name)
 			return true, nil
 		}
 		return false, nil
@@ -74,6 +74,39 @@ func TestCleanNodes(t *testing.T) {
 	assert.False(t, HasToBeDeletedTaint(updatedNode))
 }
 
+func TestSoftMarkNodes(t *testing.T) {
+	node := BuildTestNode("node", 1000, 1000)
+	fakeClient := buildFakeClient(t, node)
+	err := MarkDeletionCandidate(node, fakeClient)
+	assert.NoError(t, err)
+
+	updatedNode, err := fakeClient.Core().Nodes().Get("node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, HasDeletionCandidateTaint(updatedNode))
+	assert.False(t, HasToBeDeletedTaint(updatedNode))
+	assert.Equal(t, apiv1.TaintEffectPreferNoSchedule, updatedNode.Spec.Taints[0].Effect)
+
+	val, err := GetDeletionCandidateTime(updatedNode)
+	assert.NoError(t, err)
+	assert.True(t, time.Now().Sub(*val) < 10*time.Second)
+}
+
+func TestSoftCleanNodes(t *testing.T) {
+	node := BuildTestNode("node", 1000, 1000)
+	addToBeDeletedTaint(node)
+	addTaint(node, DeletionCandidateTaint, apiv1.TaintEffectPreferNoSchedule)
+	fakeClient := buildFakeClient(t, node)
+
+	cleaned, err := CleanDeletionCandidate(node, fakeClient)
+	assert.True(t, cleaned)
+	assert.NoError(t, err)
+
+	updatedNode, err := fakeClient.Core().Nodes().Get("node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, HasDeletionCandidateTaint(updatedNode))
+	assert.True(t, HasToBeDeletedTaint(updatedNode))
+}
+
 func buildFakeClient(t *testing.T, node *apiv1.Node) *fake.Clientset {
 	fakeClient := fake.NewSimpleClientset()
 
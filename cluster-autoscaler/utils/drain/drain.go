@@ -37,10 +37,45 @@ const (
 	// PodSafeToEvictKey - annotation that ignores constraints to evict a pod like not being replicated, being on
 	// kube-system namespace or having a local storage.
 	PodSafeToEvictKey = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+	// PodDisruptionCostKey - annotation with the relative cost of evicting a pod, a non-negative number.
+	// Pods without it have the cost of 1.
+	PodDisruptionCostKey = "cluster-autoscaler.kubernetes.io/pod-disruption-cost"
+	// HeadroomPodKey - annotation that marks synthetic pods representing headroom. The value is the id of
+	// the node group the headroom belongs to, or empty for cluster-wide headroom.
+	HeadroomPodKey = "cluster-autoscaler.kubernetes.io/headroom"
+)
+
+// BlockingPod represents a pod which is blocking the scale down of a node.
+type BlockingPod struct {
+	Pod    *apiv1.Pod
+	Reason BlockingPodReason
+}
+
+// BlockingPodReason represents a reason why a pod is blocking the scale down of a node.
+type BlockingPodReason string
+
+const (
+	// ControllerNotFound - pod is blocking scale down because its controller can't be found.
+	ControllerNotFound BlockingPodReason = "ControllerNotFound"
+	// MinReplicasReached - pod is blocking scale down because its controller already has the minimum number of replicas.
+	MinReplicasReached BlockingPodReason = "MinReplicasReached"
+	// NotReplicated - pod is blocking scale down because it's not replicated.
+	NotReplicated BlockingPodReason = "NotReplicated"
+	// LocalStorageRequested - pod is blocking scale down because it uses local storage.
+	LocalStorageRequested BlockingPodReason = "LocalStorageRequested"
+	// NotSafeToEvictAnnotation - pod is blocking scale down because it has "not safe to evict" annotation.
+	NotSafeToEvictAnnotation BlockingPodReason = "NotSafeToEvictAnnotation"
+	// UnmovableKubeSystemPod - pod is blocking scale down because it's a non-daemonset, non-mirrored, non-pdb-assigned kube-system pod.
+	UnmovableKubeSystemPod BlockingPodReason = "UnmovableKubeSystemPod"
+	// NotEnoughPdb - pod is blocking scale down because it doesn't have enough PDB left.
+	NotEnoughPdb BlockingPodReason = "NotEnoughPdb"
+	// UnexpectedError - pod is blocking scale down because of an unexpected error.
+	UnexpectedError BlockingPodReason = "UnexpectedError"
 )
 
 // GetPodsForDeletionOnNodeDrain returns pods that should be deleted on node drain as well as some extra information
-// about possibly problematic pods (unreplicated and daemonsets).
+// about possibly problematic pods (unreplicated and daemonsets). If the node can't be drained, the pod blocking
+// it is returned together with an error.
 func GetPodsForDeletionOnNodeDrain(
 	podList []*apiv1.Pod,
 	pdbs []*policyv1.PodDisruptionBudget,
@@ -50,7 +85,7 @@ func GetPodsForDeletionOnNodeDrain(
 	checkReferences bool, // Setting this to true requires client to be not-null.
 	client client.Interface,
 	minReplica int32,
-	currentTime time.Time) ([]*apiv1.Pod, error) {
+	currentTime time.Time) ([]*apiv1.Pod, *BlockingPod, error) {
 
 	pods := []*apiv1.Pod{}
 	// filter kube-system PDBs to avoid doing it for every kube-system pod
@@ -97,13 +132,13 @@ func GetPodsForDeletionOnNodeDrain(
 				// TODO: replace the minReplica check with pod disruption budget.
 				if err == nil && rc != nil {
 					if rc.Spec.Replicas != nil && *rc.Spec.Replicas < minReplica {
-						return []*apiv1.Pod{}, fmt.Errorf("replication controller for %s/%s has too few replicas spec: %d min: %d",
+						return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: MinReplicasReached}, fmt.Errorf("replication controller for %s/%s has too few replicas spec: %d min: %d",
 							pod.Namespace, pod.Name, rc.Spec.Replicas, minReplica)
 					}
 					replicated = true
 
 				} else {
-					return []*apiv1.Pod{}, fmt.Errorf("replication controller for %s/%s is not available, err: %v", pod.Namespace, pod.Name, err)
+					return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: ControllerNotFound}, fmt.Errorf("replication controller for %s/%s is not available, err: %v", pod.Namespace, pod.Name, err)
 				}
 			} else {
 				replicated = true
@@ -122,7 +157,7 @@ func GetPodsForDeletionOnNodeDrain(
 					// daemonset pods, probably using taints.
 					daemonsetPod = true
 				} else {
-					return []*apiv1.Pod{}, fmt.Errorf("daemonset for %s/%s is not present, err: %v", pod.Namespace, pod.Name, err)
+					return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: ControllerNotFound}, fmt.Errorf("daemonset for %s/%s is not present, err: %v", pod.Namespace, pod.Name, err)
 				}
 			} else {
 				daemonsetPod = true
@@ -137,7 +172,7 @@ func GetPodsForDeletionOnNodeDrain(
 				if err == nil && job != nil {
 					replicated = true
 				} else {
-					return []*apiv1.Pod{}, fmt.Errorf("job for %s/%s is not available: err: %v", pod.Namespace, pod.Name, err)
+					return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: ControllerNotFound}, fmt.Errorf("job for %s/%s is not available: err: %v", pod.Namespace, pod.Name, err)
 				}
 			} else {
 				replicated = true
@@ -151,12 +186,12 @@ func GetPodsForDeletionOnNodeDrain(
 				// sophisticated than this
 				if err == nil && rs != nil {
 					if rs.Spec.Replicas != nil && *rs.Spec.Replicas < minReplica {
-						return []*apiv1.Pod{}, fmt.Errorf("replication controller for %s/%s has too few replicas spec: %d min: %d",
+						return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: MinReplicasReached}, fmt.Errorf("replication controller for %s/%s has too few replicas spec: %d min: %d",
 							pod.Namespace, pod.Name, rs.Spec.Replicas, minReplica)
 					}
 					replicated = true
 				} else {
-					return []*apiv1.Pod{}, fmt.Errorf("replication controller for %s/%s is not available, err: %v", pod.Namespace, pod.Name, err)
+					return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: ControllerNotFound}, fmt.Errorf("replication controller for %s/%s is not available, err: %v", pod.Namespace, pod.Name, err)
 				}
 			} else {
 				replicated = true
@@ -171,7 +206,7 @@ func GetPodsForDeletionOnNodeDrain(
 				if err == nil && ss != nil {
 					replicated = true
 				} else {
-					return []*apiv1.Pod{}, fmt.Errorf("statefulset for %s/%s is not available: err: %v", pod.Namespace, pod.Name, err)
+					return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: ControllerNotFound}, fmt.Errorf("statefulset for %s/%s is not available: err: %v", pod.Namespace, pod.Name, err)
 				}
 			} else {
 				replicated = true
@@ -183,27 +218,27 @@ func GetPodsForDeletionOnNodeDrain(
 
 		if !deleteAll && !safeToEvict && !terminal {
 			if !replicated {
-				return []*apiv1.Pod{}, fmt.Errorf("%s/%s is not replicated", pod.Namespace, pod.Name)
+				return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: NotReplicated}, fmt.Errorf("%s/%s is not replicated", pod.Namespace, pod.Name)
 			}
 			if pod.Namespace == "kube-system" && skipNodesWithSystemPods {
 				hasPDB, err := checkKubeSystemPDBs(pod, kubeSystemPDBs)
 				if err != nil {
-					return []*apiv1.Pod{}, fmt.Errorf("error matching pods to pdbs: %v", err)
+					return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: UnexpectedError}, fmt.Errorf("error matching pods to pdbs: %v", err)
 				}
 				if !hasPDB {
-					return []*apiv1.Pod{}, fmt.Errorf("non-daemonset, non-mirrored, non-pdb-assigned kube-system pod present: %s", pod.Name)
+					return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: UnmovableKubeSystemPod}, fmt.Errorf("non-daemonset, non-mirrored, non-pdb-assigned kube-system pod present: %s", pod.Name)
 				}
 			}
 			if HasLocalStorage(pod) && skipNodesWithLocalStorage {
-				return []*apiv1.Pod{}, fmt.Errorf("pod with local storage present: %s", pod.Name)
+				return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: LocalStorageRequested}, fmt.Errorf("pod with local storage present: %s", pod.Name)
 			}
 			if hasNotSafeToEvictAnnotation(pod) {
-				return []*apiv1.Pod{}, fmt.Errorf("pod annotated as not safe to evict present: %s", pod.Name)
+				return []*apiv1.Pod{}, &BlockingPod{Pod: pod, Reason: NotSafeToEvictAnnotation}, fmt.Errorf("pod annotated as not safe to evict present: %s", pod.Name)
 			}
 		}
 		pods = append(pods, pod)
 	}
-	return pods, nil
+	return pods, nil, nil
 }
 
 // ControllerRef returns the OwnerReference to pod's controller.
@@ -217,6 +252,12 @@ func IsMirrorPod(pod *apiv1.Pod) bool {
 	return found
 }
 
+// IsHeadroomPod checks whether the pod is a synthetic pod representing headroom.
+func IsHeadroomPod(pod *apiv1.Pod) bool {
+	_, found := pod.ObjectMeta.Annotations[HeadroomPodKey]
+	return found
+}
+
 // isPodTerminal checks whether the pod is in a terminal state.
 func isPodTerminal(pod *apiv1.Pod) bool {
 	// pod will never be restarted
@@ -18,6 +18,7 @@ package drain
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -351,13 +352,14 @@ func TestDrain(t *testing.T) {
 	}
 
 	tests := []struct {
-		description string
-		pods        []*apiv1.Pod
-		pdbs        []*policyv1.PodDisruptionBudget
-		rcs         []apiv1.ReplicationController
-		replicaSets []extensions.ReplicaSet
-		expectFatal bool
-		expectPods  []*apiv1.Pod
+		description       string
+		pods              []*apiv1.Pod
+		pdbs              []*policyv1.PodDisruptionBudget
+		rcs               []apiv1.ReplicationController
+		replicaSets       []extensions.ReplicaSet
+		expectFatal       bool
+		expectPods        []*apiv1.Pod
+		expectBlockingPod *BlockingPod
 	}{
 		{
 			description: "RC-managed pod",
@@ -409,18 +411,20 @@ func TestDrain(t *testing.T) {
 			expectPods:  []*apiv1.Pod{},
 		},
 		{
-			description: "naked pod",
-			pods:        []*apiv1.Pod{nakedPod},
-			pdbs:        []*policyv1.PodDisruptionBudget{},
-			expectFatal: true,
-			expectPods:  []*apiv1.Pod{},
+			description:       "naked pod",
+			pods:              []*apiv1.Pod{nakedPod},
+			pdbs:              []*policyv1.PodDisruptionBudget{},
+			expectFatal:       true,
+			expectBlockingPod: &BlockingPod{Pod: nakedPod, Reason: NotReplicated},
+			expectPods:        []*apiv1.Pod{},
 		},
 		{
-			description: "pod with EmptyDir",
-			pods:        []*apiv1.Pod{emptydirPod},
-			pdbs:        []*policyv1.PodDisruptionBudget{},
-			expectFatal: true,
-			expectPods:  []*apiv1.Pod{},
+			description:       "pod with EmptyDir",
+			pods:              []*apiv1.Pod{emptydirPod},
+			pdbs:              []*policyv1.PodDisruptionBudget{},
+			expectFatal:       true,
+			expectBlockingPod: &BlockingPod{Pod: emptydirPod, Reason: NotReplicated},
+			expectPods:        []*apiv1.Pod{},
 		},
 		{
 			description: "failed pod",
@@ -465,20 +469,22 @@ func TestDrain(t *testing.T) {
 			expectPods:  []*apiv1.Pod{emptydirSafePod},
 		},
 		{
-			description: "RC-managed pod with PodSafeToEvict=false annotation",
-			pods:        []*apiv1.Pod{unsafeRcPod},
-			rcs:         []apiv1.ReplicationController{rc},
-			pdbs:        []*policyv1.PodDisruptionBudget{},
-			expectFatal: true,
-			expectPods:  []*apiv1.Pod{},
+			description:       "RC-managed pod with PodSafeToEvict=false annotation",
+			pods:              []*apiv1.Pod{unsafeRcPod},
+			rcs:               []apiv1.ReplicationController{rc},
+			pdbs:              []*policyv1.PodDisruptionBudget{},
+			expectFatal:       true,
+			expectBlockingPod: &BlockingPod{Pod: unsafeRcPod, Reason: NotSafeToEvictAnnotation},
+			expectPods:        []*apiv1.Pod{},
 		},
 		{
-			description: "Job-managed pod with PodSafeToEvict=false annotation",
-			pods:        []*apiv1.Pod{unsafeJobPod},
-			pdbs:        []*policyv1.PodDisruptionBudget{},
-			rcs:         []apiv1.ReplicationController{rc},
-			expectFatal: true,
-			expectPods:  []*apiv1.Pod{},
+			description:       "Job-managed pod with PodSafeToEvict=false annotation",
+			pods:              []*apiv1.Pod{unsafeJobPod},
+			pdbs:              []*policyv1.PodDisruptionBudget{},
+			rcs:               []apiv1.ReplicationController{rc},
+			expectFatal:       true,
+			expectBlockingPod: &BlockingPod{Pod: unsafeJobPod, Reason: NotSafeToEvictAnnotation},
+			expectPods:        []*apiv1.Pod{},
 		},
 		{
 			description: "empty PDB with RC-managed pod",
@@ -497,12 +503,13 @@ func TestDrain(t *testing.T) {
 			expectPods:  []*apiv1.Pod{kubeSystemRcPod},
 		},
 		{
-			description: "kube-system PDB with non-matching kube-system pod",
-			pods:        []*apiv1.Pod{kubeSystemRcPod},
-			pdbs:        []*policyv1.PodDisruptionBudget{kubeSystemFakePDB},
-			rcs:         []apiv1.ReplicationController{rc},
-			expectFatal: true,
-			expectPods:  []*apiv1.Pod{},
+			description:       "kube-system PDB with non-matching kube-system pod",
+			pods:              []*apiv1.Pod{kubeSystemRcPod},
+			pdbs:              []*policyv1.PodDisruptionBudget{kubeSystemFakePDB},
+			rcs:               []apiv1.ReplicationController{rc},
+			expectFatal:       true,
+			expectBlockingPod: &BlockingPod{Pod: kubeSystemRcPod, Reason: UnmovableKubeSystemPod},
+			expectPods:        []*apiv1.Pod{},
 		},
 		{
 			description: "kube-system PDB with default namespace pod",
@@ -513,12 +520,13 @@ func TestDrain(t *testing.T) {
 			expectPods:  []*apiv1.Pod{rcPod},
 		},
 		{
-			description: "default namespace PDB with matching labels kube-system pod",
-			pods:        []*apiv1.Pod{kubeSystemRcPod},
-			pdbs:        []*policyv1.PodDisruptionBudget{defaultNamespacePDB},
-			rcs:         []apiv1.ReplicationController{rc},
-			expectFatal: true,
-			expectPods:  []*apiv1.Pod{},
+			description:       "default namespace PDB with matching labels kube-system pod",
+			pods:              []*apiv1.Pod{kubeSystemRcPod},
+			pdbs:              []*policyv1.PodDisruptionBudget{defaultNamespacePDB},
+			rcs:               []apiv1.ReplicationController{rc},
+			expectFatal:       true,
+			expectBlockingPod: &BlockingPod{Pod: kubeSystemRcPod, Reason: UnmovableKubeSystemPod},
+			expectPods:        []*apiv1.Pod{},
 		},
 	}
 
@@ -543,7 +551,7 @@ func TestDrain(t *testing.T) {
 		if len(test.replicaSets) > 0 {
 			register("replicasets", &test.replicaSets[0], test.replicaSets[0].ObjectMeta)
 		}
-		pods, err := GetPodsForDeletionOnNodeDrain(test.pods, test.pdbs,
+		pods, blockingPod, err := GetPodsForDeletionOnNodeDrain(test.pods, test.pdbs,
 			false, true, true, true, fakeClient, 0, time.Now())
 
 		if test.expectFatal {
@@ -561,5 +569,16 @@ func TestDrain(t *testing.T) {
 		if len(pods) != len(test.expectPods) {
 			t.Fatalf("Wrong pod list content: %v", test.description)
 		}
+
+		if !reflect.DeepEqual(blockingPod, test.expectBlockingPod) {
+			t.Fatalf("%s: unexpected blocking pod %v, expected %v", test.description, blockingPodString(blockingPod), blockingPodString(test.expectBlockingPod))
+		}
+	}
+}
+
+func blockingPodString(blockingPod *BlockingPod) string {
+	if blockingPod == nil {
+		return "none"
 	}
+	return fmt.Sprintf("%s (%s)", blockingPod.Pod.Name, blockingPod.Reason)
 }
@@ -296,3 +296,14 @@ func NewDaemonSetLister(kubeClient client.Interface, stopchannel <-chan struct{}
 		daemonSetLister: lister,
 	}
 }
+
+// NewConfigMapListerForNamespace builds a configmap lister for the passed namespace (including all).
+func NewConfigMapListerForNamespace(kubeClient client.Interface, stopchannel <-chan struct{},
+	namespace string) v1lister.ConfigMapNamespaceLister {
+	listWatcher := cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "configmaps", namespace, fields.Everything())
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	lister := v1lister.NewConfigMapLister(store)
+	reflector := cache.NewReflector(listWatcher, &apiv1.ConfigMap{}, store, time.Hour)
+	go reflector.Run(stopchannel)
+	return lister.ConfigMaps(namespace)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// windowSeparator separates windows in a schedule specification.
+	windowSeparator = ";"
+	// searchHorizon limits how far in the future window starts are looked for.
+	searchHorizon = 5 * 366 * 24 * time.Hour
+)
+
+// Schedule is a set of recurring time windows. An empty schedule contains all points in time.
+type Schedule struct {
+	windows []*window
+}
+
+// window is a recurring period of time that starts at times matching a cron expression
+// and lasts for the given duration.
+type window struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// anyDayOfMonth and anyDayOfWeek are set if the corresponding field is "*". Following cron,
+	// if both day fields are restricted a day matches if it matches either of them.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+	duration      time.Duration
+}
+
+// Parse parses a schedule specification. The specification is a list of windows separated by
+// semicolons. Each window consists of a standard 5-field cron expression (minute, hour, day of
+// month, month, day of week) describing when the window starts, followed by the duration of
+// the window, e.g. "0 22 * * 1-5 10h; 0 0 * * 6 48h". Fields support "*", ranges, lists and
+// steps. Times are matched in the location of the time passed to Schedule methods.
+func Parse(spec string) (*Schedule, error) {
+	schedule := &Schedule{}
+	if strings.TrimSpace(spec) == "" {
+		return schedule, nil
+	}
+	for _, windowSpec := range strings.Split(spec, windowSeparator) {
+		w, err := parseWindow(windowSpec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window %q: %v", strings.TrimSpace(windowSpec), err)
+		}
+		schedule.windows = append(schedule.windows, w)
+	}
+	return schedule, nil
+}
+
+// Cache remembers the result of parsing the last specification passed to Parse, so that
+// specifications read repeatedly, e.g. from node group options, are parsed only once. The zero
+// value is ready to use. Cache is not safe for concurrent use.
+type Cache struct {
+	spec     string
+	schedule *Schedule
+	err      error
+	parsed   bool
+}
+
+// Parse returns the result of parsing spec, reusing the previous result if spec didn't change.
+func (c *Cache) Parse(spec string) (*Schedule, error) {
+	if !c.parsed || c.spec != spec {
+		c.schedule, c.err = Parse(spec)
+		c.spec, c.parsed = spec, true
+	}
+	return c.schedule, c.err
+}
+
+// IsEmpty returns true if the schedule doesn't define any windows.
+func (s *Schedule) IsEmpty() bool {
+	return len(s.windows) == 0
+}
+
+// Contains returns true if t falls into any of the schedule windows.
+func (s *Schedule) Contains(t time.Time) bool {
+	if s.IsEmpty() {
+		return true
+	}
+	for _, w := range s.windows {
+		// t is within a window if the window started in (t - duration, t].
+		if start, found := w.nextStart(t.Add(-w.duration)); found && !start.After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the earliest point in time not before t that falls into one of the schedule
+// windows. Zero time is returned if there is no such point within the next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.Contains(t) {
+		return t
+	}
+	var result time.Time
+	for _, w := range s.windows {
+		if start, found := w.nextStart(t); found && (result.IsZero() || start.Before(result)) {
+			result = start
+		}
+	}
+	return result
+}
+
+// nextStart returns the first window start strictly after t.
+func (w *window) nextStart(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	limit := t.Add(searchHorizon)
+	// Window starts are aligned to full minutes.
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	for t.Before(limit) {
+		if !hasBit(w.months, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !w.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !hasBit(w.hours, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !hasBit(w.minutes, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func (w *window) dayMatches(t time.Time) bool {
+	domMatches := hasBit(w.daysOfMonth, t.Day())
+	dowMatches := hasBit(w.daysOfWeek, int(t.Weekday()))
+	if w.anyDayOfMonth || w.anyDayOfWeek {
+		return domMatches && dowMatches
+	}
+	return domMatches || dowMatches
+}
+
+func parseWindow(spec string) (*window, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("expected 5 cron fields and a duration, got %d fields", len(fields))
+	}
+	duration, err := time.ParseDuration(fields[5])
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive, got %v", duration)
+	}
+
+	w := &window{
+		duration:      duration,
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}
+	for _, f := range []struct {
+		name   string
+		expr   string
+		min    int
+		max    int
+		target *uint64
+	}{
+		{"minute", fields[0], 0, 59, &w.minutes},
+		{"hour", fields[1], 0, 23, &w.hours},
+		{"day of month", fields[2], 1, 31, &w.daysOfMonth},
+		{"month", fields[3], 1, 12, &w.months},
+		{"day of week", fields[4], 0, 7, &w.daysOfWeek},
+	} {
+		bits, err := parseField(f.expr, f.min, f.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s field %q: %v", f.name, f.expr, err)
+		}
+		*f.target = bits
+	}
+	// Both 0 and 7 stand for Sunday.
+	if hasBit(w.daysOfWeek, 7) {
+		w.daysOfWeek |= 1
+	}
+	return w, nil
+}
+
+// parseField parses a comma separated list of values, ranges ("a-b") and steps ("*/n", "a-b/n")
+// into a bit set.
+func parseField(expr string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangeExpr = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		low, high := min, max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value in %q", part)
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value in %q", part)
+				}
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for i := low; i <= high; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func hasBit(bits uint64, i int) bool {
+	return bits&(1<<uint(i)) != 0
+}
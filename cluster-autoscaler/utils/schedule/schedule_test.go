@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(day, hour, minute int) time.Time {
+	// 2019-01-07 is a Monday.
+	return time.Date(2019, time.January, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"0 22 * * 1-5",
+		"0 22 * * 1-5 -1h",
+		"0 22 * * 1-5 soon",
+		"60 22 * * * 1h",
+		"0 24 * * * 1h",
+		"0 22 0 * * 1h",
+		"0 22 * 13 * 1h",
+		"0 22 * * 8 1h",
+		"0 22 * * 5-1 1h",
+		"*/0 22 * * * 1h",
+		"0 22 * * * 1h; 0 x * * * 1h",
+	} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestEmptySchedule(t *testing.T) {
+	s, err := Parse(" ")
+	assert.NoError(t, err)
+	assert.True(t, s.IsEmpty())
+	assert.True(t, s.Contains(date(7, 12, 0)))
+	assert.Equal(t, date(7, 12, 0), s.Next(date(7, 12, 0)))
+}
+
+func TestContains(t *testing.T) {
+	// Weekday nights and whole weekends.
+	s, err := Parse("0 22 * * 1-5 10h; 0 0 * * 6 48h")
+	assert.NoError(t, err)
+	assert.False(t, s.IsEmpty())
+
+	assert.False(t, s.Contains(date(7, 12, 0)))
+	assert.False(t, s.Contains(date(7, 21, 59)))
+	assert.True(t, s.Contains(date(7, 22, 0)))
+	assert.True(t, s.Contains(date(8, 7, 59)))
+	assert.False(t, s.Contains(date(8, 8, 0)))
+	// Saturday and Sunday.
+	assert.True(t, s.Contains(date(12, 12, 0)))
+	assert.True(t, s.Contains(date(13, 23, 59)))
+	assert.False(t, s.Contains(date(14, 12, 0)))
+}
+
+func TestNext(t *testing.T) {
+	s, err := Parse("30 22 * * 1-5 1h")
+	assert.NoError(t, err)
+
+	assert.Equal(t, date(7, 22, 30), s.Next(date(7, 12, 0)))
+	assert.Equal(t, date(7, 22, 45), s.Next(date(7, 22, 45)))
+	// Friday evening is followed by Monday evening.
+	assert.Equal(t, date(14, 22, 30), s.Next(date(11, 23, 30)))
+}
+
+func TestDayMatching(t *testing.T) {
+	// Both day fields restricted - either of them has to match.
+	s, err := Parse("0 12 1 * 0 1h")
+	assert.NoError(t, err)
+	assert.Equal(t, date(13, 12, 0), s.Next(date(7, 0, 0)))
+	assert.Equal(t, time.Date(2019, time.February, 1, 12, 0, 0, 0, time.UTC), s.Next(date(27, 13, 0)))
+
+	// 7 is Sunday as well.
+	s, err = Parse("0 12 * * 7 1h")
+	assert.NoError(t, err)
+	assert.Equal(t, date(13, 12, 0), s.Next(date(7, 0, 0)))
+
+	// Steps and lists.
+	s, err = Parse("*/20 8,20 * * * 1m")
+	assert.NoError(t, err)
+	assert.Equal(t, date(7, 8, 40), s.Next(date(7, 8, 21)))
+	assert.Equal(t, date(7, 20, 0), s.Next(date(7, 8, 41)))
+}
+
+func TestCache(t *testing.T) {
+	var cache Cache
+	first, err := cache.Parse("0 22 * * 1-5 10h")
+	assert.NoError(t, err)
+	second, err := cache.Parse("0 22 * * 1-5 10h")
+	assert.NoError(t, err)
+	assert.True(t, first == second)
+
+	_, err = cache.Parse("0 22 * * *")
+	assert.Error(t, err)
+
+	third, err := cache.Parse("0 22 * * 1-5 10h")
+	assert.NoError(t, err)
+	assert.False(t, first == third)
+	assert.Equal(t, first, third)
+}